@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
@@ -22,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/aws/aws-sdk-go/service/support"
@@ -41,6 +45,12 @@ type Config struct {
 	Region          string
 	RoleARN         string
 	SessionToken    string
+
+	// CredentialName optionally identifies the credential the RoleARN was read
+	// from. It is used by the Pool to invalidate clients in case the
+	// credential's CredentialVersion changes.
+	CredentialName    string
+	CredentialVersion string
 }
 
 type Clients struct {
@@ -54,46 +64,31 @@ type Clients struct {
 	Route53        *route53.Route53
 	S3             s3iface.S3API
 	ServiceQuotas  servicequotasiface.ServiceQuotasAPI
+	SQS            sqsiface.SQSAPI
 	STS            stsiface.STSAPI
 	Support        supportiface.SupportAPI
 }
 
 func NewClients(config Config) (Clients, error) {
-	if config.AccessKeyID == "" {
-		return Clients{}, microerror.Maskf(invalidConfigError, "%T.AccessKeyID must not be empty", config)
-	}
-	if config.AccessKeySecret == "" {
-		return Clients{}, microerror.Maskf(invalidConfigError, "%T.AccessKeySecret must not be empty", config)
-	}
-	if config.Region == "" {
-		return Clients{}, microerror.Maskf(invalidConfigError, "%T.Region must not be empty", config)
+	err := validateConfig(config)
+	if err != nil {
+		return Clients{}, microerror.Mask(err)
 	}
-	if config.RoleARN == "" {
-		return Clients{}, microerror.Maskf(invalidConfigError, "%T.RoleARN must not be empty", config)
-	}
-
-	var err error
-
-	var s *session.Session
-	{
-		c := &aws.Config{
-			Credentials: credentials.NewStaticCredentials(config.AccessKeyID, config.AccessKeySecret, config.SessionToken),
-			Region:      aws.String(config.Region),
-		}
 
-		s, err = session.NewSession(c)
-		if err != nil {
-			return Clients{}, microerror.Mask(err)
-		}
+	s, err := newSession(config)
+	if err != nil {
+		return Clients{}, microerror.Mask(err)
 	}
 
-	c := newClients(s, config.RoleARN)
+	c := newClients(s, config.RoleARN, 0)
 	return c, nil
 }
 
-func newClients(session *session.Session, roleARN string) Clients {
+func newClients(session *session.Session, roleARN string, expiryWindow time.Duration) Clients {
 	credentialsConfig := &aws.Config{
-		Credentials: stscreds.NewCredentials(session, roleARN),
+		Credentials: stscreds.NewCredentials(session, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.ExpiryWindow = expiryWindow
+		}),
 	}
 	supportConfig := aws.NewConfig().WithRegion(trustedAdvisorRegion)
 
@@ -108,9 +103,41 @@ func newClients(session *session.Session, roleARN string) Clients {
 		Route53:        route53.New(session, credentialsConfig),
 		S3:             s3.New(session, credentialsConfig),
 		ServiceQuotas:  servicequotas.New(session, credentialsConfig),
+		SQS:            sqs.New(session, credentialsConfig),
 		STS:            sts.New(session, credentialsConfig),
 		Support:        support.New(session, credentialsConfig, supportConfig),
 	}
 
 	return c
 }
+
+func newSession(config Config) (*session.Session, error) {
+	c := &aws.Config{
+		Credentials: credentials.NewStaticCredentials(config.AccessKeyID, config.AccessKeySecret, config.SessionToken),
+		Region:      aws.String(config.Region),
+	}
+
+	s, err := session.NewSession(c)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return s, nil
+}
+
+func validateConfig(config Config) error {
+	if config.AccessKeyID == "" {
+		return microerror.Maskf(invalidConfigError, "%T.AccessKeyID must not be empty", config)
+	}
+	if config.AccessKeySecret == "" {
+		return microerror.Maskf(invalidConfigError, "%T.AccessKeySecret must not be empty", config)
+	}
+	if config.Region == "" {
+		return microerror.Maskf(invalidConfigError, "%T.Region must not be empty", config)
+	}
+	if config.RoleARN == "" {
+		return microerror.Maskf(invalidConfigError, "%T.RoleARN must not be empty", config)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolEntries = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aws_operator_aws_client_pool_entries",
+			Help: "Gauge representing the number of AWS clients held by the client pool.",
+		},
+	)
+	poolEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aws_operator_aws_client_pool_evictions_total",
+			Help: "Counter representing the AWS clients evicted from the client pool.",
+		},
+		[]string{"reason"},
+	)
+	poolHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "aws_operator_aws_client_pool_hits_total",
+			Help: "Counter representing the AWS client requests served by the client pool.",
+		},
+	)
+	poolMisses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "aws_operator_aws_client_pool_misses_total",
+			Help: "Counter representing the AWS client requests which created new clients.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(poolEntries)
+	prometheus.MustRegister(poolEvictions)
+	prometheus.MustRegister(poolHits)
+	prometheus.MustRegister(poolMisses)
+}
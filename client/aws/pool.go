@@ -0,0 +1,181 @@
+package aws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	evictionReasonIdle        = "idle"
+	evictionReasonInvalidated = "invalidated"
+)
+
+type PoolConfig struct {
+	// ExpiryWindow is the duration before the expiry of assumed role
+	// credentials in which they are refreshed already. This prevents requests
+	// from being signed with credentials that expire while being in flight.
+	ExpiryWindow time.Duration
+	// IdleTTL is the duration after which clients not requested anymore are
+	// evicted from the pool.
+	IdleTTL time.Duration
+}
+
+// Pool shares AWS clients across reconciliations. Clients are pooled by
+// region and role ARN, so that all of them share one session and one set of
+// assumed role credentials, which are only renewed when about to expire.
+type Pool struct {
+	mutex sync.Mutex
+	now   func() time.Time
+
+	credentials map[string]*poolCredential
+	entries     map[poolKey]*poolEntry
+
+	expiryWindow time.Duration
+	idleTTL      time.Duration
+}
+
+type poolCredential struct {
+	keys    map[poolKey]struct{}
+	version string
+}
+
+type poolEntry struct {
+	clients  Clients
+	config   Config
+	lastUsed time.Time
+}
+
+type poolKey struct {
+	region  string
+	roleARN string
+}
+
+func NewPool(config PoolConfig) (*Pool, error) {
+	if config.ExpiryWindow == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ExpiryWindow must not be empty", config)
+	}
+	if config.IdleTTL == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.IdleTTL must not be empty", config)
+	}
+
+	p := &Pool{
+		now: time.Now,
+
+		credentials: map[string]*poolCredential{},
+		entries:     map[poolKey]*poolEntry{},
+
+		expiryWindow: config.ExpiryWindow,
+		idleTTL:      config.IdleTTL,
+	}
+
+	return p, nil
+}
+
+// Clients returns the pooled clients for the region and role ARN of the given
+// config, creating them if necessary. In case the config names the credential
+// it was read from, all clients created for a prior version of that credential
+// are invalidated.
+func (p *Pool) Clients(config Config) (Clients, error) {
+	err := validateConfig(config)
+	if err != nil {
+		return Clients{}, microerror.Mask(err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	k := poolKey{region: config.Region, roleARN: config.RoleARN}
+
+	p.evictIdle(now)
+
+	if config.CredentialName != "" {
+		p.trackCredential(config.CredentialName, config.CredentialVersion, k)
+	}
+
+	e, ok := p.entries[k]
+	if ok && sameAccessKey(e.config, config) {
+		poolHits.Inc()
+		e.lastUsed = now
+		return e.clients, nil
+	}
+
+	poolMisses.Inc()
+
+	s, err := newSession(config)
+	if err != nil {
+		return Clients{}, microerror.Mask(err)
+	}
+
+	p.entries[k] = &poolEntry{
+		clients:  newClients(s, config.RoleARN, p.expiryWindow),
+		config:   config,
+		lastUsed: now,
+	}
+	poolEntries.Set(float64(len(p.entries)))
+
+	return p.entries[k].clients, nil
+}
+
+func (p *Pool) evict(k poolKey, reason string) {
+	_, ok := p.entries[k]
+	if !ok {
+		return
+	}
+
+	delete(p.entries, k)
+	poolEvictions.WithLabelValues(reason).Inc()
+	poolEntries.Set(float64(len(p.entries)))
+}
+
+// evictIdle evicts the clients of all keys not requested within the idle TTL.
+// Credentials are forgotten together with the last key associated with them,
+// so that credentials of deleted accounts do not accumulate in the pool.
+func (p *Pool) evictIdle(now time.Time) {
+	for k, e := range p.entries {
+		if now.Sub(e.lastUsed) > p.idleTTL {
+			p.evict(k, evictionReasonIdle)
+		}
+	}
+
+	for name, c := range p.credentials {
+		for k := range c.keys {
+			_, ok := p.entries[k]
+			if !ok {
+				delete(c.keys, k)
+			}
+		}
+		if len(c.keys) == 0 {
+			delete(p.credentials, name)
+		}
+	}
+}
+
+// trackCredential associates the given pool key with the given credential. In
+// case the credential version changed, the clients of all keys associated with
+// the prior version are evicted.
+func (p *Pool) trackCredential(name string, version string, k poolKey) {
+	c, ok := p.credentials[name]
+	if ok && c.version != version {
+		for ck := range c.keys {
+			p.evict(ck, evictionReasonInvalidated)
+		}
+		ok = false
+	}
+
+	if !ok {
+		c = &poolCredential{
+			keys:    map[poolKey]struct{}{},
+			version: version,
+		}
+		p.credentials[name] = c
+	}
+
+	c.keys[k] = struct{}{}
+}
+
+func sameAccessKey(a Config, b Config) bool {
+	return a.AccessKeyID == b.AccessKeyID && a.AccessKeySecret == b.AccessKeySecret && a.SessionToken == b.SessionToken
+}
@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_Client_AWS_Pool(t *testing.T) {
+	testCases := []struct {
+		name                string
+		requests            []Config
+		advance             time.Duration
+		expected            []bool
+		expectedCredentials int
+	}{
+		{
+			name: "case 0: same region and role share clients",
+			requests: []Config{
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "", ""),
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "", ""),
+			},
+			expected: []bool{false, true},
+		},
+		{
+			name: "case 1: different roles do not share clients",
+			requests: []Config{
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "", ""),
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/b", "", ""),
+			},
+			expected: []bool{false, false},
+		},
+		{
+			name: "case 2: changed credential version invalidates clients",
+			requests: []Config{
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "org-a/credential", "1"),
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "org-a/credential", "1"),
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "org-a/credential", "2"),
+			},
+			expected:            []bool{false, true, false},
+			expectedCredentials: 1,
+		},
+		{
+			name: "case 3: idle clients are evicted",
+			requests: []Config{
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "", ""),
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "", ""),
+			},
+			advance:  time.Hour,
+			expected: []bool{false, false},
+		},
+		{
+			name: "case 4: credentials of idle clients are forgotten",
+			requests: []Config{
+				testConfig("eu-central-1", "arn:aws:iam::123456789012:role/a", "org-a/credential", "1"),
+				testConfig("eu-central-1", "arn:aws:iam::210987654321:role/b", "org-b/credential", "1"),
+			},
+			advance:             time.Hour,
+			expected:            []bool{false, false},
+			expectedCredentials: 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p, err := NewPool(PoolConfig{ExpiryWindow: time.Minute, IdleTTL: 30 * time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			now := time.Unix(0, 0)
+			p.now = func() time.Time { return now }
+
+			var previous Clients
+			for j, c := range tc.requests {
+				clients, err := p.Clients(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				hit := j > 0 && clients.EC2 == previous.EC2
+				if hit != tc.expected[j] {
+					t.Fatalf("request %d: expected hit %t got %t", j, tc.expected[j], hit)
+				}
+
+				previous = clients
+				now = now.Add(tc.advance)
+			}
+
+			if len(p.credentials) != tc.expectedCredentials {
+				t.Fatalf("expected %d credentials got %d", tc.expectedCredentials, len(p.credentials))
+			}
+		})
+	}
+}
+
+func testConfig(region string, roleARN string, credentialName string, credentialVersion string) Config {
+	return Config{
+		AccessKeyID:     "id",
+		AccessKeySecret: "secret",
+		Region:          region,
+		RoleARN:         roleARN,
+
+		CredentialName:    credentialName,
+		CredentialVersion: credentialVersion,
+	}
+}
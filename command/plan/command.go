@@ -0,0 +1,213 @@
+// Package plan implements the plan command, which renders the CloudFormation
+// templates and cloud configs of a tenant cluster offline.
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/plan"
+)
+
+// flags are the settings of the operator relevant for rendering templates.
+// Their defaults match the defaults of the operator's Helm chart.
+type flags struct {
+	alikeInstances          string
+	amiFile                 string
+	calicoCIDR              int
+	calicoMTU               int
+	calicoSubnet            string
+	clusterDomain           string
+	clusterIPRange          string
+	dockerDaemonCIDR        string
+	ignitionPath            string
+	installationName        string
+	installationTags        string
+	instanceTypesFile       string
+	manifests               []string
+	networkCIDR             string
+	networkSetupDockerImage string
+	output                  string
+	registryDomain          string
+	registryMirrors         []string
+	route53Enabled          bool
+	subnetMaskBits          int
+}
+
+type Config struct {
+	Logger micrologger.Logger
+}
+
+type Command struct {
+	logger micrologger.Logger
+
+	cobraCommand *cobra.Command
+	flags        flags
+}
+
+func New(config Config) (*Command, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	c := &Command{
+		logger: config.Logger,
+	}
+
+	c.cobraCommand = &cobra.Command{
+		Use:   "plan",
+		Short: "Render the CloudFormation templates and cloud configs of a tenant cluster offline.",
+		Long: `Render the CloudFormation templates and cloud configs of a tenant cluster
+offline and write them to the output directory. The cluster is described by
+manifest files containing the AWSCluster, AWSControlPlane and Release CRs as
+well as optional AWSMachineDeployment and NetworkPool CRs. Information the
+operator discovers at runtime is taken from the unit test defaults. Rendering
+the same manifests with different operator versions allows to review template
+changes.
+
+The cloud config templates are read from a k8scloudconfig checkout, e.g.
+
+    --ignition-path=$(go list -m -f '{{.Dir}}' github.com/giantswarm/k8scloudconfig/v18)`,
+		RunE: c.execute,
+	}
+
+	f := c.cobraCommand.Flags()
+
+	f.StringSliceVar(&c.flags.manifests, "manifest", nil, "Manifest files of the tenant cluster's CRs. Files may contain multiple YAML documents.")
+	f.StringVar(&c.flags.output, "output", "plan", "Directory the rendered files are written to.")
+
+	f.StringVar(&c.flags.alikeInstances, "alike-instances", "{}", "Overrides for the ASG's mixed instance policy.")
+	f.StringVar(&c.flags.amiFile, "ami-file", "/tmp/ami.json", "File of the AMI catalogue as configured with the operator's aws.amiJSON value.")
+	f.IntVar(&c.flags.calicoCIDR, "calico-cidr", 16, "Calico CIDR of tenant clusters.")
+	f.IntVar(&c.flags.calicoMTU, "calico-mtu", 1430, "Calico MTU of tenant clusters.")
+	f.StringVar(&c.flags.calicoSubnet, "calico-subnet", "10.2.0.0", "Calico subnet of tenant clusters.")
+	f.StringVar(&c.flags.clusterDomain, "cluster-domain", "cluster.local", "Internal Kubernetes domain.")
+	f.StringVar(&c.flags.clusterIPRange, "cluster-ip-range", "172.31.0.0/16", "Service IP range within tenant clusters.")
+	f.StringVar(&c.flags.dockerDaemonCIDR, "docker-daemon-cidr", "172.17.0.1/16", "CIDR of the Docker daemon bridge of tenant clusters.")
+	f.StringVar(&c.flags.ignitionPath, "ignition-path", "/opt/ignition", "Directory of the k8scloudconfig files cloud configs are rendered from.")
+	f.StringVar(&c.flags.installationName, "installation-name", "plan", "Installation name used for tagging AWS resources.")
+	f.StringVar(&c.flags.installationTags, "installation-tags", "", "JSON object of tags applied to the AWS resources of all tenant clusters.")
+	f.StringVar(&c.flags.instanceTypesFile, "instance-types-file", "", `JSON file describing the instance types of node pools and their alike instance types, e.g. {"m5.xlarge":{"vcpus":4,"memoryMiB":16384}}.`)
+	f.StringVar(&c.flags.networkCIDR, "network-cidr", "10.1.0.0/16", "Network segment tenant cluster subnets are allocated in, unless a NetworkPool CR is used.")
+	f.StringVar(&c.flags.networkSetupDockerImage, "network-setup-docker-image", "giantswarm/k8s-setup-network-environment:1f4ffc52095ac368847ce3428ea99b257003d9b9", "Full docker image of networksetup.")
+	f.StringVar(&c.flags.registryDomain, "registry-domain", "gsoci.azurecr.io", "Image registry domain.")
+	f.StringSliceVar(&c.flags.registryMirrors, "registry-mirrors", []string{}, "Image registry mirror domains.")
+	f.BoolVar(&c.flags.route53Enabled, "route53-enabled", true, "Whether Route 53 is enabled.")
+	f.IntVar(&c.flags.subnetMaskBits, "subnet-mask-bits", 24, "Number of bits in tenant cluster subnet network masks.")
+
+	return c, nil
+}
+
+func (c *Command) CobraCommand() *cobra.Command {
+	return c.cobraCommand
+}
+
+func (c *Command) execute(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if len(c.flags.manifests) == 0 {
+		return microerror.Maskf(invalidFlagError, "--manifest must not be empty")
+	}
+
+	var manifests [][]byte
+	for _, m := range c.flags.manifests {
+		b, err := os.ReadFile(m)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		manifests = append(manifests, b)
+	}
+
+	err := key.LoadAMIs(c.flags.amiFile)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	installationTags := map[string]string{}
+	if c.flags.installationTags != "" {
+		err = json.Unmarshal([]byte(c.flags.installationTags), &installationTags)
+		if err != nil {
+			return microerror.Maskf(invalidFlagError, "--installation-tags must be a JSON object: %s", err)
+		}
+	}
+
+	instanceTypes := map[string]plan.InstanceType{}
+	if c.flags.instanceTypesFile != "" {
+		b, err := os.ReadFile(c.flags.instanceTypesFile)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = json.Unmarshal(b, &instanceTypes)
+		if err != nil {
+			return microerror.Maskf(invalidFlagError, "--instance-types-file must be a JSON file: %s", err)
+		}
+	}
+
+	var p *plan.Plan
+	{
+		pc := plan.Config{
+			Logger: c.logger,
+
+			AlikeInstances:          c.flags.alikeInstances,
+			CalicoCIDR:              c.flags.calicoCIDR,
+			CalicoMTU:               c.flags.calicoMTU,
+			CalicoSubnet:            c.flags.calicoSubnet,
+			ClusterDomain:           c.flags.clusterDomain,
+			ClusterIPRange:          c.flags.clusterIPRange,
+			DockerDaemonCIDR:        c.flags.dockerDaemonCIDR,
+			IgnitionPath:            c.flags.ignitionPath,
+			InstallationName:        c.flags.installationName,
+			InstallationTags:        installationTags,
+			InstanceTypes:           instanceTypes,
+			NetworkCIDR:             c.flags.networkCIDR,
+			NetworkSetupDockerImage: c.flags.networkSetupDockerImage,
+			RegistryDomain:          c.flags.registryDomain,
+			RegistryMirrors:         c.flags.registryMirrors,
+			Route53Enabled:          c.flags.route53Enabled,
+			SubnetMaskBits:          c.flags.subnetMaskBits,
+		}
+
+		p, err = plan.New(pc)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	files, err := p.Render(ctx, manifests...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		p := filepath.Join(c.flags.output, n)
+
+		err = os.MkdirAll(filepath.Dir(p), 0755) // nolint:gosec
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = os.WriteFile(p, []byte(files[n]), 0644) // nolint:gosec
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		c.logger.Debugf(ctx, "wrote %#q", p)
+	}
+
+	return nil
+}
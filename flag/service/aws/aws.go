@@ -2,19 +2,28 @@ package aws
 
 import (
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/cni"
+	"github.com/giantswarm/aws-operator/v16/flag/service/aws/dns"
+	"github.com/giantswarm/aws-operator/v16/flag/service/aws/etcdsnapshots"
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/hostaccesskey"
+	"github.com/giantswarm/aws-operator/v16/flag/service/aws/interruptionhandling"
+	"github.com/giantswarm/aws-operator/v16/flag/service/aws/ipam"
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/loggingbucket"
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/role"
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/route53"
 	"github.com/giantswarm/aws-operator/v16/flag/service/aws/trustedadvisor"
+	"github.com/giantswarm/aws-operator/v16/flag/service/aws/vpcendpoints"
 )
 
 type AWS struct {
 	AlikeInstances         string
 	AdvancedMonitoringEC2  string
 	AvailabilityZones      string
+	DNS                    dns.DNS
+	EtcdSnapshots          etcdsnapshots.EtcdSnapshots
 	HostAccessKey          hostaccesskey.HostAccessKey
 	IncludeTags            string
+	InterruptionHandling   interruptionhandling.InterruptionHandling
+	IPAM                   ipam.IPAM
 	LoggingBucket          loggingbucket.LoggingBucket
 	PodInfraContainerImage string
 	Region                 string
@@ -22,7 +31,9 @@ type AWS struct {
 	Route53                route53.Route53
 	RouteTables            string
 	S3AccessLogsExpiration string
+	Tags                   string
 	TrustedAdvisor         trustedadvisor.TrustedAdvisor
 	VaultAddress           string
+	VPCEndpoints           vpcendpoints.VPCEndpoints
 	CNI                    cni.CNI
 }
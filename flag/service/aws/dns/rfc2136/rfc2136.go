@@ -0,0 +1,8 @@
+package rfc2136
+
+type RFC2136 struct {
+	Server        string
+	TSIGAlgorithm string
+	TSIGKeyName   string
+	TSIGSecret    string
+}
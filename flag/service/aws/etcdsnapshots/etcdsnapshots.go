@@ -0,0 +1,6 @@
+package etcdsnapshots
+
+type EtcdSnapshots struct {
+	Interval  string
+	Retention string
+}
@@ -0,0 +1,5 @@
+package interruptionhandling
+
+type InterruptionHandling struct {
+	Enabled string
+}
@@ -0,0 +1,5 @@
+package ipam
+
+type IPAM struct {
+	PoolID string
+}
@@ -0,0 +1,5 @@
+package vpcendpoints
+
+type VPCEndpoints struct {
+	Enabled string
+}
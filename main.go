@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/spf13/viper"
 
+	"github.com/giantswarm/aws-operator/v16/command/plan"
 	"github.com/giantswarm/aws-operator/v16/flag"
 	"github.com/giantswarm/aws-operator/v16/pkg/project"
 	"github.com/giantswarm/aws-operator/v16/server"
@@ -99,13 +100,36 @@ func mainE(ctx context.Context) error {
 		}
 	}
 
+	// The plan command renders the templates of a tenant cluster offline, e.g.
+	// to review template changes between operator versions in CI.
+	{
+		c := plan.Config{
+			Logger: logger,
+		}
+
+		planCommand, err := plan.New(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		newCommand.CobraCommand().AddCommand(planCommand.CobraCommand())
+	}
+
 	daemonCommand := newCommand.DaemonCommand().CobraCommand()
 
 	daemonCommand.PersistentFlags().String(f.Service.AWS.AlikeInstances, "", "Overrides for the ASG's mixed instance policy.")
 	daemonCommand.PersistentFlags().StringSlice(f.Service.AWS.AvailabilityZones, []string{}, "Availability zones as a slice.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.DNS.Provider, "route53", "DNS provider managing the DNS records of tenant clusters, either route53 or rfc2136.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.DNS.RFC2136.Server, "", "Address of the DNS server accepting RFC2136 dynamic updates, e.g. 10.0.0.53:53. Only used for the rfc2136 DNS provider.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.DNS.RFC2136.TSIGAlgorithm, "hmac-sha256", "TSIG algorithm used to sign RFC2136 dynamic updates, either hmac-sha256 or hmac-sha512.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.DNS.RFC2136.TSIGKeyName, "", "Name of the TSIG key used to sign RFC2136 dynamic updates.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.DNS.RFC2136.TSIGSecret, "", "Base64 encoded secret of the TSIG key used to sign RFC2136 dynamic updates.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.EtcdSnapshots.Interval, "0", "Interval in which EBS snapshots of the control plane etcd volumes are created, e.g. 24h. 0 disables snapshots. Can be overwritten per cluster.")
+	daemonCommand.PersistentFlags().Int(f.Service.AWS.EtcdSnapshots.Retention, 7, "Number of EBS snapshots kept per control plane etcd volume. Can be overwritten per cluster.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.ID, "", "AWS access key ID for the user authorized to assume Control Plane role.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.Secret, "", "AWS access key secret for the user authorized to assume Control Plane role.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.Session, "", "AWS session token for for the user authorized to assume Control Plane role.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.IPAM.PoolID, "", "ID of the AWS VPC IPAM pool tenant cluster subnets are additionally allocated in. If empty, no IPAM pool is used.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.Region, "", "Region for checking for orphaned AWS resources.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.Role.ARN, "", "AWS role ARN for the Control Plane cluster account.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.RouteTables, "", "Names of the public route tables in control plane separated by commas, required for accessing public ELBs from tenant nodes.")
@@ -116,8 +140,11 @@ func mainE(ctx context.Context) error {
 	daemonCommand.PersistentFlags().String(f.Service.AWS.PodInfraContainerImage, "", "Image to be used for the pause container. If empty, default image from gcr.io/google_containers/pause-amd64 is used.")
 	daemonCommand.PersistentFlags().Bool(f.Service.AWS.IncludeTags, true, "Should resource tags be included (especially for restricted regions, like S3 buckets in China regions).")
 	daemonCommand.PersistentFlags().Int(f.Service.AWS.S3AccessLogsExpiration, 365, "S3 access logs expiration policy.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.Tags, "", "Tags applied to the AWS resources of all tenant clusters as JSON object, e.g. {\"cost-center\":\"1234\"}. Organization, cluster and node pool tags take precedence.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.TrustedAdvisor.Enabled, "", "Whether trusted advisor metrics collection is enabled.")
 	daemonCommand.PersistentFlags().Bool(f.Service.AWS.CNI.ExternalSNAT, false, "Whether External SNAT for the AWS CNI is enabled.")
+	daemonCommand.PersistentFlags().Bool(f.Service.AWS.InterruptionHandling.Enabled, false, "Whether spot interruptions, rebalance recommendations and scheduled maintenance of tenant cluster nodes are handled by default.")
+	daemonCommand.PersistentFlags().Bool(f.Service.AWS.VPCEndpoints.Enabled, false, "Whether interface VPC endpoints for ECR, STS, EC2 and autoscaling are created in tenant cluster VPCs by default.")
 
 	daemonCommand.PersistentFlags().Int(f.Service.Cluster.Calico.CIDR, 0, "Calico CIDR of guest clusters.")
 	daemonCommand.PersistentFlags().Int(f.Service.Cluster.Calico.MTU, 0, "Calico MTU of guest clusters.")
@@ -1,8 +1,60 @@
 package annotation
 
 const (
-	Docs                    = "giantswarm.io/docs"
-	InstanceID              = "aws-operator.giantswarm.io/instance"
-	LegacyAwsCniPodCidr     = "aws-operator.giantswarm.io/legacy-aws-cni-pod-cidr"
-	MachineDeploymentSubnet = "machine-deployment.giantswarm.io/subnet"
+	APIWhitelistConfigMap       = "aws-operator.giantswarm.io/api-whitelist-config-map"
+	APIWhitelistPrivate         = "aws-operator.giantswarm.io/api-whitelist-private"
+	APIWhitelistPublic          = "aws-operator.giantswarm.io/api-whitelist-public"
+	ChangeSetApproved           = "aws-operator.giantswarm.io/change-set-approved"
+	ChangeSetChanges            = "aws-operator.giantswarm.io/change-set-changes"
+	ChangeSetPending            = "aws-operator.giantswarm.io/change-set-pending"
+	ChangeSetPreview            = "aws-operator.giantswarm.io/change-set-preview"
+	CloudTagsIssues             = "aws-operator.giantswarm.io/cloud-tags-issues"
+	Docs                        = "giantswarm.io/docs"
+	DualStack                   = "aws-operator.giantswarm.io/dual-stack"
+	EtcdRestore                 = "aws-operator.giantswarm.io/etcd-restore"
+	EtcdRestoreCondition        = "aws-operator.giantswarm.io/etcd-restore-condition"
+	EtcdRestoreRollback         = "aws-operator.giantswarm.io/etcd-restore-rollback-snapshots"
+	EtcdRestoreRunning          = "aws-operator.giantswarm.io/etcd-restore-running"
+	EtcdRestoreSnapshots        = "aws-operator.giantswarm.io/etcd-restore-snapshots"
+	EtcdSnapshotInterval        = "aws-operator.giantswarm.io/etcd-snapshot-interval"
+	EtcdSnapshotLastSuccess     = "aws-operator.giantswarm.io/etcd-snapshot-last-success"
+	EtcdSnapshotRetention       = "aws-operator.giantswarm.io/etcd-snapshot-retention"
+	InstanceID                  = "aws-operator.giantswarm.io/instance"
+	InstanceRequirements        = "aws-operator.giantswarm.io/instance-requirements"
+	InstanceRequirementsMatches = "aws-operator.giantswarm.io/instance-requirements-matches"
+	InterruptionHandling        = "aws-operator.giantswarm.io/interruption-handling"
+	IPv6NetworkIndex            = "aws-operator.giantswarm.io/ipv6-network-index"
+	KubeletEvictionHard         = "aws-operator.giantswarm.io/kubelet-eviction-hard"
+	KubeletImageGCThresholds    = "aws-operator.giantswarm.io/kubelet-image-gc-thresholds"
+	KubeletKubeReserved         = "aws-operator.giantswarm.io/kubelet-kube-reserved"
+	KubeletMaxPods              = "aws-operator.giantswarm.io/kubelet-max-pods"
+	KubeletSystemReserved       = "aws-operator.giantswarm.io/kubelet-system-reserved"
+	LegacyAwsCniPodCidr         = "aws-operator.giantswarm.io/legacy-aws-cni-pod-cidr"
+	LoadBalancerType            = "aws-operator.giantswarm.io/load-balancer-type"
+	MachineDeploymentSubnet     = "machine-deployment.giantswarm.io/subnet"
+	ManagedTagKeys              = "aws-operator.giantswarm.io/managed-tag-keys"
+	NodeConfigMap               = "aws-operator.giantswarm.io/node-config-map"
+	NodeLabels                  = "aws-operator.giantswarm.io/node-labels"
+	NodeTaints                  = "aws-operator.giantswarm.io/node-taints"
+	OnDemandAllocation          = "aws-operator.giantswarm.io/on-demand-allocation-strategy"
+	PrivateCluster              = "aws-operator.giantswarm.io/private-cluster"
+	PrivateHostedZoneCPVPC      = "aws-operator.giantswarm.io/private-hosted-zone-control-plane-vpc"
+	PrivateHostedZoneVPCs       = "aws-operator.giantswarm.io/private-hosted-zone-vpcs"
+	RolloutCondition            = "aws-operator.giantswarm.io/rollout-condition"
+	RolloutDesiredCapacity      = "aws-operator.giantswarm.io/rollout-desired-capacity"
+	SpotAllocation              = "aws-operator.giantswarm.io/spot-allocation-strategy"
+	SpotCapacityRebalance       = "aws-operator.giantswarm.io/spot-capacity-rebalance"
+	SpotMaxPrice                = "aws-operator.giantswarm.io/spot-max-price"
+	StackUpdateCondition        = "aws-operator.giantswarm.io/stack-update-condition"
+	UpdateStrategy              = "aws-operator.giantswarm.io/update-strategy"
+	VolumeKMSKeyARN             = "aws-operator.giantswarm.io/volume-kms-key-arn"
+	VolumeProfileContainerd     = "aws-operator.giantswarm.io/volume-profile-containerd"
+	VolumeProfileDocker         = "aws-operator.giantswarm.io/volume-profile-docker"
+	VolumeProfileKubelet        = "aws-operator.giantswarm.io/volume-profile-kubelet"
+	VolumeProfileLogging        = "aws-operator.giantswarm.io/volume-profile-logging"
+	VPCEndpoints                = "aws-operator.giantswarm.io/vpc-endpoints"
+	WarmPool                    = "aws-operator.giantswarm.io/warm-pool"
+	WarmPoolMaxPrepared         = "aws-operator.giantswarm.io/warm-pool-max-prepared-capacity"
+	WarmPoolMinSize             = "aws-operator.giantswarm.io/warm-pool-min-size"
+	WarmPoolState               = "aws-operator.giantswarm.io/warm-pool-state"
 )
@@ -1,6 +1,22 @@
 package awstags
 
-import "github.com/aws/aws-sdk-go/service/ec2"
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func NewEC2(tags map[string]string) []*ec2.Tag {
+	var ts []*ec2.Tag
+	for k, v := range tags {
+		t := &ec2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		}
+		ts = append(ts, t)
+	}
+
+	return ts
+}
 
 func HasTag(tags []*ec2.Tag, key string) bool {
 	for _, t := range tags {
@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
+	"github.com/giantswarm/aws-operator/v16/server/endpoint/ipam"
 	"github.com/giantswarm/aws-operator/v16/service"
 )
 
@@ -16,6 +17,7 @@ type Config struct {
 
 type Endpoint struct {
 	Healthz *healthz.Endpoint
+	IPAM    *ipam.Endpoint
 	Version *version.Endpoint
 }
 
@@ -34,6 +36,19 @@ func New(config Config) (*Endpoint, error) {
 		}
 	}
 
+	var ipamEndpoint *ipam.Endpoint
+	{
+		c := ipam.Config{
+			Logger: config.Logger,
+			Report: config.Service.IPAMReport,
+		}
+
+		ipamEndpoint, err = ipam.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var versionEndpoint *version.Endpoint
 	{
 		c := version.Config{
@@ -49,6 +64,7 @@ func New(config Config) (*Endpoint, error) {
 
 	e := &Endpoint{
 		Healthz: healthzEndpoint,
+		IPAM:    ipamEndpoint,
 		Version: versionEndpoint,
 	}
 
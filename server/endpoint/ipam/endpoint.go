@@ -0,0 +1,92 @@
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	kitendpoint "github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	"github.com/giantswarm/aws-operator/v16/service/ipamreport"
+)
+
+const (
+	// Method is the HTTP method this endpoint is registered for.
+	Method = "GET"
+	// Name identifies the endpoint. It is aligned to the package path.
+	Name = "ipam"
+	// Path is the HTTP request path this endpoint is registered for.
+	Path = "/ipam"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+	Report ipamreport.Interface
+}
+
+// Endpoint serves the IPAM report listing all allocated subnets and the
+// remaining capacity of every network range.
+type Endpoint struct {
+	logger micrologger.Logger
+	report ipamreport.Interface
+}
+
+func New(config Config) (*Endpoint, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.Report == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Report must not be empty", config)
+	}
+
+	e := &Endpoint{
+		logger: config.Logger,
+		report: config.Report,
+	}
+
+	return e, nil
+}
+
+func (e *Endpoint) Decoder() kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		return nil, nil
+	}
+}
+
+func (e *Endpoint) Encoder() kithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (e *Endpoint) Endpoint() kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		report, err := e.report.Report(ctx)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return report, nil
+	}
+}
+
+func (e *Endpoint) Method() string {
+	return Method
+}
+
+func (e *Endpoint) Middlewares() []kitendpoint.Middleware {
+	return []kitendpoint.Middleware{}
+}
+
+func (e *Endpoint) Name() string {
+	return Name
+}
+
+func (e *Endpoint) Path() string {
+	return Path
+}
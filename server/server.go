@@ -51,6 +51,7 @@ func New(config Config) (microserver.Server, error) {
 
 			Endpoints: []microserver.Endpoint{
 				endpointCollection.Healthz,
+				endpointCollection.IPAM,
 				endpointCollection.Version,
 			},
 			ErrorEncoder: encodeError,
@@ -39,6 +39,8 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cleanupvpcpeerings"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cproutetables"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cpvpc"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cpvpcassociation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/dnsrecords"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/encryptionensurer"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/endpoints"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/eniconfigcrs"
@@ -50,7 +52,9 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/s3bucket"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/secretfinalizer"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/service"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tagdrift"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpapiwhitelist"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpazs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpf"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpi"
@@ -61,8 +65,10 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpvpcidstatus"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tenantclients"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cphostedzone"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter/kms"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
@@ -71,29 +77,34 @@ import (
 )
 
 type ClusterConfig struct {
-	CloudTags cloudtags.Interface
-	Event     event.Interface
-	K8sClient k8sclient.Interface
-	HAMaster  hamaster.Interface
-	Locker    locker.Interface
-	Logger    micrologger.Logger
-
-	AccessLogsExpiration       int
-	AdvancedMonitoringEC2      bool
-	APIWhitelist               tccp.ConfigAPIWhitelist
-	CalicoCIDR                 int
-	CalicoSubnet               string
-	DeleteLoggingBucket        bool
-	GuestPrivateSubnetMaskBits int
-	GuestPublicSubnetMaskBits  int
-	GuestSubnetMaskBits        int
-	HostAWSConfig              aws.Config
-	IncludeTags                bool
-	InstallationName           string
-	IPAMNetworkRange           net.IPNet
-	RegistryDomain             string
-	RouteTables                string
-	Route53Enabled             bool
+	AWSClientPool *aws.Pool
+	CloudTags     cloudtags.Interface
+	DNS           dns.Interface
+	Event         event.Interface
+	K8sClient     k8sclient.Interface
+	HAMaster      hamaster.Interface
+	Locker        locker.Interface
+	Logger        micrologger.Logger
+
+	AccessLogsExpiration        int
+	AdvancedMonitoringEC2       bool
+	APIWhitelist                tccpapiwhitelist.ConfigAPIWhitelist
+	CalicoCIDR                  int
+	CalicoSubnet                string
+	DeleteLoggingBucket         bool
+	GuestPrivateSubnetMaskBits  int
+	GuestPublicSubnetMaskBits   int
+	GuestSubnetMaskBits         int
+	HostAWSConfig               aws.Config
+	IncludeTags                 bool
+	InstallationName            string
+	InterruptionHandlingEnabled bool
+	IPAMNetworkRange            net.IPNet
+	IPAMPoolID                  string
+	RegistryDomain              string
+	RouteTables                 string
+	Route53Enabled              bool
+	VPCEndpointsEnabled         bool
 }
 
 type Cluster struct {
@@ -103,6 +114,9 @@ type Cluster struct {
 func NewCluster(config ClusterConfig) (*Cluster, error) {
 	var err error
 
+	if config.DNS == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DNS must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -168,9 +182,8 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var encrypterObject encrypter.Interface
 	{
 		c := &kms.EncrypterConfig{
-			Logger: config.Logger,
-
-			InstallationName: config.InstallationName,
+			CloudTags: config.CloudTags,
+			Logger:    config.Logger,
 		}
 
 		encrypterObject, err = kms.NewEncrypter(c)
@@ -179,11 +192,29 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var changeSet changeset.Interface
+	{
+		c := changeset.Config{
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+		}
+
+		changeSet, err = changeset.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tccpChangeDetection *changedetection.TCCP
 	{
 		c := changedetection.TCCPConfig{
-			Event:  config.Event,
-			Logger: config.Logger,
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+
+			InterruptionHandlingEnabled: config.InterruptionHandlingEnabled,
+			VPCEndpointsEnabled:         config.VPCEndpointsEnabled,
 		}
 
 		tccpChangeDetection, err = changedetection.NewTCCP(c)
@@ -195,7 +226,9 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var tccpfChangeDetection *changedetection.TCCPF
 	{
 		c := changedetection.TCCPFConfig{
-			Logger: config.Logger,
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
 		}
 
 		tccpfChangeDetection, err = changedetection.NewTCCPF(c)
@@ -264,12 +297,52 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var ipamCollector ipam.Collector
+	var ipamPersister ipam.Persister
+	var ipamReleaser ipam.Releaser
+	{
+		ipamCollector = subnetCollector
+		ipamPersister = clusterPersister
+
+		// In case an AWS VPC IPAM pool is configured, subnets are additionally
+		// allocated in the pool and released from it on deletion.
+		if config.IPAMPoolID != "" {
+			cc := ipam.IPAMPoolCollectorConfig{
+				Collector: subnetCollector,
+				Logger:    config.Logger,
+
+				NetworkRange: config.IPAMNetworkRange,
+				PoolID:       config.IPAMPoolID,
+			}
+
+			ipamPoolCollector, err := ipam.NewIPAMPoolCollector(cc)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			pc := ipam.IPAMPoolPersisterConfig{
+				Logger:    config.Logger,
+				Persister: clusterPersister,
+
+				PoolID: config.IPAMPoolID,
+			}
+
+			ipamPoolPersister, err := ipam.NewIPAMPoolPersister(pc)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			ipamCollector = ipamPoolCollector
+			ipamPersister = ipamPoolPersister
+			ipamReleaser = ipamPoolPersister
+		}
+	}
+
 	var hostedZone *cphostedzone.HostedZone
 	{
 		c := cphostedzone.Config{
+			DNS:    config.DNS,
 			Logger: config.Logger,
-
-			Route53Enabled: config.Route53Enabled,
 		}
 
 		hostedZone, err = cphostedzone.New(c)
@@ -306,6 +379,7 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var awsClientResource resource.Interface
 	{
 		c := awsclient.Config{
+			ClientPool:    config.AWSClientPool,
 			K8sClient:     config.K8sClient.K8sClient(),
 			Logger:        config.Logger,
 			ToClusterFunc: key.ToCluster,
@@ -368,6 +442,22 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var tccpAPIWhitelistResource resource.Interface
+	{
+		c := tccpapiwhitelist.Config{
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+
+			APIWhitelist: config.APIWhitelist,
+		}
+
+		tccpAPIWhitelistResource, err = tccpapiwhitelist.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var encryptionEnsurerResource resource.Interface
 	{
 		c := encryptionensurer.Config{
@@ -399,11 +489,12 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	{
 		c := ipam.Config{
 			Checker:   clusterChecker,
-			Collector: subnetCollector,
+			Collector: ipamCollector,
 			K8sClient: config.K8sClient,
 			Locker:    config.Locker,
 			Logger:    config.Logger,
-			Persister: clusterPersister,
+			Persister: ipamPersister,
+			Releaser:  ipamReleaser,
 
 			AllocatedSubnetMaskBits: config.GuestSubnetMaskBits,
 			NetworkRange:            config.IPAMNetworkRange,
@@ -424,7 +515,7 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 			K8sClient:     config.K8sClient.K8sClient(),
 			Logger:        config.Logger,
 
-			Route53Enabled: config.Route53Enabled,
+			DNS: config.DNS,
 		}
 
 		bridgeZoneResource, err = bridgezone.New(c)
@@ -433,11 +524,25 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var cpVPCAssociationResource resource.Interface
+	{
+		c := cpvpcassociation.Config{
+			Logger: config.Logger,
+
+			Route53Enabled: config.Route53Enabled,
+		}
+
+		cpVPCAssociationResource, err = cpvpcassociation.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var s3BucketResource resource.Interface
 	{
 		c := s3bucket.Config{
-			CtrlClient: config.K8sClient.CtrlClient(),
-			Logger:     config.Logger,
+			CloudTags: config.CloudTags,
+			Logger:    config.Logger,
 
 			AccessLogsExpiration: config.AccessLogsExpiration,
 			DeleteLoggingBucket:  config.DeleteLoggingBucket,
@@ -509,9 +614,8 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var cleanupRecordSets resource.Interface
 	{
 		c := cleanuprecordsets.Config{
+			DNS:    config.DNS,
 			Logger: config.Logger,
-
-			Route53Enabled: config.Route53Enabled,
 		}
 
 		cleanupRecordSets, err = cleanuprecordsets.New(c)
@@ -560,6 +664,7 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var tccpResource resource.Interface
 	{
 		c := tccp.Config{
+			ChangeSet:  changeSet,
 			CloudTags:  config.CloudTags,
 			Event:      config.Event,
 			CtrlClient: config.K8sClient.CtrlClient(),
@@ -567,13 +672,15 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 			K8sClient:  config.K8sClient,
 			Logger:     config.Logger,
 
-			APIWhitelist:       config.APIWhitelist,
-			CIDRBlockAWSCNI:    fmt.Sprintf("%s/%d", config.CalicoSubnet, config.CalicoCIDR),
-			Detection:          tccpChangeDetection,
-			InstallationName:   config.InstallationName,
-			InstanceMonitoring: config.AdvancedMonitoringEC2,
-			PublicRouteTables:  config.RouteTables,
-			Route53Enabled:     config.Route53Enabled,
+			CIDRBlockAWSCNI:             fmt.Sprintf("%s/%d", config.CalicoSubnet, config.CalicoCIDR),
+			Detection:                   tccpChangeDetection,
+			InstallationName:            config.InstallationName,
+			InstanceMonitoring:          config.AdvancedMonitoringEC2,
+			InterruptionHandlingEnabled: config.InterruptionHandlingEnabled,
+			LoadBalancerDNSNamesEnabled: config.DNS.Enabled() && !config.DNS.Route53(),
+			PublicRouteTables:           config.RouteTables,
+			Route53Enabled:              config.Route53Enabled,
+			VPCEndpointsEnabled:         config.VPCEndpointsEnabled,
 		}
 
 		tccpResource, err = tccp.New(c)
@@ -609,16 +716,29 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var dnsRecordsResource resource.Interface
+	{
+		c := dnsrecords.Config{
+			DNS:    config.DNS,
+			Logger: config.Logger,
+		}
+
+		dnsRecordsResource, err = dnsrecords.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tccpfResource resource.Interface
 	{
 		c := tccpf.Config{
+			CloudTags:  config.CloudTags,
 			Detection:  tccpfChangeDetection,
 			Event:      config.Event,
 			HostedZone: hostedZone,
 			Logger:     config.Logger,
 
-			InstallationName: config.InstallationName,
-			Route53Enabled:   config.Route53Enabled,
+			Route53Enabled: config.Route53Enabled,
 		}
 
 		tccpfResource, err = tccpf.New(c)
@@ -630,10 +750,9 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 	var tccpiResource resource.Interface
 	{
 		c := tccpi.Config{
-			Event:  config.Event,
-			Logger: config.Logger,
-
-			InstallationName: config.InstallationName,
+			CloudTags: config.CloudTags,
+			Event:     config.Event,
+			Logger:    config.Logger,
 		}
 
 		tccpiResource, err = tccpi.New(c)
@@ -716,6 +835,21 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		}
 	}
 
+	var tagDriftResource resource.Interface
+	{
+		c := tagdrift.Config{
+			CloudTags:  config.CloudTags,
+			CtrlClient: config.K8sClient.CtrlClient(),
+			Encrypter:  encrypterObject,
+			Logger:     config.Logger,
+		}
+
+		tagDriftResource, err = tagdrift.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var secretFinalizerResource resource.Interface
 	{
 		c := secretfinalizer.Config{
@@ -827,15 +961,19 @@ func newClusterResources(config ClusterConfig) ([]resource.Interface, error) {
 		apiEndpointResource,
 		ipamResource,
 		bridgeZoneResource,
+		cpVPCAssociationResource,
 		tccpSecurityGroupsResource,
 		s3BucketResource,
 		tccpAZsResource,
+		tccpAPIWhitelistResource,
 		tccpiResource,
 		tccpResource,
+		dnsRecordsResource,
 		tccpfResource,
 		serviceResource,
 		endpointsResource,
 		eniConfigCRsResource,
+		tagDriftResource,
 		secretFinalizerResource,
 
 		// All these resources implement logic to update CR status information.
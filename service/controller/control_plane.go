@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/certs/v4/pkg/certs"
@@ -25,6 +26,8 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/awsclient"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cleanuptccpniamroles"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/cpvpc"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/etcdrestore"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/etcdsnapshot"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/region"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/s3object"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/snapshotid"
@@ -37,6 +40,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpvpcid"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpvpcpcx"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudconfig"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
@@ -51,6 +55,7 @@ import (
 )
 
 type ControlPlaneConfig struct {
+	AWSClientPool      *aws.Pool
 	CertsSearcher      certs.Interface
 	CloudTags          cloudtags.Interface
 	Event              event.Interface
@@ -67,6 +72,8 @@ type ControlPlaneConfig struct {
 	ClusterIPRange          string
 	DockerDaemonCIDR        string
 	DockerhubToken          string
+	EtcdSnapshotInterval    time.Duration
+	EtcdSnapshotRetention   int
 	ExternalSNAT            bool
 	HostAWSConfig           aws.Config
 	IgnitionPath            string
@@ -165,6 +172,7 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 	var awsClientResource resource.Interface
 	{
 		c := awsclient.Config{
+			ClientPool:    config.AWSClientPool,
 			K8sClient:     config.K8sClient.K8sClient(),
 			Logger:        config.Logger,
 			ToClusterFunc: newControlPlaneToClusterFunc(config.K8sClient.CtrlClient()),
@@ -202,25 +210,11 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 		}
 	}
 
-	var cloudtagObject cloudtags.Interface
-	{
-		c := cloudtags.Config{
-			K8sClient: config.K8sClient,
-			Logger:    config.Logger,
-		}
-
-		cloudtagObject, err = cloudtags.New(c)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
-	}
-
 	var encrypterObject encrypter.Interface
 	{
 		c := &kms.EncrypterConfig{
-			Logger: config.Logger,
-
-			InstallationName: config.InstallationName,
+			CloudTags: config.CloudTags,
+			Logger:    config.Logger,
 		}
 
 		encrypterObject, err = kms.NewEncrypter(c)
@@ -278,13 +272,28 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 		}
 	}
 
+	var changeSet changeset.Interface
+	{
+		c := changeset.Config{
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+		}
+
+		changeSet, err = changeset.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tccpnChangeDetection *changedetection.TCCPN
 	{
 		c := changedetection.TCCPNConfig{
-			Event:    config.Event,
-			HAMaster: config.HAMaster,
-			Logger:   config.Logger,
-			Releases: rel,
+			Event:     config.Event,
+			HAMaster:  config.HAMaster,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+			Releases:  rel,
 		}
 
 		tccpnChangeDetection, err = changedetection.NewTCCPN(c)
@@ -338,6 +347,39 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 		}
 	}
 
+	var etcdRestoreResource resource.Interface
+	{
+		c := etcdrestore.Config{
+			CtrlClient:    config.K8sClient.CtrlClient(),
+			Event:         config.Event,
+			HAMaster:      config.HAMaster,
+			Logger:        config.Logger,
+			ToClusterFunc: newControlPlaneToClusterFunc(config.K8sClient.CtrlClient()),
+		}
+
+		etcdRestoreResource, err = etcdrestore.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var etcdSnapshotResource resource.Interface
+	{
+		c := etcdsnapshot.Config{
+			CtrlClient:    config.K8sClient.CtrlClient(),
+			Logger:        config.Logger,
+			ToClusterFunc: newControlPlaneToClusterFunc(config.K8sClient.CtrlClient()),
+
+			Interval:  config.EtcdSnapshotInterval,
+			Retention: config.EtcdSnapshotRetention,
+		}
+
+		etcdSnapshotResource, err = etcdsnapshot.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var snapshotIDResource resource.Interface
 	{
 		c := snapshotid.Config{
@@ -409,7 +451,8 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 	var tccpnResource resource.Interface
 	{
 		c := tccpn.Config{
-			CloudTags: cloudtagObject,
+			ChangeSet: changeSet,
+			CloudTags: config.CloudTags,
 			Detection: tccpnChangeDetection,
 			Encrypter: encrypterObject,
 			Event:     config.Event,
@@ -418,8 +461,7 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 			K8sClient: config.K8sClient,
 			Logger:    config.Logger,
 
-			InstallationName: config.InstallationName,
-			Route53Enabled:   config.Route53Enabled,
+			Route53Enabled: config.Route53Enabled,
 		}
 
 		tccpnResource, err = tccpn.New(c)
@@ -486,7 +528,9 @@ func newControlPlaneResources(config ControlPlaneConfig) ([]resource.Interface,
 		// All these resources implement certain business logic and operate based on
 		// the information given in the controller context.
 		s3ObjectResource,
+		etcdRestoreResource,
 		tccpnResource,
+		etcdSnapshotResource,
 
 		// All these resources implement cleanup functionality only being executed
 		// on delete events.
@@ -14,9 +14,23 @@ type ContextSpecTenantCluster struct {
 }
 
 type ContextSpecTenantClusterTCCP struct {
+	APIWhitelist      ContextSpecTenantClusterTCCPAPIWhitelist
 	AvailabilityZones []ContextSpecTenantClusterTCCPAvailabilityZone
 }
 
+type ContextSpecTenantClusterTCCPAPIWhitelist struct {
+	// Hash identifies the cluster specific whitelist entries. It is empty in
+	// case the cluster only uses the installation wide whitelist.
+	Hash    string
+	Private ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup
+	Public  ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup
+}
+
+type ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup struct {
+	Enabled    bool
+	SubnetList []string
+}
+
 type ContextSpecTenantClusterTCCPAvailabilityZone struct {
 	Name   string
 	Subnet ContextSpecTenantClusterTCCPAvailabilityZoneSubnet
@@ -53,7 +67,10 @@ type ContextSpecTenantClusterTCCPAvailabilityZoneRouteTablePublic struct {
 
 type ContextSpecTenantClusterTCNP struct {
 	AvailabilityZones []ContextSpecTenantClusterTCNPAvailabilityZone
-	SecurityGroupIDs  []string
+	// NodeConfigData is the data of the ConfigMap holding the node pool's node
+	// config, if any, see key.MachineDeploymentNodeConfig.
+	NodeConfigData   map[string]string
+	SecurityGroupIDs []string
 }
 
 type ContextSpecTenantClusterTCNPAvailabilityZone struct {
@@ -71,5 +88,6 @@ type ContextSpecTenantClusterTCNPAvailabilityZoneSubnet struct {
 }
 
 type ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate struct {
-	CIDR net.IPNet
+	CIDR          net.IPNet
+	IPv6CIDRIndex int
 }
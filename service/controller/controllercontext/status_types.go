@@ -58,7 +58,9 @@ type ContextStatusTenantClusterAWS struct {
 }
 
 type ContextStatusTenantClusterDNS struct {
+	APIInternalLoadBalancer   string
 	APIPublicLoadBalancer     string
+	EtcdLoadBalancer          string
 	HostedZoneID              string
 	HostedZoneNameServers     string
 	IngressPublicLoadBalancer string
@@ -75,13 +77,24 @@ type ContextStatusTenantClusterS3Object struct {
 }
 
 type ContextStatusTenantClusterTCCP struct {
+	// APIWhitelistHash is the hash of the cluster specific API whitelist
+	// entries the stack got created or updated with.
+	APIWhitelistHash  string
 	AvailabilityZones []ContextStatusTenantClusterTCCPAvailabilityZone
-	IsTransitioning   bool
-	NATGateways       []*ec2.NatGateway
-	RouteTables       []*ec2.RouteTable
-	SecurityGroups    []*ec2.SecurityGroup
-	Subnets           []*ec2.Subnet
-	VPC               ContextStatusTenantClusterTCCPVPC
+	// InterruptionHandling is whether the stack provides the interruption
+	// queue.
+	InterruptionHandling bool
+	IsTransitioning      bool
+	LoadBalancers        ContextStatusTenantClusterTCCPLoadBalancers
+	NATGateways          []*ec2.NatGateway
+	// PrivateCluster is whether the stack omits the public hosted zone and the
+	// public API load balancer.
+	PrivateCluster bool
+	RouteTables    []*ec2.RouteTable
+	SecurityGroups []*ec2.SecurityGroup
+	Subnets        []*ec2.Subnet
+	VPC            ContextStatusTenantClusterTCCPVPC
+	VPCEndpoints   bool
 }
 
 type ContextStatusTenantClusterTCCPAvailabilityZone struct {
@@ -110,30 +123,57 @@ type ContextStatusTenantClusterTCCPAvailabilityZoneSubnetPublic struct {
 	ID   string
 }
 
+type ContextStatusTenantClusterTCCPLoadBalancers struct {
+	TargetGroupARNs ContextStatusTenantClusterTCCPLoadBalancersTargetGroupARNs
+	Types           []string
+}
+
+type ContextStatusTenantClusterTCCPLoadBalancersTargetGroupARNs struct {
+	API         string
+	APIInternal string
+	Etcd        string
+}
+
 type ContextStatusTenantClusterTCCPVPC struct {
-	ID                  string
-	PeeringConnectionID string
+	EgressOnlyInternetGatewayID string
+	ID                          string
+	IPv6CIDR                    string
+	PeeringConnectionID         string
 }
 
 type ContextStatusTenantClusterTCCPN struct {
-	IsTransitioning bool
-	InstanceType    string
-	MasterReplicas  int
+	EtcdRestore      string
+	IsTransitioning  bool
+	InstanceType     string
+	LoadBalancerType string
+	MasterReplicas   int
 }
 
 type ContextStatusTenantClusterTCNP struct {
-	Instances        ContextStatusTenantClusterTCNPInstances
-	SecurityGroupIDs []string
-	WorkerInstance   ContextStatusTenantClusterTCNPWorkerInstance
+	DualStack            bool
+	InstanceRequirements string
+	Instances            ContextStatusTenantClusterTCNPInstances
+	NodeConfig           string
+	SecurityGroupIDs     []string
+	SpotAllocation       string
+	WarmPool             string
+	WorkerInstance       ContextStatusTenantClusterTCNPWorkerInstance
 }
 
 type ContextStatusTenantClusterTCNPInstances struct {
-	InstanceTypes         []string
+	InstanceTypes []string
+	// MatchingInstanceTypes are the instance types currently matching the
+	// instance requirements of the node pool.
+	MatchingInstanceTypes []string
 	NumberOfSpotInstances int
+	// SpotAllocation is the canonical representation of the allocation settings
+	// currently applied to the node pool's ASG.
+	SpotAllocation string
 }
 
 type ContextStatusTenantClusterTCNPWorkerInstance struct {
 	DockerVolumeSizeGB string
 	Image              string
 	Type               string
+	VolumeProfiles     string
 }
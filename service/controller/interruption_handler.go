@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"context"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/certs/v4/pkg/certs"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/giantswarm/operatorkit/v7/pkg/controller"
+	"github.com/giantswarm/operatorkit/v7/pkg/resource"
+	"github.com/giantswarm/operatorkit/v7/pkg/resource/wrapper/metricsresource"
+	"github.com/giantswarm/operatorkit/v7/pkg/resource/wrapper/retryresource"
+	"github.com/giantswarm/tenantcluster/v6/pkg/tenantcluster"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/aws-operator/v16/client/aws"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/pkg/project"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/accountid"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/awsclient"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/interruptionhandler"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tenantclients"
+	"github.com/giantswarm/aws-operator/v16/service/internal/interruption"
+	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type InterruptionHandlerConfig struct {
+	AWSClientPool *aws.Pool
+	K8sClient     k8sclient.Interface
+	Event         event.Interface
+	Logger        micrologger.Logger
+
+	HostAWSConfig               aws.Config
+	InterruptionHandlingEnabled bool
+}
+
+type InterruptionHandler struct {
+	*controller.Controller
+}
+
+func NewInterruptionHandler(config InterruptionHandlerConfig) (*InterruptionHandler, error) {
+	var err error
+
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+
+	var resources []resource.Interface
+	{
+		resources, err = newInterruptionHandlerResources(config)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var operatorkitController *controller.Controller
+	{
+		c := controller.Config{
+			InitCtx: func(ctx context.Context, obj interface{}) (context.Context, error) {
+				return controllercontext.NewContext(ctx, controllercontext.Context{}), nil
+			},
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+			NewRuntimeObjectFunc: func() ctrlClient.Object {
+				return new(infrastructurev1alpha3.AWSCluster)
+			},
+			Resources:    resources,
+			ResyncPeriod: key.InterruptionHandlerResyncPeriod,
+
+			// Name is used to compute finalizer names. This results in something
+			// like operatorkit.giantswarm.io/aws-operator-cluster-controller.
+			Name: project.Name() + "-interruption-handler-controller",
+			Selector: labels.SelectorFromSet(map[string]string{
+				label.OperatorVersion: project.Version(),
+			}),
+		}
+
+		operatorkitController, err = controller.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	c := &InterruptionHandler{
+		Controller: operatorkitController,
+	}
+
+	return c, nil
+}
+
+func newInterruptionHandlerResources(config InterruptionHandlerConfig) ([]resource.Interface, error) {
+	var err error
+
+	var certsSearcher *certs.Searcher
+	{
+		c := certs.Config{
+			K8sClient: config.K8sClient.K8sClient(),
+			Logger:    config.Logger,
+		}
+
+		certsSearcher, err = certs.NewSearcher(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var tenantCluster tenantcluster.Interface
+	{
+		c := tenantcluster.Config{
+			CertsSearcher: certsSearcher,
+			Logger:        config.Logger,
+			CertID:        certs.AWSOperatorAPICert,
+		}
+
+		tenantCluster, err = tenantcluster.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var accountIDResource resource.Interface
+	{
+		c := accountid.Config{
+			Logger: config.Logger,
+		}
+
+		accountIDResource, err = accountid.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var awsClientResource resource.Interface
+	{
+		c := awsclient.Config{
+			ClientPool:    config.AWSClientPool,
+			K8sClient:     config.K8sClient.K8sClient(),
+			Logger:        config.Logger,
+			ToClusterFunc: key.ToCluster,
+
+			CPAWSConfig: config.HostAWSConfig,
+		}
+
+		awsClientResource, err = awsclient.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var tenantClientsResource resource.Interface
+	{
+		c := tenantclients.Config{
+			Logger: config.Logger,
+			Tenant: tenantCluster,
+
+			ToClusterFunc: key.ToCluster,
+		}
+
+		tenantClientsResource, err = tenantclients.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var queue interruption.Interface
+	{
+		c := interruption.QueueConfig{
+			Logger: config.Logger,
+		}
+
+		queue, err = interruption.NewQueue(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var interruptionHandlerResource resource.Interface
+	{
+		c := interruptionhandler.Config{
+			CtrlClient: config.K8sClient.CtrlClient(),
+			Event:      config.Event,
+			Logger:     config.Logger,
+			Queue:      queue,
+
+			InterruptionHandlingEnabled: config.InterruptionHandlingEnabled,
+		}
+
+		interruptionHandlerResource, err = interruptionhandler.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	resources := []resource.Interface{
+		// All these resources only fetch information from remote APIs and put them
+		// into the controller context.
+		awsClientResource,
+		accountIDResource,
+		tenantClientsResource,
+
+		// All these resources implement certain business logic and operate based on
+		// the information given in the controller context.
+		interruptionHandlerResource,
+	}
+
+	{
+		c := retryresource.WrapConfig{
+			Logger: config.Logger,
+		}
+
+		resources, err = retryresource.Wrap(resources, c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := metricsresource.WrapConfig{}
+
+		resources, err = metricsresource.Wrap(resources, c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return resources, nil
+}
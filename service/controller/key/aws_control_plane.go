@@ -2,12 +2,16 @@ package key
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/k8smetadata/pkg/annotation"
 	"github.com/giantswarm/microerror"
 
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
 )
 
@@ -79,6 +83,25 @@ func ControlPlaneVolumeName(getter LabelsGetter, id int) string {
 	return fmt.Sprintf("%s-master%d-etcd", ClusterID(getter), id)
 }
 
+// ControlPlaneVolumeMasterID returns the master ID of the etcd volume with the
+// given name, as computed by ControlPlaneVolumeName. The returned bool is false
+// in case the name does not belong to an etcd volume of the given cluster.
+func ControlPlaneVolumeMasterID(getter LabelsGetter, name string) (int, bool) {
+	prefix := fmt.Sprintf("%s-master", ClusterID(getter))
+	suffix := "-etcd"
+
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix))
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
 func ControlPlaneVolumeResourceName(id int) string {
 	if id == 0 || id == 1 {
 		return "EtcdVolume"
@@ -151,3 +174,124 @@ func ToControlPlane(v interface{}) (infrastructurev1alpha3.AWSControlPlane, erro
 
 	return *c, nil
 }
+
+// ControlPlaneEtcdSnapshotInterval returns the interval in which snapshots of
+// the etcd volumes are created. The interval configured via annotation takes
+// precedence over the given default. An interval of 0 disables snapshots.
+func ControlPlaneEtcdSnapshotInterval(cr infrastructurev1alpha3.AWSControlPlane, def time.Duration) (time.Duration, error) {
+	v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdSnapshotInterval]
+	if !ok {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+// ControlPlaneEtcdSnapshotRetention returns the number of snapshots kept per
+// etcd volume. The retention configured via annotation takes precedence over
+// the given default.
+func ControlPlaneEtcdSnapshotRetention(cr infrastructurev1alpha3.AWSControlPlane, def int) (int, error) {
+	v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdSnapshotRetention]
+	if !ok {
+		return def, nil
+	}
+
+	r, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	return r, nil
+}
+
+// ControlPlaneEtcdRestore returns the snapshot the etcd volumes of the control
+// plane nodes are requested to be restored from. The value is either a snapshot
+// ID or EtcdRestoreLatest. The returned bool is false in case no restore is
+// requested.
+func ControlPlaneEtcdRestore(cr infrastructurev1alpha3.AWSControlPlane) (string, bool) {
+	v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdRestore]
+	return strings.TrimSpace(v), ok
+}
+
+// ControlPlaneEtcdRestoreRunning returns the number of control plane nodes
+// allowed to run while the etcd volumes are restored. The first n masters of the
+// HA Masters mapping are running. The returned bool is false in case no restore
+// is in progress, which means all control plane nodes are running.
+func ControlPlaneEtcdRestoreRunning(cr infrastructurev1alpha3.AWSControlPlane) (int, bool, error) {
+	v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdRestoreRunning]
+	if !ok {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false, microerror.Mask(err)
+	}
+
+	return n, true, nil
+}
+
+// ControlPlaneEtcdRestoreSnapshotIDs returns the snapshot IDs the etcd volumes
+// of the control plane nodes got restored from, keyed by master ID. The
+// annotation value is formatted by ControlPlaneEtcdRestoreSnapshotIDsValue.
+func ControlPlaneEtcdRestoreSnapshotIDs(cr infrastructurev1alpha3.AWSControlPlane) (map[int]string, error) {
+	v := cr.GetAnnotations()[awsoperatorannotation.EtcdRestoreSnapshots]
+	if v == "" {
+		return nil, nil
+	}
+
+	ids := map[int]string{}
+	for _, p := range strings.Split(v, ",") {
+		s := strings.SplitN(p, "=", 2)
+		if len(s) != 2 || s[1] == "" {
+			return nil, microerror.Maskf(invalidParameterError, "expected <master-id>=<snapshot-id>, got %#q", p)
+		}
+
+		id, err := strconv.Atoi(s[0])
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ids[id] = s[1]
+	}
+
+	return ids, nil
+}
+
+// ControlPlaneEtcdRestoreSnapshotIDsValue formats the given snapshot IDs keyed
+// by master ID as annotation value, e.g. 1=snap-1,2=snap-2,3=snap-3.
+func ControlPlaneEtcdRestoreSnapshotIDsValue(ids map[int]string) string {
+	var masters []int
+	for id := range ids {
+		masters = append(masters, id)
+	}
+	sort.Ints(masters)
+
+	var parts []string
+	for _, id := range masters {
+		parts = append(parts, fmt.Sprintf("%d=%s", id, ids[id]))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ControlPlaneEtcdRestoreState returns the restore state of the control plane
+// nodes as rendered into the TCCPN stack outputs. A change of the state means
+// the TCCPN stack has to be updated. The state is empty in case the etcd
+// volumes were never restored.
+func ControlPlaneEtcdRestoreState(cr infrastructurev1alpha3.AWSControlPlane) string {
+	var parts []string
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdRestoreSnapshots]; ok {
+		parts = append(parts, fmt.Sprintf("snapshots=%s", v))
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.EtcdRestoreRunning]; ok {
+		parts = append(parts, fmt.Sprintf("running=%s", v))
+	}
+
+	return strings.Join(parts, ";")
+}
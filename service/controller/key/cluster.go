@@ -3,6 +3,7 @@ package key
 import (
 	"context"
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -39,7 +40,22 @@ const (
 )
 
 const (
-	HAMasterSnapshotIDValue = "ha-master-migration"
+	// EtcdRestoreLatest is the value of the etcd restore annotation requesting
+	// to restore every etcd volume from its latest completed scheduled snapshot.
+	EtcdRestoreLatest = "latest"
+	// EtcdScheduledSnapshotValue is the value of the snapshot tag of the etcd
+	// volume snapshots created periodically by the etcdsnapshot resource.
+	EtcdScheduledSnapshotValue = "etcd-scheduled"
+	HAMasterSnapshotIDValue    = "ha-master-migration"
+)
+
+const (
+	// LoadBalancerTypeClassic is the default load balancer type of the tenant
+	// cluster's API and etcd endpoints, backed by classic ELBs.
+	LoadBalancerTypeClassic = "classic"
+	// LoadBalancerTypeNetwork is the load balancer type of the tenant cluster's
+	// API and etcd endpoints backed by NLBs.
+	LoadBalancerTypeNetwork = "network"
 )
 
 const (
@@ -59,8 +75,10 @@ const (
 	TagControlPlane      = "giantswarm.io/control-plane"
 	TagInstallation      = "giantswarm.io/installation"
 	TagMachineDeployment = "giantswarm.io/machine-deployment"
+	TagMasterID          = "giantswarm.io/master-id"
 	TagName              = "Name"
 	TagOrganization      = "giantswarm.io/organization"
+	TagReleaseVersion    = "giantswarm.io/release-version"
 	TagRouteTableType    = "giantswarm.io/route-table-type"
 	TagStack             = "giantswarm.io/stack"
 	TagSnapshot          = "giantswarm.io/snapshot"
@@ -81,6 +99,90 @@ const (
 	LifeCycleHookNodePool     = "NodePool"
 )
 
+const (
+	BurstablePerformanceExcluded = "excluded"
+	BurstablePerformanceIncluded = "included"
+	BurstablePerformanceRequired = "required"
+)
+
+const (
+	InstanceGenerationCurrent  = "current"
+	InstanceGenerationPrevious = "previous"
+)
+
+const (
+	// InstanceRequirementsDisabled represents node pools without instance
+	// requirements in the TCNP stack outputs.
+	InstanceRequirementsDisabled = "disabled"
+)
+
+const (
+	// NodeConfigDefault represents node pools without node labels, taints and
+	// kubelet settings in the TCNP stack outputs.
+	NodeConfigDefault = "default"
+)
+
+const (
+	OnDemandAllocationStrategyLowestPrice = "lowest-price"
+	OnDemandAllocationStrategyPrioritized = "prioritized"
+)
+
+const (
+	SpotAllocationStrategyCapacityOptimized            = "capacity-optimized"
+	SpotAllocationStrategyCapacityOptimizedPrioritized = "capacity-optimized-prioritized"
+	SpotAllocationStrategyLowestPrice                  = "lowest-price"
+	SpotAllocationStrategyPriceCapacityOptimized       = "price-capacity-optimized"
+)
+
+const (
+	TaintEffectNoExecute        = "NoExecute"
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+)
+
+const (
+	// UpdateStrategyCloudFormation is the default update strategy of node pools,
+	// which replaces instances via the rolling update policy of the ASG.
+	UpdateStrategyCloudFormation = "cloudformation"
+	// UpdateStrategyOperator is the update strategy of node pools, which lets the
+	// operator replace instances only once their replacements became ready.
+	UpdateStrategyOperator = "operator"
+)
+
+const (
+	VolumeDeviceContainerd = "containerd"
+	VolumeDeviceDocker     = "docker"
+	VolumeDeviceKubelet    = "kubelet"
+	VolumeDeviceLogging    = "logging"
+)
+
+const (
+	// WarmPoolDisabled represents node pools without warm pool in the TCNP
+	// stack outputs.
+	WarmPoolDisabled = "disabled"
+)
+
+const (
+	WarmPoolStateHibernated = "Hibernated"
+	WarmPoolStateStopped    = "Stopped"
+)
+
+const (
+	// HibernationMaxMemoryMiB is the maximum amount of memory of instance types
+	// able to hibernate.
+	HibernationMaxMemoryMiB = 150 * 1024
+	// HibernationRootVolumeBaseSizeGB is the size of the root volume of
+	// hibernating instances without the space taken by the RAM contents, which
+	// hibernation writes to the root volume.
+	HibernationRootVolumeBaseSizeGB = 10
+)
+
+const (
+	// VolumeTypeDefault is the EBS volume type of node pool volumes without
+	// volume profile.
+	VolumeTypeDefault = "gp3"
+)
+
 const (
 	RefWorkerASG = "workerAutoScalingGroup"
 )
@@ -140,6 +242,40 @@ func LegacyAWSCniCIDRBlock(cluster infrastructurev1alpha3.AWSCluster) string {
 	return cluster.Annotations[awsoperatorannotation.LegacyAwsCniPodCidr]
 }
 
+// ManagedTagKeys returns the keys of the user defined tags the tag policy
+// defined for the cluster's AWS resources the last time the tagdrift resource
+// repaired them. The keys are grouped by the scope of the tags, which is
+// either the cluster ID or the ID of a node pool. Tags with these keys are
+// removed from the resources of the scope once they are not part of the tag
+// policy anymore.
+func ManagedTagKeys(cluster infrastructurev1alpha3.AWSCluster) (map[string][]string, error) {
+	v := cluster.Annotations[awsoperatorannotation.ManagedTagKeys]
+	if v == "" {
+		return nil, nil
+	}
+
+	var keys map[string][]string
+	err := json.Unmarshal([]byte(v), &keys)
+	if err != nil {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be a JSON object mapping scopes to tag keys: %s", awsoperatorannotation.ManagedTagKeys, err)
+	}
+
+	return keys, nil
+}
+
+// DualStackEnabled returns whether the tenant cluster's networks are
+// dual-stack, meaning the VPC and all of its subnets get IPv6 CIDR blocks next
+// to their IPv4 ones. The IPv6 CIDR block of a VPC cannot be removed as long
+// as subnets use it. Thus dual-stack stays enabled once the given current IPv6
+// CIDR block of the VPC is known.
+func DualStackEnabled(cluster infrastructurev1alpha3.AWSCluster, vpcIPv6CIDR string) bool {
+	if vpcIPv6CIDR != "" {
+		return true
+	}
+
+	return cluster.GetAnnotations()[awsoperatorannotation.DualStack] == "true"
+}
+
 func EtcdQuotaBackendBytes(cluster apiv1beta1.Cluster) int64 {
 	str := cluster.Annotations["etcd.giantswarm.io/quota-backend-bytes"]
 	if str != "" {
@@ -186,6 +322,27 @@ func HasCilium(cluster LabelsGetter) (bool, error) {
 	return false, nil
 }
 
+// InterruptionHandlingEnabled returns whether spot interruptions, rebalance
+// recommendations and scheduled maintenance events of the tenant cluster's
+// nodes are handled. The annotation takes precedence over the given operator
+// wide default.
+func InterruptionHandlingEnabled(cluster infrastructurev1alpha3.AWSCluster, enabled bool) bool {
+	switch cluster.GetAnnotations()[awsoperatorannotation.InterruptionHandling] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return enabled
+}
+
+// InterruptionQueueName returns the name of the SQS queue EventBridge delivers
+// the interruption events of the tenant cluster's nodes to.
+func InterruptionQueueName(cluster infrastructurev1alpha3.AWSCluster) string {
+	return fmt.Sprintf("%s-interruption", ClusterID(&cluster))
+}
+
 func IsChinaRegion(awsRegion string) bool {
 	return strings.HasPrefix(awsRegion, "cn-")
 }
@@ -267,14 +424,27 @@ func MasterInstanceType(cluster infrastructurev1alpha3.AWSCluster) string {
 	return cluster.Spec.Provider.Master.InstanceType
 }
 
+// LoadBalancerType returns the desired load balancer type of the tenant
+// cluster's API and etcd endpoints. Clusters without or with an unknown
+// annotation value use classic ELBs.
+func LoadBalancerType(cluster infrastructurev1alpha3.AWSCluster) string {
+	if cluster.GetAnnotations()[awsoperatorannotation.LoadBalancerType] == LoadBalancerTypeNetwork {
+		return LoadBalancerTypeNetwork
+	}
+
+	return LoadBalancerTypeClassic
+}
+
+// ManagedRecordSets returns the names of the record sets managed by the Cloud
+// Formation stack of the tenant cluster in its hosted zones.
 func ManagedRecordSets(cluster infrastructurev1alpha3.AWSCluster) []string {
 	tcBaseDomain := TenantClusterBaseDomain(cluster)
 	return []string{
-		fmt.Sprintf("%s.", tcBaseDomain),
-		fmt.Sprintf("\\052.%s.", tcBaseDomain), // \\052 - `*` wildcard record
-		fmt.Sprintf("api.%s.", tcBaseDomain),
-		fmt.Sprintf("etcd.%s.", tcBaseDomain),
-		fmt.Sprintf("internal-api.%s.", tcBaseDomain),
+		tcBaseDomain,
+		fmt.Sprintf("*.%s", tcBaseDomain),
+		fmt.Sprintf("api.%s", tcBaseDomain),
+		fmt.Sprintf("etcd.%s", tcBaseDomain),
+		fmt.Sprintf("internal-api.%s", tcBaseDomain),
 	}
 }
 
@@ -291,6 +461,42 @@ func OIDCGroupsClaim(cluster infrastructurev1alpha3.AWSCluster) string {
 	return cluster.Spec.Cluster.OIDC.Claims.Groups
 }
 
+// PrivateClusterEnabled returns whether the cluster does not expose any public
+// DNS records and public API load balancer. All its records then live in the
+// private hosted zone of the cluster.
+func PrivateClusterEnabled(cluster infrastructurev1alpha3.AWSCluster) bool {
+	return cluster.GetAnnotations()[awsoperatorannotation.PrivateCluster] == "true"
+}
+
+// PrivateHostedZoneControlPlaneVPC returns whether the private hosted zone of
+// a private cluster gets associated with the control plane VPC, so that the
+// control plane can resolve the records of the cluster.
+func PrivateHostedZoneControlPlaneVPC(cluster infrastructurev1alpha3.AWSCluster) bool {
+	return cluster.GetAnnotations()[awsoperatorannotation.PrivateHostedZoneCPVPC] == "true"
+}
+
+// PrivateHostedZoneVPCs returns the IDs of the additional VPCs the private
+// hosted zone of a private cluster gets associated with, e.g.
+// "vpc-0a1b2c3d,vpc-4e5f6a7b". The VPCs must live in the account and region
+// of the cluster.
+func PrivateHostedZoneVPCs(cluster infrastructurev1alpha3.AWSCluster) ([]string, error) {
+	v := cluster.GetAnnotations()[awsoperatorannotation.PrivateHostedZoneVPCs]
+	if v == "" {
+		return nil, nil
+	}
+
+	var vpcs []string
+	for _, f := range strings.Split(v, ",") {
+		id := strings.TrimSpace(f)
+		if !strings.HasPrefix(id, "vpc-") {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain VPC IDs like %#q, got %#q", awsoperatorannotation.PrivateHostedZoneVPCs, "vpc-0a1b2c3d", f)
+		}
+		vpcs = append(vpcs, id)
+	}
+
+	return vpcs, nil
+}
+
 func PolicyNameMaster(cluster infrastructurev1alpha3.AWSCluster) string {
 	return fmt.Sprintf("%s-master-%s", ClusterID(&cluster), EC2PolicyK8s)
 }
@@ -338,6 +544,20 @@ func ToCluster(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCl
 	return *c, nil
 }
 
+// VPCEndpointsEnabled returns whether the interface VPC endpoints are created
+// in the tenant cluster's VPC. The annotation takes precedence over the given
+// operator wide default.
+func VPCEndpointsEnabled(cluster infrastructurev1alpha3.AWSCluster, enabled bool) bool {
+	switch cluster.GetAnnotations()[awsoperatorannotation.VPCEndpoints] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return enabled
+}
+
 func VolumeNameDocker(cluster infrastructurev1alpha3.AWSCluster) string {
 	return fmt.Sprintf("%s-docker", ClusterID(&cluster))
 }
@@ -0,0 +1,78 @@
+package key
+
+import (
+	"strconv"
+	"testing"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
+)
+
+func TestPrivateHostedZoneVPCs(t *testing.T) {
+	testCases := []struct {
+		name         string
+		annotation   string
+		expected     []string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:       "case 0: no annotation",
+			annotation: "",
+			expected:   nil,
+		},
+		{
+			name:       "case 1: single VPC",
+			annotation: "vpc-0a1b2c3d",
+			expected:   []string{"vpc-0a1b2c3d"},
+		},
+		{
+			name:       "case 2: multiple VPCs with whitespace",
+			annotation: "vpc-0a1b2c3d, vpc-4e5f6a7b ",
+			expected:   []string{"vpc-0a1b2c3d", "vpc-4e5f6a7b"},
+		},
+		{
+			name:         "case 3: invalid VPC ID",
+			annotation:   "vpc-0a1b2c3d,subnet-4e5f6a7b",
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 4: trailing comma",
+			annotation:   "vpc-0a1b2c3d,",
+			errorMatcher: IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			cluster := infrastructurev1alpha3.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						awsoperatorannotation.PrivateHostedZoneVPCs: tc.annotation,
+					},
+				},
+			}
+
+			vpcs, err := PrivateHostedZoneVPCs(cluster)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !cmp.Equal(vpcs, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, vpcs))
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package key
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
@@ -25,6 +29,23 @@ const (
 
 	ELBInstanceStateInService = "InService"
 
+	// IPv6SubnetsPerNetwork is the number of /64 IPv6 CIDR blocks reserved for
+	// the subnets of a single IPv4 network of the VPC.
+	IPv6SubnetsPerNetwork = 8
+	// IPv6NetworkCount is the number of IPv4 networks of a VPC that can be
+	// assigned IPv6 CIDR blocks. The /56 IPv6 CIDR block of a VPC holds 256 /64
+	// blocks.
+	IPv6NetworkCount = 256 / IPv6SubnetsPerNetwork
+	// IPv6NetworkIndexTCCP is the index of the range of IPv6 CIDR blocks of the
+	// public and private subnets of the control plane.
+	IPv6NetworkIndexTCCP = 0
+	// IPv6NetworkIndexAWSCNI is the index of the range of IPv6 CIDR blocks of
+	// the AWS CNI subnets.
+	IPv6NetworkIndexAWSCNI = 1
+	// IPv6NetworkIndexNodePoolMin is the first index of the ranges of IPv6 CIDR
+	// blocks allocated to node pools. See MachineDeploymentIPv6NetworkIndex.
+	IPv6NetworkIndexNodePoolMin = 2
+
 	DrainerResyncPeriod = time.Minute * 2
 
 	DefaultPauseTimeBetweenUpdates = "PT10M"
@@ -42,9 +63,56 @@ const (
 	amiFilePath = "/tmp/ami.json"
 )
 
-var amiInfo = map[string]map[string]string{}
+const (
+	ArchitectureAMD64 = "amd64"
+	ArchitectureARM64 = "arm64"
+)
+
+// amiInfo maps Flatcar versions to the AMIs of every region and architecture.
+var amiInfo = map[string]map[string]architectureAMIs{}
+
+// architectureAMIs maps CPU architectures to AMIs. Catalogue entries used to
+// be plain AMI IDs, which are still supported and refer to amd64 AMIs.
+type architectureAMIs map[string]string
+
+func (a *architectureAMIs) UnmarshalJSON(b []byte) error {
+	var ami string
+	err := json.Unmarshal(b, &ami)
+	if err == nil {
+		*a = architectureAMIs{ArchitectureAMD64: ami}
+		return nil
+	}
+
+	m := map[string]string{}
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	*a = m
+
+	return nil
+}
+
+// armInstanceTypeRegexp matches the AWS Graviton instance families, e.g. a1,
+// m6g, c6gn, c7g, r7gd, t4g or x2gd.
+var armInstanceTypeRegexp = regexp.MustCompile(`^(a1|[a-z]+[0-9]+g[a-z]*)\.`)
+
+// hibernationInstanceFamilies are the instance families supporting
+// hibernation. See
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/hibernating-prerequisites.html.
+var hibernationInstanceFamilies = map[string]bool{
+	"c3": true, "c4": true, "c5": true, "c5d": true, "c6i": true, "c6id": true, "c6in": true, "c7a": true, "c7i": true, "c7i-flex": true,
+	"i3": true,
+	"m3": true, "m4": true, "m5": true, "m5a": true, "m5ad": true, "m5d": true, "m6a": true, "m6i": true, "m6id": true, "m6idn": true, "m6in": true, "m7a": true, "m7i": true, "m7i-flex": true,
+	"r3": true, "r4": true, "r5": true, "r5a": true, "r5ad": true, "r5d": true, "r5dn": true, "r5n": true, "r6a": true, "r6i": true, "r6id": true, "r6idn": true, "r6in": true, "r7a": true, "r7i": true, "r7iz": true,
+	"t2": true, "t3": true, "t3a": true,
+}
 
 const (
+	// InterruptionHandlerResyncPeriod defines resync period for the
+	// interruptionhandler controller. Spot instances are reclaimed two minutes
+	// after the interruption warning, so the queue has to be polled frequently.
+	InterruptionHandlerResyncPeriod = time.Second * 30
 	// TerminateUnhealthyNodeResyncPeriod defines resync period for the terminateunhealthynode controller
 	TerminateUnhealthyNodeResyncPeriod = time.Minute * 3
 )
@@ -79,21 +147,37 @@ func loadAMIs() error {
 		return nil
 	}
 
-	amiJSON, err := os.ReadFile(amiFilePath)
+	err := LoadAMIs(amiFilePath)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// LoadAMIs replaces the AMI catalogue with the one of the given file. The
+// operator lazily loads the catalogue mounted to /tmp/ami.json. Commands
+// running outside of the operator's pod use LoadAMIs to provide their own.
+func LoadAMIs(path string) error {
+	amiJSON, err := os.ReadFile(path)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = json.Unmarshal(amiJSON, &amiInfo)
+	info := map[string]map[string]architectureAMIs{}
+	err = json.Unmarshal(amiJSON, &info)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	amiInfo = info
+
 	return nil
 }
 
-// AMI returns the EC2 AMI for the configured region and given version.
-func AMI(region string, release releasev1alpha1.Release, flatcarReleaseVersion string) (string, error) {
+// AMI returns the EC2 AMI for the configured region, given version and CPU
+// architecture.
+func AMI(region string, release releasev1alpha1.Release, flatcarReleaseVersion string, architecture string) (string, error) {
 
 	var osVersion string
 	var err error
@@ -117,12 +201,74 @@ func AMI(region string, release releasev1alpha1.Release, flatcarReleaseVersion s
 		return "", microerror.Maskf(notFoundError, "no image id for version '%s'", osVersion)
 	}
 
-	regionAMI, ok := regionAMIs[region]
+	architectureAMIs, ok := regionAMIs[region]
 	if !ok {
 		return "", microerror.Maskf(notFoundError, "no image id for region '%s'", region)
 	}
 
-	return regionAMI, nil
+	ami, ok := architectureAMIs[architecture]
+	if !ok {
+		return "", microerror.Maskf(notFoundError, "no image id for architecture '%s' in region '%s'", architecture, region)
+	}
+
+	return ami, nil
+}
+
+// InstanceTypeArchitecture returns the CPU architecture of the given EC2
+// instance type, which is arm64 for AWS Graviton instance types and amd64
+// otherwise.
+func InstanceTypeArchitecture(instanceType string) string {
+	if armInstanceTypeRegexp.MatchString(instanceType) {
+		return ArchitectureARM64
+	}
+
+	return ArchitectureAMD64
+}
+
+// InstanceTypeHibernationSupported returns whether instances of the given EC2
+// instance type can hibernate. Bare metal instance types cannot hibernate.
+// Note that hibernation is also limited to instance types with less than
+// HibernationMaxMemoryMiB of memory, which is not known here.
+func InstanceTypeHibernationSupported(instanceType string) bool {
+	family, size, _ := strings.Cut(instanceType, ".")
+	if strings.HasPrefix(size, "metal") {
+		return false
+	}
+
+	return hibernationInstanceFamilies[family]
+}
+
+// InstanceFamilyPatterns returns the instance type patterns matching all
+// instance types of the given instance families, e.g. "m5.*" for "m5".
+func InstanceFamilyPatterns(families []string) []string {
+	var patterns []string
+	for _, f := range families {
+		patterns = append(patterns, f+".*")
+	}
+
+	return patterns
+}
+
+// InstanceRequirementsCPUManufacturers returns the CPU manufacturers of
+// instance types with the given CPU architecture. Instance requirements have
+// no architecture attribute, so that the CPU manufacturers restrict them to
+// instance types able to boot the node pool's AMI.
+func InstanceRequirementsCPUManufacturers(architecture string) []string {
+	if architecture == ArchitectureARM64 {
+		return []string{"amazon-web-services"}
+	}
+
+	return []string{"amd", "intel"}
+}
+
+// TaintValue returns the given taint in the kubelet's "key=value:Effect"
+// format.
+func TaintValue(t Taint) string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
 }
 
 func AWSBaseDomain(region string) string {
@@ -139,6 +285,10 @@ func AWSCNINATRouteName(az string) string {
 	return fmt.Sprintf("AWSCNINATRoute-%s", az)
 }
 
+func AWSCNIEgressOnlyInternetGatewayRouteName(az string) string {
+	return fmt.Sprintf("AWSCNIEgressOnlyInternetGatewayRoute-%s", az)
+}
+
 func AWSCNIRouteTableName(az string) string {
 	return fmt.Sprintf("AWSCNIRouteTable-%s", az)
 }
@@ -175,6 +325,13 @@ func BucketName(getter LabelsGetter, accountID string) string {
 	return fmt.Sprintf("%s-g8s-%s", accountID, ClusterID(getter))
 }
 
+// ChangeSetPreviewEnabled returns true when the given object is annotated to
+// preview CloudFormation stack updates via change sets instead of applying
+// them directly.
+func ChangeSetPreviewEnabled(getter AnnotationsGetter) bool {
+	return getter.GetAnnotations()[annotation.ChangeSetPreview] == "true"
+}
+
 func ClusterCloudProviderTag(getter LabelsGetter) string {
 	return fmt.Sprintf("kubernetes.io/cluster/%s", ClusterID(getter))
 }
@@ -193,6 +350,10 @@ func EC2ServiceDomain(region string) string {
 	return domain
 }
 
+func EgressOnlyInternetGatewayRouteName(az string) string {
+	return fmt.Sprintf("EgressOnlyInternetGatewayRoute-%s", az)
+}
+
 func ELBNameAPI(getter LabelsGetter) string {
 	return fmt.Sprintf("%s-api", ClusterID(getter))
 }
@@ -213,10 +374,44 @@ func HealthCheckHTTPTarget(port int) string {
 	return fmt.Sprintf("HTTP:%d/healthz", port)
 }
 
+func InternalNLBNameAPI(getter LabelsGetter) string {
+	return fmt.Sprintf("%s-api-internal-nlb", ClusterID(getter))
+}
+
 func InternalELBNameAPI(getter LabelsGetter) string {
 	return fmt.Sprintf("%s-api-internal", ClusterID(getter))
 }
 
+// IPv6SubnetIndex returns the index of the /64 IPv6 CIDR block, within the
+// /56 IPv6 CIDR block of the VPC, that is assigned to the given IPv4 subnet.
+// The 256 /64 blocks of the VPC are partitioned into IPv6NetworkCount ranges of
+// IPv6SubnetsPerNetwork blocks, one range per IPv4 network of the VPC, e.g. the
+// cluster network, the AWS CNI network or the network of a node pool. Within
+// its range the subnet is indexed by its offset within the given network,
+// normalised to an eighth of the network. The index is thus stable across
+// reconciliations and distinct for all subnets of the VPC, as long as each
+// network gets its own range and its subnets are no smaller than an eighth of
+// it, which holds for the way tccpazs and tcnpazs split networks.
+func IPv6SubnetIndex(networkIndex int, network net.IPNet, subnet net.IPNet) int {
+	var offset int
+	{
+		ip := subnet.IP.To4()
+		base := network.IP.To4()
+
+		ones, bits := network.Mask.Size()
+		if ip != nil && base != nil && bits == 32 {
+			shift := 32 - ones - 3
+			if shift < 0 {
+				shift = 0
+			}
+
+			offset = int((binary.BigEndian.Uint32(ip)-binary.BigEndian.Uint32(base))>>shift) % IPv6SubnetsPerNetwork
+		}
+	}
+
+	return networkIndex*IPv6SubnetsPerNetwork + offset
+}
+
 func IsDeleted(getter DeletionTimestampGetter) bool {
 	return getter.GetDeletionTimestamp() != nil
 }
@@ -258,6 +453,46 @@ func MachineDeploymentID(getter LabelsGetter) string {
 	return getter.GetLabels()[label.MachineDeployment]
 }
 
+// LoadBalancerEIPName returns the resource name of the static EIP the public API
+// NLB uses in the given availability zone.
+func LoadBalancerEIPName(az string) string {
+	return fmt.Sprintf("LoadBalancerEIP-%s", az)
+}
+
+// LoadBalancerTypesTCCP returns the load balancer types the TCCP stack has to
+// provide. Next to the desired type, the type the TCCPN stack is currently
+// attached to is kept, so that the control plane nodes can be moved over to
+// the desired load balancers before the current ones get removed. The TCCPN
+// type is empty in case the TCCPN stack does not exist yet.
+func LoadBalancerTypesTCCP(desired string, tccpn string) []string {
+	types := []string{desired}
+	if tccpn != "" && tccpn != desired {
+		types = append(types, tccpn)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// LoadBalancerTypeTCCPN returns the load balancer type the TCCPN stack has to
+// attach the control plane nodes to. In case the TCCP stack provides multiple
+// load balancer types, a migration is ongoing and the control plane nodes move
+// away from the type they are currently attached to.
+func LoadBalancerTypeTCCPN(tccp []string, current string) string {
+	if len(tccp) == 0 {
+		return LoadBalancerTypeClassic
+	}
+
+	for _, t := range tccp {
+		if t != current {
+			return t
+		}
+	}
+
+	return current
+}
+
 func NATEIPName(az string) string {
 	return fmt.Sprintf("NATEIP-%s", az)
 }
@@ -270,6 +505,14 @@ func NATRouteName(az string) string {
 	return fmt.Sprintf("NATRoute-%s", az)
 }
 
+func NLBNameAPI(getter LabelsGetter) string {
+	return fmt.Sprintf("%s-api-nlb", ClusterID(getter))
+}
+
+func NLBNameEtcd(getter LabelsGetter) string {
+	return fmt.Sprintf("%s-etcd-nlb", ClusterID(getter))
+}
+
 func OperatorVersion(getter LabelsGetter) string {
 	return getter.GetLabels()[label.OperatorVersion]
 }
@@ -298,6 +541,10 @@ func PublicInternetGatewayRouteName(az string) string {
 	return fmt.Sprintf("PublicInternetGatewayRoute-%s", az)
 }
 
+func PublicInternetGatewayIPv6RouteName(az string) string {
+	return fmt.Sprintf("PublicInternetGatewayIPv6Route-%s", az)
+}
+
 func PublicSubnetName(az string) string {
 	return fmt.Sprintf("PublicSubnet-%s", az)
 }
@@ -435,14 +682,54 @@ func StackNameTCNP(getter LabelsGetter) string {
 	return fmt.Sprintf("cluster-%s-tcnp-%s", ClusterID(getter), MachineDeploymentID(getter))
 }
 
+// StackNameTCNPPrefix returns the name prefix shared by the TCNP stacks of all
+// node pools of the given cluster. Resources with names generated by
+// CloudFormation, e.g. the node pool ASGs, carry the prefix as well.
+func StackNameTCNPPrefix(getter LabelsGetter) string {
+	return fmt.Sprintf("cluster-%s-tcnp-", ClusterID(getter))
+}
+
 func StackNameTCNPF(getter LabelsGetter) string {
 	return fmt.Sprintf("cluster-%s-tcnpf-%s", ClusterID(getter), MachineDeploymentID(getter))
 }
 
+// StackName returns the name of the given stack, e.g. StackTCNP, of the
+// cluster or node pool the given object belongs to.
+func StackName(getter LabelsGetter, stack string) string {
+	switch stack {
+	case StackTCNP, StackTCNPF:
+		return fmt.Sprintf("cluster-%s-%s-%s", ClusterID(getter), stack, MachineDeploymentID(getter))
+	default:
+		return fmt.Sprintf("cluster-%s-%s", ClusterID(getter), stack)
+	}
+}
+
+// StackUpdateConditionAnnotation returns the annotation used to record the
+// update condition of the given stack, e.g. StackTCNP.
+func StackUpdateConditionAnnotation(stack string) string {
+	return fmt.Sprintf("%s-%s", annotation.StackUpdateCondition, stack)
+}
+
 func TargetLogBucketName(getter LabelsGetter, accountID string) string {
 	return fmt.Sprintf("%s-g8s-%s-access-logs", accountID, ClusterID(getter))
 }
 
+// VPCEndpointName returns the resource name of the interface VPC endpoint of
+// the given service, e.g. ecr.api.
+func VPCEndpointName(service string) string {
+	return fmt.Sprintf("VPCEndpoint-%s", service)
+}
+
+// VPCEndpointServiceName returns the name of the given AWS service used to
+// create interface VPC endpoints in the given region.
+func VPCEndpointServiceName(region string, service string) string {
+	if isChinaRegion(region) {
+		return fmt.Sprintf("cn.com.amazonaws.%s.%s", region, service)
+	}
+
+	return fmt.Sprintf("com.amazonaws.%s.%s", region, service)
+}
+
 func VPCPeeringRouteName(az string) string {
 	return fmt.Sprintf("VPCPeeringRoute-%s", az)
 }
@@ -1,6 +1,8 @@
 package key
 
 import (
+	"encoding/json"
+	"net"
 	"strconv"
 	"testing"
 
@@ -97,3 +99,311 @@ func TestIsMinimumFlatcarVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestIPv6SubnetIndex(t *testing.T) {
+	testCases := []struct {
+		name         string
+		networkIndex int
+		network      string
+		subnet       string
+		expected     int
+	}{
+		{
+			name:         "case 0: first /27 of the cluster network",
+			networkIndex: IPv6NetworkIndexTCCP,
+			network:      "10.1.0.0/24",
+			subnet:       "10.1.0.0/27",
+			expected:     0,
+		},
+		{
+			name:         "case 1: last /27 of the cluster network",
+			networkIndex: IPv6NetworkIndexTCCP,
+			network:      "10.1.0.0/24",
+			subnet:       "10.1.0.224/27",
+			expected:     7,
+		},
+		{
+			name:         "case 2: second /18 of the aws-cni network",
+			networkIndex: IPv6NetworkIndexAWSCNI,
+			network:      "10.2.0.0/16",
+			subnet:       "10.2.64.0/18",
+			expected:     10,
+		},
+		{
+			name:         "case 3: second /25 of a node pool network",
+			networkIndex: 5,
+			network:      "10.1.42.0/24",
+			subnet:       "10.1.42.128/25",
+			expected:     44,
+		},
+		{
+			name:         "case 4: the whole node pool network",
+			networkIndex: IPv6NetworkCount - 1,
+			network:      "10.1.42.0/24",
+			subnet:       "10.1.42.0/24",
+			expected:     248,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			output := IPv6SubnetIndex(tc.networkIndex, mustParseCIDR(tc.network), mustParseCIDR(tc.subnet))
+
+			if output != tc.expected {
+				t.Fatalf("\n\n%s\n", cmp.Diff(output, tc.expected))
+			}
+		})
+	}
+}
+
+func TestIPv6SubnetIndexUnique(t *testing.T) {
+	type network struct {
+		index   int
+		cidr    string
+		subnets []string
+	}
+
+	testCases := []struct {
+		name     string
+		networks []network
+	}{
+		{
+			name: "case 0: /27 control plane subnets, /18 aws-cni subnets and /26 node pool subnets",
+			networks: []network{
+				{index: IPv6NetworkIndexTCCP, cidr: "10.1.1.0/24", subnets: []string{"10.1.1.0/27", "10.1.1.32/27", "10.1.1.64/27", "10.1.1.96/27", "10.1.1.128/27", "10.1.1.160/27"}},
+				{index: IPv6NetworkIndexAWSCNI, cidr: "10.2.0.0/16", subnets: []string{"10.2.0.0/18", "10.2.64.0/18", "10.2.128.0/18"}},
+				{index: 2, cidr: "10.1.5.0/24", subnets: []string{"10.1.5.0/26", "10.1.5.64/26", "10.1.5.128/26"}},
+			},
+		},
+		{
+			name: "case 1: /27 control plane subnets and /26, /25 and /24 node pool subnets",
+			networks: []network{
+				{index: IPv6NetworkIndexTCCP, cidr: "10.1.2.0/24", subnets: []string{"10.1.2.0/27", "10.1.2.32/27", "10.1.2.128/27", "10.1.2.160/27"}},
+				{index: 2, cidr: "10.1.5.0/24", subnets: []string{"10.1.5.0/26", "10.1.5.64/26", "10.1.5.128/26", "10.1.5.192/26"}},
+				{index: 3, cidr: "10.1.0.0/24", subnets: []string{"10.1.0.0/25", "10.1.0.128/25"}},
+				{index: 4, cidr: "10.1.9.0/24", subnets: []string{"10.1.9.0/24"}},
+			},
+		},
+		{
+			name: "case 2: /27 and /25 subnets of the same network",
+			networks: []network{
+				{index: IPv6NetworkIndexTCCP, cidr: "10.1.0.0/24", subnets: []string{"10.1.0.0/27", "10.1.0.32/27", "10.1.0.128/25"}},
+				{index: IPv6NetworkIndexAWSCNI, cidr: "10.2.0.0/16", subnets: []string{"10.2.0.0/18", "10.2.64.0/18", "10.2.128.0/18", "10.2.192.0/18"}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			indexes := map[int]string{}
+
+			for _, n := range tc.networks {
+				for _, s := range n.subnets {
+					index := IPv6SubnetIndex(n.index, mustParseCIDR(n.cidr), mustParseCIDR(s))
+
+					if index < 0 || index > 255 {
+						t.Fatalf("expected index of subnet %#q to be within [0, 255], got %d", s, index)
+					}
+					if other, ok := indexes[index]; ok {
+						t.Fatalf("expected subnets %#q and %#q to have distinct indexes, got %d", other, s, index)
+					}
+
+					indexes[index] = s
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBalancerTypesTCCP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		desired  string
+		tccpn    string
+		expected []string
+	}{
+		{
+			name:     "case 0: new cluster only gets the desired load balancers",
+			desired:  LoadBalancerTypeNetwork,
+			tccpn:    "",
+			expected: []string{LoadBalancerTypeNetwork},
+		},
+		{
+			name:     "case 1: unchanged type only keeps the current load balancers",
+			desired:  LoadBalancerTypeClassic,
+			tccpn:    LoadBalancerTypeClassic,
+			expected: []string{LoadBalancerTypeClassic},
+		},
+		{
+			name:     "case 2: migration keeps the current load balancers",
+			desired:  LoadBalancerTypeNetwork,
+			tccpn:    LoadBalancerTypeClassic,
+			expected: []string{LoadBalancerTypeClassic, LoadBalancerTypeNetwork},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output := LoadBalancerTypesTCCP(tc.desired, tc.tccpn)
+
+			if !cmp.Equal(output, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(output, tc.expected))
+			}
+		})
+	}
+}
+
+func TestLoadBalancerTypeTCCPN(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tccp     []string
+		current  string
+		expected string
+	}{
+		{
+			name:     "case 0: unknown TCCP load balancers default to classic",
+			tccp:     nil,
+			current:  "",
+			expected: LoadBalancerTypeClassic,
+		},
+		{
+			name:     "case 1: single TCCP load balancer type is used",
+			tccp:     []string{LoadBalancerTypeNetwork},
+			current:  "",
+			expected: LoadBalancerTypeNetwork,
+		},
+		{
+			name:     "case 2: migration moves away from the current type",
+			tccp:     []string{LoadBalancerTypeClassic, LoadBalancerTypeNetwork},
+			current:  LoadBalancerTypeClassic,
+			expected: LoadBalancerTypeNetwork,
+		},
+		{
+			name:     "case 3: unchanged type is kept",
+			tccp:     []string{LoadBalancerTypeNetwork},
+			current:  LoadBalancerTypeNetwork,
+			expected: LoadBalancerTypeNetwork,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output := LoadBalancerTypeTCCPN(tc.tccp, tc.current)
+
+			if output != tc.expected {
+				t.Fatalf("\n\n%s\n", cmp.Diff(output, tc.expected))
+			}
+		})
+	}
+}
+
+func TestInstanceTypeArchitecture(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instanceType string
+		expected     string
+	}{
+		{
+			name:         "case 0: intel instance type",
+			instanceType: "m5.xlarge",
+			expected:     ArchitectureAMD64,
+		},
+		{
+			name:         "case 1: graviton2 instance type",
+			instanceType: "m6g.xlarge",
+			expected:     ArchitectureARM64,
+		},
+		{
+			name:         "case 2: graviton3 instance type with network suffix",
+			instanceType: "c7gn.2xlarge",
+			expected:     ArchitectureARM64,
+		},
+		{
+			name:         "case 3: first generation graviton instance type",
+			instanceType: "a1.large",
+			expected:     ArchitectureARM64,
+		},
+		{
+			name:         "case 4: gpu instance type",
+			instanceType: "g4dn.xlarge",
+			expected:     ArchitectureAMD64,
+		},
+		{
+			name:         "case 5: amd instance type",
+			instanceType: "m5a.large",
+			expected:     ArchitectureAMD64,
+		},
+		{
+			name:         "case 6: graviton burstable instance type",
+			instanceType: "t4g.medium",
+			expected:     ArchitectureARM64,
+		},
+		{
+			name:         "case 7: flex instance type",
+			instanceType: "c7i-flex.large",
+			expected:     ArchitectureAMD64,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output := InstanceTypeArchitecture(tc.instanceType)
+
+			if output != tc.expected {
+				t.Fatalf("\n\n%s\n", cmp.Diff(output, tc.expected))
+			}
+		})
+	}
+}
+
+func TestArchitectureAMIsUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected map[string]map[string]architectureAMIs
+	}{
+		{
+			name: "case 0: legacy catalogue refers to amd64 AMIs",
+			data: `{"3510.2.0": {"eu-central-1": "ami-a"}}`,
+			expected: map[string]map[string]architectureAMIs{
+				"3510.2.0": {"eu-central-1": {ArchitectureAMD64: "ami-a"}},
+			},
+		},
+		{
+			name: "case 1: architecture aware catalogue",
+			data: `{"3510.2.0": {"eu-central-1": {"amd64": "ami-a", "arm64": "ami-b"}, "eu-west-1": "ami-c"}}`,
+			expected: map[string]map[string]architectureAMIs{
+				"3510.2.0": {
+					"eu-central-1": {ArchitectureAMD64: "ami-a", ArchitectureARM64: "ami-b"},
+					"eu-west-1":    {ArchitectureAMD64: "ami-c"},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var output map[string]map[string]architectureAMIs
+			err := json.Unmarshal([]byte(tc.data), &output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(output, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(output, tc.expected))
+			}
+		})
+	}
+}
+
+func mustParseCIDR(v string) net.IPNet {
+	_, n, err := net.ParseCIDR(v)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
@@ -2,16 +2,38 @@ package key
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/dylanmei/iso8601"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/k8smetadata/pkg/annotation"
 	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
 )
 
+// MachineDeploymentArchitecture returns the CPU architecture of the node pool's
+// instances, which is inferred from its instance type and the given launch
+// template overrides. Instance types of different architectures within one
+// node pool result in invalidParameterError.
+func MachineDeploymentArchitecture(cr infrastructurev1alpha3.AWSMachineDeployment, overrides []template.LaunchTemplateOverride) (string, error) {
+	architecture := InstanceTypeArchitecture(MachineDeploymentInstanceType(cr))
+
+	for _, o := range overrides {
+		if InstanceTypeArchitecture(o.InstanceType) != architecture {
+			return "", microerror.Maskf(invalidParameterError, "instance type %#q must have architecture %#q of instance type %#q", o.InstanceType, architecture, MachineDeploymentInstanceType(cr))
+		}
+	}
+
+	return architecture, nil
+}
+
 func MachineDeploymentAvailabilityZones(cr infrastructurev1alpha3.AWSMachineDeployment) []string {
 	return cr.Spec.Provider.AvailabilityZones
 }
@@ -61,6 +83,129 @@ func MachineDeploymentLoggingVolumeSizeGB(cr infrastructurev1alpha3.AWSMachineDe
 	return value
 }
 
+// MachineDeploymentInstanceRequirements returns the attributes of the instance
+// types the node pool's ASG may launch, configured via the
+// InstanceRequirements annotation on the AWSMachineDeployment CR in the format
+// "vcpu=4-16,memoryMiB=16384-,families=m5;m6i,generations=current,burstable=excluded,gpus=0".
+// Ranges are inclusive, an omitted max leaves them unbounded and a single
+// number sets min and max. The vcpu and memoryMiB ranges are required, while
+// families and excludedFamilies are mutually exclusive. It returns nil for
+// node pools without instance requirements. Instance requirements cannot be
+// used with warm pools and invalid requirements result in
+// invalidParameterError.
+func MachineDeploymentInstanceRequirements(cr infrastructurev1alpha3.AWSMachineDeployment) (*InstanceRequirements, error) {
+	a := awsoperatorannotation.InstanceRequirements
+
+	v, ok := cr.GetAnnotations()[a]
+	if !ok {
+		return nil, nil
+	}
+
+	r := &InstanceRequirements{
+		MemoryMiB: IntRange{Min: -1, Max: -1},
+		VCPUs:     IntRange{Min: -1, Max: -1},
+	}
+
+	for _, f := range strings.Split(v, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(f), "=")
+		if !ok {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain key=value pairs, got %#q", a, f)
+		}
+
+		switch k {
+		case "burstable":
+			if v != BurstablePerformanceExcluded && v != BurstablePerformanceIncluded && v != BurstablePerformanceRequired {
+				return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a burstable value of %#q, %#q or %#q, got %#q", a, BurstablePerformanceIncluded, BurstablePerformanceExcluded, BurstablePerformanceRequired, v)
+			}
+			r.Burstable = v
+		case "excludedFamilies", "families":
+			var families []string
+			for _, family := range strings.Split(v, ";") {
+				if !instanceFamilyRegexp.MatchString(family) {
+					return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain instance families like %#q, got %#q", a, "m5", family)
+				}
+				families = append(families, family)
+			}
+			if k == "families" {
+				r.AllowedFamilies = families
+			} else {
+				r.ExcludedFamilies = families
+			}
+		case "generations":
+			for _, g := range strings.Split(v, ";") {
+				if g != InstanceGenerationCurrent && g != InstanceGenerationPrevious {
+					return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain generations of %#q or %#q, got %#q", a, InstanceGenerationCurrent, InstanceGenerationPrevious, g)
+				}
+				r.Generations = append(r.Generations, g)
+			}
+		case "gpus":
+			g, err := parseIntRange(v)
+			if err != nil {
+				return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a gpus range like %#q, got %#q", a, "1-4", v)
+			}
+			r.GPUs = &g
+		case "memoryMiB":
+			m, err := parseIntRange(v)
+			if err != nil || m.Min < 1 {
+				return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a memoryMiB range like %#q, got %#q", a, "16384-32768", v)
+			}
+			r.MemoryMiB = m
+		case "vcpu":
+			c, err := parseIntRange(v)
+			if err != nil || c.Min < 1 {
+				return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a vcpu range like %#q, got %#q", a, "4-16", v)
+			}
+			r.VCPUs = c
+		default:
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not contain the unknown key %#q", a, k)
+		}
+	}
+
+	if r.VCPUs.Min == -1 || r.MemoryMiB.Min == -1 {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain the vcpu and memoryMiB ranges", a)
+	}
+	if len(r.AllowedFamilies) != 0 && len(r.ExcludedFamilies) != 0 {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not contain both families and excludedFamilies", a)
+	}
+	if cr.GetAnnotations()[awsoperatorannotation.WarmPool] == "true" {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not be used with annotation %#q", a, awsoperatorannotation.WarmPool)
+	}
+
+	return r, nil
+}
+
+// MachineDeploymentInstanceRequirementsValue returns the canonical
+// representation of the given instance requirements, e.g.
+// "vcpu=4-16,memoryMiB=16384-,families=m5;m6i,burstable=excluded", or
+// "disabled" for node pools without instance requirements.
+func MachineDeploymentInstanceRequirementsValue(r *InstanceRequirements) string {
+	if r == nil {
+		return InstanceRequirementsDisabled
+	}
+
+	fields := []string{
+		fmt.Sprintf("vcpu=%s", formatIntRange(r.VCPUs)),
+		fmt.Sprintf("memoryMiB=%s", formatIntRange(r.MemoryMiB)),
+	}
+	if len(r.AllowedFamilies) != 0 {
+		fields = append(fields, fmt.Sprintf("families=%s", strings.Join(r.AllowedFamilies, ";")))
+	}
+	if len(r.ExcludedFamilies) != 0 {
+		fields = append(fields, fmt.Sprintf("excludedFamilies=%s", strings.Join(r.ExcludedFamilies, ";")))
+	}
+	if len(r.Generations) != 0 {
+		fields = append(fields, fmt.Sprintf("generations=%s", strings.Join(r.Generations, ";")))
+	}
+	if r.Burstable != "" {
+		fields = append(fields, fmt.Sprintf("burstable=%s", r.Burstable))
+	}
+	if r.GPUs != nil {
+		fields = append(fields, fmt.Sprintf("gpus=%s", formatIntRange(*r.GPUs)))
+	}
+
+	return strings.Join(fields, ",")
+}
+
 func MachineDeploymentInstanceType(cr infrastructurev1alpha3.AWSMachineDeployment) string {
 	return cr.Spec.Provider.Worker.InstanceType
 }
@@ -157,6 +302,259 @@ func MachineDeploymentScalingMin(cr infrastructurev1alpha3.AWSMachineDeployment)
 	return cr.Spec.NodePool.Scaling.Min
 }
 
+// MachineDeploymentNodeConfig returns the node labels, taints and kubelet
+// settings of the node pool configured via annotations on the
+// AWSMachineDeployment CR or the given data of the ConfigMap the node pool
+// references, see MachineDeploymentNodeConfigMap. ConfigMap keys are the
+// annotation names without the "aws-operator.giantswarm.io/" prefix, e.g.
+// "node-taints". Annotations take precedence over the ConfigMap. Labels,
+// reserved resources and eviction thresholds
+// are configured as "key=value" pairs separated by commas, taints in the
+// kubelet's "key=value:Effect" format, image garbage collection thresholds as
+// "high=85,low=80" and max pods as number. Labels of Kubernetes and Giant
+// Swarm domains are reserved. Taints must have unique keys, because the
+// cluster autoscaler's node template tags are keyed by the taint key only.
+// Invalid settings result in invalidParameterError.
+func MachineDeploymentNodeConfig(cr infrastructurev1alpha3.AWSMachineDeployment, data map[string]string) (NodeConfig, error) {
+	var err error
+	var c NodeConfig
+
+	setting := func(name string) (string, bool) {
+		v, ok := cr.GetAnnotations()[name]
+		if ok {
+			return v, true
+		}
+		v, ok = data[strings.TrimPrefix(name, nodeConfigMapKeyPrefix)]
+		return v, ok
+	}
+
+	if v, ok := setting(awsoperatorannotation.KubeletEvictionHard); ok {
+		c.EvictionHard, err = parseKeyValuePairs(awsoperatorannotation.KubeletEvictionHard, v, validateEvictionThreshold)
+		if err != nil {
+			return NodeConfig{}, microerror.Mask(err)
+		}
+	}
+	if v, ok := setting(awsoperatorannotation.KubeletImageGCThresholds); ok {
+		t, err := parseKeyValuePairs(awsoperatorannotation.KubeletImageGCThresholds, v, validateImageGCThreshold)
+		if err != nil {
+			return NodeConfig{}, microerror.Mask(err)
+		}
+		high, err := strconv.Atoi(t["high"])
+		if err != nil {
+			return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must contain the high threshold", awsoperatorannotation.KubeletImageGCThresholds)
+		}
+		low, err := strconv.Atoi(t["low"])
+		if err != nil {
+			return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must contain the low threshold", awsoperatorannotation.KubeletImageGCThresholds)
+		}
+		if low >= high {
+			return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must contain a low threshold smaller than the high threshold", awsoperatorannotation.KubeletImageGCThresholds)
+		}
+		c.ImageGCHighThreshold = high
+		c.ImageGCLowThreshold = low
+	}
+	if v, ok := setting(awsoperatorannotation.KubeletKubeReserved); ok {
+		c.KubeReserved, err = parseKeyValuePairs(awsoperatorannotation.KubeletKubeReserved, v, validateReservedResource)
+		if err != nil {
+			return NodeConfig{}, microerror.Mask(err)
+		}
+	}
+	if v, ok := setting(awsoperatorannotation.KubeletMaxPods); ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must be a positive number, got %#q", awsoperatorannotation.KubeletMaxPods, v)
+		}
+		c.MaxPods = i
+	}
+	if v, ok := setting(awsoperatorannotation.KubeletSystemReserved); ok {
+		c.SystemReserved, err = parseKeyValuePairs(awsoperatorannotation.KubeletSystemReserved, v, validateReservedResource)
+		if err != nil {
+			return NodeConfig{}, microerror.Mask(err)
+		}
+	}
+	if v, ok := setting(awsoperatorannotation.NodeLabels); ok {
+		c.Labels, err = parseKeyValuePairs(awsoperatorannotation.NodeLabels, v, validateNodeLabel)
+		if err != nil {
+			return NodeConfig{}, microerror.Mask(err)
+		}
+	}
+	if v, ok := setting(awsoperatorannotation.NodeTaints); ok {
+		seen := map[string]bool{}
+		for _, f := range strings.Split(v, ",") {
+			t, err := parseTaint(strings.TrimSpace(f))
+			if err != nil {
+				return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must contain taints like %#q, got %#q", awsoperatorannotation.NodeTaints, "key=value:NoSchedule", f)
+			}
+			if seen[t.Key] {
+				return NodeConfig{}, microerror.Maskf(invalidParameterError, "annotation %#q must not contain multiple taints with key %#q", awsoperatorannotation.NodeTaints, t.Key)
+			}
+			seen[t.Key] = true
+			c.Taints = append(c.Taints, t)
+		}
+	}
+
+	return c, nil
+}
+
+// nodeConfigMapKeyPrefix is trimmed from node config annotation names to get
+// the keys of the node config in the ConfigMap referenced by a node pool.
+const nodeConfigMapKeyPrefix = "aws-operator.giantswarm.io/"
+
+// MachineDeploymentNodeConfigMap returns the name of the ConfigMap in the
+// namespace of the AWSMachineDeployment CR holding the node pool's node
+// config, see MachineDeploymentNodeConfig.
+func MachineDeploymentNodeConfigMap(cr infrastructurev1alpha3.AWSMachineDeployment) string {
+	return cr.GetAnnotations()[awsoperatorannotation.NodeConfigMap]
+}
+
+// MachineDeploymentNodeConfigValue returns the canonical representation of the
+// given node config, e.g. "labels=team=a;tier=b,maxPods=50,taints=dedicated=a:NoSchedule",
+// or "default" for node pools without node config.
+func MachineDeploymentNodeConfigValue(c NodeConfig) string {
+	var fields []string
+	if len(c.EvictionHard) != 0 {
+		fields = append(fields, fmt.Sprintf("evictionHard=%s", formatKeyValuePairs(c.EvictionHard)))
+	}
+	if c.ImageGCHighThreshold != 0 {
+		fields = append(fields, fmt.Sprintf("imageGC=%d-%d", c.ImageGCLowThreshold, c.ImageGCHighThreshold))
+	}
+	if len(c.KubeReserved) != 0 {
+		fields = append(fields, fmt.Sprintf("kubeReserved=%s", formatKeyValuePairs(c.KubeReserved)))
+	}
+	if len(c.Labels) != 0 {
+		fields = append(fields, fmt.Sprintf("labels=%s", formatKeyValuePairs(c.Labels)))
+	}
+	if c.MaxPods != 0 {
+		fields = append(fields, fmt.Sprintf("maxPods=%d", c.MaxPods))
+	}
+	if len(c.SystemReserved) != 0 {
+		fields = append(fields, fmt.Sprintf("systemReserved=%s", formatKeyValuePairs(c.SystemReserved)))
+	}
+	if len(c.Taints) != 0 {
+		var taints []string
+		for _, t := range c.Taints {
+			taints = append(taints, TaintValue(t))
+		}
+		fields = append(fields, fmt.Sprintf("taints=%s", strings.Join(taints, ";")))
+	}
+
+	if len(fields) == 0 {
+		return NodeConfigDefault
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// MachineDeploymentPrioritizedOverrides returns the given launch template
+// overrides with the node pool's instance type first, so that the prioritized
+// on-demand allocation strategy prefers the configured instance type over
+// alike instance types.
+func MachineDeploymentPrioritizedOverrides(cr infrastructurev1alpha3.AWSMachineDeployment, overrides []template.LaunchTemplateOverride) []template.LaunchTemplateOverride {
+	var prioritized []template.LaunchTemplateOverride
+	var others []template.LaunchTemplateOverride
+	for _, o := range overrides {
+		if o.InstanceType == MachineDeploymentInstanceType(cr) {
+			prioritized = append(prioritized, o)
+		} else {
+			others = append(others, o)
+		}
+	}
+
+	return append(prioritized, others...)
+}
+
+// MachineDeploymentSpotAllocation returns the allocation settings of the node
+// pool's ASG configured via annotations on the AWSMachineDeployment CR. Without
+// annotations spot instances are allocated from the lowest priced pools and
+// on-demand instances in the order of the launch template overrides, or from
+// the lowest priced instance types matching the node pool's instance
+// requirements. Spot
+// settings require spot instances and cannot be used with warm pools. Invalid
+// settings result in invalidParameterError.
+func MachineDeploymentSpotAllocation(cr infrastructurev1alpha3.AWSMachineDeployment) (SpotAllocation, error) {
+	a := SpotAllocation{
+		CapacityRebalance:          false,
+		OnDemandAllocationStrategy: OnDemandAllocationStrategyPrioritized,
+		SpotAllocationStrategy:     SpotAllocationStrategyLowestPrice,
+		SpotMaxPrice:               "",
+	}
+
+	// Instance requirements do not define an order of instance types to
+	// prioritize.
+	_, instanceRequirements := cr.GetAnnotations()[awsoperatorannotation.InstanceRequirements]
+	if instanceRequirements {
+		a.OnDemandAllocationStrategy = OnDemandAllocationStrategyLowestPrice
+	}
+
+	var spotSettings []string
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.OnDemandAllocation]; ok {
+		if v != OnDemandAllocationStrategyLowestPrice && v != OnDemandAllocationStrategyPrioritized {
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotation %#q must be %#q or %#q, got %#q", awsoperatorannotation.OnDemandAllocation, OnDemandAllocationStrategyPrioritized, OnDemandAllocationStrategyLowestPrice, v)
+		}
+		a.OnDemandAllocationStrategy = v
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.SpotAllocation]; ok {
+		switch v {
+		case SpotAllocationStrategyCapacityOptimized, SpotAllocationStrategyCapacityOptimizedPrioritized, SpotAllocationStrategyLowestPrice, SpotAllocationStrategyPriceCapacityOptimized:
+		default:
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotation %#q must be one of %#q, %#q, %#q or %#q, got %#q", awsoperatorannotation.SpotAllocation, SpotAllocationStrategyLowestPrice, SpotAllocationStrategyCapacityOptimized, SpotAllocationStrategyCapacityOptimizedPrioritized, SpotAllocationStrategyPriceCapacityOptimized, v)
+		}
+		a.SpotAllocationStrategy = v
+		spotSettings = append(spotSettings, awsoperatorannotation.SpotAllocation)
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.SpotCapacityRebalance]; ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotation %#q must be %#q or %#q, got %#q", awsoperatorannotation.SpotCapacityRebalance, "true", "false", v)
+		}
+		a.CapacityRebalance = b
+		if b {
+			spotSettings = append(spotSettings, awsoperatorannotation.SpotCapacityRebalance)
+		}
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.SpotMaxPrice]; ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotation %#q must be a positive price in USD per hour, got %#q", awsoperatorannotation.SpotMaxPrice, v)
+		}
+		a.SpotMaxPrice = strconv.FormatFloat(f, 'f', -1, 64)
+		spotSettings = append(spotSettings, awsoperatorannotation.SpotMaxPrice)
+	}
+
+	if a.SpotAllocationStrategy == SpotAllocationStrategyCapacityOptimizedPrioritized && a.OnDemandAllocationStrategy != OnDemandAllocationStrategyPrioritized {
+		return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotation %#q %#q requires annotation %#q %#q", awsoperatorannotation.SpotAllocation, a.SpotAllocationStrategy, awsoperatorannotation.OnDemandAllocation, OnDemandAllocationStrategyPrioritized)
+	}
+
+	if instanceRequirements && (a.OnDemandAllocationStrategy == OnDemandAllocationStrategyPrioritized || a.SpotAllocationStrategy == SpotAllocationStrategyCapacityOptimizedPrioritized) {
+		return SpotAllocation{}, microerror.Maskf(invalidParameterError, "prioritized allocation strategies must not be used with annotation %#q", awsoperatorannotation.InstanceRequirements)
+	}
+
+	if len(spotSettings) != 0 {
+		p := cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity
+		if p == nil || *p >= 100 {
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotations %s require spot instances", strings.Join(spotSettings, ", "))
+		}
+		if cr.GetAnnotations()[awsoperatorannotation.WarmPool] == "true" {
+			return SpotAllocation{}, microerror.Maskf(invalidParameterError, "annotations %s must not be used with annotation %#q", strings.Join(spotSettings, ", "), awsoperatorannotation.WarmPool)
+		}
+	}
+
+	return a, nil
+}
+
+// MachineDeploymentSpotAllocationValue returns the canonical representation of
+// the given allocation settings, e.g.
+// "capacityRebalance=true,onDemandAllocationStrategy=prioritized,spotAllocationStrategy=price-capacity-optimized,spotMaxPrice=0.5".
+// The spot max price is omitted when it is not set.
+func MachineDeploymentSpotAllocationValue(a SpotAllocation) string {
+	v := fmt.Sprintf("capacityRebalance=%t,onDemandAllocationStrategy=%s,spotAllocationStrategy=%s", a.CapacityRebalance, a.OnDemandAllocationStrategy, a.SpotAllocationStrategy)
+	if a.SpotMaxPrice != "" {
+		v += fmt.Sprintf(",spotMaxPrice=%s", a.SpotMaxPrice)
+	}
+
+	return v
+}
+
 // MachineDeploymentSpotInstancePools ensures that the number of spot instance pools
 // we submit to AWS is within AWS limits of more than 0 and less than 21 pools.
 func MachineDeploymentSpotInstancePools(overrides []template.LaunchTemplateOverride) int {
@@ -170,6 +568,14 @@ func MachineDeploymentSpotInstancePools(overrides []template.LaunchTemplateOverr
 	return pools
 }
 
+// MachineDeploymentIPv6NetworkIndex returns the index of the range of IPv6
+// CIDR blocks allocated to the node pool, see IPv6SubnetIndex. The index is
+// allocated by the tcnpazs resource once the VPC is dual-stack and persisted in
+// an annotation, because node pools live in separate stacks of the same VPC.
+func MachineDeploymentIPv6NetworkIndex(cr infrastructurev1alpha3.AWSMachineDeployment) string {
+	return cr.Annotations[awsoperatorannotation.IPv6NetworkIndex]
+}
+
 func MachineDeploymentSubnet(cr infrastructurev1alpha3.AWSMachineDeployment) string {
 	return cr.Annotations[annotation.MachineDeploymentSubnet]
 }
@@ -182,6 +588,193 @@ func MachineDeploymentOnDemandPercentageAboveBaseCapacity(cr infrastructurev1alp
 	return *cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity
 }
 
+// MachineDeploymentUpdateStrategy returns the strategy used to replace the
+// instances of the node pool on updates. The strategy configured on the
+// AWSMachineDeployment CR takes precedence over the one configured on the
+// AWSCluster CR. Unknown strategies fall back to UpdateStrategyCloudFormation.
+func MachineDeploymentUpdateStrategy(cl infrastructurev1alpha3.AWSCluster, cr infrastructurev1alpha3.AWSMachineDeployment) string {
+	v, ok := cr.GetAnnotations()[awsoperatorannotation.UpdateStrategy]
+	if !ok {
+		v = cl.GetAnnotations()[awsoperatorannotation.UpdateStrategy]
+	}
+
+	if v == UpdateStrategyOperator {
+		return UpdateStrategyOperator
+	}
+
+	return UpdateStrategyCloudFormation
+}
+
+// MachineDeploymentVolumeProfiles returns the EBS volume profiles of the node
+// pool's containerd, docker, kubelet and logging volumes keyed by volume
+// device. Profiles are configured via annotations on the AWSMachineDeployment
+// CR in the format "type=io2,iops=10000,throughput=250". The KMS key is
+// configured per cluster via the VolumeKMSKeyARN annotation on the AWSCluster
+// CR and applies to all volumes of all node pools. Invalid profiles result in
+// invalidParameterError.
+func MachineDeploymentVolumeProfiles(cl infrastructurev1alpha3.AWSCluster, cr infrastructurev1alpha3.AWSMachineDeployment) (map[string]VolumeProfile, error) {
+	if _, ok := cr.GetAnnotations()[awsoperatorannotation.VolumeKMSKeyARN]; ok {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be set on the AWSCluster CR", awsoperatorannotation.VolumeKMSKeyARN)
+	}
+
+	kmsKeyARN := cl.GetAnnotations()[awsoperatorannotation.VolumeKMSKeyARN]
+	if kmsKeyARN != "" && !kmsKeyARNRegexp.MatchString(kmsKeyARN) {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be a KMS key ARN, got %#q", awsoperatorannotation.VolumeKMSKeyARN, kmsKeyARN)
+	}
+
+	profiles := map[string]VolumeProfile{}
+	for _, device := range volumeDevices {
+		a := volumeProfileAnnotations[device]
+
+		p := VolumeProfile{
+			KMSKeyARN: kmsKeyARN,
+			Type:      VolumeTypeDefault,
+		}
+
+		v, ok := cr.GetAnnotations()[a]
+		if ok {
+			for _, f := range strings.Split(v, ",") {
+				k, v, ok := strings.Cut(strings.TrimSpace(f), "=")
+				if !ok {
+					return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain key=value pairs, got %#q", a, f)
+				}
+
+				switch k {
+				case "iops":
+					i, err := strconv.Atoi(v)
+					if err != nil {
+						return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a numeric iops value, got %#q", a, v)
+					}
+					p.IOPS = i
+				case "kmsKeyARN":
+					return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not contain a KMS key, which is configured per cluster via annotation %#q on the AWSCluster CR", a, awsoperatorannotation.VolumeKMSKeyARN)
+				case "throughput":
+					i, err := strconv.Atoi(v)
+					if err != nil {
+						return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain a numeric throughput value, got %#q", a, v)
+					}
+					p.Throughput = i
+				case "type":
+					p.Type = v
+				default:
+					return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not contain the unknown key %#q", a, k)
+				}
+			}
+		}
+
+		err := validateVolumeProfile(a, p)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		profiles[device] = p
+	}
+
+	return profiles, nil
+}
+
+// MachineDeploymentVolumeProfilesValue returns the canonical representation of
+// the given volume profiles, e.g. "containerd:type=gp3;docker:type=io2,iops=10000;...".
+// Volumes without profile are represented by their default profile.
+func MachineDeploymentVolumeProfilesValue(profiles map[string]VolumeProfile) string {
+	var devices []string
+	for _, d := range volumeDevices {
+		p, ok := profiles[d]
+		if !ok {
+			p = VolumeProfile{Type: VolumeTypeDefault}
+		}
+
+		fields := []string{"type=" + p.Type}
+		if p.IOPS != 0 {
+			fields = append(fields, fmt.Sprintf("iops=%d", p.IOPS))
+		}
+		if p.Throughput != 0 {
+			fields = append(fields, fmt.Sprintf("throughput=%d", p.Throughput))
+		}
+		if p.KMSKeyARN != "" {
+			fields = append(fields, "kmsKeyARN="+p.KMSKeyARN)
+		}
+
+		devices = append(devices, d+":"+strings.Join(fields, ","))
+	}
+
+	return strings.Join(devices, ";")
+}
+
+// MachineDeploymentWarmPool returns the warm pool of the node pool's ASG, which
+// is enabled via the WarmPool annotation on the AWSMachineDeployment CR. It
+// returns nil for node pools without warm pool. Warm pools cannot be used with
+// spot instances and invalid settings result in invalidParameterError.
+func MachineDeploymentWarmPool(cr infrastructurev1alpha3.AWSMachineDeployment) (*WarmPool, error) {
+	if cr.GetAnnotations()[awsoperatorannotation.WarmPool] != "true" {
+		return nil, nil
+	}
+
+	w := &WarmPool{
+		MaxPreparedCapacity: -1,
+		MinSize:             0,
+		State:               WarmPoolStateStopped,
+	}
+
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.WarmPoolMaxPrepared]; ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < -1 {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be -1 or a positive number, got %#q", awsoperatorannotation.WarmPoolMaxPrepared, v)
+		}
+		w.MaxPreparedCapacity = i
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.WarmPoolMinSize]; ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be a positive number, got %#q", awsoperatorannotation.WarmPoolMinSize, v)
+		}
+		w.MinSize = i
+	}
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.WarmPoolState]; ok {
+		if v != WarmPoolStateHibernated && v != WarmPoolStateStopped {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must be %#q or %#q, got %#q", awsoperatorannotation.WarmPoolState, WarmPoolStateStopped, WarmPoolStateHibernated, v)
+		}
+		w.State = v
+	}
+
+	if w.MaxPreparedCapacity != -1 && w.MaxPreparedCapacity < w.MinSize {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not be smaller than annotation %#q", awsoperatorannotation.WarmPoolMaxPrepared, awsoperatorannotation.WarmPoolMinSize)
+	}
+
+	// Warm pools cannot be used with mixed instances policies, so that all the
+	// settings of the mixed instances policy are rejected instead of being
+	// dropped silently.
+	p := cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity
+	if p != nil && *p < 100 {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not be used with spot instances", awsoperatorannotation.WarmPool)
+	}
+	if cr.Spec.Provider.Worker.UseAlikeInstanceTypes {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not be used with alike instance types", awsoperatorannotation.WarmPool)
+	}
+	for _, a := range []string{awsoperatorannotation.InstanceRequirements, awsoperatorannotation.OnDemandAllocation, awsoperatorannotation.SpotAllocation, awsoperatorannotation.SpotCapacityRebalance, awsoperatorannotation.SpotMaxPrice} {
+		if _, ok := cr.GetAnnotations()[a]; ok {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must not be used with annotation %#q", a, awsoperatorannotation.WarmPool)
+		}
+	}
+
+	if w.State == WarmPoolStateHibernated && !InstanceTypeHibernationSupported(MachineDeploymentInstanceType(cr)) {
+		return nil, microerror.Maskf(invalidParameterError, "annotation %#q %#q must not be used with instance type %#q, which cannot hibernate", awsoperatorannotation.WarmPoolState, WarmPoolStateHibernated, MachineDeploymentInstanceType(cr))
+	}
+
+	return w, nil
+}
+
+// MachineDeploymentWarmPoolValue returns the canonical representation of the
+// given warm pool, e.g. "maxPreparedCapacity=-1,minSize=2,state=Stopped", or
+// "disabled" for node pools without warm pool.
+func MachineDeploymentWarmPoolValue(w *WarmPool) string {
+	if w == nil {
+		return WarmPoolDisabled
+	}
+
+	return fmt.Sprintf("maxPreparedCapacity=%d,minSize=%d,state=%s", w.MaxPreparedCapacity, w.MinSize, w.State)
+}
+
 func MachineDeploymentWorkerCountRatio(workers int, ratio float32) string {
 	value := float32(workers) * ratio
 	rounded := int(value + 0.5)
@@ -211,3 +804,230 @@ func ToMachineDeployment(v interface{}) (infrastructurev1alpha3.AWSMachineDeploy
 
 	return *c, nil
 }
+
+var (
+	instanceFamilyRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+	kmsKeyARNRegexp = regexp.MustCompile(`^arn:aws[a-z-]*:kms:[a-z0-9-]+:[0-9]{12}:key/[a-zA-Z0-9-]+$`)
+
+	volumeDevices = []string{
+		VolumeDeviceContainerd,
+		VolumeDeviceDocker,
+		VolumeDeviceKubelet,
+		VolumeDeviceLogging,
+	}
+
+	volumeProfileAnnotations = map[string]string{
+		VolumeDeviceContainerd: awsoperatorannotation.VolumeProfileContainerd,
+		VolumeDeviceDocker:     awsoperatorannotation.VolumeProfileDocker,
+		VolumeDeviceKubelet:    awsoperatorannotation.VolumeProfileKubelet,
+		VolumeDeviceLogging:    awsoperatorannotation.VolumeProfileLogging,
+	}
+)
+
+// validateVolumeProfile checks the given profile against the limits of its EBS
+// volume type. IOPS are only configurable for gp3, io1 and io2 volumes and
+// required for the latter. Throughput is only configurable for gp3 volumes.
+func validateVolumeProfile(a string, p VolumeProfile) error {
+	var minIOPS, maxIOPS int
+	switch p.Type {
+	case "gp3":
+		minIOPS, maxIOPS = 3000, 16000
+	case "io1":
+		minIOPS, maxIOPS = 100, 64000
+	case "io2":
+		minIOPS, maxIOPS = 100, 256000
+	case "gp2", "sc1", "st1", "standard":
+	default:
+		return microerror.Maskf(invalidParameterError, "annotation %#q must contain a valid volume type, got %#q", a, p.Type)
+	}
+
+	if maxIOPS == 0 && p.IOPS != 0 {
+		return microerror.Maskf(invalidParameterError, "annotation %#q must not configure iops for volume type %#q", a, p.Type)
+	}
+	if (p.Type == "io1" || p.Type == "io2") && p.IOPS == 0 {
+		return microerror.Maskf(invalidParameterError, "annotation %#q must configure iops for volume type %#q", a, p.Type)
+	}
+	if p.IOPS != 0 && (p.IOPS < minIOPS || p.IOPS > maxIOPS) {
+		return microerror.Maskf(invalidParameterError, "annotation %#q must configure iops between %d and %d for volume type %#q, got %d", a, minIOPS, maxIOPS, p.Type, p.IOPS)
+	}
+
+	if p.Type != "gp3" && p.Throughput != 0 {
+		return microerror.Maskf(invalidParameterError, "annotation %#q must not configure throughput for volume type %#q", a, p.Type)
+	}
+	if p.Throughput != 0 && (p.Throughput < 125 || p.Throughput > 1000) {
+		return microerror.Maskf(invalidParameterError, "annotation %#q must configure throughput between 125 and 1000, got %d", a, p.Throughput)
+	}
+
+	return nil
+}
+
+// formatIntRange returns the given range in the format parsed by
+// parseIntRange.
+func formatIntRange(r IntRange) string {
+	if r.Max == -1 {
+		return fmt.Sprintf("%d-", r.Min)
+	}
+	if r.Min == r.Max {
+		return strconv.Itoa(r.Min)
+	}
+
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// parseIntRange parses inclusive ranges like "4-16", open ranges like "4-"
+// and single numbers like "4".
+func parseIntRange(v string) (IntRange, error) {
+	lowerValue, upperValue, ok := strings.Cut(v, "-")
+	if !ok {
+		upperValue = lowerValue
+	}
+
+	lower, err := strconv.Atoi(lowerValue)
+	if err != nil || lower < 0 {
+		return IntRange{}, microerror.Maskf(invalidParameterError, "range minimum must be a positive number, got %#q", lowerValue)
+	}
+
+	upper := -1
+	if upperValue != "" {
+		upper, err = strconv.Atoi(upperValue)
+		if err != nil || upper < lower {
+			return IntRange{}, microerror.Maskf(invalidParameterError, "range maximum must be a number not smaller than %d, got %#q", lower, upperValue)
+		}
+	}
+
+	return IntRange{Min: lower, Max: upper}, nil
+}
+
+// formatKeyValuePairs returns the given pairs sorted by key and separated by
+// semicolons.
+func formatKeyValuePairs(pairs map[string]string) string {
+	var keys []string
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var formatted []string
+	for _, k := range keys {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", k, pairs[k]))
+	}
+
+	return strings.Join(formatted, ";")
+}
+
+// parseKeyValuePairs parses the comma separated key=value pairs of the given
+// annotation and validates every pair with the given function.
+func parseKeyValuePairs(annotation string, v string, validate func(k string, v string) error) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, f := range strings.Split(v, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(f), "=")
+		if !ok {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain key=value pairs, got %#q", annotation, f)
+		}
+
+		err := validate(k, v)
+		if err != nil {
+			return nil, microerror.Maskf(invalidParameterError, "annotation %#q must contain valid pairs, got %#q: %s", annotation, f, err)
+		}
+
+		pairs[k] = v
+	}
+
+	return pairs, nil
+}
+
+// parseTaint parses taints in the format "key=value:Effect" or "key:Effect".
+func parseTaint(v string) (Taint, error) {
+	kv, effect, ok := strings.Cut(v, ":")
+	if !ok {
+		return Taint{}, microerror.Maskf(invalidParameterError, "taint must contain an effect")
+	}
+	k, value, _ := strings.Cut(kv, "=")
+
+	if effect != TaintEffectNoExecute && effect != TaintEffectNoSchedule && effect != TaintEffectPreferNoSchedule {
+		return Taint{}, microerror.Maskf(invalidParameterError, "taint effect must be %#q, %#q or %#q", TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute)
+	}
+	if len(validation.IsQualifiedName(k)) != 0 {
+		return Taint{}, microerror.Maskf(invalidParameterError, "taint key must be a qualified name")
+	}
+	if len(validation.IsValidLabelValue(value)) != 0 {
+		return Taint{}, microerror.Maskf(invalidParameterError, "taint value must be a valid label value")
+	}
+
+	return Taint{Effect: effect, Key: k, Value: value}, nil
+}
+
+func validateEvictionThreshold(k string, v string) error {
+	switch k {
+	case "imagefs.available", "imagefs.inodesFree", "memory.available", "nodefs.available", "nodefs.inodesFree", "pid.available":
+	default:
+		return microerror.Maskf(invalidParameterError, "unknown eviction signal %#q", k)
+	}
+
+	if p, ok := strings.CutSuffix(v, "%"); ok {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil || f < 0 || f > 100 {
+			return microerror.Maskf(invalidParameterError, "eviction threshold percentage must be between 0 and 100")
+		}
+		return nil
+	}
+
+	_, err := resource.ParseQuantity(v)
+	if err != nil {
+		return microerror.Maskf(invalidParameterError, "eviction threshold must be a quantity or percentage")
+	}
+
+	return nil
+}
+
+func validateImageGCThreshold(k string, v string) error {
+	if k != "high" && k != "low" {
+		return microerror.Maskf(invalidParameterError, "unknown image garbage collection threshold %#q", k)
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 || i > 100 {
+		return microerror.Maskf(invalidParameterError, "image garbage collection threshold must be a percentage between 0 and 100")
+	}
+
+	return nil
+}
+
+// validateNodeLabel rejects labels kubelets are not allowed to set by the
+// NodeRestriction admission plugin, as well as labels of Giant Swarm domains,
+// which are managed by the operator.
+func validateNodeLabel(k string, v string) error {
+	if len(validation.IsQualifiedName(k)) != 0 {
+		return microerror.Maskf(invalidParameterError, "label key must be a qualified name")
+	}
+	if len(validation.IsValidLabelValue(v)) != 0 {
+		return microerror.Maskf(invalidParameterError, "label value must be a valid label value")
+	}
+
+	domain, _, ok := strings.Cut(k, "/")
+	if ok && domain != "node.kubernetes.io" {
+		for _, reserved := range []string{"giantswarm.io", "k8s.io", "kubernetes.io"} {
+			if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+				return microerror.Maskf(invalidParameterError, "label key must not use the reserved domain %#q", reserved)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateReservedResource(k string, v string) error {
+	switch k {
+	case "cpu", "ephemeral-storage", "memory", "pid":
+	default:
+		return microerror.Maskf(invalidParameterError, "unknown reserved resource %#q", k)
+	}
+
+	_, err := resource.ParseQuantity(v)
+	if err != nil {
+		return microerror.Maskf(invalidParameterError, "reserved resource must be a quantity")
+	}
+
+	return nil
+}
@@ -1,8 +1,15 @@
 package key
 
 import (
+	"reflect"
 	"strconv"
 	"testing"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
 )
 
 func Test_MachineDeploymentParseMaxBatchSize(t *testing.T) {
@@ -188,3 +195,713 @@ func Test_MachineDeploymentWorkerCountRatio(t *testing.T) {
 		})
 	}
 }
+
+func Test_MachineDeploymentUpdateStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cluster  map[string]string
+		nodePool map[string]string
+		expected string
+	}{
+		{
+			name:     "case 0: default",
+			expected: UpdateStrategyCloudFormation,
+		},
+		{
+			name:     "case 1: set on cluster",
+			cluster:  map[string]string{annotation.UpdateStrategy: UpdateStrategyOperator},
+			expected: UpdateStrategyOperator,
+		},
+		{
+			name:     "case 2: overridden on node pool",
+			cluster:  map[string]string{annotation.UpdateStrategy: UpdateStrategyOperator},
+			nodePool: map[string]string{annotation.UpdateStrategy: UpdateStrategyCloudFormation},
+			expected: UpdateStrategyCloudFormation,
+		},
+		{
+			name:     "case 3: invalid value",
+			nodePool: map[string]string{annotation.UpdateStrategy: "foo"},
+			expected: UpdateStrategyCloudFormation,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cl := infrastructurev1alpha3.AWSCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tc.cluster}}
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.nodePool}}
+
+			output := MachineDeploymentUpdateStrategy(cl, cr)
+
+			if output != tc.expected {
+				t.Fatalf("%s -  expected '%s' got '%s'\n", tc.name, tc.expected, output)
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentVolumeProfiles(t *testing.T) {
+	kmsKeyARN := "arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	testCases := []struct {
+		name         string
+		cluster      map[string]string
+		annotations  map[string]string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: default profiles",
+			expected: "containerd:type=gp3;docker:type=gp3;kubelet:type=gp3;logging:type=gp3",
+		},
+		{
+			name: "case 1: custom profiles",
+			annotations: map[string]string{
+				annotation.VolumeProfileContainerd: "type=io2,iops=20000",
+				annotation.VolumeProfileDocker:     "type=gp3, iops=4000, throughput=250",
+				annotation.VolumeProfileLogging:    "type=st1",
+			},
+			expected: "containerd:type=io2,iops=20000;docker:type=gp3,iops=4000,throughput=250;kubelet:type=gp3;logging:type=st1",
+		},
+		{
+			name: "case 2: cluster KMS key applies to all volumes",
+			cluster: map[string]string{
+				annotation.VolumeKMSKeyARN: kmsKeyARN,
+			},
+			annotations: map[string]string{
+				annotation.VolumeProfileContainerd: "type=gp3",
+				annotation.VolumeProfileLogging:    "type=gp2",
+			},
+			expected: "containerd:type=gp3,kmsKeyARN=" + kmsKeyARN + ";docker:type=gp3,kmsKeyARN=" + kmsKeyARN + ";kubelet:type=gp3,kmsKeyARN=" + kmsKeyARN + ";logging:type=gp2,kmsKeyARN=" + kmsKeyARN,
+		},
+		{
+			name:         "case 3: unknown volume type",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "type=gp4"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 4: io2 without iops",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "type=io2"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 5: gp3 iops out of range",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "iops=20000"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 6: throughput for io1",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "type=io1,iops=1000,throughput=250"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 7: iops for st1",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "type=st1,iops=1000"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 8: invalid KMS key ARN",
+			cluster:      map[string]string{annotation.VolumeKMSKeyARN: "alias/foo"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 9: unknown key",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "type=gp3,size=100"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 10: malformed profile",
+			annotations:  map[string]string{annotation.VolumeProfileDocker: "gp3"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 11: KMS key on the node pool",
+			annotations:  map[string]string{annotation.VolumeKMSKeyARN: kmsKeyARN},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 12: KMS key per volume",
+			annotations:  map[string]string{annotation.VolumeProfileKubelet: "kmsKeyARN=" + kmsKeyARN},
+			errorMatcher: IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cl := infrastructurev1alpha3.AWSCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tc.cluster}}
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			profiles, err := MachineDeploymentVolumeProfiles(cl, cr)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			output := MachineDeploymentVolumeProfilesValue(profiles)
+			if output != tc.expected {
+				t.Fatalf("%s -  expected '%s' got '%s'\n", tc.name, tc.expected, output)
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentWarmPool(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		annotations               map[string]string
+		instanceType              string
+		onDemandAboveBaseCapacity int
+		useAlikeInstanceTypes     bool
+		expected                  *WarmPool
+		errorMatcher              func(error) bool
+	}{
+		{
+			name:                      "case 0: warm pool disabled",
+			onDemandAboveBaseCapacity: 0,
+			expected:                  nil,
+		},
+		{
+			name:                      "case 1: warm pool defaults",
+			annotations:               map[string]string{annotation.WarmPool: "true"},
+			onDemandAboveBaseCapacity: 100,
+			expected:                  &WarmPool{MaxPreparedCapacity: -1, MinSize: 0, State: WarmPoolStateStopped},
+		},
+		{
+			name: "case 2: warm pool configured",
+			annotations: map[string]string{
+				annotation.WarmPool:            "true",
+				annotation.WarmPoolMaxPrepared: "5",
+				annotation.WarmPoolMinSize:     "2",
+				annotation.WarmPoolState:       WarmPoolStateHibernated,
+			},
+			instanceType:              "m5.xlarge",
+			onDemandAboveBaseCapacity: 100,
+			expected:                  &WarmPool{MaxPreparedCapacity: 5, MinSize: 2, State: WarmPoolStateHibernated},
+		},
+		{
+			name: "case 3: invalid state",
+			annotations: map[string]string{
+				annotation.WarmPool:      "true",
+				annotation.WarmPoolState: "Running",
+			},
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 4: max prepared capacity smaller than min size",
+			annotations: map[string]string{
+				annotation.WarmPool:            "true",
+				annotation.WarmPoolMaxPrepared: "1",
+				annotation.WarmPoolMinSize:     "2",
+			},
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 5: negative min size",
+			annotations: map[string]string{
+				annotation.WarmPool:        "true",
+				annotation.WarmPoolMinSize: "-1",
+			},
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 6: spot instances",
+			annotations:               map[string]string{annotation.WarmPool: "true"},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 7: alike instance types",
+			annotations:               map[string]string{annotation.WarmPool: "true"},
+			onDemandAboveBaseCapacity: 100,
+			useAlikeInstanceTypes:     true,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 8: on-demand allocation strategy",
+			annotations: map[string]string{
+				annotation.OnDemandAllocation: OnDemandAllocationStrategyLowestPrice,
+				annotation.WarmPool:           "true",
+			},
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 9: hibernation with instance family unable to hibernate",
+			annotations: map[string]string{
+				annotation.WarmPool:      "true",
+				annotation.WarmPoolState: WarmPoolStateHibernated,
+			},
+			instanceType:              "m6g.xlarge",
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 10: hibernation with bare metal instance type",
+			annotations: map[string]string{
+				annotation.WarmPool:      "true",
+				annotation.WarmPoolState: WarmPoolStateHibernated,
+			},
+			instanceType:              "m5.metal",
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 11: stopped instances of any instance type",
+			annotations:               map[string]string{annotation.WarmPool: "true"},
+			instanceType:              "m6g.xlarge",
+			onDemandAboveBaseCapacity: 100,
+			expected:                  &WarmPool{MaxPreparedCapacity: -1, MinSize: 0, State: WarmPoolStateStopped},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity = &tc.onDemandAboveBaseCapacity
+			cr.Spec.Provider.Worker.InstanceType = tc.instanceType
+			cr.Spec.Provider.Worker.UseAlikeInstanceTypes = tc.useAlikeInstanceTypes
+
+			warmPool, err := MachineDeploymentWarmPool(cr)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !reflect.DeepEqual(warmPool, tc.expected) {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expected, warmPool)
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentArchitecture(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instanceType string
+		overrides    []template.LaunchTemplateOverride
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: amd64 node pool",
+			instanceType: "m5.xlarge",
+			overrides:    []template.LaunchTemplateOverride{{InstanceType: "m5.xlarge"}, {InstanceType: "m4.xlarge"}},
+			expected:     ArchitectureAMD64,
+		},
+		{
+			name:         "case 1: arm64 node pool",
+			instanceType: "m6g.xlarge",
+			overrides:    []template.LaunchTemplateOverride{{InstanceType: "m6g.xlarge"}, {InstanceType: "m7g.xlarge"}},
+			expected:     ArchitectureARM64,
+		},
+		{
+			name:         "case 2: mixed architectures",
+			instanceType: "m6g.xlarge",
+			overrides:    []template.LaunchTemplateOverride{{InstanceType: "m6g.xlarge"}, {InstanceType: "m5.xlarge"}},
+			errorMatcher: IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cr := infrastructurev1alpha3.AWSMachineDeployment{}
+			cr.Spec.Provider.Worker.InstanceType = tc.instanceType
+
+			architecture, err := MachineDeploymentArchitecture(cr, tc.overrides)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if architecture != tc.expected {
+				t.Fatalf("%s -  expected '%s' got '%s'\n", tc.name, tc.expected, architecture)
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentSpotAllocation(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		annotations               map[string]string
+		onDemandAboveBaseCapacity int
+		expected                  SpotAllocation
+		expectedValue             string
+		errorMatcher              func(error) bool
+	}{
+		{
+			name:                      "case 0: defaults",
+			onDemandAboveBaseCapacity: 100,
+			expected: SpotAllocation{
+				OnDemandAllocationStrategy: OnDemandAllocationStrategyPrioritized,
+				SpotAllocationStrategy:     SpotAllocationStrategyLowestPrice,
+			},
+			expectedValue: "capacityRebalance=false,onDemandAllocationStrategy=prioritized,spotAllocationStrategy=lowest-price",
+		},
+		{
+			name: "case 1: spot allocation configured",
+			annotations: map[string]string{
+				annotation.OnDemandAllocation:    OnDemandAllocationStrategyLowestPrice,
+				annotation.SpotAllocation:        SpotAllocationStrategyPriceCapacityOptimized,
+				annotation.SpotCapacityRebalance: "true",
+				annotation.SpotMaxPrice:          "0.50",
+			},
+			onDemandAboveBaseCapacity: 50,
+			expected: SpotAllocation{
+				CapacityRebalance:          true,
+				OnDemandAllocationStrategy: OnDemandAllocationStrategyLowestPrice,
+				SpotAllocationStrategy:     SpotAllocationStrategyPriceCapacityOptimized,
+				SpotMaxPrice:               "0.5",
+			},
+			expectedValue: "capacityRebalance=true,onDemandAllocationStrategy=lowest-price,spotAllocationStrategy=price-capacity-optimized,spotMaxPrice=0.5",
+		},
+		{
+			name:                      "case 2: invalid spot allocation strategy",
+			annotations:               map[string]string{annotation.SpotAllocation: "cheapest"},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 3: invalid on-demand allocation strategy",
+			annotations:               map[string]string{annotation.OnDemandAllocation: SpotAllocationStrategyCapacityOptimized},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 4: invalid spot max price",
+			annotations:               map[string]string{annotation.SpotMaxPrice: "-1"},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 5: capacity optimized prioritized without prioritized on-demand allocation",
+			annotations: map[string]string{
+				annotation.OnDemandAllocation: OnDemandAllocationStrategyLowestPrice,
+				annotation.SpotAllocation:     SpotAllocationStrategyCapacityOptimizedPrioritized,
+			},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 6: spot settings without spot instances",
+			annotations:               map[string]string{annotation.SpotCapacityRebalance: "true"},
+			onDemandAboveBaseCapacity: 100,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name: "case 7: spot settings with warm pool",
+			annotations: map[string]string{
+				annotation.SpotAllocation: SpotAllocationStrategyCapacityOptimized,
+				annotation.WarmPool:       "true",
+			},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+		{
+			name:                      "case 8: on-demand allocation without spot instances",
+			annotations:               map[string]string{annotation.OnDemandAllocation: OnDemandAllocationStrategyLowestPrice},
+			onDemandAboveBaseCapacity: 100,
+			expected: SpotAllocation{
+				OnDemandAllocationStrategy: OnDemandAllocationStrategyLowestPrice,
+				SpotAllocationStrategy:     SpotAllocationStrategyLowestPrice,
+			},
+			expectedValue: "capacityRebalance=false,onDemandAllocationStrategy=lowest-price,spotAllocationStrategy=lowest-price",
+		},
+		{
+			name:                      "case 9: instance requirements",
+			annotations:               map[string]string{annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384"},
+			onDemandAboveBaseCapacity: 50,
+			expected: SpotAllocation{
+				OnDemandAllocationStrategy: OnDemandAllocationStrategyLowestPrice,
+				SpotAllocationStrategy:     SpotAllocationStrategyLowestPrice,
+			},
+			expectedValue: "capacityRebalance=false,onDemandAllocationStrategy=lowest-price,spotAllocationStrategy=lowest-price",
+		},
+		{
+			name: "case 10: instance requirements with prioritized on-demand allocation",
+			annotations: map[string]string{
+				annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384",
+				annotation.OnDemandAllocation:   OnDemandAllocationStrategyPrioritized,
+			},
+			onDemandAboveBaseCapacity: 50,
+			errorMatcher:              IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity = &tc.onDemandAboveBaseCapacity
+
+			spotAllocation, err := MachineDeploymentSpotAllocation(cr)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !reflect.DeepEqual(spotAllocation, tc.expected) {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expected, spotAllocation)
+			}
+			if tc.errorMatcher == nil && MachineDeploymentSpotAllocationValue(spotAllocation) != tc.expectedValue {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expectedValue, MachineDeploymentSpotAllocationValue(spotAllocation))
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentInstanceRequirements(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		expected      *InstanceRequirements
+		expectedValue string
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:          "case 0: no instance requirements",
+			expected:      nil,
+			expectedValue: InstanceRequirementsDisabled,
+		},
+		{
+			name:        "case 1: vcpu and memory ranges",
+			annotations: map[string]string{annotation.InstanceRequirements: "vcpu=4-16,memoryMiB=16384-"},
+			expected: &InstanceRequirements{
+				MemoryMiB: IntRange{Min: 16384, Max: -1},
+				VCPUs:     IntRange{Min: 4, Max: 16},
+			},
+			expectedValue: "vcpu=4-16,memoryMiB=16384-",
+		},
+		{
+			name:        "case 2: all attributes",
+			annotations: map[string]string{annotation.InstanceRequirements: "gpus=0, burstable=excluded,generations=current,families=m5;m6i,memoryMiB=8192-32768,vcpu=4"},
+			expected: &InstanceRequirements{
+				AllowedFamilies: []string{"m5", "m6i"},
+				Burstable:       BurstablePerformanceExcluded,
+				Generations:     []string{InstanceGenerationCurrent},
+				GPUs:            &IntRange{Min: 0, Max: 0},
+				MemoryMiB:       IntRange{Min: 8192, Max: 32768},
+				VCPUs:           IntRange{Min: 4, Max: 4},
+			},
+			expectedValue: "vcpu=4,memoryMiB=8192-32768,families=m5;m6i,generations=current,burstable=excluded,gpus=0",
+		},
+		{
+			name:         "case 3: missing memory range",
+			annotations:  map[string]string{annotation.InstanceRequirements: "vcpu=4-16"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 4: inverted vcpu range",
+			annotations:  map[string]string{annotation.InstanceRequirements: "vcpu=16-4,memoryMiB=16384"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 5: allowed and excluded families",
+			annotations:  map[string]string{annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384,families=m5,excludedFamilies=t3"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 6: invalid burstable value",
+			annotations:  map[string]string{annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384,burstable=maybe"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 7: unknown key",
+			annotations:  map[string]string{annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384,network=25"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name: "case 8: warm pool",
+			annotations: map[string]string{
+				annotation.InstanceRequirements: "vcpu=4,memoryMiB=16384",
+				annotation.WarmPool:             "true",
+			},
+			errorMatcher: IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			instanceRequirements, err := MachineDeploymentInstanceRequirements(cr)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !reflect.DeepEqual(instanceRequirements, tc.expected) {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expected, instanceRequirements)
+			}
+			if tc.errorMatcher == nil && MachineDeploymentInstanceRequirementsValue(instanceRequirements) != tc.expectedValue {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expectedValue, MachineDeploymentInstanceRequirementsValue(instanceRequirements))
+			}
+		})
+	}
+}
+
+func Test_MachineDeploymentNodeConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		data          map[string]string
+		expected      NodeConfig
+		expectedValue string
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:          "case 0: default node config",
+			expected:      NodeConfig{},
+			expectedValue: NodeConfigDefault,
+		},
+		{
+			name: "case 1: full node config",
+			annotations: map[string]string{
+				annotation.KubeletEvictionHard:      "memory.available=500Mi,nodefs.available=10%",
+				annotation.KubeletImageGCThresholds: "high=85,low=80",
+				annotation.KubeletKubeReserved:      "cpu=500m,memory=1Gi",
+				annotation.KubeletMaxPods:           "50",
+				annotation.KubeletSystemReserved:    "memory=512Mi",
+				annotation.NodeLabels:               "team=a, node.kubernetes.io/tier=b",
+				annotation.NodeTaints:               "dedicated=a:NoSchedule,spot:PreferNoSchedule",
+			},
+			expected: NodeConfig{
+				EvictionHard:         map[string]string{"memory.available": "500Mi", "nodefs.available": "10%"},
+				ImageGCHighThreshold: 85,
+				ImageGCLowThreshold:  80,
+				KubeReserved:         map[string]string{"cpu": "500m", "memory": "1Gi"},
+				Labels:               map[string]string{"node.kubernetes.io/tier": "b", "team": "a"},
+				MaxPods:              50,
+				SystemReserved:       map[string]string{"memory": "512Mi"},
+				Taints: []Taint{
+					{Effect: TaintEffectNoSchedule, Key: "dedicated", Value: "a"},
+					{Effect: TaintEffectPreferNoSchedule, Key: "spot"},
+				},
+			},
+			expectedValue: "evictionHard=memory.available=500Mi;nodefs.available=10%,imageGC=80-85,kubeReserved=cpu=500m;memory=1Gi,labels=node.kubernetes.io/tier=b;team=a,maxPods=50,systemReserved=memory=512Mi,taints=dedicated=a:NoSchedule;spot:PreferNoSchedule",
+		},
+		{
+			name:         "case 2: reserved label domain",
+			annotations:  map[string]string{annotation.NodeLabels: "node-role.kubernetes.io/worker=true"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 3: giant swarm label domain",
+			annotations:  map[string]string{annotation.NodeLabels: "giantswarm.io/machine-deployment=abc"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 4: invalid taint effect",
+			annotations:  map[string]string{annotation.NodeTaints: "dedicated=a:NoRun"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 5: taint without effect",
+			annotations:  map[string]string{annotation.NodeTaints: "dedicated=a"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 6: unknown reserved resource",
+			annotations:  map[string]string{annotation.KubeletKubeReserved: "gpu=1"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 7: invalid eviction threshold",
+			annotations:  map[string]string{annotation.KubeletEvictionHard: "memory.available=lots"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 8: low image gc threshold not smaller than high threshold",
+			annotations:  map[string]string{annotation.KubeletImageGCThresholds: "high=80,low=85"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 9: invalid max pods",
+			annotations:  map[string]string{annotation.KubeletMaxPods: "0"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name: "case 10: node config of the ConfigMap with annotations taking precedence",
+			annotations: map[string]string{
+				annotation.KubeletMaxPods: "50",
+			},
+			data: map[string]string{
+				"kubelet-max-pods": "30",
+				"node-labels":      "team=a",
+				"node-taints":      "dedicated=a:NoSchedule",
+			},
+			expected: NodeConfig{
+				Labels:  map[string]string{"team": "a"},
+				MaxPods: 50,
+				Taints: []Taint{
+					{Effect: TaintEffectNoSchedule, Key: "dedicated", Value: "a"},
+				},
+			},
+			expectedValue: "labels=team=a,maxPods=50,taints=dedicated=a:NoSchedule",
+		},
+		{
+			name:         "case 11: invalid node config of the ConfigMap",
+			data:         map[string]string{"node-labels": "node-role.kubernetes.io/worker=true"},
+			errorMatcher: IsInvalidParameter,
+		},
+		{
+			name:         "case 12: multiple taints with the same key",
+			annotations:  map[string]string{annotation.NodeTaints: "dedicated=a:NoSchedule,dedicated=a:NoExecute"},
+			errorMatcher: IsInvalidParameter,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			cr := infrastructurev1alpha3.AWSMachineDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			nodeConfig, err := MachineDeploymentNodeConfig(cr, tc.data)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !reflect.DeepEqual(nodeConfig, tc.expected) {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expected, nodeConfig)
+			}
+			if tc.errorMatcher == nil && MachineDeploymentNodeConfigValue(nodeConfig) != tc.expectedValue {
+				t.Fatalf("%s -  expected %#v got %#v\n", tc.name, tc.expectedValue, MachineDeploymentNodeConfigValue(nodeConfig))
+			}
+		})
+	}
+}
@@ -9,3 +9,73 @@ type AMIInfo struct {
 type AMIInfoList struct {
 	AMIs []AMIInfo `json:"amis"`
 }
+
+// InstanceRequirements describes the attributes of the instance types the ASG
+// of a node pool may launch, as alternative to explicitly listed instance
+// types. Empty AllowedFamilies, Burstable, ExcludedFamilies and Generations as
+// well as nil GPUs leave the respective attribute unconstrained.
+type InstanceRequirements struct {
+	AllowedFamilies  []string
+	Burstable        string
+	ExcludedFamilies []string
+	Generations      []string
+	GPUs             *IntRange
+	MemoryMiB        IntRange
+	VCPUs            IntRange
+}
+
+// IntRange is an inclusive range of integers. A Max of -1 leaves the range
+// unbounded.
+type IntRange struct {
+	Min int
+	Max int
+}
+
+// NodeConfig describes the node labels, taints and kubelet settings of a node
+// pool. Empty fields leave the defaults of the kubelet configuration in place.
+type NodeConfig struct {
+	EvictionHard         map[string]string
+	ImageGCHighThreshold int
+	ImageGCLowThreshold  int
+	KubeReserved         map[string]string
+	Labels               map[string]string
+	MaxPods              int
+	SystemReserved       map[string]string
+	Taints               []Taint
+}
+
+// SpotAllocation describes how the ASG of a node pool allocates its on-demand
+// and spot instances. An empty SpotMaxPrice caps the spot price at the
+// on-demand price.
+type SpotAllocation struct {
+	CapacityRebalance          bool
+	OnDemandAllocationStrategy string
+	SpotAllocationStrategy     string
+	SpotMaxPrice               string
+}
+
+// VolumeProfile describes the EBS volume settings of a node pool volume.
+// Zero values of IOPS, KMSKeyARN and Throughput leave the AWS defaults in
+// place.
+type VolumeProfile struct {
+	IOPS       int
+	KMSKeyARN  string
+	Throughput int
+	Type       string
+}
+
+// WarmPool describes the warm pool of a node pool's ASG. A MaxPreparedCapacity
+// of -1 lets the warm pool grow up to the ASG's max size.
+type WarmPool struct {
+	MaxPreparedCapacity int
+	MinSize             int
+	State               string
+}
+
+// Taint describes a node taint registered by the kubelet. The value is
+// optional.
+type Taint struct {
+	Effect string
+	Key    string
+	Value  string
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/s3object"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpazs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpnatgateways"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpoutputs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpsecuritygroups"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpsubnets"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpvpcid"
@@ -46,23 +47,27 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpazs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpf"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpinstanceinfo"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpnodeconfig"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpoutputs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpsecuritygroups"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpstatus"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tenantclients"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudconfig"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter/kms"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
 	"github.com/giantswarm/aws-operator/v16/service/internal/images"
+	"github.com/giantswarm/aws-operator/v16/service/internal/instancetypes"
 	"github.com/giantswarm/aws-operator/v16/service/internal/locker"
 	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 	"github.com/giantswarm/aws-operator/v16/service/internal/releases"
 )
 
 type MachineDeploymentConfig struct {
+	AWSClientPool      *aws.Pool
 	CertsSearcher      certs.Interface
 	CloudTags          cloudtags.Interface
 	Event              event.Interface
@@ -88,6 +93,7 @@ type MachineDeploymentConfig struct {
 	IgnitionPath               string
 	InstallationName           string
 	IPAMNetworkRange           net.IPNet
+	IPAMPoolID                 string
 	ClusterDomain              string
 	NetworkSetupDockerImage    string
 	PodInfraContainerImage     string
@@ -170,41 +176,39 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		}
 	}
 
-	var cloudtagObject cloudtags.Interface
+	var randomKeysSearcher randomkeys.Interface
 	{
-		c := cloudtags.Config{
-			K8sClient: config.K8sClient,
+		c := randomkeys.Config{
+			K8sClient: config.K8sClient.K8sClient(),
 			Logger:    config.Logger,
 		}
 
-		cloudtagObject, err = cloudtags.New(c)
+		randomKeysSearcher, err = randomkeys.NewSearcher(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 	}
 
-	var randomKeysSearcher randomkeys.Interface
+	var encrypterObject encrypter.Interface
 	{
-		c := randomkeys.Config{
-			K8sClient: config.K8sClient.K8sClient(),
+		c := &kms.EncrypterConfig{
+			CloudTags: config.CloudTags,
 			Logger:    config.Logger,
 		}
 
-		randomKeysSearcher, err = randomkeys.NewSearcher(c)
+		encrypterObject, err = kms.NewEncrypter(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 	}
 
-	var encrypterObject encrypter.Interface
+	var instanceTypes instancetypes.Interface
 	{
-		c := &kms.EncrypterConfig{
+		c := instancetypes.Config{
 			Logger: config.Logger,
-
-			InstallationName: config.InstallationName,
 		}
 
-		encrypterObject, err = kms.NewEncrypter(c)
+		instanceTypes, err = instancetypes.New(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -250,12 +254,27 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		}
 	}
 
+	var changeSet changeset.Interface
+	{
+		c := changeset.Config{
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+		}
+
+		changeSet, err = changeset.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tcnpChangeDetection *changedetection.TCNP
 	{
 		c := changedetection.TCNPConfig{
-			Logger:   config.Logger,
-			Event:    config.Event,
-			Releases: rel,
+			Event:     config.Event,
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+			Releases:  rel,
 		}
 
 		tcnpChangeDetection, err = changedetection.NewTCNP(c)
@@ -314,6 +333,47 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		}
 	}
 
+	var ipamCollector ipam.Collector
+	var ipamPersister ipam.Persister
+	var ipamReleaser ipam.Releaser
+	{
+		ipamCollector = subnetCollector
+		ipamPersister = machineDeploymentPersister
+
+		// In case an AWS VPC IPAM pool is configured, subnets are additionally
+		// allocated in the pool and released from it on deletion.
+		if config.IPAMPoolID != "" {
+			cc := ipam.IPAMPoolCollectorConfig{
+				Collector: subnetCollector,
+				Logger:    config.Logger,
+
+				NetworkRange: config.IPAMNetworkRange,
+				PoolID:       config.IPAMPoolID,
+			}
+
+			ipamPoolCollector, err := ipam.NewIPAMPoolCollector(cc)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			pc := ipam.IPAMPoolPersisterConfig{
+				Logger:    config.Logger,
+				Persister: machineDeploymentPersister,
+
+				PoolID: config.IPAMPoolID,
+			}
+
+			ipamPoolPersister, err := ipam.NewIPAMPoolPersister(pc)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			ipamCollector = ipamPoolCollector
+			ipamPersister = ipamPoolPersister
+			ipamReleaser = ipamPoolPersister
+		}
+	}
+
 	var tenantCluster tenantcluster.Interface
 	{
 		c := tenantcluster.Config{
@@ -358,6 +418,7 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 	var awsClientResource resource.Interface
 	{
 		c := awsclient.Config{
+			ClientPool:    config.AWSClientPool,
 			K8sClient:     config.K8sClient.K8sClient(),
 			Logger:        config.Logger,
 			ToClusterFunc: newMachineDeploymentToClusterFunc(config.K8sClient.CtrlClient()),
@@ -452,11 +513,12 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 	{
 		c := ipam.Config{
 			Checker:   machineDeploymentChecker,
-			Collector: subnetCollector,
+			Collector: ipamCollector,
 			K8sClient: config.K8sClient,
 			Locker:    config.Locker,
 			Logger:    config.Logger,
-			Persister: machineDeploymentPersister,
+			Persister: ipamPersister,
+			Releaser:  ipamReleaser,
 
 			AllocatedSubnetMaskBits: config.GuestSubnetMaskBits,
 			NetworkRange:            config.IPAMNetworkRange,
@@ -582,13 +644,15 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 	var tcnpResource resource.Interface
 	{
 		c := tcnp.Config{
-			CloudTags: cloudtagObject,
-			Detection: tcnpChangeDetection,
-			Encrypter: encrypterObject,
-			Event:     config.Event,
-			Images:    config.Images,
-			K8sClient: config.K8sClient,
-			Logger:    config.Logger,
+			ChangeSet:     changeSet,
+			CloudTags:     config.CloudTags,
+			Detection:     tcnpChangeDetection,
+			Encrypter:     encrypterObject,
+			Event:         config.Event,
+			Images:        config.Images,
+			InstanceTypes: instanceTypes,
+			K8sClient:     config.K8sClient,
+			Logger:        config.Logger,
 
 			AlikeInstances:   config.AlikeInstances,
 			InstallationName: config.InstallationName,
@@ -603,10 +667,9 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 	var tcnpfResource resource.Interface
 	{
 		c := tcnpf.Config{
-			Event:  config.Event,
-			Logger: config.Logger,
-
-			InstallationName: config.InstallationName,
+			CloudTags: config.CloudTags,
+			Event:     config.Event,
+			Logger:    config.Logger,
 		}
 
 		tcnpfResource, err = tcnpf.New(c)
@@ -627,6 +690,19 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		}
 	}
 
+	var tcnpNodeConfigResource resource.Interface
+	{
+		c := tcnpnodeconfig.Config{
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+		}
+
+		tcnpNodeConfigResource, err = tcnpnodeconfig.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tcnpStatusResource resource.Interface
 	{
 		c := tcnpstatus.Config{
@@ -654,6 +730,19 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		}
 	}
 
+	var tccpOutputsResource resource.Interface
+	{
+		c := tccpoutputs.Config{
+			Logger:        config.Logger,
+			ToClusterFunc: newMachineDeploymentToClusterFunc(config.K8sClient.CtrlClient()),
+		}
+
+		tccpOutputsResource, err = tccpoutputs.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var tccpVPCIDResource resource.Interface
 	{
 		c := tccpvpcid.Config{
@@ -689,6 +778,7 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		cpRouteTablesResource,
 		cpVPCResource,
 		tccpNATGatewaysResource,
+		tccpOutputsResource,
 		tccpSecurityGroupsResource,
 		tccpVPCIDResource,
 		tccpVPCPCXResource,
@@ -699,6 +789,7 @@ func newMachineDeploymentResources(config MachineDeploymentConfig) ([]resource.I
 		tcnpAZsResource,
 		tcnpOutputsResource,
 		tcnpInstanceInfoResource,
+		tcnpNodeConfigResource,
 		tcnpSecurityGroupsResource,
 
 		// All these resources implement certain business logic and operate based on
@@ -28,9 +28,10 @@ import (
 )
 
 type MachineDeploymentDrainerConfig struct {
-	Event     event.Interface
-	K8sClient k8sclient.Interface
-	Logger    micrologger.Logger
+	AWSClientPool *aws.Pool
+	Event         event.Interface
+	K8sClient     k8sclient.Interface
+	Logger        micrologger.Logger
 
 	HostAWSConfig aws.Config
 }
@@ -109,8 +110,9 @@ func newMachineDeploymentDrainerResources(config MachineDeploymentDrainerConfig)
 	var awsClientResource resource.Interface
 	{
 		c := awsclient.Config{
-			K8sClient: config.K8sClient.K8sClient(),
-			Logger:    config.Logger,
+			ClientPool: config.AWSClientPool,
+			K8sClient:  config.K8sClient.K8sClient(),
+			Logger:     config.Logger,
 
 			CPAWSConfig:   config.HostAWSConfig,
 			ToClusterFunc: newMachineDeploymentToClusterFunc(config.K8sClient.CtrlClient()),
@@ -13,6 +13,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -110,7 +111,7 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+		instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if IsNotFound(err) {
 			r.logger.Debugf(ctx, "auto scaling group not available yet")
 			r.logger.Debugf(ctx, "canceling resource")
@@ -119,18 +120,13 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 			return microerror.Mask(err)
 		}
 
-		if len(o.Reservations) == 0 {
-			r.logger.Debugf(ctx, "auto scaling group not available yet")
-			r.logger.Debugf(ctx, "canceling resource")
-			return nil
-		}
-		if len(o.Reservations[0].Instances) == 0 {
+		if len(instances) == 0 {
 			r.logger.Debugf(ctx, "auto scaling group not available yet")
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
 		}
 
-		cc.Status.TenantCluster.ASG.Name = awstags.ValueForKey(o.Reservations[0].Instances[0].Tags, "aws:autoscaling:groupName")
+		cc.Status.TenantCluster.ASG.Name = awstags.ValueForKey(instances[0].Tags, "aws:autoscaling:groupName")
 
 		r.logger.Debugf(ctx, "found auto scaling group name %#q", cc.Status.TenantCluster.ASG.Name)
 	}
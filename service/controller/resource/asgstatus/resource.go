@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -66,15 +67,15 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(i)
+		groups, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		if len(o.AutoScalingGroups) != 1 {
+		if len(groups) != 1 {
 			return microerror.Maskf(executionFailedError, "there must be one item for auto scaling group %#q", asgName)
 		}
-		asg = o.AutoScalingGroups[0]
+		asg = groups[0]
 
 		r.logger.Debugf(ctx, "found auto scaling group %#q", asgName)
 	}
@@ -2,6 +2,7 @@ package awsclient
 
 import (
 	"context"
+	"fmt"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
@@ -18,22 +19,27 @@ const (
 )
 
 type Config struct {
-	K8sClient kubernetes.Interface
-	Logger    micrologger.Logger
+	ClientPool *aws.Pool
+	K8sClient  kubernetes.Interface
+	Logger     micrologger.Logger
 
 	CPAWSConfig   aws.Config
 	ToClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
 }
 
 type Resource struct {
-	k8sClient kubernetes.Interface
-	logger    micrologger.Logger
+	clientPool *aws.Pool
+	k8sClient  kubernetes.Interface
+	logger     micrologger.Logger
 
 	cpAWSConfig   aws.Config
 	toClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
 }
 
 func New(config Config) (*Resource, error) {
+	if config.ClientPool == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ClientPool must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -46,8 +52,9 @@ func New(config Config) (*Resource, error) {
 	}
 
 	r := &Resource{
-		k8sClient: config.K8sClient,
-		logger:    config.Logger,
+		clientPool: config.ClientPool,
+		k8sClient:  config.K8sClient,
+		logger:     config.Logger,
 
 		cpAWSConfig:   config.CPAWSConfig,
 		toClusterFunc: config.ToClusterFunc,
@@ -69,7 +76,7 @@ func (r *Resource) addAWSClientsToContext(ctx context.Context, cr infrastructure
 	{
 		c := r.cpAWSConfig
 
-		clients, err := aws.NewClients(c)
+		clients, err := r.clientPool.Clients(c)
 		if err != nil {
 			return microerror.Mask(err)
 		}
@@ -78,15 +85,17 @@ func (r *Resource) addAWSClientsToContext(ctx context.Context, cr infrastructure
 	}
 
 	{
-		arn, err := credential.GetARN(ctx, r.k8sClient, cr)
+		cred, err := credential.Get(ctx, r.k8sClient, cr)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
 		c := r.cpAWSConfig
-		c.RoleARN = arn
+		c.CredentialName = fmt.Sprintf("%s/%s", cred.Namespace, cred.Name)
+		c.CredentialVersion = cred.ResourceVersion
+		c.RoleARN = cred.ARN
 
-		clients, err := aws.NewClients(c)
+		clients, err := r.clientPool.Clients(c)
 		if err != nil {
 			return microerror.Mask(err)
 		}
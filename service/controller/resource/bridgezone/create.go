@@ -3,11 +3,12 @@ package bridgezone
 import (
 	"context"
 
-	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/giantswarm/microerror"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -16,63 +17,78 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	if !r.route53Enabled {
+	// The intermediate and the final zone only exist in case the hosted zones
+	// of tenant clusters are managed in Route53.
+	if !r.dns.Route53() {
 		r.logger.Debugf(ctx, "route53 disabled")
 		r.logger.Debugf(ctx, "canceling resource")
 		return nil
 	}
 
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Private clusters only have an internal hosted zone, which cannot be
+	// delegated to.
+	if cc.Status.TenantCluster.TCCP.PrivateCluster {
+		r.logger.Debugf(ctx, "private cluster")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
 	baseDomain := key.ClusterBaseDomain(cr)
 	intermediateZone := "k8s." + baseDomain
 	finalZone := key.ClusterID(&cr) + ".k8s." + baseDomain
 
-	guest, defaultGuest, err := r.route53Clients(ctx)
+	guest, defaultGuest, err := r.providers(ctx)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
 	g := &errgroup.Group{}
 
-	var intermediateZoneID string
+	var intermediate dns.Zone
 	g.Go(func() error {
-		r.logger.Debugf(ctx, "getting intermediate zone ID")
+		r.logger.Debugf(ctx, "getting intermediate zone")
 
-		id, err := r.findHostedZoneID(ctx, defaultGuest, intermediateZone)
-		if IsNotFound(err) {
+		zone, err := defaultGuest.FindZone(ctx, intermediateZone, false)
+		if dns.IsZoneNotFound(err) {
 			r.logger.Debugf(ctx, "intermediate zone not found")
 
 			return microerror.Mask(err)
 		} else if err != nil {
 			return microerror.Mask(err)
 		}
-		intermediateZoneID = id
+		intermediate = zone
 
-		r.logger.Debugf(ctx, "got intermediate zone ID")
+		r.logger.Debugf(ctx, "got intermediate zone")
 
 		return nil
 	})
 
-	var finalZoneID string
+	var final dns.Zone
 	g.Go(func() error {
-		r.logger.Debugf(ctx, "getting final zone ID")
+		r.logger.Debugf(ctx, "getting final zone")
 
-		id, err := r.findHostedZoneID(ctx, guest, finalZone)
-		if IsNotFound(err) {
+		zone, err := guest.FindZone(ctx, finalZone, false)
+		if dns.IsZoneNotFound(err) {
 			r.logger.Debugf(ctx, "final zone not found")
 
 			return microerror.Mask(err)
 		} else if err != nil {
 			return microerror.Mask(err)
 		}
-		finalZoneID = id
+		final = zone
 
-		r.logger.Debugf(ctx, "got final zone ID")
+		r.logger.Debugf(ctx, "got final zone")
 
 		return nil
 	})
 
 	err = g.Wait()
-	if IsNotFound(err) {
+	if dns.IsZoneNotFound(err) {
 		r.logger.Debugf(ctx, "canceling resource")
 
 		return nil
@@ -80,50 +96,22 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	var finalZoneRecords []*route53.ResourceRecord
+	var nameServers []string
 	{
 		r.logger.Debugf(ctx, "getting final zone name servers")
 
-		nameServers, _, err := r.getNameServersAndTTL(ctx, guest, finalZoneID, finalZone)
+		nameServers, err = guest.NameServers(ctx, final)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		for _, ns := range nameServers {
-			copy := ns
-			v := &route53.ResourceRecord{
-				Value: &copy,
-			}
-			finalZoneRecords = append(finalZoneRecords, v)
-		}
-
 		r.logger.Debugf(ctx, "got final zone name servers")
 	}
 
 	{
 		r.logger.Debugf(ctx, "ensuring final zone delegation from intermediate zone")
 
-		upsert := route53.ChangeActionUpsert
-		ns := route53.RRTypeNs
-		ttl := int64(300)
-
-		in := &route53.ChangeResourceRecordSetsInput{
-			ChangeBatch: &route53.ChangeBatch{
-				Changes: []*route53.Change{
-					{
-						Action: &upsert,
-						ResourceRecordSet: &route53.ResourceRecordSet{
-							Name:            &finalZone,
-							Type:            &ns,
-							TTL:             &ttl,
-							ResourceRecords: finalZoneRecords,
-						},
-					},
-				},
-			},
-			HostedZoneId: &intermediateZoneID,
-		}
-		_, err := defaultGuest.ChangeResourceRecordSetsWithContext(ctx, in)
+		err = defaultGuest.Delegate(ctx, intermediate, finalZone, nameServers)
 		if err != nil {
 			return microerror.Mask(err)
 		}
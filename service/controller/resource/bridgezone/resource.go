@@ -3,10 +3,7 @@ package bridgezone
 import (
 	"context"
 	"reflect"
-	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	"k8s.io/client-go/kubernetes"
@@ -14,6 +11,7 @@ import (
 	clientaws "github.com/giantswarm/aws-operator/v16/client/aws"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/internal/credential"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 )
 
 const (
@@ -25,7 +23,7 @@ type Config struct {
 	K8sClient     kubernetes.Interface
 	Logger        micrologger.Logger
 
-	Route53Enabled bool
+	DNS dns.Interface
 }
 
 // Resource is bridgezone resource making sure we have fallback delegation in
@@ -112,7 +110,7 @@ type Resource struct {
 	k8sClient     kubernetes.Interface
 	logger        micrologger.Logger
 
-	route53Enabled bool
+	dns dns.Interface
 }
 
 func New(config Config) (*Resource, error) {
@@ -126,12 +124,16 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
+	if config.DNS == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DNS must not be empty", config)
+	}
+
 	r := &Resource{
 		hostAWSConfig: config.HostAWSConfig,
 		k8sClient:     config.K8sClient,
 		logger:        config.Logger,
 
-		route53Enabled: config.Route53Enabled,
+		dns: config.DNS,
 	}
 
 	return r, nil
@@ -141,130 +143,17 @@ func (r *Resource) Name() string {
 	return name
 }
 
-// findHostedZoneID fetches Route53 hosted zone IDs based on a given name. The
-// implementation fetches up to 100 matching results to find the right one. The
-// bridgezone resource here is only concerned with the hosted zone ID of the
-// hosted zone name provided. The desired ID will always be carried in the first
-// Route53 response as the one we want to fetch is the most accurate and always
-// listed as the first item in the response. This is because of the
-// lexicographical order of the response items as the API documentation puts it.
-// See also
-// https://godoc.org/github.com/aws/aws-sdk-go/service/route53#Route53.ListHostedZonesByName.
-//
-//	Retrieves a list of your hosted zones in lexicographic order.
-//
-// Here is an example to make it clearer. Let's consider the following hosted
-// zone name.
-//
-//	9cvgo.k8s.ginger.eu-central-1.aws.gigantic.io
-//
-// Given this name, findHostedZoneID will receive a response from Route53
-// similar to the following example, containing a single hosted zone carrying
-// its ID.
-//
-//	{
-//	  ...
-//	  HostedZones: [{
-//	    ...
-//	    Id: "/hostedzone/Z1A4QS1NDU6NW6",
-//	    Name: "9cvgo.k8s.ginger.eu-central-1.aws.gigantic.io.",
-//	    ...
-//	  }],
-//	  ...
-//	}
-//
-// The example above was about a very specific domain name, which list result
-// could only find a single item in the response. Let's consider a less specific
-// domain name as input for findHostedZoneID.
-//
-//	k8s.ginger.eu-central-1.aws.gigantic.io
-//
-// The result from Route53 will again list all the childs within the given
-// domain name. In the example response below there where only two tenant
-// clusters.
-//
-//	{
-//	  ...
-//	  HostedZones: [{
-//	    ...
-//	    Id: "/hostedzone/Z1HJGG5VLG8GZH",
-//	    Name: "k8s.ginger.eu-central-1.aws.gigantic.io.",
-//	    ...
-//	  },{
-//	    ...
-//	    Id: "/hostedzone/Z1KSFLSM1JEQYM",
-//	    Name: "0tz6i.k8s.ginger.eu-central-1.aws.gigantic.io.",
-//	    ...
-//	  },{
-//	    ...
-//	    Id: "/hostedzone/Z1A4QS1NDU6NW6",
-//	    Name: "9cvgo.k8s.ginger.eu-central-1.aws.gigantic.io.",
-//	    ...
-//	  }],
-//	  ...
-//	}
-func (r *Resource) findHostedZoneID(ctx context.Context, client *route53.Route53, name string) (string, error) {
-	in := &route53.ListHostedZonesByNameInput{
-		DNSName: aws.String(name),
-	}
-
-	out, err := client.ListHostedZonesByName(in)
-	if err != nil {
-		return "", microerror.Mask(err)
-	}
-
-	for _, hostedZone := range out.HostedZones {
-		if *hostedZone.Name == name {
-			return *hostedZone.Id, nil
-		}
-	}
-
-	return "", microerror.Maskf(notFoundError, "hosted zone name %#q", name)
-}
-
-func (r *Resource) getNameServersAndTTL(ctx context.Context, client *route53.Route53, zoneID, name string) (nameServers []string, ttl int64, err error) {
-	one := "1"
-	ns := route53.RRTypeNs
-	in := &route53.ListResourceRecordSetsInput{
-		HostedZoneId:    &zoneID,
-		MaxItems:        &one,
-		StartRecordName: &name,
-		StartRecordType: &ns,
-	}
-	out, err := client.ListResourceRecordSetsWithContext(ctx, in)
-	if err != nil {
-		return nil, 0, microerror.Mask(err)
-	}
-
-	if len(out.ResourceRecordSets) == 0 {
-		return nil, 0, microerror.Maskf(notFoundError, "NS record %q for HostedZone %q not found", name, zoneID)
-	}
-	if len(out.ResourceRecordSets) != 1 {
-		return nil, 0, microerror.Maskf(executionError, "expected single NS record %q for HostedZone %q, found %#v", name, zoneID, out.ResourceRecordSets)
-	}
-
-	rs := *out.ResourceRecordSets[0]
-
-	if strings.TrimSuffix(*rs.Name, ".") != name {
-		return nil, 0, microerror.Maskf(notFoundError, "NS record %q for HostedZone %q not found", name, zoneID)
-	}
-
-	var servers []string
-	for _, r := range rs.ResourceRecords {
-		servers = append(servers, *r.Value)
-	}
-
-	return servers, *rs.TTL, nil
-}
-
-func (r *Resource) route53Clients(ctx context.Context) (guest, defaultGuest *route53.Route53, err error) {
+// providers returns the DNS providers of the tenant cluster account, which
+// manages the final zone, and the default tenant cluster account, which
+// manages the intermediate zone.
+func (r *Resource) providers(ctx context.Context) (guest, defaultGuest dns.Provider, err error) {
 	// guest
 	{
 		cc, err := controllercontext.FromContext(ctx)
 		if err != nil {
 			return nil, nil, microerror.Mask(err)
 		}
-		guest = cc.Client.TenantCluster.AWS.Route53
+		guest = r.dns.Provider(cc.Client.TenantCluster.AWS.Route53)
 	}
 
 	// defaultGuest
@@ -282,7 +171,7 @@ func (r *Resource) route53Clients(ctx context.Context) (guest, defaultGuest *rou
 			return nil, nil, microerror.Mask(err)
 		}
 
-		defaultGuest = newClients.Route53
+		defaultGuest = r.dns.Provider(newClients.Route53)
 	}
 
 	return guest, defaultGuest, nil
@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/operatorkit/v7/pkg/controller/context/finalizerskeptcontext"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -34,12 +35,12 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSubnets(i)
+		subnets, err := awslist.Subnets(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		for _, s := range o.Subnets {
+		for _, s := range subnets {
 			values = append(values, s.SubnetId)
 		}
 
@@ -59,13 +60,11 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeNetworkInterfaces(i)
+		enis, err = awslist.NetworkInterfaces(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		enis = o.NetworkInterfaces
-
 		r.logger.Debugf(ctx, "found %d network interfaces", len(enis))
 	}
 
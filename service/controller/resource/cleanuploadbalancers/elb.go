@@ -9,6 +9,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) clusterClassicLoadBalancers(ctx context.Context, customObject infrastructurev1alpha3.AWSCluster) (*LoadBalancerState, error) {
@@ -21,13 +22,13 @@ func (r *Resource) clusterClassicLoadBalancers(ctx context.Context, customObject
 	}
 
 	// We get all load balancers because the API does not allow tag filters.
-	output, err := cc.Client.TenantCluster.AWS.ELB.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
+	loadBalancers, err := awslist.ClassicLoadBalancers(ctx, cc.Client.TenantCluster.AWS.ELB, &elb.DescribeLoadBalancersInput{})
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
 	allLBNames := []*string{}
-	for _, lb := range output.LoadBalancerDescriptions {
+	for _, lb := range loadBalancers {
 		allLBNames = append(allLBNames, lb.LoadBalancerName)
 	}
 
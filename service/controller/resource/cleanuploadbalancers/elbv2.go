@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) clusterLoadBalancersV2(ctx context.Context, cl infrastructurev1alpha3.AWSCluster) (*LoadBalancerStateV2, error) {
@@ -22,13 +23,13 @@ func (r *Resource) clusterLoadBalancersV2(ctx context.Context, cl infrastructure
 	}
 
 	// We get all load balancers because the API does not allow tag filters.
-	output, err := cc.Client.TenantCluster.AWS.ELBv2.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{})
+	loadBalancers, err := awslist.LoadBalancers(ctx, cc.Client.TenantCluster.AWS.ELBv2, &elbv2.DescribeLoadBalancersInput{})
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
 	allLBArns := []*string{}
-	for _, lb := range output.LoadBalancers {
+	for _, lb := range loadBalancers {
 		allLBArns = append(allLBArns, lb.LoadBalancerArn)
 	}
 
@@ -62,11 +63,11 @@ func (r *Resource) clusterLoadBalancersV2(ctx context.Context, cl infrastructure
 			LoadBalancerArn: aws.String(lbArn),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.ELBv2.DescribeTargetGroups(i)
+		targetGroups, err := awslist.TargetGroups(ctx, cc.Client.TenantCluster.AWS.ELBv2, i)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
-		for _, targetGroup := range o.TargetGroups {
+		for _, targetGroup := range targetGroups {
 			targetGroupsArns = append(targetGroupsArns, *targetGroup.TargetGroupArn)
 		}
 
@@ -2,14 +2,12 @@ package cleanuprecordsets
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -22,100 +20,67 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	if !r.route53Enabled {
-		r.logger.Debugf(ctx, "route53 disabled")
+	if !r.dns.Enabled() {
+		r.logger.Debugf(ctx, "dns disabled")
 		r.logger.Debugf(ctx, "canceling resource")
 		return nil
 	}
 
-	var hostedZones []*route53.HostedZone
-	{
+	provider := r.dns.Provider(cc.Client.TenantCluster.AWS.Route53)
+	tcBaseDomain := key.TenantClusterBaseDomain(cr)
+
+	// In case of Route53 the tenant cluster has its own public and private
+	// hosted zones, which are managed by its Cloud Formation stack. Only the
+	// record sets the stack does not know about have to be deleted so that
+	// the hosted zones can be deleted. Other DNS providers have the records of
+	// the tenant cluster in the zone enclosing its base domain, where all of
+	// them have to be deleted.
+	var zones []dns.Zone
+	var keep []string
+	if r.dns.Route53() {
 		r.logger.Debugf(ctx, "finding hosted zones")
 
-		hostedZonesInput := &route53.ListHostedZonesByNameInput{}
+		for _, private := range []bool{false, true} {
+			zone, err := provider.FindZone(ctx, tcBaseDomain, private)
+			if dns.IsZoneNotFound(err) {
+				continue
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
 
-		o, err := cc.Client.TenantCluster.AWS.Route53.ListHostedZonesByName(hostedZonesInput)
-		if err != nil {
-			return microerror.Mask(err)
+			zones = append(zones, zone)
 		}
 
-		for _, zone := range o.HostedZones {
-			baseDomain := fmt.Sprintf("%s.", key.TenantClusterBaseDomain(cr))
+		keep = key.ManagedRecordSets(cr)
 
-			if *zone.Name == baseDomain {
-				hostedZones = append(hostedZones, zone)
+		r.logger.Debugf(ctx, "found %d hosted zones", len(zones))
+	} else {
+		r.logger.Debugf(ctx, "finding zone enclosing %#q", tcBaseDomain)
 
-				r.logger.Debugf(ctx, "found hosted zones")
-			}
+		zone, err := dns.FindEnclosingZone(ctx, provider, tcBaseDomain)
+		if dns.IsZoneNotFound(err) {
+			r.logger.Debugf(ctx, "did not find zone enclosing %#q", tcBaseDomain)
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		} else if err != nil {
+			return microerror.Mask(err)
 		}
-	}
 
-	for _, hostedZone := range hostedZones {
-		r.logger.Debugf(ctx, "finding record sets in hosted zone %#q", *hostedZone.Id)
+		zones = append(zones, zone)
 
-		recordSetsInput := &route53.ListResourceRecordSetsInput{
-			HostedZoneId: hostedZone.Id,
-		}
+		r.logger.Debugf(ctx, "found zone %#q enclosing %#q", zone.Name, tcBaseDomain)
+	}
 
-		o, err := cc.Client.TenantCluster.AWS.Route53.ListResourceRecordSets(recordSetsInput)
+	for _, zone := range zones {
+		r.logger.Debugf(ctx, "deleting non-managed record sets in zone %#q", zone.ID)
+
+		err = provider.CleanupRecords(ctx, zone, tcBaseDomain, keep)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		resourceRecordSets := o.ResourceRecordSets
-
-		managedRecordSets := key.ManagedRecordSets(cr)
-		route53Changes := []*route53.Change{}
-		for _, rr := range resourceRecordSets {
-			r.logger.Debugf(ctx, "looking for non-managed record sets in hosted zone %#q", *hostedZone.Id)
-
-			if !stringInSlice(*rr.Name, managedRecordSets) {
-				route53Change := &route53.Change{
-					Action: aws.String("DELETE"),
-					ResourceRecordSet: &route53.ResourceRecordSet{
-						AliasTarget:     rr.AliasTarget,
-						Name:            rr.Name,
-						ResourceRecords: rr.ResourceRecords,
-						TTL:             rr.TTL,
-						Type:            rr.Type,
-						Weight:          rr.Weight,
-						SetIdentifier:   rr.SetIdentifier,
-					},
-				}
-
-				route53Changes = append(route53Changes, route53Change)
-
-				r.logger.Debugf(ctx, "found non-managed record set %#q in hosted zone %#q", *rr.Name, *hostedZone.Id)
-			}
-		}
-
-		if len(route53Changes) > 0 {
-			r.logger.Debugf(ctx, "deleteting non-managed record sets in hosted zone %#q", *hostedZone.Id)
-
-			changeRecordSetInput := &route53.ChangeResourceRecordSetsInput{
-				ChangeBatch: &route53.ChangeBatch{
-					Changes: route53Changes,
-				},
-				HostedZoneId: hostedZone.Id,
-			}
-
-			_, err = cc.Client.TenantCluster.AWS.Route53.ChangeResourceRecordSets(changeRecordSetInput)
-			if err != nil {
-				return microerror.Mask(err)
-			}
-
-			r.logger.Debugf(ctx, "deleted non-managed record sets in hosted zone %#q", *hostedZone.Id)
-		}
+		r.logger.Debugf(ctx, "deleted non-managed record sets in zone %#q", zone.ID)
 	}
 
 	return nil
 }
-
-func stringInSlice(str string, list []string) bool {
-	for _, value := range list {
-		if value == str {
-			return true
-		}
-	}
-	return false
-}
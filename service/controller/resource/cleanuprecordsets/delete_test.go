@@ -0,0 +1,94 @@
+package cleanuprecordsets
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_Controller_Resource_CleanupRecordSets_EnsureDeleted(t *testing.T) {
+	testCases := []struct {
+		name            string
+		zone            dns.Zone
+		records         []dns.Record
+		route53         bool
+		expectedRecords []dns.Record
+	}{
+		{
+			name: "case 0: non-managed record sets are deleted from the hosted zone of the cluster",
+			zone: dns.Zone{ID: "/hostedzone/Z1", Name: "8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"},
+			records: []dns.Record{
+				{Name: "*.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"}},
+				{Name: "api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeA, Values: []string{"10.0.0.1"}},
+				{Name: "ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.elb.amazonaws.com"}},
+			},
+			route53: true,
+			expectedRecords: []dns.Record{
+				{Name: "*.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"}},
+				{Name: "8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+				{Name: "api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeA, Values: []string{"10.0.0.1"}},
+			},
+		},
+		{
+			name: "case 1: all record sets of the cluster are deleted from the enclosing zone",
+			zone: dns.Zone{ID: "gigantic.io", Name: "gigantic.io"},
+			records: []dns.Record{
+				{Name: "*.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"}},
+				{Name: "api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"api.elb.amazonaws.com"}},
+				{Name: "api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"other.elb.amazonaws.com"}},
+			},
+			route53: false,
+			expectedRecords: []dns.Record{
+				{Name: "api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"other.elb.amazonaws.com"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			p := dns.NewFakeProvider()
+			p.AddZone(tc.zone, []string{"ns1.gigantic.io"})
+			err = p.UpsertRecords(context.Background(), tc.zone, tc.records)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					DNS:    dns.NewFakeDNS(dns.FakeDNSConfig{Provider: p, Route53: tc.route53}),
+					Logger: microloggertest.New(),
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cr := unittest.DefaultCluster()
+			err = r.EnsureDeleted(unittest.DefaultContext(), &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			records := p.Records(tc.zone)
+			if !cmp.Equal(records, tc.expectedRecords) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedRecords, records))
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package cleanuprecordsets
 import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 )
 
 const (
@@ -10,26 +12,26 @@ const (
 )
 
 type Config struct {
+	DNS    dns.Interface
 	Logger micrologger.Logger
-
-	Route53Enabled bool
 }
 
 type Resource struct {
+	dns    dns.Interface
 	logger micrologger.Logger
-
-	route53Enabled bool
 }
 
 func New(config Config) (*Resource, error) {
+	if config.DNS == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DNS must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	r := &Resource{
+		dns:    config.DNS,
 		logger: config.Logger,
-
-		route53Enabled: config.Route53Enabled,
 	}
 
 	return r, nil
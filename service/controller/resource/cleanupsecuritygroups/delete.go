@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -43,13 +44,11 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		groups, err = awslist.SecurityGroups(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		groups = o.SecurityGroups
-
 		r.logger.Debugf(ctx, "found security groups for tenant cluster %#q", key.ClusterID(&cr))
 	}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -29,7 +30,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			RoleName: aws.String(key.ControlPlaneNodeRole(cr)),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.IAM.ListAttachedRolePolicies(i)
+		attached, err := awslist.AttachedRolePolicies(ctx, cc.Client.TenantCluster.AWS.IAM, i)
 		if IsNotFound(err) {
 			r.logger.Debugf(ctx, "no attached policies")
 			r.logger.Debugf(ctx, "canceling resource")
@@ -38,7 +39,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 			return microerror.Mask(err)
 		}
 
-		for _, p := range o.AttachedPolicies {
+		for _, p := range attached {
 			policies = append(policies, *p.PolicyArn)
 		}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -78,13 +79,13 @@ func getVPCPeeringConnectionByFilter(ctx context.Context, filterKey string, filt
 		},
 	}
 
-	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeVpcPeeringConnections(i)
+	vpcPeeringConnections, err := awslist.VpcPeeringConnections(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
 	var vpcPeeringConnectionIDs []*string
-	for _, s := range o.VpcPeeringConnections {
+	for _, s := range vpcPeeringConnections {
 		if isTCCPVPCPeering(s.Tags) {
 			// skip vpc peering connection created by the TCCP CF stack
 			// it will be deleted by the CF
@@ -12,6 +12,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -121,15 +122,15 @@ func (r *Resource) lookupByName(ctx context.Context, client EC2, name string) (*
 		},
 	}
 
-	o, err := client.DescribeRouteTables(i)
+	routeTables, err := awslist.RouteTables(ctx, client, i)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	if len(o.RouteTables) != 1 {
-		return nil, microerror.Maskf(executionFailedError, "expected one route table, got %d", len(o.RouteTables))
+	if len(routeTables) != 1 {
+		return nil, microerror.Maskf(executionFailedError, "expected one route table, got %d", len(routeTables))
 	}
 
-	rt := o.RouteTables[0]
+	rt := routeTables[0]
 
 	r.logger.Debugf(ctx, "found route table for %#q", name)
 
@@ -156,15 +157,15 @@ func (r *Resource) lookupByTag(ctx context.Context, client EC2, installation str
 		},
 	}
 
-	o, err := client.DescribeRouteTables(i)
+	routeTables, err := awslist.RouteTables(ctx, client, i)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	if len(o.RouteTables) == 0 {
+	if len(routeTables) == 0 {
 		return nil, microerror.Maskf(executionFailedError, "expected at least one route table, got 0")
 	}
 
-	r.logger.Debugf(ctx, "found %d route tables for installation %#q", len(o.RouteTables), installation)
+	r.logger.Debugf(ctx, "found %d route tables for installation %#q", len(routeTables), installation)
 
-	return o.RouteTables, nil
+	return routeTables, nil
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -119,16 +120,16 @@ func (r *Resource) lookup(ctx context.Context, client EC2, installationName stri
 			},
 		}
 
-		o, err := client.DescribeVpcs(i)
+		vpcs, err := awslist.Vpcs(ctx, client, i)
 		if err != nil {
 			return "", "", microerror.Mask(err)
 		}
-		if len(o.Vpcs) != 1 {
-			return "", "", microerror.Maskf(executionFailedError, "expected one vpc, got %d", len(o.Vpcs))
+		if len(vpcs) != 1 {
+			return "", "", microerror.Maskf(executionFailedError, "expected one vpc, got %d", len(vpcs))
 		}
 
-		vpcCIDR = *o.Vpcs[0].CidrBlock
-		vpcID = *o.Vpcs[0].VpcId
+		vpcCIDR = *vpcs[0].CidrBlock
+		vpcID = *vpcs[0].VpcId
 
 		r.logger.Debugf(ctx, "found vpc cidr %#q and vpc id %#q for %#q", vpcCIDR, vpcID, installationName)
 	}
@@ -0,0 +1,142 @@
+package cpvpcassociation
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+type association struct {
+	// CrossAccount is whether the control plane VPC and the hosted zone live in
+	// different accounts, which requires the association to be authorized.
+	CrossAccount bool
+	HostedZoneID string
+	Region       string
+	VPCID        string
+}
+
+// newAssociation returns the association of the control plane VPC with the
+// internal hosted zone of the tenant cluster. The VPCs of the control plane
+// and the tenant cluster are peered, so they live in the same region.
+func newAssociation(cr infrastructurev1alpha3.AWSCluster, cc *controllercontext.Context) association {
+	return association{
+		CrossAccount: cc.Status.ControlPlane.AWSAccountID != cc.Status.TenantCluster.AWS.AccountID,
+		HostedZoneID: cc.Status.TenantCluster.DNS.InternalHostedZoneID,
+		Region:       key.Region(cr),
+		VPCID:        cc.Status.ControlPlane.VPC.ID,
+	}
+}
+
+// ensureAssociation associates the control plane VPC with the hosted zone, or
+// removes the association, depending on desired.
+func (r *Resource) ensureAssociation(ctx context.Context, tenant TenantClusterRoute53, cp ControlPlaneRoute53, a association, desired bool) error {
+	var associated bool
+	{
+		r.logger.Debugf(ctx, "finding association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+
+		i := &route53.GetHostedZoneInput{
+			Id: aws.String(a.HostedZoneID),
+		}
+
+		o, err := tenant.GetHostedZone(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, v := range o.VPCs {
+			if aws.StringValue(v.VPCId) == a.VPCID {
+				associated = true
+			}
+		}
+
+		if associated {
+			r.logger.Debugf(ctx, "found association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+		} else {
+			r.logger.Debugf(ctx, "did not find association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+		}
+	}
+
+	vpc := &route53.VPC{
+		VPCId:     aws.String(a.VPCID),
+		VPCRegion: aws.String(a.Region),
+	}
+
+	if desired && !associated {
+		if a.CrossAccount {
+			r.logger.Debugf(ctx, "authorizing association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+
+			i := &route53.CreateVPCAssociationAuthorizationInput{
+				HostedZoneId: aws.String(a.HostedZoneID),
+				VPC:          vpc,
+			}
+
+			_, err := tenant.CreateVPCAssociationAuthorization(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "authorized association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+		}
+
+		{
+			r.logger.Debugf(ctx, "associating control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+
+			i := &route53.AssociateVPCWithHostedZoneInput{
+				HostedZoneId: aws.String(a.HostedZoneID),
+				VPC:          vpc,
+			}
+
+			_, err := cp.AssociateVPCWithHostedZone(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "associated control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+		}
+
+		// The authorization is only needed for creating the association. AWS
+		// recommends to remove it afterwards, so that the association cannot be
+		// recreated by accident once it got removed.
+		if a.CrossAccount {
+			r.logger.Debugf(ctx, "removing authorization of association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+
+			i := &route53.DeleteVPCAssociationAuthorizationInput{
+				HostedZoneId: aws.String(a.HostedZoneID),
+				VPC:          vpc,
+			}
+
+			_, err := tenant.DeleteVPCAssociationAuthorization(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "removed authorization of association of control plane vpc %#q with hosted zone %#q", a.VPCID, a.HostedZoneID)
+		}
+	}
+
+	if !desired && associated {
+		r.logger.Debugf(ctx, "disassociating control plane vpc %#q from hosted zone %#q", a.VPCID, a.HostedZoneID)
+
+		i := &route53.DisassociateVPCFromHostedZoneInput{
+			HostedZoneId: aws.String(a.HostedZoneID),
+			VPC:          vpc,
+		}
+
+		_, err := cp.DisassociateVPCFromHostedZone(i)
+		if IsVPCAssociationNotFound(err) {
+			// fall through
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "disassociated control plane vpc %#q from hosted zone %#q", a.VPCID, a.HostedZoneID)
+	}
+
+	return nil
+}
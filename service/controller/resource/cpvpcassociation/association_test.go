@@ -0,0 +1,188 @@
+package cpvpcassociation
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeRoute53 struct {
+	calls          *[]string
+	disassociateFn func() error
+	vpcs           []string
+}
+
+func (f *fakeRoute53) AssociateVPCWithHostedZone(input *route53.AssociateVPCWithHostedZoneInput) (*route53.AssociateVPCWithHostedZoneOutput, error) {
+	*f.calls = append(*f.calls, "associate "+aws.StringValue(input.VPC.VPCId)+" "+aws.StringValue(input.HostedZoneId))
+	return &route53.AssociateVPCWithHostedZoneOutput{}, nil
+}
+
+func (f *fakeRoute53) CreateVPCAssociationAuthorization(input *route53.CreateVPCAssociationAuthorizationInput) (*route53.CreateVPCAssociationAuthorizationOutput, error) {
+	*f.calls = append(*f.calls, "authorize "+aws.StringValue(input.VPC.VPCId)+" "+aws.StringValue(input.HostedZoneId))
+	return &route53.CreateVPCAssociationAuthorizationOutput{}, nil
+}
+
+func (f *fakeRoute53) DeleteVPCAssociationAuthorization(input *route53.DeleteVPCAssociationAuthorizationInput) (*route53.DeleteVPCAssociationAuthorizationOutput, error) {
+	*f.calls = append(*f.calls, "unauthorize "+aws.StringValue(input.VPC.VPCId)+" "+aws.StringValue(input.HostedZoneId))
+	return &route53.DeleteVPCAssociationAuthorizationOutput{}, nil
+}
+
+func (f *fakeRoute53) DisassociateVPCFromHostedZone(input *route53.DisassociateVPCFromHostedZoneInput) (*route53.DisassociateVPCFromHostedZoneOutput, error) {
+	*f.calls = append(*f.calls, "disassociate "+aws.StringValue(input.VPC.VPCId)+" "+aws.StringValue(input.HostedZoneId))
+	if f.disassociateFn != nil {
+		return nil, f.disassociateFn()
+	}
+	return &route53.DisassociateVPCFromHostedZoneOutput{}, nil
+}
+
+func (f *fakeRoute53) GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	var vpcs []*route53.VPC
+	for _, v := range f.vpcs {
+		vpcs = append(vpcs, &route53.VPC{VPCId: aws.String(v)})
+	}
+
+	return &route53.GetHostedZoneOutput{VPCs: vpcs}, nil
+}
+
+func Test_Resource_ensureAssociation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		association    association
+		desired        bool
+		vpcs           []string
+		disassociateFn func() error
+		expectedCalls  []string
+	}{
+		{
+			name: "case 0: associate control plane vpc of the same account",
+			association: association{
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired: true,
+			vpcs:    []string{"vpc-tc"},
+			expectedCalls: []string{
+				"associate vpc-cp Z1",
+			},
+		},
+		{
+			name: "case 1: associate control plane vpc of another account",
+			association: association{
+				CrossAccount: true,
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired: true,
+			vpcs:    []string{"vpc-tc"},
+			expectedCalls: []string{
+				"authorize vpc-cp Z1",
+				"associate vpc-cp Z1",
+				"unauthorize vpc-cp Z1",
+			},
+		},
+		{
+			name: "case 2: existing association is kept",
+			association: association{
+				CrossAccount: true,
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired:       true,
+			vpcs:          []string{"vpc-tc", "vpc-cp"},
+			expectedCalls: nil,
+		},
+		{
+			name: "case 3: existing association is removed when not desired",
+			association: association{
+				CrossAccount: true,
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired: false,
+			vpcs:    []string{"vpc-tc", "vpc-cp"},
+			expectedCalls: []string{
+				"disassociate vpc-cp Z1",
+			},
+		},
+		{
+			name: "case 4: missing association is not removed",
+			association: association{
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired:       false,
+			vpcs:          []string{"vpc-tc"},
+			expectedCalls: nil,
+		},
+		{
+			name: "case 5: association removed in the meantime is tolerated",
+			association: association{
+				HostedZoneID: "Z1",
+				Region:       "eu-central-1",
+				VPCID:        "vpc-cp",
+			},
+			desired: false,
+			vpcs:    []string{"vpc-tc", "vpc-cp"},
+			disassociateFn: func() error {
+				return awserr.New(route53.ErrCodeVPCAssociationNotFound, "not found", nil)
+			},
+			expectedCalls: []string{
+				"disassociate vpc-cp Z1",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			var r *Resource
+			{
+				c := Config{
+					Logger: microloggertest.New(),
+
+					Route53Enabled: true,
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Both accounts record their calls in the same order they happen.
+			var calls []string
+
+			tenant := &fakeRoute53{
+				calls: &calls,
+				vpcs:  tc.vpcs,
+			}
+			cp := &fakeRoute53{
+				calls:          &calls,
+				disassociateFn: tc.disassociateFn,
+			}
+
+			err = r.ensureAssociation(context.Background(), tenant, cp, tc.association, tc.desired)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(calls, tc.expectedCalls) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedCalls, calls))
+			}
+		})
+	}
+}
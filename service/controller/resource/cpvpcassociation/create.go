@@ -0,0 +1,50 @@
+package cpvpcassociation
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !r.route53Enabled {
+		r.logger.Debugf(ctx, "route53 disabled")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+	if !cc.Status.TenantCluster.TCCP.PrivateCluster {
+		r.logger.Debugf(ctx, "not a private cluster")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+	if cc.Status.TenantCluster.DNS.InternalHostedZoneID == "" {
+		r.logger.Debugf(ctx, "did not find the tenant cluster's internal hosted zone ID in the controller context")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	err = r.ensureAssociation(
+		ctx,
+		cc.Client.TenantCluster.AWS.Route53,
+		cc.Client.ControlPlane.AWS.Route53,
+		newAssociation(cr, cc),
+		key.PrivateHostedZoneControlPlaneVPC(cr),
+	)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package cpvpcassociation
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+// EnsureDeleted removes the association of the control plane VPC before the
+// TCCP stack deletes the internal hosted zone, so that no association managed
+// from the control plane account outlives the tenant cluster.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !r.route53Enabled {
+		r.logger.Debugf(ctx, "route53 disabled")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	var a association
+	{
+		r.logger.Debugf(ctx, "finding the tenant cluster's private hosted zone ID")
+
+		id, err := findPrivateHostedZoneID(ctx, &cr, cc)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if id == "" {
+			r.logger.Debugf(ctx, "did not find the tenant cluster's private hosted zone ID")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		r.logger.Debugf(ctx, "found the tenant cluster's private hosted zone ID %#q", id)
+
+		a = newAssociation(cr, cc)
+		a.HostedZoneID = id
+	}
+
+	err = r.ensureAssociation(
+		ctx,
+		cc.Client.TenantCluster.AWS.Route53,
+		cc.Client.ControlPlane.AWS.Route53,
+		a,
+		false,
+	)
+	if IsHostedZoneNotFound(err) {
+		r.logger.Debugf(ctx, "the tenant cluster's internal hosted zone does not exist anymore")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cpvpcassociation
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+// IsHostedZoneNotFound asserts the Route53 error returned in case the hosted
+// zone does not exist anymore.
+func IsHostedZoneNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	return aerr.Code() == route53.ErrCodeNoSuchHostedZone
+}
+
+// IsVPCAssociationNotFound asserts the Route53 error returned in case the VPC
+// is not associated with the hosted zone.
+func IsVPCAssociationNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	return aerr.Code() == route53.ErrCodeVPCAssociationNotFound
+}
@@ -0,0 +1,65 @@
+package cpvpcassociation
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpoutputs"
+	cloudformationutils "github.com/giantswarm/aws-operator/v16/service/internal/cloudformation"
+)
+
+// findPrivateHostedZoneID returns the ID of the internal hosted zone of a
+// private cluster based on the outputs of the TCCP stack. The controller
+// context is not populated with the stack outputs on delete events, which is
+// why the outputs are looked up here directly. An empty ID is returned in case
+// the stack outputs are not available or the cluster is not a private
+// cluster.
+func findPrivateHostedZoneID(ctx context.Context, cr key.LabelsGetter, cc *controllercontext.Context) (string, error) {
+	var err error
+
+	var cloudFormation *cloudformationutils.CloudFormation
+	{
+		c := cloudformationutils.Config{
+			Client: cc.Client.TenantCluster.AWS.CloudFormation,
+		}
+
+		cloudFormation, err = cloudformationutils.New(c)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	}
+
+	o, _, err := cloudFormation.DescribeOutputsAndStatus(key.StackNameTCCP(cr))
+	if cloudformationutils.IsStackNotFound(err) {
+		return "", nil
+	} else if cloudformationutils.IsOutputsNotAccessible(err) {
+		return "", nil
+	} else if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(o, tccpoutputs.PrivateClusterKey)
+		if cloudformationutils.IsOutputNotFound(err) {
+			return "", nil
+		} else if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		if v != "true" {
+			return "", nil
+		}
+	}
+
+	v, err := cloudFormation.GetOutputValue(o, tccpoutputs.InternalHostedZoneID)
+	if cloudformationutils.IsOutputNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return v, nil
+}
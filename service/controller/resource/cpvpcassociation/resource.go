@@ -0,0 +1,47 @@
+package cpvpcassociation
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	Name = "cpvpcassociation"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+
+	Route53Enabled bool
+}
+
+// Resource associates the control plane VPC with the internal hosted zone of
+// private clusters, so that the control plane can resolve the records of
+// private clusters, which are not delegated to from the control plane's hosted
+// zones. The association cannot be managed by the TCCP stack, because the
+// control plane VPC usually lives in a different account than the hosted zone.
+// Cross account associations have to be authorized by the account of the
+// hosted zone before the account of the VPC can create them.
+type Resource struct {
+	logger micrologger.Logger
+
+	route53Enabled bool
+}
+
+func New(config Config) (*Resource, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		logger: config.Logger,
+
+		route53Enabled: config.Route53Enabled,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
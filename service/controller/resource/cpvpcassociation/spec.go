@@ -0,0 +1,18 @@
+package cpvpcassociation
+
+import "github.com/aws/aws-sdk-go/service/route53"
+
+// ControlPlaneRoute53 is the Route53 client of the account of the control
+// plane VPC.
+type ControlPlaneRoute53 interface {
+	AssociateVPCWithHostedZone(input *route53.AssociateVPCWithHostedZoneInput) (*route53.AssociateVPCWithHostedZoneOutput, error)
+	DisassociateVPCFromHostedZone(input *route53.DisassociateVPCFromHostedZoneInput) (*route53.DisassociateVPCFromHostedZoneOutput, error)
+}
+
+// TenantClusterRoute53 is the Route53 client of the account of the internal
+// hosted zone of the tenant cluster.
+type TenantClusterRoute53 interface {
+	CreateVPCAssociationAuthorization(input *route53.CreateVPCAssociationAuthorizationInput) (*route53.CreateVPCAssociationAuthorizationOutput, error)
+	DeleteVPCAssociationAuthorization(input *route53.DeleteVPCAssociationAuthorizationInput) (*route53.DeleteVPCAssociationAuthorizationOutput, error)
+	GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error)
+}
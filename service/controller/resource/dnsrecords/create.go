@@ -0,0 +1,108 @@
+package dnsrecords
+
+import (
+	"context"
+	"fmt"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !r.dns.Enabled() {
+		r.logger.Debugf(ctx, "dns disabled")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+	if r.dns.Route53() {
+		r.logger.Debugf(ctx, "records managed by the tenant cluster's control plane cloud formation stack")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	records := newRecords(cr, cc)
+	if len(records) == 0 {
+		r.logger.Debugf(ctx, "did not find the tenant cluster's load balancer DNS names in the controller context")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	provider := r.dns.Provider(cc.Client.TenantCluster.AWS.Route53)
+	tcBaseDomain := key.TenantClusterBaseDomain(cr)
+
+	var zone dns.Zone
+	{
+		r.logger.Debugf(ctx, "finding zone enclosing %#q", tcBaseDomain)
+
+		zone, err = dns.FindEnclosingZone(ctx, provider, tcBaseDomain)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "found zone %#q enclosing %#q", zone.Name, tcBaseDomain)
+	}
+
+	{
+		r.logger.Debugf(ctx, "ensuring %d records in zone %#q", len(records), zone.Name)
+
+		err = provider.UpsertRecords(ctx, zone, records)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "ensured %d records in zone %#q", len(records), zone.Name)
+	}
+
+	return nil
+}
+
+// newRecords returns the records of the tenant cluster, which correspond to
+// the Route53 records of the TCCP stack. DNS providers other than Route53 do
+// not have split horizon zones, which is why the API record of private
+// clusters points to the internal API load balancer. No records are returned
+// as long as the TCCP stack does not expose the load balancer DNS names.
+func newRecords(cr infrastructurev1alpha3.AWSCluster, cc *controllercontext.Context) []dns.Record {
+	apiInternal := cc.Status.TenantCluster.DNS.APIInternalLoadBalancer
+	apiPublic := cc.Status.TenantCluster.DNS.APIPublicLoadBalancer
+	etcd := cc.Status.TenantCluster.DNS.EtcdLoadBalancer
+
+	if cc.Status.TenantCluster.TCCP.PrivateCluster {
+		apiPublic = apiInternal
+	}
+	if apiInternal == "" || apiPublic == "" || etcd == "" {
+		return nil
+	}
+
+	tcBaseDomain := key.TenantClusterBaseDomain(cr)
+
+	records := []dns.Record{
+		newCNAMERecord(fmt.Sprintf("api.%s", tcBaseDomain), apiPublic),
+		newCNAMERecord(fmt.Sprintf("internal-api.%s", tcBaseDomain), apiInternal),
+		newCNAMERecord(key.ClusterEtcdEndpoint(cr), etcd),
+		newCNAMERecord(fmt.Sprintf("*.%s", tcBaseDomain), fmt.Sprintf("ingress.%s", tcBaseDomain)),
+	}
+
+	return records
+}
+
+func newCNAMERecord(name, value string) dns.Record {
+	return dns.Record{
+		Name:   name,
+		TTL:    recordTTL,
+		Type:   dns.RecordTypeCNAME,
+		Values: []string{value},
+	}
+}
@@ -0,0 +1,122 @@
+package dnsrecords
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_Controller_Resource_DNSRecords_EnsureCreated(t *testing.T) {
+	zone := dns.Zone{ID: "gigantic.io", Name: "gigantic.io"}
+
+	testCases := []struct {
+		name            string
+		ctx             context.Context
+		route53         bool
+		expectedRecords []dns.Record
+	}{
+		{
+			name:    "case 0: records point to the public load balancers",
+			ctx:     withLoadBalancers(unittest.DefaultContext(), false),
+			route53: false,
+			expectedRecords: []dns.Record{
+				{Name: "*.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"}},
+				{Name: "api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"api.elb.amazonaws.com"}},
+				{Name: "etcd.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"etcd.elb.amazonaws.com"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+				{Name: "internal-api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"internal-api.elb.amazonaws.com"}},
+			},
+		},
+		{
+			name:    "case 1: api record of private clusters points to the internal load balancer",
+			ctx:     withLoadBalancers(unittest.DefaultContext(), true),
+			route53: false,
+			expectedRecords: []dns.Record{
+				{Name: "*.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"ingress.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io"}},
+				{Name: "api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"internal-api.elb.amazonaws.com"}},
+				{Name: "etcd.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"etcd.elb.amazonaws.com"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+				{Name: "internal-api.8y5ck.k8s.gauss.eu-central-1.aws.gigantic.io", TTL: 300, Type: dns.RecordTypeCNAME, Values: []string{"internal-api.elb.amazonaws.com"}},
+			},
+		},
+		{
+			name:    "case 2: records are not managed without load balancer dns names",
+			ctx:     unittest.DefaultContext(),
+			route53: false,
+			expectedRecords: []dns.Record{
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+			},
+		},
+		{
+			name:    "case 3: records are not managed in case of route53",
+			ctx:     withLoadBalancers(unittest.DefaultContext(), false),
+			route53: true,
+			expectedRecords: []dns.Record{
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeNS, Values: []string{"ns1.gigantic.io"}},
+				{Name: "gigantic.io", TTL: 300, Type: dns.RecordTypeSOA, Values: []string{"ns1.gigantic.io"}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			p := dns.NewFakeProvider()
+			p.AddZone(zone, []string{"ns1.gigantic.io"})
+
+			var err error
+
+			var r *Resource
+			{
+				c := Config{
+					DNS:    dns.NewFakeDNS(dns.FakeDNSConfig{Provider: p, Route53: tc.route53}),
+					Logger: microloggertest.New(),
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cr := unittest.DefaultCluster()
+			err = r.EnsureCreated(tc.ctx, &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			records := p.Records(zone)
+			if !cmp.Equal(records, tc.expectedRecords) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedRecords, records))
+			}
+		})
+	}
+}
+
+func withLoadBalancers(ctx context.Context, private bool) context.Context {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cc.Status.TenantCluster.DNS.APIInternalLoadBalancer = "internal-api.elb.amazonaws.com"
+	cc.Status.TenantCluster.DNS.EtcdLoadBalancer = "etcd.elb.amazonaws.com"
+	cc.Status.TenantCluster.TCCP.PrivateCluster = private
+
+	if !private {
+		cc.Status.TenantCluster.DNS.APIPublicLoadBalancer = "api.elb.amazonaws.com"
+	}
+
+	return ctx
+}
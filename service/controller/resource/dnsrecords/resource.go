@@ -0,0 +1,54 @@
+package dnsrecords
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
+)
+
+const (
+	Name = "dnsrecords"
+)
+
+const (
+	// recordTTL is the TTL of the records pointing to the load balancers of
+	// the tenant cluster, which is the same the TCCP stack uses for its
+	// Route53 records.
+	recordTTL = 300
+)
+
+type Config struct {
+	DNS    dns.Interface
+	Logger micrologger.Logger
+}
+
+// Resource manages the DNS records of tenant clusters in case they are not
+// managed in Route53 by the TCCP stack. The records are then upserted into the
+// zone enclosing the tenant cluster's base domain, pointing to the DNS names
+// of the load balancers exposed by the TCCP stack. The records are deleted by
+// the cleanuprecordsets resource.
+type Resource struct {
+	dns    dns.Interface
+	logger micrologger.Logger
+}
+
+func New(config Config) (*Resource, error) {
+	if config.DNS == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DNS must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		dns:    config.DNS,
+		logger: config.Logger,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -2,6 +2,7 @@ package drainerinitializer
 
 import (
 	"context"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -19,6 +20,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/internal/asg"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,6 +30,12 @@ const (
 	Name = "drainerinitializer"
 )
 
+const (
+	// warmedLifecycleStatePrefix is the prefix of the lifecycle states of
+	// instances in the warm pool of an ASG, e.g. Warmed:Terminating:Wait.
+	warmedLifecycleStatePrefix = "Warmed:"
+)
+
 type ResourceConfig struct {
 	ASG        asg.Interface
 	CtrlClient ctrlClient.Client
@@ -178,21 +186,34 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(i)
+		groups, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
 		var c int
-		for _, g := range o.AutoScalingGroups {
+		for _, g := range groups {
 			for _, i := range g.Instances {
 				c++
 				r.logger.Debugf(ctx, "checking instance %#q with state %#q", *i.InstanceId, *i.LifecycleState)
 
+				// Instances transitioning from or into the warm pool never joined the
+				// cluster or are about to join it and must not be drained.
+				if strings.HasPrefix(*i.LifecycleState, warmedLifecycleStatePrefix) {
+					continue
+				}
+
 				if *i.LifecycleState == autoscaling.LifecycleStateTerminatingWait || *i.LifecycleState == autoscaling.LifecycleStateTerminatingProceed {
 					instances = append(instances, i)
 				}
 			}
+
+			if g.WarmPoolConfiguration != nil {
+				err = r.completeWarmPoolLifeCycleHooks(ctx, asgName)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+			}
 		}
 
 		// In case there aren't any EC2 instances in the ASG we assume all draining
@@ -287,6 +308,56 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 	return nil
 }
 
+// completeWarmPoolLifeCycleHooks completes the lifecycle actions of instances
+// terminated from the warm pool of the given ASG. These instances never joined
+// the cluster, so there is nothing to drain.
+func (r *Resource) completeWarmPoolLifeCycleHooks(ctx context.Context, asgName string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var instanceIDs []string
+	{
+		r.logger.Debugf(ctx, "finding warm pool ec2 instances in %#q state", autoscaling.LifecycleStateWarmedTerminatingWait)
+
+		var nextToken *string
+		for {
+			i := &autoscaling.DescribeWarmPoolInput{
+				AutoScalingGroupName: aws.String(asgName),
+				NextToken:            nextToken,
+			}
+
+			o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeWarmPool(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, i := range o.Instances {
+				if aws.StringValue(i.LifecycleState) == autoscaling.LifecycleStateWarmedTerminatingWait {
+					instanceIDs = append(instanceIDs, aws.StringValue(i.InstanceId))
+				}
+			}
+
+			if o.NextToken == nil {
+				break
+			}
+			nextToken = o.NextToken
+		}
+
+		r.logger.Debugf(ctx, "found %d warm pool ec2 instances in %#q state", len(instanceIDs), autoscaling.LifecycleStateWarmedTerminatingWait)
+	}
+
+	for _, id := range instanceIDs {
+		err = r.completeLifeCycleHook(ctx, id, asgName)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
 func (r *Resource) privateDNSForInstance(ctx context.Context, instanceID string) (string, error) {
 	i := &ec2.DescribeInstancesInput{
 		InstanceIds: []*string{
@@ -299,19 +370,16 @@ func (r *Resource) privateDNSForInstance(ctx context.Context, instanceID string)
 		return "", microerror.Mask(err)
 	}
 
-	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+	instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
 
-	if len(o.Reservations) != 1 {
-		return "", microerror.Maskf(executionFailedError, "expected 1 reservation, got %d", len(o.Reservations))
-	}
-	if len(o.Reservations[0].Instances) != 1 {
-		return "", microerror.Maskf(executionFailedError, "expected 1 node, got %d", len(o.Reservations[0].Instances))
+	if len(instances) != 1 {
+		return "", microerror.Maskf(executionFailedError, "expected 1 node, got %d", len(instances))
 	}
 
-	privateDNS := *o.Reservations[0].Instances[0].PrivateDnsName
+	privateDNS := *instances[0].PrivateDnsName
 
 	return privateDNS, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/operatorkit/v7/pkg/controller/context/resourcecanceledcontext"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interface{}, error) {
@@ -111,34 +113,25 @@ func (r Resource) searchMasterInstances(ctx context.Context, cr infrastructurev1
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(instancesInput)
+		running, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, instancesInput)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 
-		if len(o.Reservations) == 0 {
+		if len(running) == 0 {
 			return nil, microerror.Maskf(notFoundError, "master instance")
 		}
 
 		// check for health of the instance
-		instanceHealthInput := &elb.DescribeInstanceHealthInput{
-			LoadBalancerName: aws.String(key.ELBNameAPI(&cr)),
-		}
-
-		o2, err := cc.Client.TenantCluster.AWS.ELB.DescribeInstanceHealth(instanceHealthInput)
+		healthy, err := r.healthyMasterInstanceIDs(ctx, cr)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 
-		for _, r := range o.Reservations {
-			for _, i := range r.Instances {
-				for _, iState := range o2.InstanceStates {
-					if *i.InstanceId == *iState.InstanceId && *iState.State == key.ELBInstanceStateInService {
-						instances = append(instances, i)
-					}
-				}
+		for _, i := range running {
+			if healthy[*i.InstanceId] {
+				instances = append(instances, i)
 			}
-
 		}
 	}
 
@@ -148,3 +141,57 @@ func (r Resource) searchMasterInstances(ctx context.Context, cr infrastructurev1
 
 	return instances, nil
 }
+
+// healthyMasterInstanceIDs returns the IDs of the master instances which are
+// healthy from the point of view of the API load balancer the master instances
+// are currently attached to, either the classic ELB or the target group of the
+// NLB. Private clusters only have the internal API load balancer.
+func (r Resource) healthyMasterInstanceIDs(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (map[string]bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	targetGroupARN := cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.API
+	loadBalancerName := key.ELBNameAPI(&cr)
+	if cc.Status.TenantCluster.TCCP.PrivateCluster {
+		targetGroupARN = cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.APIInternal
+		loadBalancerName = key.InternalELBNameAPI(&cr)
+	}
+
+	healthy := map[string]bool{}
+
+	if cc.Status.TenantCluster.TCCPN.LoadBalancerType == key.LoadBalancerTypeNetwork {
+		i := &elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.ELBv2.DescribeTargetHealth(i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, d := range o.TargetHealthDescriptions {
+			if *d.TargetHealth.State == elbv2.TargetHealthStateEnumHealthy {
+				healthy[*d.Target.Id] = true
+			}
+		}
+	} else {
+		i := &elb.DescribeInstanceHealthInput{
+			LoadBalancerName: aws.String(loadBalancerName),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.ELB.DescribeInstanceHealth(i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, s := range o.InstanceStates {
+			if *s.State == key.ELBInstanceStateInService {
+				healthy[*s.InstanceId] = true
+			}
+		}
+	}
+
+	return healthy, nil
+}
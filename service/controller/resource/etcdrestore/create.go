@@ -0,0 +1,514 @@
+package etcdrestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
+)
+
+const (
+	conditionType = "EtcdRestored"
+
+	phaseCompleted        = "Completed"
+	phaseFailed           = "Failed"
+	phaseReplacingVolumes = "ReplacingVolumes"
+	phaseStartingMasters  = "StartingMasters"
+	phaseStoppingMasters  = "StoppingMasters"
+)
+
+// EnsureCreated advances the restore of the etcd volumes by one step per
+// reconciliation. Every step only changes the annotations of the
+// AWSControlPlane CR, which the tccpn resource renders into the TCCPN stack.
+// The next step is only taken once the TCCPN stack got updated accordingly.
+//
+//	StoppingMasters   all control plane nodes are stopped.
+//	ReplacingVolumes  the etcd volumes get created from the snapshots.
+//	StartingMasters   the control plane nodes are started one at a time.
+//	Completed         all control plane nodes are running again.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToControlPlane(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	request, ok := key.ControlPlaneEtcdRestore(cr)
+	if !ok {
+		// In case the request got removed while the control plane nodes are
+		// stopped, we abort the restore so that they get started again.
+		_, restoring, err := key.ControlPlaneEtcdRestoreRunning(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if restoring {
+			return r.fail(ctx, cr, "the request got removed")
+		}
+
+		return nil
+	}
+
+	{
+		if cc.Status.TenantCluster.TCCPN.InstanceType == "" {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack does not exist yet")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		if cc.Status.TenantCluster.TCCPN.IsTransitioning {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack is in transitioning state")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		if cc.Status.TenantCluster.TCCPN.EtcdRestore != key.ControlPlaneEtcdRestoreState(cr) {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack does not reflect the current etcd restore step yet")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+	}
+
+	var mappings []hamaster.Mapping
+	{
+		mappings, err = r.haMaster.Mapping(ctx, &cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	condition, err := currentCondition(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	switch condition.Reason {
+	case phaseStoppingMasters:
+		stopped, err := r.instancesStopped(ctx, cr, mappings)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if !stopped {
+			r.logger.Debugf(ctx, "waiting for control plane nodes to be stopped")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		ids, err := r.restoreSnapshots(ctx, cr, request, mappings)
+		if IsSnapshotNotFound(err) {
+			return r.fail(ctx, cr, microerror.Pretty(err, false))
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// The snapshots the current etcd volumes reference are kept in case the
+		// restore fails before the volumes got replaced.
+		message := fmt.Sprintf("replacing etcd volumes with volumes created from snapshots %#q", key.ControlPlaneEtcdRestoreSnapshotIDsValue(ids))
+		set := map[string]string{
+			annotation.EtcdRestoreRollback:  cr.GetAnnotations()[annotation.EtcdRestoreSnapshots],
+			annotation.EtcdRestoreSnapshots: key.ControlPlaneEtcdRestoreSnapshotIDsValue(ids),
+		}
+
+		err = r.transition(ctx, cr, phaseReplacingVolumes, message, set, nil)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case phaseReplacingVolumes:
+		message := fmt.Sprintf("starting control plane node 1 of %d", len(mappings))
+		set := map[string]string{
+			annotation.EtcdRestoreRunning: "1",
+		}
+
+		err = r.transition(ctx, cr, phaseStartingMasters, message, set, nil)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case phaseStartingMasters:
+		running, _, err := key.ControlPlaneEtcdRestoreRunning(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if running < 1 || running > len(mappings) {
+			return microerror.Maskf(executionFailedError, "expected running control plane nodes between 1 and %d, got %d", len(mappings), running)
+		}
+
+		healthy, err := r.instanceHealthy(ctx, cr, mappings[running-1])
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if !healthy {
+			r.logger.Debugf(ctx, "waiting for control plane node %d of %d to be healthy", running, len(mappings))
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		if running < len(mappings) {
+			message := fmt.Sprintf("starting control plane node %d of %d", running+1, len(mappings))
+			set := map[string]string{
+				annotation.EtcdRestoreRunning: fmt.Sprintf("%d", running+1),
+			}
+
+			err = r.transition(ctx, cr, phaseStartingMasters, message, set, nil)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		} else {
+			message := fmt.Sprintf("restored etcd volumes from snapshots %#q", cr.GetAnnotations()[annotation.EtcdRestoreSnapshots])
+			remove := []string{
+				annotation.EtcdRestore,
+				annotation.EtcdRestoreRollback,
+				annotation.EtcdRestoreRunning,
+			}
+
+			err = r.transition(ctx, cr, phaseCompleted, message, nil, remove)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+	default:
+		// Any other phase means that a new restore got requested. We resolve the
+		// snapshots upfront in order to fail before stopping any control plane
+		// node.
+		_, err := r.restoreSnapshots(ctx, cr, request, mappings)
+		if IsSnapshotNotFound(err) {
+			return r.fail(ctx, cr, microerror.Pretty(err, false))
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		message := fmt.Sprintf("stopping control plane nodes for restoring etcd volumes from snapshot %#q", request)
+		set := map[string]string{
+			annotation.EtcdRestoreRunning: "0",
+		}
+
+		err = r.transition(ctx, cr, phaseStoppingMasters, message, set, nil)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// fail aborts the restore. The request gets removed and all control plane
+// nodes are started again with their current etcd volumes. In case the restore
+// fails before the TCCPN stack replaced the etcd volumes, the snapshots of the
+// current etcd volumes are restored, so that the TCCPN stack does not replace
+// them anymore. The failure is recorded as terminal condition, which only a new
+// request resets.
+func (r *Resource) fail(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, reason string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	message := fmt.Sprintf("failed to restore etcd volumes: %s", reason)
+	var set map[string]string
+	remove := []string{
+		annotation.EtcdRestore,
+		annotation.EtcdRestoreRollback,
+		annotation.EtcdRestoreRunning,
+	}
+
+	rollback, ok := cr.GetAnnotations()[annotation.EtcdRestoreRollback]
+	if ok {
+		condition, err := currentCondition(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		replaced := condition.Reason != phaseReplacingVolumes || cc.Status.TenantCluster.TCCPN.EtcdRestore == key.ControlPlaneEtcdRestoreState(cr)
+
+		if !replaced && cc.Status.TenantCluster.TCCPN.IsTransitioning {
+			r.logger.Debugf(ctx, "waiting for the tenant cluster's control plane nodes cloud formation stack to finish transitioning before failing the etcd restore")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		if replaced {
+			message += fmt.Sprintf(", etcd volumes were already replaced with volumes created from snapshots %#q", cr.GetAnnotations()[annotation.EtcdRestoreSnapshots])
+		} else if rollback == "" {
+			remove = append(remove, annotation.EtcdRestoreSnapshots)
+		} else {
+			set = map[string]string{
+				annotation.EtcdRestoreSnapshots: rollback,
+			}
+		}
+	}
+
+	err = r.transition(ctx, cr, phaseFailed, message, set, remove)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// instanceHealthy returns whether the ASG of the given master has a healthy
+// instance in service.
+func (r *Resource) instanceHealthy(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, m hamaster.Mapping) (bool, error) {
+	groups, err := r.autoScalingGroups(ctx, cr, []hamaster.Mapping{m})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, g := range groups {
+		for _, i := range g.Instances {
+			if aws.StringValue(i.LifecycleState) == autoscaling.LifecycleStateInService && aws.StringValue(i.HealthStatus) == "Healthy" {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// instancesStopped returns whether the ASGs of all given masters are free of
+// instances.
+func (r *Resource) instancesStopped(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, mappings []hamaster.Mapping) (bool, error) {
+	groups, err := r.autoScalingGroups(ctx, cr, mappings)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, g := range groups {
+		if len(g.Instances) != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *Resource) autoScalingGroups(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, mappings []hamaster.Mapping) ([]*autoscaling.Group, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var names []*string
+	for _, m := range mappings {
+		i := &cloudformation.DescribeStackResourceInput{
+			LogicalResourceId: aws.String(key.ControlPlaneASGResourceName(&cr, m.ID)),
+			StackName:         aws.String(key.StackNameTCCPN(&cr)),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeStackResource(i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		names = append(names, o.StackResourceDetail.PhysicalResourceId)
+	}
+
+	i := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: names,
+	}
+
+	groups, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if len(groups) != len(names) {
+		return nil, microerror.Maskf(executionFailedError, "expected %d auto scaling groups, got %d", len(names), len(groups))
+	}
+
+	return groups, nil
+}
+
+func (r *Resource) restoreSnapshots(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, request string, mappings []hamaster.Mapping) (map[int]string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var ebsService ebs.Interface
+	{
+		c := ebs.Config{
+			Client: cc.Client.TenantCluster.AWS.EC2,
+			Logger: r.logger,
+		}
+
+		ebsService, err = ebs.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var snapshots []ebs.Snapshot
+	{
+		tags := map[string]string{
+			key.TagCluster:  key.ClusterID(&cr),
+			key.TagSnapshot: key.EtcdScheduledSnapshotValue,
+		}
+
+		// An explicitly requested snapshot may be any snapshot of the cluster,
+		// e.g. one taken manually or for the migration to HA masters.
+		if request != key.EtcdRestoreLatest {
+			delete(tags, key.TagSnapshot)
+		}
+
+		snapshots, err = ebsService.ListSnapshots(ctx, tags)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	// Snapshots taken manually of the current etcd volumes may lack the tags of
+	// the cluster. So we also consider all snapshots of these volumes in case a
+	// snapshot is requested explicitly and derive the master IDs of untagged
+	// snapshots from the volumes they were taken of.
+	if request != key.EtcdRestoreLatest {
+		cl, err := r.toClusterFunc(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		volumes, err := ebsService.ListVolumes(ctx, cl, ebs.NewControlPlaneEtcdVolumeFilter(cl))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		masterIDs := map[string]int{}
+		var volumeIDs []string
+		for _, v := range volumes {
+			id, ok := key.ControlPlaneVolumeMasterID(&cr, v.Name)
+			if ok {
+				masterIDs[v.VolumeID] = id
+				volumeIDs = append(volumeIDs, v.VolumeID)
+			}
+		}
+
+		if len(volumeIDs) != 0 {
+			volumeSnapshots, err := ebsService.ListVolumeSnapshots(ctx, volumeIDs)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			known := map[string]bool{}
+			for _, s := range snapshots {
+				known[s.SnapshotID] = true
+			}
+			for _, s := range volumeSnapshots {
+				if !known[s.SnapshotID] {
+					snapshots = append(snapshots, s)
+				}
+			}
+		}
+
+		for _, s := range snapshots {
+			id, ok := masterIDs[s.VolumeID]
+			if _, tagged := s.Tags[key.TagMasterID]; ok && !tagged {
+				s.Tags[key.TagMasterID] = strconv.Itoa(id)
+			}
+		}
+	}
+
+	var masters []int
+	for _, m := range mappings {
+		masters = append(masters, m.ID)
+	}
+
+	ids, err := restoreSnapshots(snapshots, request, masters)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return ids, nil
+}
+
+// transition records the given phase as condition on the AWSControlPlane CR,
+// together with the annotations to set and to remove, and emits an event for
+// it.
+func (r *Resource) transition(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, phase string, message string, set map[string]string, remove []string) error {
+	r.logger.Debugf(ctx, message)
+
+	status := metav1.ConditionFalse
+	if phase == phaseCompleted {
+		status = metav1.ConditionTrue
+	}
+
+	c := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		Reason:             phase,
+		Message:            message,
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	latest := &infrastructurev1alpha3.AWSControlPlane{}
+	err = r.ctrlClient.Get(ctx, ctrlClient.ObjectKey{Name: cr.GetName(), Namespace: cr.GetNamespace()}, latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	for k, v := range set {
+		latest.Annotations[k] = v
+	}
+	for _, k := range remove {
+		delete(latest.Annotations, k)
+	}
+	latest.Annotations[annotation.EtcdRestoreCondition] = string(b)
+
+	err = r.ctrlClient.Update(ctx, latest, &ctrlClient.UpdateOptions{Raw: &metav1.UpdateOptions{}})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.event.Emit(ctx, latest, "EtcdRestore"+phase, message)
+
+	return nil
+}
+
+// currentCondition returns the restore condition recorded on the given
+// AWSControlPlane CR. The returned condition is empty in case no restore
+// happened yet.
+func currentCondition(cr infrastructurev1alpha3.AWSControlPlane) (metav1.Condition, error) {
+	var c metav1.Condition
+
+	v, ok := cr.GetAnnotations()[annotation.EtcdRestoreCondition]
+	if !ok {
+		return c, nil
+	}
+
+	err := json.Unmarshal([]byte(v), &c)
+	if err != nil {
+		return metav1.Condition{}, microerror.Mask(err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,212 @@
+package etcdrestore
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_Controller_Resource_EtcdRestore_fail(t *testing.T) {
+	testCases := []struct {
+		name                string
+		annotations         map[string]string
+		phase               string
+		stackUpdated        bool
+		transitioning       bool
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "case 0: failing before stopping the control plane nodes keeps the snapshots of a previous restore",
+			annotations: map[string]string{
+				annotation.EtcdRestore:          "latest",
+				annotation.EtcdRestoreSnapshots: "1=snap-1a",
+			},
+			expectedAnnotations: map[string]string{
+				annotation.EtcdRestoreSnapshots: "1=snap-1a",
+			},
+		},
+		{
+			name: "case 1: failing before the etcd volumes got replaced removes the snapshots",
+			annotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "",
+				annotation.EtcdRestoreRunning:   "0",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+			phase:               phaseReplacingVolumes,
+			expectedAnnotations: map[string]string{},
+		},
+		{
+			name: "case 2: failing before the etcd volumes got replaced restores the snapshots of a previous restore",
+			annotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "1=snap-1a",
+				annotation.EtcdRestoreRunning:   "0",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+			phase: phaseReplacingVolumes,
+			expectedAnnotations: map[string]string{
+				annotation.EtcdRestoreSnapshots: "1=snap-1a",
+			},
+		},
+		{
+			name: "case 3: failing after the etcd volumes got replaced keeps the snapshots",
+			annotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "1=snap-1a",
+				annotation.EtcdRestoreRunning:   "0",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+			phase:        phaseReplacingVolumes,
+			stackUpdated: true,
+			expectedAnnotations: map[string]string{
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+		},
+		{
+			name: "case 4: failing while starting the control plane nodes keeps the snapshots",
+			annotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "1=snap-1a",
+				annotation.EtcdRestoreRunning:   "1",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+			phase: phaseStartingMasters,
+			expectedAnnotations: map[string]string{
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+		},
+		{
+			name: "case 5: failing waits for the stack to finish transitioning",
+			annotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "1=snap-1a",
+				annotation.EtcdRestoreRunning:   "0",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+			phase:         phaseReplacingVolumes,
+			transitioning: true,
+			expectedAnnotations: map[string]string{
+				annotation.EtcdRestoreRollback:  "1=snap-1a",
+				annotation.EtcdRestoreRunning:   "0",
+				annotation.EtcdRestoreSnapshots: "1=snap-1b",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			ctx := unittest.DefaultContextControlPlane()
+			k := unittest.FakeK8sClient()
+
+			cr := unittest.DefaultAWSControlPlane()
+			for a, v := range tc.annotations {
+				cr.Annotations[a] = v
+			}
+			if tc.phase != "" {
+				b, err := json.Marshal(metav1.Condition{Type: conditionType, Reason: tc.phase})
+				if err != nil {
+					t.Fatal(err)
+				}
+				cr.Annotations[annotation.EtcdRestoreCondition] = string(b)
+			}
+
+			err = k.CtrlClient().Create(ctx, &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			{
+				cc, err := controllercontext.FromContext(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cc.Status.TenantCluster.TCCPN.IsTransitioning = tc.transitioning
+				if tc.stackUpdated {
+					cc.Status.TenantCluster.TCCPN.EtcdRestore = key.ControlPlaneEtcdRestoreState(cr)
+				}
+			}
+
+			var h hamaster.Interface
+			{
+				c := hamaster.Config{
+					K8sClient: k,
+				}
+
+				h, err = hamaster.New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					CtrlClient: k.CtrlClient(),
+					Event:      recorder.New(recorder.Config{K8sClient: k}),
+					HAMaster:   h,
+					Logger:     microloggertest.New(),
+					ToClusterFunc: func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error) {
+						return unittest.DefaultCluster(), nil
+					},
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = r.fail(ctx, cr, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var latest infrastructurev1alpha3.AWSControlPlane
+			err = k.CtrlClient().Get(context.Background(), ctrlClient.ObjectKeyFromObject(&cr), &latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			condition, err := currentCondition(latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			annotations := map[string]string{}
+			for a, v := range latest.Annotations {
+				if a != annotation.EtcdRestoreCondition {
+					annotations[a] = v
+				}
+			}
+
+			if !cmp.Equal(annotations, tc.expectedAnnotations) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedAnnotations, annotations))
+			}
+
+			if tc.transitioning {
+				if condition.Reason != tc.phase {
+					t.Fatalf("expected condition reason %#q, got %#q", tc.phase, condition.Reason)
+				}
+			} else {
+				if condition.Reason != phaseFailed {
+					t.Fatalf("expected condition reason %#q, got %#q", phaseFailed, condition.Reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package etcdrestore
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+// executionFailedError is an error type for situations where Resource execution
+// cannot continue and must always fall back to operatorkit.
+//
+// This error should never be matched against and therefore there is no matcher
+// implement. For further information see:
+//
+//	https://github.com/giantswarm/fmt/blob/master/go/errors.md#matching-errors
+var executionFailedError = &microerror.Error{
+	Kind: "executionFailedError",
+}
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var snapshotNotFoundError = &microerror.Error{
+	Kind: "snapshotNotFoundError",
+}
+
+// IsSnapshotNotFound asserts snapshotNotFoundError.
+func IsSnapshotNotFound(err error) bool {
+	return microerror.Cause(err) == snapshotNotFoundError
+}
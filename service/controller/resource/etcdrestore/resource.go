@@ -0,0 +1,72 @@
+package etcdrestore
+
+import (
+	"context"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
+	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+)
+
+const (
+	Name = "etcdrestore"
+)
+
+type Config struct {
+	CtrlClient    ctrlClient.Client
+	Event         event.Interface
+	HAMaster      hamaster.Interface
+	Logger        micrologger.Logger
+	ToClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
+}
+
+// Resource restores the etcd volumes of the control plane nodes from EBS
+// snapshots once requested via annotation on the AWSControlPlane CR. The
+// restore is driven step by step through the TCCPN stack. First all control
+// plane nodes are stopped, then the etcd volumes are replaced with volumes
+// created from the snapshots and finally the control plane nodes are started
+// again one at a time. The progress is recorded as condition in the
+// annotations of the AWSControlPlane CR and emitted as events.
+type Resource struct {
+	ctrlClient    ctrlClient.Client
+	event         event.Interface
+	haMaster      hamaster.Interface
+	logger        micrologger.Logger
+	toClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
+}
+
+func New(config Config) (*Resource, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.HAMaster == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.HAMaster must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.ToClusterFunc == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ToClusterFunc must not be empty", config)
+	}
+
+	r := &Resource{
+		ctrlClient:    config.CtrlClient,
+		event:         config.Event,
+		haMaster:      config.HAMaster,
+		logger:        config.Logger,
+		toClusterFunc: config.ToClusterFunc,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
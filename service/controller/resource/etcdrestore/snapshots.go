@@ -0,0 +1,123 @@
+package etcdrestore
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+)
+
+const (
+	// snapshotRunWindow is the maximum distance of the start times of
+	// snapshots taken in the same run. The etcdsnapshot resource takes the
+	// snapshots of all etcd volumes within a single reconciliation. Snapshots
+	// of different etcd volumes taken manually have to be taken within this
+	// window as well in order to be restored together.
+	snapshotRunWindow = 5 * time.Minute
+)
+
+// restoreSnapshots maps the given master IDs to the IDs of the snapshots their
+// etcd volumes are restored from. All etcd volumes are restored from snapshots
+// taken in the same run, so that the restored etcd members are consistent. In
+// case key.EtcdRestoreLatest is requested, the latest run with completed
+// snapshots of all etcd volumes is used. Otherwise the requested snapshot is
+// used for the etcd volume it was taken from and the other etcd volumes are
+// restored from the snapshots taken in the same run. Snapshots without master
+// ID, like the one taken for the migration to HA masters, can only be restored
+// to a single master.
+func restoreSnapshots(snapshots []ebs.Snapshot, request string, masters []int) (map[int]string, error) {
+	byMaster := map[int][]ebs.Snapshot{}
+	var completed []ebs.Snapshot
+	for _, s := range snapshots {
+		if s.State != ec2.SnapshotStateCompleted {
+			continue
+		}
+
+		id, err := strconv.Atoi(s.Tags[key.TagMasterID])
+		if err != nil && len(masters) == 1 {
+			id, err = masters[0], nil
+		}
+		if err != nil {
+			continue
+		}
+
+		byMaster[id] = append(byMaster[id], s)
+		completed = append(completed, s)
+	}
+
+	if request != key.EtcdRestoreLatest {
+		for m, list := range byMaster {
+			for _, s := range list {
+				if s.SnapshotID == request {
+					return runSnapshots(byMaster, s, m, masters)
+				}
+			}
+		}
+
+		return nil, microerror.Maskf(snapshotNotFoundError, "completed snapshot %#q", request)
+	}
+
+	if len(completed) == 0 {
+		return nil, microerror.Maskf(snapshotNotFoundError, "completed snapshot for master %d", masters[0])
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].StartTime.After(completed[j].StartTime)
+	})
+
+	var err error
+	for _, s := range completed {
+		var ids map[int]string
+		ids, err = runSnapshots(byMaster, s, -1, masters)
+		if err == nil {
+			return ids, nil
+		}
+	}
+
+	return nil, microerror.Mask(err)
+}
+
+// runSnapshots maps the given master IDs to the IDs of the snapshots taken in
+// the same run as the given snapshot. The given snapshot itself is used for
+// the given master ID.
+func runSnapshots(byMaster map[int][]ebs.Snapshot, run ebs.Snapshot, master int, masters []int) (map[int]string, error) {
+	ids := map[int]string{}
+	for _, m := range masters {
+		if m == master {
+			ids[m] = run.SnapshotID
+			continue
+		}
+
+		var best ebs.Snapshot
+		for _, s := range byMaster[m] {
+			d := absDuration(s.StartTime.Sub(run.StartTime))
+			if d > snapshotRunWindow {
+				continue
+			}
+			if best.SnapshotID == "" || d < absDuration(best.StartTime.Sub(run.StartTime)) {
+				best = s
+			}
+		}
+
+		if best.SnapshotID == "" {
+			return nil, microerror.Maskf(snapshotNotFoundError, "completed snapshot for master %d taken in the same run as snapshot %#q", m, run.SnapshotID)
+		}
+
+		ids[m] = best.SnapshotID
+	}
+
+	return ids, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
@@ -0,0 +1,150 @@
+package etcdrestore
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+)
+
+func Test_EtcdRestore_restoreSnapshots(t *testing.T) {
+	now := time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	snapshot := func(id string, master string, start time.Time, state string) ebs.Snapshot {
+		return ebs.Snapshot{
+			SnapshotID: id,
+			StartTime:  start,
+			State:      state,
+			Tags:       map[string]string{key.TagMasterID: master},
+		}
+	}
+
+	snapshots := []ebs.Snapshot{
+		snapshot("snap-1a", "1", now.Add(-48*time.Hour), ec2.SnapshotStateCompleted),
+		snapshot("snap-2a", "2", now.Add(-48*time.Hour+time.Second), ec2.SnapshotStateCompleted),
+		snapshot("snap-3a", "3", now.Add(-48*time.Hour+2*time.Second), ec2.SnapshotStateCompleted),
+		snapshot("snap-1b", "1", now.Add(-24*time.Hour), ec2.SnapshotStateCompleted),
+		snapshot("snap-2b", "2", now.Add(-24*time.Hour+time.Second), ec2.SnapshotStateCompleted),
+		snapshot("snap-3b", "3", now.Add(-24*time.Hour+2*time.Second), ec2.SnapshotStateCompleted),
+		snapshot("snap-1c", "1", now, ec2.SnapshotStatePending),
+	}
+
+	testCases := []struct {
+		name         string
+		snapshots    []ebs.Snapshot
+		request      string
+		masters      []int
+		expected     map[int]string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:      "case 0: latest snapshots of ha masters",
+			snapshots: snapshots,
+			request:   key.EtcdRestoreLatest,
+			masters:   []int{1, 2, 3},
+			expected:  map[int]string{1: "snap-1b", 2: "snap-2b", 3: "snap-3b"},
+		},
+		{
+			name:      "case 1: snapshots taken together with the requested one",
+			snapshots: snapshots,
+			request:   "snap-2a",
+			masters:   []int{1, 2, 3},
+			expected:  map[int]string{1: "snap-1a", 2: "snap-2a", 3: "snap-3a"},
+		},
+		{
+			name:         "case 2: pending snapshot requested",
+			snapshots:    snapshots,
+			request:      "snap-1c",
+			masters:      []int{1, 2, 3},
+			errorMatcher: IsSnapshotNotFound,
+		},
+		{
+			name:         "case 3: no snapshot of a master",
+			snapshots:    snapshots,
+			request:      key.EtcdRestoreLatest,
+			masters:      []int{0},
+			errorMatcher: IsSnapshotNotFound,
+		},
+		{
+			name: "case 4: latest snapshot of single master",
+			snapshots: []ebs.Snapshot{
+				snapshot("snap-0a", "0", now.Add(-24*time.Hour), ec2.SnapshotStateCompleted),
+				snapshot("snap-0b", "0", now.Add(-time.Hour), ec2.SnapshotStateCompleted),
+				snapshot("snap-0c", "0", now, ec2.SnapshotStateError),
+			},
+			request:  key.EtcdRestoreLatest,
+			masters:  []int{0},
+			expected: map[int]string{0: "snap-0b"},
+		},
+		{
+			name: "case 5: requested snapshot without snapshots of other masters in the same run",
+			snapshots: append(snapshots,
+				snapshot("snap-2m", "2", now.Add(-36*time.Hour), ec2.SnapshotStateCompleted),
+			),
+			request:      "snap-2m",
+			masters:      []int{1, 2, 3},
+			errorMatcher: IsSnapshotNotFound,
+		},
+		{
+			name: "case 6: latest run with completed snapshots of all masters",
+			snapshots: append(snapshots,
+				snapshot("snap-2c", "2", now.Add(time.Second), ec2.SnapshotStateCompleted),
+				snapshot("snap-3c", "3", now.Add(2*time.Second), ec2.SnapshotStateCompleted),
+			),
+			request:  key.EtcdRestoreLatest,
+			masters:  []int{1, 2, 3},
+			expected: map[int]string{1: "snap-1b", 2: "snap-2b", 3: "snap-3b"},
+		},
+		{
+			name: "case 7: requested snapshot without master id of single master",
+			snapshots: []ebs.Snapshot{
+				snapshot("snap-0a", "0", now.Add(-24*time.Hour), ec2.SnapshotStateCompleted),
+				{
+					SnapshotID: "snap-ha",
+					StartTime:  now.Add(-48 * time.Hour),
+					State:      ec2.SnapshotStateCompleted,
+					Tags:       map[string]string{},
+				},
+			},
+			request:  "snap-ha",
+			masters:  []int{0},
+			expected: map[int]string{0: "snap-ha"},
+		},
+		{
+			name: "case 8: requested snapshot without master id of ha masters",
+			snapshots: append(snapshots, ebs.Snapshot{
+				SnapshotID: "snap-ha",
+				StartTime:  now.Add(-48 * time.Hour),
+				State:      ec2.SnapshotStateCompleted,
+				Tags:       map[string]string{},
+			}),
+			request:      "snap-ha",
+			masters:      []int{1, 2, 3},
+			errorMatcher: IsSnapshotNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			ids, err := restoreSnapshots(tc.snapshots, tc.request, tc.masters)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !cmp.Equal(ids, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, ids))
+			}
+		})
+	}
+}
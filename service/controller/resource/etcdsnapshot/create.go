@@ -0,0 +1,199 @@
+package etcdsnapshot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+)
+
+// EnsureCreated creates a snapshot of every etcd volume of the control plane
+// nodes once the latest snapshot is older than the configured interval and
+// deletes the snapshots exceeding the configured retention. The start time of
+// the latest snapshot, which completed for all etcd volumes, is recorded on the
+// AWSControlPlane CR.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToControlPlane(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	interval, err := key.ControlPlaneEtcdSnapshotInterval(cr, r.interval)
+	if err != nil {
+		r.logger.Errorf(ctx, err, "invalid annotation %#q", annotation.EtcdSnapshotInterval)
+		interval = r.interval
+	}
+	retention, err := key.ControlPlaneEtcdSnapshotRetention(cr, r.retention)
+	if err != nil {
+		r.logger.Errorf(ctx, err, "invalid annotation %#q", annotation.EtcdSnapshotRetention)
+		retention = r.retention
+	} else if retention < 1 {
+		r.logger.Debugf(ctx, "ignoring annotation %#q as retention must be at least 1", annotation.EtcdSnapshotRetention)
+		retention = r.retention
+	}
+
+	if interval == 0 {
+		r.logger.Debugf(ctx, "etcd volume snapshots are disabled")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	// While the etcd volumes get restored, we neither want to snapshot the
+	// volumes being replaced nor prune the snapshots they are restored from.
+	if _, ok := key.ControlPlaneEtcdRestore(cr); ok {
+		r.logger.Debugf(ctx, "etcd volumes are being restored")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	cl, err := r.toClusterFunc(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var ebsService ebs.Interface
+	{
+		c := ebs.Config{
+			Client: cc.Client.TenantCluster.AWS.EC2,
+			Logger: r.logger,
+		}
+
+		ebsService, err = ebs.New(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	var volumes []ebs.Volume
+	{
+		r.logger.Debugf(ctx, "finding etcd volumes")
+
+		volumes, err = ebsService.ListVolumes(ctx, cl, ebs.NewControlPlaneEtcdVolumeFilter(cl))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if len(volumes) == 0 {
+			r.logger.Debugf(ctx, "did not find etcd volumes")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		r.logger.Debugf(ctx, "found %d etcd volumes", len(volumes))
+	}
+
+	var snapshots []ebs.Snapshot
+	{
+		tags := map[string]string{
+			key.TagCluster:  key.ClusterID(&cr),
+			key.TagSnapshot: key.EtcdScheduledSnapshotValue,
+		}
+
+		snapshots, err = ebsService.ListSnapshots(ctx, tags)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	now := time.Now()
+
+	var found bool
+	var oldest time.Time
+	for _, v := range volumes {
+		masterID, ok := key.ControlPlaneVolumeMasterID(&cr, v.Name)
+		if !ok {
+			continue
+		}
+
+		var own []ebs.Snapshot
+		for _, s := range snapshots {
+			if s.Tags[key.TagMasterID] == strconv.Itoa(masterID) {
+				own = append(own, s)
+			}
+		}
+
+		if isDue(own, now, interval) {
+			tags := map[string]string{
+				key.TagCluster:        key.ClusterID(&cr),
+				key.TagMasterID:       strconv.Itoa(masterID),
+				key.TagName:           fmt.Sprintf("%s-%s", v.Name, now.UTC().Format("20060102T150405Z")),
+				key.TagReleaseVersion: key.ReleaseVersion(&cr),
+				key.TagSnapshot:       key.EtcdScheduledSnapshotValue,
+			}
+
+			_, err = ebsService.CreateSnapshot(ctx, v.VolumeID, tags)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		for _, s := range expired(own, retention) {
+			err = ebsService.DeleteSnapshot(ctx, s.SnapshotID)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		latest := latestCompleted(own)
+		if !latest.IsZero() {
+			lastSuccess.WithLabelValues(key.ClusterID(&cr), strconv.Itoa(masterID)).Set(float64(latest.Unix()))
+		}
+
+		if !found || latest.Before(oldest) {
+			oldest = latest
+		}
+		found = true
+	}
+
+	if !oldest.IsZero() {
+		err = r.ensureLastSuccess(ctx, cr, oldest)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Resource) ensureLastSuccess(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane, t time.Time) error {
+	v := t.UTC().Format(time.RFC3339)
+	if cr.GetAnnotations()[annotation.EtcdSnapshotLastSuccess] == v {
+		return nil
+	}
+
+	r.logger.Debugf(ctx, "updating last successful etcd volume snapshot time to %#q", v)
+
+	latest := &infrastructurev1alpha3.AWSControlPlane{}
+	err := r.ctrlClient.Get(ctx, ctrlClient.ObjectKey{Name: cr.GetName(), Namespace: cr.GetNamespace()}, latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	latest.Annotations[annotation.EtcdSnapshotLastSuccess] = v
+
+	err = r.ctrlClient.Update(ctx, latest, &ctrlClient.UpdateOptions{Raw: &metav1.UpdateOptions{}})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "updated last successful etcd volume snapshot time to %#q", v)
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package etcdsnapshot
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+// EnsureDeleted only removes the metrics of the deleted cluster. The snapshots
+// themselves are kept on purpose, so that the cluster can still be restored
+// from them after its deletion. They have to be cleaned up manually.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToControlPlane(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	lastSuccess.DeletePartialMatch(prometheus.Labels{labelClusterID: key.ClusterID(&cr)})
+
+	return nil
+}
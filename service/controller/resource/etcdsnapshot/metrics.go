@@ -0,0 +1,24 @@
+package etcdsnapshot
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	labelClusterID = "cluster_id"
+	labelMasterID  = "master_id"
+)
+
+var (
+	lastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aws_operator_etcd_snapshot_last_success_timestamp_seconds",
+			Help: "Gauge representing the start time of the latest completed etcd volume snapshot per control plane node.",
+		},
+		[]string{labelClusterID, labelMasterID},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(lastSuccess)
+}
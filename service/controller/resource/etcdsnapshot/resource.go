@@ -0,0 +1,74 @@
+package etcdsnapshot
+
+import (
+	"context"
+	"time"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	Name = "etcdsnapshot"
+)
+
+type Config struct {
+	CtrlClient    ctrlClient.Client
+	Logger        micrologger.Logger
+	ToClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
+
+	// Interval is the default interval in which snapshots of the etcd volumes
+	// are created. It can be overwritten per cluster via annotation. An interval
+	// of 0 disables snapshots.
+	Interval time.Duration
+	// Retention is the default number of snapshots kept per etcd volume. It can
+	// be overwritten per cluster via annotation.
+	Retention int
+}
+
+// Resource periodically creates EBS snapshots of the etcd volumes of the
+// control plane nodes and prunes them according to the retention policy.
+type Resource struct {
+	ctrlClient    ctrlClient.Client
+	logger        micrologger.Logger
+	toClusterFunc func(ctx context.Context, v interface{}) (infrastructurev1alpha3.AWSCluster, error)
+
+	interval  time.Duration
+	retention int
+}
+
+func New(config Config) (*Resource, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.ToClusterFunc == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ToClusterFunc must not be empty", config)
+	}
+
+	if config.Interval < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Interval must not be negative", config)
+	}
+	if config.Retention < 1 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Retention must be at least 1", config)
+	}
+
+	r := &Resource{
+		ctrlClient:    config.CtrlClient,
+		logger:        config.Logger,
+		toClusterFunc: config.ToClusterFunc,
+
+		interval:  config.Interval,
+		retention: config.Retention,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -0,0 +1,66 @@
+package etcdsnapshot
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+)
+
+// isDue returns whether a new snapshot has to be created given the existing
+// snapshots of a single etcd volume. Snapshots still in progress count as
+// recent, so that no further snapshots are requested while they complete.
+func isDue(snapshots []ebs.Snapshot, now time.Time, interval time.Duration) bool {
+	for _, s := range snapshots {
+		if s.State == ec2.SnapshotStatePending {
+			return false
+		}
+		if s.State == ec2.SnapshotStateCompleted && now.Sub(s.StartTime) < interval {
+			return false
+		}
+	}
+
+	return true
+}
+
+// expired returns the snapshots of a single etcd volume which exceed the given
+// retention. Only completed snapshots are considered, so that pruning never
+// leaves a volume without a usable snapshot. Failed snapshots are always
+// expired.
+func expired(snapshots []ebs.Snapshot, retention int) []ebs.Snapshot {
+	var completed []ebs.Snapshot
+	var result []ebs.Snapshot
+	for _, s := range snapshots {
+		switch s.State {
+		case ec2.SnapshotStateCompleted:
+			completed = append(completed, s)
+		case ec2.SnapshotStateError:
+			result = append(result, s)
+		}
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].StartTime.After(completed[j].StartTime)
+	})
+
+	if len(completed) > retention {
+		result = append(result, completed[retention:]...)
+	}
+
+	return result
+}
+
+// latestCompleted returns the start time of the latest completed snapshot of a
+// single etcd volume. The returned time is zero if there is none.
+func latestCompleted(snapshots []ebs.Snapshot) time.Time {
+	var latest time.Time
+	for _, s := range snapshots {
+		if s.State == ec2.SnapshotStateCompleted && s.StartTime.After(latest) {
+			latest = s.StartTime
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,119 @@
+package etcdsnapshot
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/ebs"
+)
+
+func Test_EtcdSnapshot_isDue(t *testing.T) {
+	now := time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		snapshots []ebs.Snapshot
+		expected  bool
+	}{
+		{
+			name:      "case 0: no snapshots",
+			snapshots: nil,
+			expected:  true,
+		},
+		{
+			name: "case 1: recent completed snapshot",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-time.Hour), State: ec2.SnapshotStateCompleted},
+			},
+			expected: false,
+		},
+		{
+			name: "case 2: outdated completed snapshot",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-25 * time.Hour), State: ec2.SnapshotStateCompleted},
+			},
+			expected: true,
+		},
+		{
+			name: "case 3: outdated pending snapshot",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-25 * time.Hour), State: ec2.SnapshotStatePending},
+			},
+			expected: false,
+		},
+		{
+			name: "case 4: recent failed snapshot",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-time.Hour), State: ec2.SnapshotStateError},
+			},
+			expected: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			due := isDue(tc.snapshots, now, 24*time.Hour)
+			if due != tc.expected {
+				t.Fatalf("expected %t got %t", tc.expected, due)
+			}
+		})
+	}
+}
+
+func Test_EtcdSnapshot_expired(t *testing.T) {
+	now := time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		snapshots []ebs.Snapshot
+		retention int
+		expected  []string
+	}{
+		{
+			name: "case 0: snapshots within retention",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-2 * time.Hour), State: ec2.SnapshotStateCompleted},
+				{SnapshotID: "snap-2", StartTime: now.Add(-1 * time.Hour), State: ec2.SnapshotStateCompleted},
+			},
+			retention: 2,
+			expected:  nil,
+		},
+		{
+			name: "case 1: oldest completed snapshots exceed retention",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-3 * time.Hour), State: ec2.SnapshotStateCompleted},
+				{SnapshotID: "snap-3", StartTime: now.Add(-1 * time.Hour), State: ec2.SnapshotStateCompleted},
+				{SnapshotID: "snap-2", StartTime: now.Add(-2 * time.Hour), State: ec2.SnapshotStateCompleted},
+				{SnapshotID: "snap-4", StartTime: now, State: ec2.SnapshotStatePending},
+			},
+			retention: 1,
+			expected:  []string{"snap-2", "snap-1"},
+		},
+		{
+			name: "case 2: failed snapshots are always expired",
+			snapshots: []ebs.Snapshot{
+				{SnapshotID: "snap-1", StartTime: now.Add(-2 * time.Hour), State: ec2.SnapshotStateCompleted},
+				{SnapshotID: "snap-2", StartTime: now.Add(-1 * time.Hour), State: ec2.SnapshotStateError},
+			},
+			retention: 3,
+			expected:  []string{"snap-2"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var ids []string
+			for _, s := range expired(tc.snapshots, tc.retention) {
+				ids = append(ids, s.SnapshotID)
+			}
+
+			if !cmp.Equal(ids, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, ids))
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package interruptionhandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	g8sv1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/core/v1alpha1"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/interruption"
+)
+
+// autoScaling provides the set of methods required to replace interrupted
+// instances. *AutoScaling struct from
+// "github.com/aws/aws-sdk-go/service/autoscaling" fulfils this interface.
+type autoScaling interface {
+	DescribeAutoScalingInstances(input *autoscaling.DescribeAutoScalingInstancesInput) (*autoscaling.DescribeAutoScalingInstancesOutput, error)
+	TerminateInstanceInAutoScalingGroup(input *autoscaling.TerminateInstanceInAutoScalingGroupInput) (*autoscaling.TerminateInstanceInAutoScalingGroupOutput, error)
+}
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !key.InterruptionHandlingEnabled(cr, r.interruptionHandling) {
+		r.logger.Debugf(ctx, "interruption handling is disabled for this cluster")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	if cc.Client.TenantCluster.K8s == nil {
+		r.logger.Debugf(ctx, "kubernetes clients are not available in controller context yet")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	err = r.handleEvents(ctx, cr, cc.Client.TenantCluster.K8s.CtrlClient(), cc.Client.TenantCluster.AWS.SQS, cc.Client.TenantCluster.AWS.AutoScaling)
+	if interruption.IsQueueNotFound(err) {
+		// The queue is created with the TCCP stack update following the
+		// enablement of the interruption handling.
+		r.logger.Debugf(ctx, "did not find interruption queue %#q", key.InterruptionQueueName(cr))
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// handleEvents drains the node pool nodes affected by the events received from
+// the interruption queue of the tenant cluster. Events are only deleted from
+// the queue once they got handled, so that failures are retried with the next
+// delivery of the event.
+func (r *Resource) handleEvents(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, tenantClient ctrlClient.Client, sqsClient interruption.SQS, asgClient autoScaling) error {
+	var err error
+
+	var events []interruption.Event
+	{
+		r.logger.Debugf(ctx, "receiving interruption events")
+
+		events, err = r.queue.Receive(ctx, sqsClient, key.InterruptionQueueName(cr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if len(events) == 0 {
+			r.logger.Debugf(ctx, "did not receive any interruption event")
+			return nil
+		}
+
+		r.logger.Debugf(ctx, "received %d interruption events", len(events))
+	}
+
+	nodes := map[string]corev1.Node{}
+	{
+		var list corev1.NodeList
+		err = tenantClient.List(ctx, &list)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, n := range list.Items {
+			id := instanceIDFromProviderID(n.Spec.ProviderID)
+			if id != "" {
+				nodes[id] = n
+			}
+		}
+	}
+
+	for _, e := range events {
+		err = r.handleEvent(ctx, cr, nodes, asgClient, e)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = r.queue.Delete(ctx, sqsClient, e)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Resource) handleEvent(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, nodes map[string]corev1.Node, asgClient autoScaling, e interruption.Event) error {
+	if e.Kind == interruption.KindUnknown {
+		r.logger.Debugf(ctx, "skipping interruption event %#q of unknown kind", e.ID)
+		return nil
+	}
+
+	for _, id := range e.InstanceIDs {
+		node, ok := nodes[id]
+		if !ok {
+			// The EventBridge rules match the events of all instances in the
+			// account and region, so events may refer to instances of other
+			// clusters or to instances which already left the cluster.
+			r.logger.Debugf(ctx, "ec2 instance %#q of %s event %#q is not a node of the tenant cluster", id, e.Kind, e.ID)
+			continue
+		}
+		if key.MachineDeploymentID(&node) == "" {
+			// Control plane nodes are drained by the control plane drainer as soon
+			// as their ASG terminates them.
+			r.logger.Debugf(ctx, "node %#q of %s event %#q is not a node pool node", node.Name, e.Kind, e.ID)
+			continue
+		}
+
+		created, err := r.ensureDrainerConfig(ctx, cr, node, id)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !created {
+			r.logger.Debugf(ctx, "node %#q of %s event %#q is already being drained", node.Name, e.Kind, e.ID)
+		}
+
+		// Rebalance recommendations and scheduled maintenance leave time for a
+		// graceful replacement. Terminating the instance through its ASG launches
+		// the replacement right away and keeps the instance in the lifecycle hook
+		// until the drainer completed it for the drained node. Spot interruptions
+		// and terminate lifecycle actions are already on their way out. The
+		// instance is also terminated in case the drainer config already existed,
+		// because the event may be redelivered after the termination failed.
+		if e.Kind == interruption.KindRebalanceRecommendation || e.Kind == interruption.KindScheduledChange {
+			err = r.terminateInstance(ctx, asgClient, id)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		if created {
+			r.event.Emit(ctx, &cr, "NodeInterrupted", fmt.Sprintf("node %q (instance %q) is drained due to %s event %q", node.Name, id, e.Kind, e.ID))
+		}
+	}
+
+	return nil
+}
+
+// ensureDrainerConfig creates the DrainerConfig for the given node, which lets
+// node-operator cordon and drain the node. The DrainerConfig is labelled like
+// the ones of the machine deployment drainer, which completes the lifecycle
+// hook of the instance and deletes the DrainerConfig once the node is
+// drained. ensureDrainerConfig returns false in case the node is already being
+// drained.
+func (r *Resource) ensureDrainerConfig(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, node corev1.Node, instanceID string) (bool, error) {
+	{
+		dc := &g8sv1alpha1.DrainerConfig{}
+		err := r.ctrlClient.Get(ctx, ctrlClient.ObjectKey{Name: node.Name, Namespace: cr.GetNamespace()}, dc)
+		if errors.IsNotFound(err) {
+			// fall through
+		} else if err != nil {
+			return false, microerror.Mask(err)
+		} else if !key.IsWrongDrainerConfig(dc, key.ClusterID(&cr), instanceID) {
+			return false, nil
+		} else {
+			err = r.ctrlClient.Delete(ctx, dc, &ctrlClient.DeleteOptions{Raw: &metav1.DeleteOptions{}})
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "deleted leftover drainer config for ec2 instance %#q", instanceID)
+		}
+	}
+
+	{
+		r.logger.Debugf(ctx, "creating drainer config for ec2 instance %#q", instanceID)
+
+		dc := &g8sv1alpha1.DrainerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					annotation.InstanceID: instanceID,
+				},
+				Labels: map[string]string{
+					label.Cluster:           key.ClusterID(&cr),
+					label.MachineDeployment: key.MachineDeploymentID(&node),
+					label.OperatorVersion:   key.OperatorVersion(&cr),
+				},
+				Name:      node.Name,
+				Namespace: cr.GetNamespace(),
+			},
+			Spec: g8sv1alpha1.DrainerConfigSpec{
+				Guest: g8sv1alpha1.DrainerConfigSpecGuest{
+					Cluster: g8sv1alpha1.DrainerConfigSpecGuestCluster{
+						API: g8sv1alpha1.DrainerConfigSpecGuestClusterAPI{
+							Endpoint: key.ClusterAPIEndpoint(cr),
+						},
+						ID: key.ClusterID(&cr),
+					},
+					Node: g8sv1alpha1.DrainerConfigSpecGuestNode{
+						Name: node.Name,
+					},
+				},
+				VersionBundle: g8sv1alpha1.DrainerConfigSpecVersionBundle{
+					Version: "0.2.0",
+				},
+			},
+		}
+
+		err := r.ctrlClient.Create(ctx, dc, &ctrlClient.CreateOptions{Raw: &metav1.CreateOptions{}})
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "created drainer config for ec2 instance %#q", instanceID)
+	}
+
+	return true, nil
+}
+
+// terminateInstance terminates the given instance in its ASG without
+// decrementing the desired capacity. Only instances in service are terminated,
+// so that redelivered events do not terminate the replacement of an instance
+// which is already terminating.
+func (r *Resource) terminateInstance(ctx context.Context, asgClient autoScaling, instanceID string) error {
+	{
+		i := &autoscaling.DescribeAutoScalingInstancesInput{
+			InstanceIds: []*string{
+				aws.String(instanceID),
+			},
+		}
+
+		o, err := asgClient.DescribeAutoScalingInstances(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if len(o.AutoScalingInstances) == 0 {
+			r.logger.Debugf(ctx, "did not find ec2 instance %#q in any auto scaling group", instanceID)
+			return nil
+		}
+
+		state := aws.StringValue(o.AutoScalingInstances[0].LifecycleState)
+		if state != autoscaling.LifecycleStateInService {
+			r.logger.Debugf(ctx, "not terminating ec2 instance %#q in lifecycle state %#q", instanceID, state)
+			return nil
+		}
+	}
+
+	r.logger.Debugf(ctx, "terminating ec2 instance %#q in its auto scaling group", instanceID)
+
+	i := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(instanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	}
+
+	_, err := asgClient.TerminateInstanceInAutoScalingGroup(i)
+	if IsInstanceNotFound(err) {
+		r.logger.Debugf(ctx, "did not find ec2 instance %#q in any auto scaling group", instanceID)
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "terminated ec2 instance %#q in its auto scaling group", instanceID)
+
+	return nil
+}
+
+// instanceIDFromProviderID returns the EC2 instance ID of the given provider
+// ID, which has the format aws:///AVAILABILITY_ZONE/INSTANCE-ID, e.g.
+// aws:///eu-west-1c/i-06a1d2fe9b3e8c916.
+func instanceIDFromProviderID(providerID string) string {
+	parts := strings.Split(providerID, "/")
+	if len(parts) != 5 {
+		return ""
+	}
+
+	return parts[4]
+}
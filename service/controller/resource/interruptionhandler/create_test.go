@@ -0,0 +1,291 @@
+package interruptionhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	g8sv1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/core/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck // v0.6.4 has a deprecation on pkg/client/fake that was removed in later versions
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/internal/interruption"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+type autoScalingMock struct {
+	err error
+	// states are the lifecycle states of the instances managed by any ASG.
+	states     map[string]string
+	terminated []string
+}
+
+func (a *autoScalingMock) DescribeAutoScalingInstances(input *autoscaling.DescribeAutoScalingInstancesInput) (*autoscaling.DescribeAutoScalingInstancesOutput, error) {
+	o := &autoscaling.DescribeAutoScalingInstancesOutput{}
+
+	for _, id := range input.InstanceIds {
+		state, ok := a.states[aws.StringValue(id)]
+		if !ok {
+			continue
+		}
+
+		o.AutoScalingInstances = append(o.AutoScalingInstances, &autoscaling.InstanceDetails{
+			InstanceId:     id,
+			LifecycleState: aws.String(state),
+		})
+	}
+
+	return o, nil
+}
+
+func (a *autoScalingMock) TerminateInstanceInAutoScalingGroup(input *autoscaling.TerminateInstanceInAutoScalingGroupInput) (*autoscaling.TerminateInstanceInAutoScalingGroupOutput, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	if aws.BoolValue(input.ShouldDecrementDesiredCapacity) {
+		return nil, errors.New("desired capacity must not be decremented")
+	}
+
+	a.terminated = append(a.terminated, aws.StringValue(input.InstanceId))
+	a.states[aws.StringValue(input.InstanceId)] = autoscaling.LifecycleStateTerminatingWait
+
+	return &autoscaling.TerminateInstanceInAutoScalingGroupOutput{}, nil
+}
+
+type recorderMock struct {
+	reasons []string
+}
+
+func (r *recorderMock) Emit(ctx context.Context, obj runtime.Object, reason, message string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func Test_Controller_Resource_InterruptionHandler_handleEvents(t *testing.T) {
+	node := func(name string, instanceID string, machineDeployment string) *corev1.Node {
+		n := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{},
+				Name:   name,
+			},
+			Spec: corev1.NodeSpec{
+				ProviderID: "aws:///eu-central-1a/" + instanceID,
+			},
+		}
+		if machineDeployment != "" {
+			n.Labels[label.MachineDeployment] = machineDeployment
+		}
+		return n
+	}
+	drainerConfig := func(name string, clusterID string, instanceID string) *g8sv1alpha1.DrainerConfig {
+		return &g8sv1alpha1.DrainerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotation.InstanceID: instanceID},
+				Labels:      map[string]string{label.Cluster: clusterID},
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+			},
+		}
+	}
+	event := func(id string, kind string, instanceIDs ...string) interruption.Event {
+		return interruption.Event{ID: id, InstanceIDs: instanceIDs, Kind: kind}
+	}
+
+	nodes := []client.Object{
+		node("ip-10-1-1-1.eu-central-1.compute.internal", "i-1", "a7k1m"),
+		node("ip-10-1-1-2.eu-central-1.compute.internal", "i-2", "a7k1m"),
+		node("ip-10-1-1-3.eu-central-1.compute.internal", "i-3", ""),
+	}
+
+	testCases := []struct {
+		name           string
+		events         []interruption.Event
+		drainerConfigs []client.Object
+		asgErr         error
+		// asgStates are the lifecycle states of the instances. All instances
+		// are in service if not given otherwise.
+		asgStates map[string]string
+		// expectedDrainerConfigs are formatted as name/instance/machine deployment.
+		expectedDrainerConfigs []string
+		expectedTerminated     []string
+		expectedReasons        []string
+	}{
+		{
+			name: "case 0: empty queue",
+		},
+		{
+			name: "case 1: spot interruption drains the node without terminating it",
+			events: []interruption.Event{
+				event("e-1", interruption.KindSpotInterruption, "i-1"),
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m"},
+			expectedReasons:        []string{"NodeInterrupted"},
+		},
+		{
+			name: "case 2: rebalance recommendation and scheduled change drain and replace the nodes",
+			events: []interruption.Event{
+				event("e-1", interruption.KindRebalanceRecommendation, "i-1"),
+				event("e-2", interruption.KindScheduledChange, "i-2", "i-9"),
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m", "ip-10-1-1-2.eu-central-1.compute.internal/i-2/a7k1m"},
+			expectedTerminated:     []string{"i-1", "i-2"},
+			expectedReasons:        []string{"NodeInterrupted", "NodeInterrupted"},
+		},
+		{
+			name: "case 3: events of other clusters, control plane nodes and unknown kinds are skipped",
+			events: []interruption.Event{
+				event("e-1", interruption.KindSpotInterruption, "i-9"),
+				event("e-2", interruption.KindInstanceTerminate, "i-3"),
+				event("e-3", interruption.KindUnknown),
+			},
+		},
+		{
+			name: "case 4: redelivered events replace nodes already being drained",
+			events: []interruption.Event{
+				event("e-1", interruption.KindRebalanceRecommendation, "i-1"),
+			},
+			drainerConfigs: []client.Object{
+				drainerConfig("ip-10-1-1-1.eu-central-1.compute.internal", unittest.DefaultClusterID, "i-1"),
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/"},
+			expectedTerminated:     []string{"i-1"},
+		},
+		{
+			name: "case 5: leftover drainer configs of former instances are replaced",
+			events: []interruption.Event{
+				event("e-1", interruption.KindInstanceTerminate, "i-1"),
+			},
+			drainerConfigs: []client.Object{
+				drainerConfig("ip-10-1-1-1.eu-central-1.compute.internal", unittest.DefaultClusterID, "i-0"),
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m"},
+			expectedReasons:        []string{"NodeInterrupted"},
+		},
+		{
+			name: "case 6: instances not managed by any ASG are only drained",
+			events: []interruption.Event{
+				event("e-1", interruption.KindScheduledChange, "i-1"),
+			},
+			asgErr:                 errors.New("ValidationError: Instance Id not found - No managed instance found for instance ID: i-1"),
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m"},
+			expectedReasons:        []string{"NodeInterrupted"},
+		},
+		{
+			name: "case 7: redelivered events do not terminate instances twice",
+			events: []interruption.Event{
+				event("e-1", interruption.KindScheduledChange, "i-1"),
+				event("e-1", interruption.KindScheduledChange, "i-1"),
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m"},
+			expectedTerminated:     []string{"i-1"},
+			expectedReasons:        []string{"NodeInterrupted"},
+		},
+		{
+			name: "case 8: instances not in service are not terminated",
+			events: []interruption.Event{
+				event("e-1", interruption.KindRebalanceRecommendation, "i-1"),
+			},
+			asgStates: map[string]string{
+				"i-1": autoscaling.LifecycleStateTerminatingWait,
+				"i-2": autoscaling.LifecycleStateInService,
+			},
+			expectedDrainerConfigs: []string{"ip-10-1-1-1.eu-central-1.compute.internal/i-1/a7k1m"},
+			expectedReasons:        []string{"NodeInterrupted"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			ctx := context.Background()
+			cr := unittest.DefaultCluster()
+
+			scheme := runtime.NewScheme()
+			err = g8sv1alpha1.AddToScheme(scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = corev1.AddToScheme(scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctrlClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.drainerConfigs...).Build()
+			tenantClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodes...).Build()
+			queue := interruption.NewFake(tc.events...)
+			asgStates := tc.asgStates
+			if asgStates == nil {
+				asgStates = map[string]string{
+					"i-1": autoscaling.LifecycleStateInService,
+					"i-2": autoscaling.LifecycleStateInService,
+				}
+			}
+			asgClient := &autoScalingMock{err: tc.asgErr, states: asgStates}
+			recorder := &recorderMock{}
+
+			var r *Resource
+			{
+				c := Config{
+					CtrlClient: ctrlClient,
+					Event:      recorder,
+					Logger:     microloggertest.New(),
+					Queue:      queue,
+
+					InterruptionHandlingEnabled: true,
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = r.handleEvents(ctx, cr, tenantClient, nil, asgClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(queue.Deleted()) != len(tc.events) {
+				t.Fatalf("expected %d deleted events got %d", len(tc.events), len(queue.Deleted()))
+			}
+
+			var drainerConfigs []string
+			{
+				var list g8sv1alpha1.DrainerConfigList
+				err = ctrlClient.List(ctx, &list)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, dc := range list.Items {
+					drainerConfigs = append(drainerConfigs, fmt.Sprintf("%s/%s/%s", dc.Name, dc.Annotations[annotation.InstanceID], dc.Labels[label.MachineDeployment]))
+				}
+				sort.Strings(drainerConfigs)
+			}
+
+			if !cmp.Equal(drainerConfigs, tc.expectedDrainerConfigs) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedDrainerConfigs, drainerConfigs))
+			}
+			if !cmp.Equal(asgClient.terminated, tc.expectedTerminated) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedTerminated, asgClient.terminated))
+			}
+			if !cmp.Equal(recorder.reasons, tc.expectedReasons) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedReasons, recorder.reasons))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package interruptionhandler
+
+import (
+	"regexp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var (
+	// instanceNotFoundRegExp is a fuzzy regular expression to match Autoscaling
+	// errors for instances not managed by any ASG, which we have to string
+	// match due to the lack of proper error types in the AWS SDK.
+	instanceNotFoundRegExp = regexp.MustCompile(`(?im)instance.*not found`)
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var instanceNotFoundError = &microerror.Error{
+	Kind: "instanceNotFoundError",
+}
+
+// IsInstanceNotFound asserts instanceNotFoundError. It also checks for some
+// string matching in the error message to figure if the AWS API gives the
+// error we expect.
+func IsInstanceNotFound(err error) bool {
+	c := microerror.Cause(err)
+
+	if c == nil {
+		return false
+	}
+
+	if instanceNotFoundRegExp.MatchString(c.Error()) {
+		return true
+	}
+
+	if c == instanceNotFoundError {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package interruptionhandler
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/interruption"
+	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+)
+
+const (
+	Name = "interruptionhandler"
+)
+
+type Config struct {
+	CtrlClient ctrlClient.Client
+	Event      event.Interface
+	Logger     micrologger.Logger
+	Queue      interruption.Interface
+
+	// InterruptionHandlingEnabled defines whether interruption events are
+	// handled for clusters not configuring it via annotation.
+	InterruptionHandlingEnabled bool
+}
+
+// Resource consumes the interruption queue of the tenant cluster and drains
+// the affected node pool nodes before AWS reclaims the underlying instances.
+type Resource struct {
+	ctrlClient ctrlClient.Client
+	event      event.Interface
+	logger     micrologger.Logger
+	queue      interruption.Interface
+
+	interruptionHandling bool
+}
+
+func New(config Config) (*Resource, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.Queue == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Queue must not be empty", config)
+	}
+
+	r := &Resource{
+		ctrlClient: config.CtrlClient,
+		event:      config.Event,
+		logger:     config.Logger,
+		queue:      config.Queue,
+
+		interruptionHandling: config.InterruptionHandlingEnabled,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
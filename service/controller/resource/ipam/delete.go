@@ -1,9 +1,42 @@
 package ipam
 
-import "context"
+import (
+	"context"
 
-// EnsureDeleted is a NOP for IPAM resource as allocated subnet will get
-// released when the guest cluster VPC and AWSConfig CR is deleted.
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/v7/pkg/controller/context/finalizerskeptcontext"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// EnsureDeleted releases the allocated subnet in case a Releaser is configured.
+// Otherwise it is a NOP, as the allocated subnet will get released when the
+// guest cluster VPC and AWSConfig CR is deleted.
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	if r.releaser == nil {
+		return nil
+	}
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "releasing allocated subnet")
+
+	err = r.releaser.Release(ctx, m.GetNamespace(), m.GetName())
+	if IsSubnetInUse(err) {
+		// The subnet must only be released once the VPC is gone, so that it is
+		// not allocated to somebody else while still being in use.
+		r.logger.Debugf(ctx, "allocated subnet is still in use")
+		r.logger.Debugf(ctx, "keeping finalizers")
+		finalizerskeptcontext.SetKept(ctx)
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "released allocated subnet")
+
 	return nil
 }
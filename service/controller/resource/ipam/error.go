@@ -10,3 +10,12 @@ var invalidConfigError = &microerror.Error{
 func IsInvalidConfig(err error) bool {
 	return microerror.Cause(err) == invalidConfigError
 }
+
+var subnetInUseError = &microerror.Error{
+	Kind: "subnet in use",
+}
+
+// IsSubnetInUse asserts subnetInUseError.
+func IsSubnetInUse(err error) bool {
+	return microerror.Cause(err) == subnetInUseError
+}
@@ -0,0 +1,67 @@
+package ipam
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+)
+
+// ipamPoolAllocations returns all allocations of the AWS VPC IPAM pool
+// identified by the given pool ID.
+func ipamPoolAllocations(ctx context.Context, client ec2iface.EC2API, poolID string) ([]*ec2.IpamPoolAllocation, error) {
+	i := &ec2.GetIpamPoolAllocationsInput{
+		IpamPoolId: aws.String(poolID),
+	}
+
+	allocations, err := awslist.IpamPoolAllocations(ctx, client, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return allocations, nil
+}
+
+// isAssociatedWithVPC returns whether the given subnet is still associated
+// with any VPC, either as primary or secondary CIDR block.
+func isAssociatedWithVPC(ctx context.Context, client ec2iface.EC2API, subnet string) (bool, error) {
+	i := &ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("cidr-block-association.cidr-block"),
+				Values: aws.StringSlice([]string{subnet}),
+			},
+		},
+	}
+
+	vpcs, err := awslist.Vpcs(ctx, client, i)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return len(vpcs) != 0, nil
+}
+
+// ipamPoolDescription returns the description of the AWS VPC IPAM pool
+// allocations made for the Kubernetes runtime object defined by namespace and
+// name. The description is used to find the allocations when releasing them.
+func ipamPoolDescription(namespace string, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// ipamPoolClientToken returns the idempotency token used to allocate the given
+// subnet for the Kubernetes runtime object defined by namespace and name. The
+// token includes the time of the allocation attempt, so that retried requests
+// of the same attempt do not fail, while an allocation released in the
+// meantime is not returned again by AWS for a later attempt. Allocations
+// surviving from prior attempts are found using their description.
+func ipamPoolClientToken(namespace string, name string, subnet string, t time.Time) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", ipamPoolDescription(namespace, name), subnet, t.UnixNano()))))
+}
@@ -0,0 +1,103 @@
+package ipam
+
+import (
+	"context"
+	"net"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/giantswarm/ipam"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+)
+
+type IPAMPoolCollectorConfig struct {
+	Collector Collector
+	Logger    micrologger.Logger
+
+	NetworkRange net.IPNet
+	PoolID       string
+}
+
+// IPAMPoolCollector decorates another Collector and adds the allocations of
+// the configured AWS VPC IPAM pool to the subnets collected by it. The pool is
+// looked up using the control plane EC2 client of the controller context.
+type IPAMPoolCollector struct {
+	collector Collector
+	logger    micrologger.Logger
+
+	networkRange net.IPNet
+	poolID       string
+}
+
+func NewIPAMPoolCollector(config IPAMPoolCollectorConfig) (*IPAMPoolCollector, error) {
+	if config.Collector == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Collector must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if reflect.DeepEqual(config.NetworkRange, net.IPNet{}) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRange must not be empty", config)
+	}
+	if config.PoolID == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.PoolID must not be empty", config)
+	}
+
+	c := &IPAMPoolCollector{
+		collector: config.Collector,
+		logger:    config.Logger,
+
+		networkRange: config.NetworkRange,
+		poolID:       config.PoolID,
+	}
+
+	return c, nil
+}
+
+func (c *IPAMPoolCollector) Collect(ctx context.Context, networkRange net.IPNet) ([]net.IPNet, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	reservedSubnets, err := c.collector.Collect(ctx, networkRange)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	{
+		c.logger.Debugf(ctx, "finding allocated subnets from IPAM pool %#q", c.poolID)
+
+		allocations, err := ipamPoolAllocations(ctx, cc.Client.ControlPlane.AWS.EC2, c.poolID)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, a := range allocations {
+			_, n, err := net.ParseCIDR(aws.StringValue(a.Cidr))
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			reservedSubnets = append(reservedSubnets, *n)
+		}
+
+		c.logger.Debugf(ctx, "found allocated subnets from IPAM pool %#q", c.poolID)
+	}
+
+	var nr net.IPNet
+	{
+		nr = networkRange
+		if nr.IP.Equal(net.IP{}) {
+			nr = c.networkRange
+		}
+	}
+
+	reservedSubnets = ipam.CanonicalizeSubnets(nr, reservedSubnets)
+
+	return reservedSubnets, nil
+}
@@ -0,0 +1,170 @@
+package ipam
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+)
+
+type IPAMPoolPersisterConfig struct {
+	Logger    micrologger.Logger
+	Persister Persister
+
+	PoolID string
+}
+
+// IPAMPoolPersister decorates another Persister and allocates the given subnet
+// in the configured AWS VPC IPAM pool before the decorated Persister is
+// executed. The allocation fails in case the subnet is already allocated in the
+// pool by anybody else, e.g. another installation sharing the pool.
+type IPAMPoolPersister struct {
+	logger    micrologger.Logger
+	persister Persister
+
+	poolID string
+}
+
+func NewIPAMPoolPersister(config IPAMPoolPersisterConfig) (*IPAMPoolPersister, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.Persister == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Persister must not be empty", config)
+	}
+
+	if config.PoolID == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.PoolID must not be empty", config)
+	}
+
+	p := &IPAMPoolPersister{
+		logger:    config.Logger,
+		persister: config.Persister,
+
+		poolID: config.PoolID,
+	}
+
+	return p, nil
+}
+
+func (p *IPAMPoolPersister) Persist(ctx context.Context, subnet net.IPNet, namespace string, name string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var allocation *ec2.IpamPoolAllocation
+	{
+		allocations, err := ipamPoolAllocations(ctx, cc.Client.ControlPlane.AWS.EC2, p.poolID)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, a := range allocations {
+			if aws.StringValue(a.Description) == ipamPoolDescription(namespace, name) && aws.StringValue(a.Cidr) == subnet.String() {
+				allocation = a
+				break
+			}
+		}
+	}
+
+	if allocation != nil {
+		p.logger.Debugf(ctx, "subnet %#q already allocated in IPAM pool %#q", subnet.String(), p.poolID)
+	} else {
+		p.logger.Debugf(ctx, "allocating subnet %#q in IPAM pool %#q", subnet.String(), p.poolID)
+
+		i := &ec2.AllocateIpamPoolCidrInput{
+			Cidr:        aws.String(subnet.String()),
+			ClientToken: aws.String(ipamPoolClientToken(namespace, name, subnet.String(), time.Now())),
+			Description: aws.String(ipamPoolDescription(namespace, name)),
+			IpamPoolId:  aws.String(p.poolID),
+		}
+
+		o, err := cc.Client.ControlPlane.AWS.EC2.AllocateIpamPoolCidr(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		allocation = o.IpamPoolAllocation
+
+		p.logger.Debugf(ctx, "allocated subnet %#q in IPAM pool %#q", subnet.String(), p.poolID)
+	}
+
+	err = p.persister.Persist(ctx, subnet, namespace, name)
+	if err != nil {
+		// The subnet is not persisted and the next reconciliation may pick
+		// another one. So we release the allocation in order to not leak it in
+		// the pool. In case the release fails as well we return the original
+		// error and the allocation is cleaned up on deletion.
+		p.logger.Debugf(ctx, "releasing subnet %#q in IPAM pool %#q", subnet.String(), p.poolID)
+
+		i := &ec2.ReleaseIpamPoolAllocationInput{
+			Cidr:                 allocation.Cidr,
+			IpamPoolAllocationId: allocation.IpamPoolAllocationId,
+			IpamPoolId:           aws.String(p.poolID),
+		}
+
+		_, releaseErr := cc.Client.ControlPlane.AWS.EC2.ReleaseIpamPoolAllocation(i)
+		if releaseErr != nil {
+			p.logger.Errorf(ctx, releaseErr, "failed to release subnet %#q in IPAM pool %#q", subnet.String(), p.poolID)
+		} else {
+			p.logger.Debugf(ctx, "released subnet %#q in IPAM pool %#q", subnet.String(), p.poolID)
+		}
+
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// Release releases all allocations of the configured AWS VPC IPAM pool made
+// for the Kubernetes runtime object defined by namespace and name. As long as
+// an allocated subnet is still associated with a tenant cluster VPC, the
+// allocation is kept and subnetInUseError is returned.
+func (p *IPAMPoolPersister) Release(ctx context.Context, namespace string, name string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	allocations, err := ipamPoolAllocations(ctx, cc.Client.ControlPlane.AWS.EC2, p.poolID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, a := range allocations {
+		if aws.StringValue(a.Description) != ipamPoolDescription(namespace, name) {
+			continue
+		}
+
+		inUse, err := isAssociatedWithVPC(ctx, cc.Client.TenantCluster.AWS.EC2, aws.StringValue(a.Cidr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if inUse {
+			return microerror.Maskf(subnetInUseError, "subnet %#q is still associated with a VPC", aws.StringValue(a.Cidr))
+		}
+
+		p.logger.Debugf(ctx, "releasing subnet %#q in IPAM pool %#q", aws.StringValue(a.Cidr), p.poolID)
+
+		i := &ec2.ReleaseIpamPoolAllocationInput{
+			Cidr:                 a.Cidr,
+			IpamPoolAllocationId: a.IpamPoolAllocationId,
+			IpamPoolId:           aws.String(p.poolID),
+		}
+
+		_, err = cc.Client.ControlPlane.AWS.EC2.ReleaseIpamPoolAllocation(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		p.logger.Debugf(ctx, "released subnet %#q in IPAM pool %#q", aws.StringValue(a.Cidr), p.poolID)
+	}
+
+	return nil
+}
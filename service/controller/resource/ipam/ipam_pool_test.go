@@ -0,0 +1,303 @@
+package ipam
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/giantswarm/ipam"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+)
+
+func Test_IPAMPool_Collect_Persist(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		collected []net.IPNet
+		pool      []net.IPNet
+
+		expectedSubnet net.IPNet
+		expectedPool   []string
+	}{
+		{
+			name: "case 0 allocate first subnet in empty pool",
+
+			collected: []net.IPNet{},
+			pool:      []net.IPNet{},
+
+			expectedSubnet: mustParseCIDR("10.100.0.0/24"),
+			expectedPool: []string{
+				"10.100.0.0/24",
+			},
+		},
+		{
+			name: "case 1 skip subnets allocated in pool by others",
+
+			collected: []net.IPNet{
+				mustParseCIDR("10.100.1.0/24"),
+			},
+			pool: []net.IPNet{
+				mustParseCIDR("10.100.0.0/24"),
+				mustParseCIDR("10.100.2.0/23"),
+			},
+
+			expectedSubnet: mustParseCIDR("10.100.4.0/24"),
+			expectedPool: []string{
+				"10.100.0.0/24",
+				"10.100.2.0/23",
+				"10.100.4.0/24",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var err error
+
+			networkRange := mustParseCIDR("10.100.0.0/16")
+			pool := NewTestIPAMPool(tc.pool)
+
+			var ctx context.Context
+			{
+				cc := controllercontext.Context{}
+				cc.Client.ControlPlane.AWS.EC2 = pool
+
+				ctx = controllercontext.NewContext(context.Background(), cc)
+			}
+
+			var collector *IPAMPoolCollector
+			{
+				c := IPAMPoolCollectorConfig{
+					Collector: NewTestCollector(tc.collected),
+					Logger:    microloggertest.New(),
+
+					NetworkRange: networkRange,
+					PoolID:       "ipam-pool-0123456789abcdef0",
+				}
+
+				collector, err = NewIPAMPoolCollector(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var persister *IPAMPoolPersister
+			{
+				c := IPAMPoolPersisterConfig{
+					Logger:    microloggertest.New(),
+					Persister: NewTestPersister(tc.expectedSubnet),
+
+					PoolID: "ipam-pool-0123456789abcdef0",
+				}
+
+				persister, err = NewIPAMPoolPersister(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			allocated, err := collector.Collect(ctx, net.IPNet{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			subnet, err := ipam.Free(networkRange, net.CIDRMask(24, 32), allocated)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Persisting twice simulates a retry after a failed reconciliation.
+			// The existing allocation must be found and prevent the second
+			// allocation from failing.
+			for j := 0; j < 2; j++ {
+				err = persister.Persist(ctx, subnet, "default", "al9qy")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !cmp.Equal(pool.Subnets(), tc.expectedPool) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedPool, pool.Subnets()))
+			}
+		})
+	}
+}
+
+func Test_IPAMPool_Persist_Failure(t *testing.T) {
+	var err error
+
+	pool := NewTestIPAMPool([]net.IPNet{
+		mustParseCIDR("10.100.0.0/24"),
+	})
+
+	var ctx context.Context
+	{
+		cc := controllercontext.Context{}
+		cc.Client.ControlPlane.AWS.EC2 = pool
+
+		ctx = controllercontext.NewContext(context.Background(), cc)
+	}
+
+	newPersister := func(subnet net.IPNet) *IPAMPoolPersister {
+		c := IPAMPoolPersisterConfig{
+			Logger:    microloggertest.New(),
+			Persister: NewTestPersister(subnet),
+
+			PoolID: "ipam-pool-0123456789abcdef0",
+		}
+
+		p, err := NewIPAMPoolPersister(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return p
+	}
+
+	// The decorated Persister fails for any subnet other than the one it is
+	// configured with. The allocation made in the pool must be released.
+	err = newPersister(mustParseCIDR("10.100.2.0/24")).Persist(ctx, mustParseCIDR("10.100.1.0/24"), "default", "al9qy")
+	if !IsInvalidConfig(err) {
+		t.Fatalf("error == %#v, want matching", err)
+	}
+
+	{
+		expectedPool := []string{
+			"10.100.0.0/24",
+		}
+		if !cmp.Equal(pool.Subnets(), expectedPool) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(expectedPool, pool.Subnets()))
+		}
+	}
+
+	// A retry with the same subnet must allocate it again.
+	err = newPersister(mustParseCIDR("10.100.1.0/24")).Persist(ctx, mustParseCIDR("10.100.1.0/24"), "default", "al9qy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expectedPool := []string{
+			"10.100.0.0/24",
+			"10.100.1.0/24",
+		}
+		if !cmp.Equal(pool.Subnets(), expectedPool) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(expectedPool, pool.Subnets()))
+		}
+	}
+}
+
+func Test_IPAMPool_Release(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		associated []string
+
+		expectedPool []string
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0 release subnet no longer associated with a VPC",
+
+			associated: []string{},
+
+			expectedPool: []string{
+				"10.100.0.0/24",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 1 keep subnet still associated with a VPC",
+
+			associated: []string{
+				"10.100.1.0/24",
+			},
+
+			expectedPool: []string{
+				"10.100.0.0/24",
+				"10.100.1.0/24",
+			},
+			errorMatcher: IsSubnetInUse,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var err error
+
+			pool := NewTestIPAMPool([]net.IPNet{
+				mustParseCIDR("10.100.0.0/24"),
+			})
+
+			var ctx context.Context
+			{
+				cc := controllercontext.Context{}
+				cc.Client.ControlPlane.AWS.EC2 = pool
+				cc.Client.TenantCluster.AWS.EC2 = &testVPCs{cidrs: tc.associated}
+
+				ctx = controllercontext.NewContext(context.Background(), cc)
+			}
+
+			var persister *IPAMPoolPersister
+			{
+				c := IPAMPoolPersisterConfig{
+					Logger:    microloggertest.New(),
+					Persister: NewTestPersister(mustParseCIDR("10.100.1.0/24")),
+
+					PoolID: "ipam-pool-0123456789abcdef0",
+				}
+
+				persister, err = NewIPAMPoolPersister(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = persister.Persist(ctx, mustParseCIDR("10.100.1.0/24"), "default", "al9qy")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = persister.Release(ctx, "default", "al9qy")
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !cmp.Equal(pool.Subnets(), tc.expectedPool) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedPool, pool.Subnets()))
+			}
+		})
+	}
+}
+
+type testVPCs struct {
+	ec2iface.EC2API
+
+	cidrs []string
+}
+
+func (v *testVPCs) DescribeVpcs(i *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	o := &ec2.DescribeVpcsOutput{}
+	for _, c := range v.cidrs {
+		for _, f := range i.Filters {
+			if aws.StringValue(f.Values[0]) == c {
+				o.Vpcs = append(o.Vpcs, &ec2.Vpc{CidrBlock: aws.String(c)})
+			}
+		}
+	}
+
+	return o, nil
+}
@@ -29,6 +29,9 @@ type Config struct {
 	Locker    locker.Interface
 	Logger    micrologger.Logger
 	Persister Persister
+	// Releaser is optional. If given, it is executed on deletion in order to
+	// release the subnets persisted for the deleted runtime object.
+	Releaser Releaser
 
 	AllocatedSubnetMaskBits int
 	NetworkRange            net.IPNet
@@ -43,6 +46,7 @@ type Resource struct {
 	locker    locker.Interface
 	logger    micrologger.Logger
 	persister Persister
+	releaser  Releaser
 
 	allocatedSubnetMask net.IPMask
 	networkRange        net.IPNet
@@ -88,6 +92,7 @@ func New(config Config) (*Resource, error) {
 		locker:    config.Locker,
 		logger:    config.Logger,
 		persister: config.Persister,
+		releaser:  config.Releaser,
 
 		allocatedSubnetMask: net.CIDRMask(config.AllocatedSubnetMaskBits, 32),
 		networkRange:        config.NetworkRange,
@@ -25,3 +25,10 @@ type Collector interface {
 type Persister interface {
 	Persist(ctx context.Context, subnet net.IPNet, namespace string, name string) error
 }
+
+// Releaser must release all subnets persisted for the Kubernetes runtime object
+// defined by namespace and name, so that Collector implementations do not
+// consider them allocated anymore.
+type Releaser interface {
+	Release(ctx context.Context, namespace string, name string) error
+}
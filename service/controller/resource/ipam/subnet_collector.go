@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/ipam"
 	"github.com/giantswarm/microerror"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 type SubnetCollectorConfig struct {
@@ -187,13 +189,13 @@ func (c *SubnetCollector) getSubnetsFromVPCs(ctx context.Context) ([]net.IPNet,
 		return nil, microerror.Mask(err)
 	}
 
-	out, err := cc.Client.TenantCluster.AWS.EC2.DescribeSubnets(nil)
+	subnets, err := awslist.Subnets(ctx, cc.Client.TenantCluster.AWS.EC2, &ec2.DescribeSubnetsInput{})
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
 	var results []net.IPNet
-	for _, subnet := range out.Subnets {
+	for _, subnet := range subnets {
 		_, n, err := net.ParseCIDR(*subnet.CidrBlock)
 		if err != nil {
 			return nil, microerror.Mask(err)
@@ -0,0 +1,100 @@
+package ipam
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/giantswarm/microerror"
+)
+
+// TestIPAMPool is an in-memory AWS VPC IPAM pool. It only implements the EC2
+// API calls the IPAM pool Collector and Persister implementations make.
+type TestIPAMPool struct {
+	ec2iface.EC2API
+
+	allocations []*ec2.IpamPoolAllocation
+	nextID      int
+	tokens      map[string]*ec2.IpamPoolAllocation
+}
+
+func NewTestIPAMPool(subnets []net.IPNet) *TestIPAMPool {
+	p := &TestIPAMPool{
+		tokens: map[string]*ec2.IpamPoolAllocation{},
+	}
+
+	for _, s := range subnets {
+		p.allocate(s.String(), "")
+	}
+
+	return p
+}
+
+func (p *TestIPAMPool) AllocateIpamPoolCidr(i *ec2.AllocateIpamPoolCidrInput) (*ec2.AllocateIpamPoolCidrOutput, error) {
+	a, ok := p.tokens[aws.StringValue(i.ClientToken)]
+	if ok {
+		return &ec2.AllocateIpamPoolCidrOutput{IpamPoolAllocation: a}, nil
+	}
+
+	_, n, err := net.ParseCIDR(aws.StringValue(i.Cidr))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, a := range p.allocations {
+		_, e, err := net.ParseCIDR(aws.StringValue(a.Cidr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if e.Contains(n.IP) || n.Contains(e.IP) {
+			return nil, microerror.Maskf(invalidConfigError, "subnet %#q overlaps with allocation %#q", n.String(), e.String())
+		}
+	}
+
+	a = p.allocate(n.String(), aws.StringValue(i.Description))
+	if i.ClientToken != nil {
+		p.tokens[aws.StringValue(i.ClientToken)] = a
+	}
+
+	return &ec2.AllocateIpamPoolCidrOutput{IpamPoolAllocation: a}, nil
+}
+
+func (p *TestIPAMPool) GetIpamPoolAllocations(i *ec2.GetIpamPoolAllocationsInput) (*ec2.GetIpamPoolAllocationsOutput, error) {
+	return &ec2.GetIpamPoolAllocationsOutput{IpamPoolAllocations: p.allocations}, nil
+}
+
+func (p *TestIPAMPool) ReleaseIpamPoolAllocation(i *ec2.ReleaseIpamPoolAllocationInput) (*ec2.ReleaseIpamPoolAllocationOutput, error) {
+	for j, a := range p.allocations {
+		if aws.StringValue(a.IpamPoolAllocationId) == aws.StringValue(i.IpamPoolAllocationId) {
+			p.allocations = append(p.allocations[:j], p.allocations[j+1:]...)
+			return &ec2.ReleaseIpamPoolAllocationOutput{Success: aws.Bool(true)}, nil
+		}
+	}
+
+	return nil, microerror.Maskf(invalidConfigError, "allocation %#q not found", aws.StringValue(i.IpamPoolAllocationId))
+}
+
+// Subnets returns the CIDRs of all allocations currently held by the pool.
+func (p *TestIPAMPool) Subnets() []string {
+	var subnets []string
+	for _, a := range p.allocations {
+		subnets = append(subnets, aws.StringValue(a.Cidr))
+	}
+
+	return subnets
+}
+
+func (p *TestIPAMPool) allocate(cidr string, description string) *ec2.IpamPoolAllocation {
+	a := &ec2.IpamPoolAllocation{
+		Cidr:                 aws.String(cidr),
+		Description:          aws.String(description),
+		IpamPoolAllocationId: aws.String(fmt.Sprintf("ipam-pool-alloc-%d", p.nextID)),
+	}
+	p.nextID++
+	p.allocations = append(p.allocations, a)
+
+	return a
+}
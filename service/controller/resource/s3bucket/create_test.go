@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 )
 
 func Test_Resource_S3Bucket_newCreate(t *testing.T) {
@@ -86,6 +87,7 @@ func Test_Resource_S3Bucket_newCreate(t *testing.T) {
 	var newResource *Resource
 	{
 		c := Config{
+			CloudTags:        &cloudtags.Mock{},
 			Logger:           microloggertest.New(),
 			InstallationName: "test-install",
 		}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 )
 
 func Test_Resource_S3Bucket_GetDesiredState(t *testing.T) {
@@ -40,6 +41,7 @@ func Test_Resource_S3Bucket_GetDesiredState(t *testing.T) {
 	var newResource *Resource
 	{
 		c := Config{
+			CloudTags:        &cloudtags.Mock{},
 			Logger:           microloggertest.New(),
 			InstallationName: "test-install",
 		}
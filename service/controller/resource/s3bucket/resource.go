@@ -2,18 +2,14 @@ package s3bucket
 
 import (
 	"context"
-	"strings"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
-	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
-	"github.com/giantswarm/aws-operator/v16/pkg/label"
-	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 )
 
 const (
@@ -26,8 +22,8 @@ const (
 // Config represents the configuration used to create a new s3bucket resource.
 type Config struct {
 	// Dependencies.
-	Logger     micrologger.Logger
-	CtrlClient client.Client
+	CloudTags cloudtags.Interface
+	Logger    micrologger.Logger
 
 	// Settings.
 	AccessLogsExpiration int
@@ -39,8 +35,8 @@ type Config struct {
 // Resource implements the s3bucket resource.
 type Resource struct {
 	// Dependencies.
-	ctrlClient client.Client
-	logger     micrologger.Logger
+	cloudTags cloudtags.Interface
+	logger    micrologger.Logger
 
 	// Settings.
 	accessLogsExpiration int
@@ -52,6 +48,9 @@ type Resource struct {
 // New creates a new configured s3bucket resource.
 func New(config Config) (*Resource, error) {
 	// Dependencies.
+	if config.CloudTags == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
@@ -66,8 +65,8 @@ func New(config Config) (*Resource, error) {
 
 	r := &Resource{
 		// Dependencies.
-		ctrlClient: config.CtrlClient,
-		logger:     config.Logger,
+		cloudTags: config.CloudTags,
+		logger:    config.Logger,
 
 		// Settings.
 		accessLogsExpiration: config.AccessLogsExpiration,
@@ -107,47 +106,14 @@ func containsBucketState(bucketStateName string, bucketStateList []BucketState)
 }
 
 func (r *Resource) getS3BucketTags(ctx context.Context, customObject infrastructurev1alpha3.AWSCluster) ([]*s3.Tag, error) {
-	tags := key.AWSTags(&customObject, r.installationName)
-
-	list := &apiv1beta1.ClusterList{}
-	err := r.ctrlClient.List(
-		ctx,
-		list,
-		client.MatchingLabels{label.Cluster: key.ClusterID(&customObject)},
-	)
+	tags, err := r.cloudTags.ClusterTags(ctx, &customObject)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	if len(list.Items) != 1 {
-		return nil, microerror.Maskf(executionFailedError, "expected 1 CR got %d", len(list.Items))
-	}
-
-	allTags := addCloudTags(tags, list.Items[0].GetLabels())
 
-	return awstags.NewS3(allTags), nil
+	return awstags.NewS3(tags), nil
 }
 
 func (r *Resource) canBeDeleted(bucket BucketState) bool {
 	return !bucket.IsLoggingBucket || bucket.IsLoggingBucket && r.deleteLoggingBucket
 }
-
-// add cloud tags from `labels` to `tags` map
-func addCloudTags(tags map[string]string, labels map[string]string) map[string]string {
-	for k, v := range labels {
-		if isCloudTagKey(k) {
-			tags[trimCloudTagKey(k)] = v
-		}
-	}
-
-	return tags
-}
-
-// IsCloudTagKey checks if a tag has proper prefix
-func isCloudTagKey(tagKey string) bool {
-	return strings.HasPrefix(tagKey, key.KeyCloudPrefix)
-}
-
-// TrimCloudTagKey trims key cloud prefix from a tag
-func trimCloudTagKey(tagKey string) string {
-	return strings.TrimPrefix(tagKey, key.KeyCloudPrefix)
-}
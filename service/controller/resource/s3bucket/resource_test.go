@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/giantswarm/micrologger/microloggertest"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 )
 
 func Test_ContainsBucketState(t *testing.T) {
@@ -94,6 +96,7 @@ func Test_BucketCanBeDeleted(t *testing.T) {
 
 	c := Config{}
 
+	c.CloudTags = &cloudtags.Mock{}
 	c.Logger = microloggertest.New()
 	c.AccessLogsExpiration = 0
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -50,22 +51,22 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSnapshots(i)
+		snapshots, err := awslist.Snapshots(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		if len(o.Snapshots) == 0 {
+		if len(snapshots) == 0 {
 			r.logger.Debugf(ctx, "the tenant cluster snapshot id is not available yet")
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
 		}
 
-		if len(o.Snapshots) > 1 {
-			return microerror.Maskf(executionFailedError, "expected one snapshot, got %d", len(o.Snapshots))
+		if len(snapshots) > 1 {
+			return microerror.Maskf(executionFailedError, "expected one snapshot, got %d", len(snapshots))
 		}
 
-		id = *o.Snapshots[0].SnapshotId
+		id = *snapshots[0].SnapshotId
 
 		r.logger.Debugf(ctx, "found the tenant cluster snapshot id %#q", id)
 	}
@@ -0,0 +1,452 @@
+package tagdrift
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/kms"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+)
+
+const (
+	// maxFilterValues is the number of values used per EC2 filter, in order
+	// to stay below the limits of the EC2 API.
+	maxFilterValues = 200
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if cc.Status.TenantCluster.TCCP.VPC.ID == "" {
+		r.logger.Debugf(ctx, "vpc id not available yet")
+		r.logger.Debugf(ctx, "canceling resource")
+		return nil
+	}
+
+	clusterTags, err := r.cloudTags.ClusterTags(ctx, &cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Tags are desired per scope, which is either the cluster or one of its
+	// node pools. Resources of node pools get the tags of their node pool, all
+	// other resources the tags of the cluster.
+	clusterScope := key.ClusterID(&cr)
+	scopeTags := map[string]map[string]string{
+		clusterScope: clusterTags,
+	}
+	{
+		var list infrastructurev1alpha3.AWSMachineDeploymentList
+		err := r.ctrlClient.List(
+			ctx,
+			&list,
+			client.InNamespace(cr.Namespace),
+			client.MatchingLabels{label.Cluster: key.ClusterID(&cr)},
+		)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, md := range list.Items {
+			tags, err := r.cloudTags.NodePoolTags(ctx, md)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			scopeTags[key.MachineDeploymentID(&md)] = tags
+		}
+	}
+
+	// Tags are managed by the tag policy for the resources of a scope if they
+	// are part of the scope's tags now or were part of them during the last
+	// reconciliation. Only managed tags which are not desired anymore are
+	// removed. Tags owned by the operator are never managed, so that they are
+	// never removed.
+	policyKeys := map[string][]string{}
+	managed := map[string]map[string]bool{}
+	{
+		managedKeys, err := key.ManagedTagKeys(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for scope, tags := range scopeTags {
+			managed[scope] = map[string]bool{}
+			for _, k := range managedKeys[scope] {
+				if !isOperatorTag(cr, k) {
+					managed[scope][k] = true
+				}
+			}
+
+			var keys []string
+			for k := range tags {
+				if !isOperatorTag(cr, k) {
+					managed[scope][k] = true
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			if len(keys) != 0 {
+				policyKeys[scope] = keys
+			}
+		}
+	}
+
+	newResource := func(id string, current map[string]string, scope string) taggedResource {
+		return taggedResource{ID: id, Current: current, Desired: scopeTags[scope], Managed: managed[scope]}
+	}
+
+	var resources []taggedResource
+
+	// Volumes and network interfaces which live and die with their instance
+	// get the same tags as the instance.
+	instanceScopes := map[string]string{}
+	{
+		r.logger.Debugf(ctx, "finding instances")
+
+		i := &ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("tag:" + key.TagCluster),
+					Values: []*string{aws.String(key.ClusterID(&cr))},
+				},
+				{
+					Name: aws.String("instance-state-name"),
+					Values: []*string{
+						aws.String(ec2.InstanceStateNamePending),
+						aws.String(ec2.InstanceStateNameRunning),
+						aws.String(ec2.InstanceStateNameStopping),
+						aws.String(ec2.InstanceStateNameStopped),
+					},
+				},
+			},
+		}
+
+		instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, instance := range instances {
+			scope := clusterScope
+			if id := awstags.ValueForKey(instance.Tags, key.TagMachineDeployment); id != "" {
+				if _, ok := scopeTags[id]; ok {
+					scope = id
+				}
+			}
+
+			id := aws.StringValue(instance.InstanceId)
+			instanceScopes[id] = scope
+			resources = append(resources, newResource(id, ec2TagsToMap(instance.Tags), scope))
+		}
+
+		r.logger.Debugf(ctx, "found %d instances", len(instanceScopes))
+	}
+
+	{
+		r.logger.Debugf(ctx, "finding volumes")
+
+		var instanceIDs []string
+		for id := range instanceScopes {
+			instanceIDs = append(instanceIDs, id)
+		}
+		sort.Strings(instanceIDs)
+
+		// Volumes are either attached to the cluster's instances or were created
+		// for persistent volume claims, in which case they carry the cluster's
+		// cloud provider tag.
+		var filters [][]*ec2.Filter
+		for i := 0; i < len(instanceIDs); i += maxFilterValues {
+			j := i + maxFilterValues
+			if j > len(instanceIDs) {
+				j = len(instanceIDs)
+			}
+
+			filters = append(filters, []*ec2.Filter{
+				{
+					Name:   aws.String("attachment.instance-id"),
+					Values: aws.StringSlice(instanceIDs[i:j]),
+				},
+			})
+		}
+		filters = append(filters, []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(key.ClusterCloudProviderTag(&cr))},
+			},
+		})
+
+		volumes := map[string]taggedResource{}
+		for _, f := range filters {
+			i := &ec2.DescribeVolumesInput{
+				Filters: f,
+			}
+
+			vs, err := awslist.Volumes(ctx, cc.Client.TenantCluster.AWS.EC2, i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, v := range vs {
+				volumes[aws.StringValue(v.VolumeId)] = newResource(aws.StringValue(v.VolumeId), ec2TagsToMap(v.Tags), volumeScope(v, instanceScopes, clusterScope))
+			}
+		}
+
+		for _, v := range volumes {
+			resources = append(resources, v)
+		}
+
+		r.logger.Debugf(ctx, "found %d volumes", len(volumes))
+	}
+
+	{
+		r.logger.Debugf(ctx, "finding network interfaces")
+
+		i := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []*string{aws.String(cc.Status.TenantCluster.TCCP.VPC.ID)},
+				},
+			},
+		}
+
+		networkInterfaces, err := awslist.NetworkInterfaces(ctx, cc.Client.TenantCluster.AWS.EC2, i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		var count int
+		for _, n := range networkInterfaces {
+			// Network interfaces of e.g. load balancers or VPC endpoints are
+			// managed by AWS services and their tags are out of our control.
+			if aws.BoolValue(n.RequesterManaged) {
+				continue
+			}
+
+			scope := clusterScope
+			if n.Attachment != nil {
+				if s, ok := instanceScopes[aws.StringValue(n.Attachment.InstanceId)]; ok {
+					scope = s
+				}
+			}
+
+			resources = append(resources, newResource(aws.StringValue(n.NetworkInterfaceId), ec2TagsToMap(n.TagSet), scope))
+			count++
+		}
+
+		r.logger.Debugf(ctx, "found %d network interfaces", count)
+	}
+
+	changes := groupTagChanges(resources, driftedResourceTags)
+	if len(changes) == 0 {
+		r.logger.Debugf(ctx, "did not find tag drift of ec2 resources")
+	}
+
+	for _, c := range changes {
+		r.logger.Debugf(ctx, "repairing tag drift of %d ec2 resources", len(c.IDs))
+
+		i := &ec2.CreateTagsInput{
+			Resources: aws.StringSlice(c.IDs),
+			Tags:      awstags.NewEC2(c.Tags),
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.EC2.CreateTags(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "repaired tag drift of %d ec2 resources", len(c.IDs))
+	}
+
+	removals := groupTagChanges(resources, removedResourceTags)
+	if len(removals) == 0 {
+		r.logger.Debugf(ctx, "did not find removed tags of ec2 resources")
+	}
+
+	for _, c := range removals {
+		r.logger.Debugf(ctx, "removing tags of %d ec2 resources", len(c.IDs))
+
+		// Tags are deleted regardless of their value only if no value is given at
+		// all, which is why the tags are not created using awstags.NewEC2.
+		var tags []*ec2.Tag
+		for _, k := range sortedKeys(c.Tags) {
+			tags = append(tags, &ec2.Tag{Key: aws.String(k)})
+		}
+
+		i := &ec2.DeleteTagsInput{
+			Resources: aws.StringSlice(c.IDs),
+			Tags:      tags,
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.EC2.DeleteTags(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "removed tags of %d ec2 resources", len(c.IDs))
+	}
+
+	err = r.repairEncryptionKeyTags(ctx, cr, clusterTags, managed[clusterScope])
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// The keys of the tag policy are only persisted once all resources are
+	// repaired, so that tags removed from the policy are removed from all
+	// resources eventually, even if the repair fails in between.
+	{
+		var desired string
+		if len(policyKeys) != 0 {
+			b, err := json.Marshal(policyKeys)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			desired = string(b)
+		}
+
+		if desired != cr.Annotations[annotation.ManagedTagKeys] {
+			r.logger.Debugf(ctx, "updating managed tag keys")
+
+			var latest infrastructurev1alpha3.AWSCluster
+			err := r.ctrlClient.Get(ctx, client.ObjectKey{Name: cr.GetName(), Namespace: cr.GetNamespace()}, &latest)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			if desired == "" {
+				delete(latest.Annotations, annotation.ManagedTagKeys)
+			} else {
+				if latest.Annotations == nil {
+					latest.Annotations = map[string]string{}
+				}
+				latest.Annotations[annotation.ManagedTagKeys] = desired
+			}
+
+			err = r.ctrlClient.Update(ctx, &latest)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "updated managed tag keys")
+		}
+	}
+
+	return nil
+}
+
+func (r *Resource) repairEncryptionKeyTags(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, clusterTags map[string]string, managed map[string]bool) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "finding encryption key")
+
+	keyID, err := r.encrypter.EncryptionKey(ctx, key.ClusterID(&cr))
+	if r.encrypter.IsKeyNotFound(err) {
+		r.logger.Debugf(ctx, "did not find encryption key")
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	i := &kms.ListResourceTagsInput{
+		KeyId: aws.String(keyID),
+	}
+
+	tags, err := awslist.ResourceTags(ctx, cc.Client.TenantCluster.AWS.KMS, i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	current := kmsTagsToMap(tags)
+
+	drifted := driftedTags(current, clusterTags)
+	if len(drifted) == 0 {
+		r.logger.Debugf(ctx, "did not find tag drift of encryption key")
+	} else {
+		r.logger.Debugf(ctx, "repairing tag drift of encryption key")
+
+		t := &kms.TagResourceInput{
+			KeyId: aws.String(keyID),
+			Tags:  awstags.NewKMS(drifted),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.KMS.TagResource(t)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "repaired tag drift of encryption key")
+	}
+
+	removed := removedTags(current, clusterTags, managed)
+	if len(removed) == 0 {
+		r.logger.Debugf(ctx, "did not find removed tags of encryption key")
+	} else {
+		r.logger.Debugf(ctx, "removing tags of encryption key")
+
+		u := &kms.UntagResourceInput{
+			KeyId:   aws.String(keyID),
+			TagKeys: aws.StringSlice(sortedKeys(removed)),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.KMS.UntagResource(u)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "removed tags of encryption key")
+	}
+
+	return nil
+}
+
+// volumeScope returns the scope of the desired tags of the given volume.
+// Volumes deleted together with their instance get the tags of the instance.
+// All other volumes, like the ones of persistent volume claims, may move
+// between instances of different node pools and get the tags of the cluster.
+func volumeScope(v *ec2.Volume, instanceScopes map[string]string, clusterScope string) string {
+	for _, a := range v.Attachments {
+		if !aws.BoolValue(a.DeleteOnTermination) {
+			continue
+		}
+
+		s, ok := instanceScopes[aws.StringValue(a.InstanceId)]
+		if ok {
+			return s
+		}
+	}
+
+	return clusterScope
+}
+
+// isOperatorTag returns whether the given tag key is owned by the operator,
+// like the cluster ID or the node pool ID. These tags are set by the Cloud
+// Formation stacks and must never be removed by the tagdrift resource.
+func isOperatorTag(cr infrastructurev1alpha3.AWSCluster, k string) bool {
+	_, ok := key.AWSTags(&cr, "")[k]
+	return ok || strings.HasPrefix(k, "giantswarm.io/")
+}
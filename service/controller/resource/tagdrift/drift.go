@@ -0,0 +1,157 @@
+package tagdrift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+const (
+	// maxResourcesPerRequest is the maximum number of resources EC2 allows to
+	// tag with a single CreateTags request.
+	maxResourcesPerRequest = 1000
+)
+
+// taggedResource is an AWS resource together with its current tags, the tags
+// the tag policy defines for it and the keys of the tags the tag policy
+// manages for it.
+type taggedResource struct {
+	ID      string
+	Current map[string]string
+	Desired map[string]string
+	Managed map[string]bool
+}
+
+// tagChange describes the tags to be set on or removed from a group of
+// resources. The values of tags to be removed are empty.
+type tagChange struct {
+	IDs  []string
+	Tags map[string]string
+}
+
+// driftedTags returns the desired tags which are either missing in the
+// current tags or have a different value.
+func driftedTags(current map[string]string, desired map[string]string) map[string]string {
+	drifted := map[string]string{}
+	for k, v := range desired {
+		c, ok := current[k]
+		if !ok || c != v {
+			drifted[k] = v
+		}
+	}
+
+	return drifted
+}
+
+// removedTags returns the current tags which are managed by the tag policy
+// but not desired anymore. Tags are removed regardless of their value, which
+// is why the values of the returned tags are empty. Tags not managed by the
+// tag policy are never removed, since they may be set by users or other
+// controllers.
+func removedTags(current map[string]string, desired map[string]string, managed map[string]bool) map[string]string {
+	removed := map[string]string{}
+	for k := range current {
+		_, ok := desired[k]
+		if managed[k] && !ok {
+			removed[k] = ""
+		}
+	}
+
+	return removed
+}
+
+func driftedResourceTags(r taggedResource) map[string]string {
+	return driftedTags(r.Current, r.Desired)
+}
+
+func removedResourceTags(r taggedResource) map[string]string {
+	return removedTags(r.Current, r.Desired, r.Managed)
+}
+
+// groupTagChanges groups the resources with tag drift by the tags the given
+// diff function returns, so that resources drifting the same way can be
+// repaired with a single request. Groups are split according to
+// maxResourcesPerRequest. The result is sorted for the sake of determinism.
+func groupTagChanges(resources []taggedResource, diff func(r taggedResource) map[string]string) []tagChange {
+	groups := map[string]*tagChange{}
+	for _, r := range resources {
+		drifted := diff(r)
+		if len(drifted) == 0 {
+			continue
+		}
+
+		k := tagsKey(drifted)
+		g, ok := groups[k]
+		if !ok {
+			g = &tagChange{Tags: drifted}
+			groups[k] = g
+		}
+		g.IDs = append(g.IDs, r.ID)
+	}
+
+	var keys []string
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []tagChange
+	for _, k := range keys {
+		g := groups[k]
+		sort.Strings(g.IDs)
+
+		for i := 0; i < len(g.IDs); i += maxResourcesPerRequest {
+			j := i + maxResourcesPerRequest
+			if j > len(g.IDs) {
+				j = len(g.IDs)
+			}
+
+			changes = append(changes, tagChange{IDs: g.IDs[i:j], Tags: g.Tags})
+		}
+	}
+
+	return changes
+}
+
+func ec2TagsToMap(tags []*ec2.Tag) map[string]string {
+	m := map[string]string{}
+	for _, t := range tags {
+		m[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+
+	return m
+}
+
+func kmsTagsToMap(tags []*kms.Tag) map[string]string {
+	m := map[string]string{}
+	for _, t := range tags {
+		m[aws.StringValue(t.TagKey)] = aws.StringValue(t.TagValue)
+	}
+
+	return m
+}
+
+func sortedKeys(tags map[string]string) []string {
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// tagsKey returns a canonical representation of the given tags.
+func tagsKey(tags map[string]string) string {
+	var pairs []string
+	for k, v := range tags {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", k, v))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
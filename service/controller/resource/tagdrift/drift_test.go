@@ -0,0 +1,177 @@
+package tagdrift
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Controller_Resource_TagDrift_groupTagChanges(t *testing.T) {
+	desired := map[string]string{
+		"cost-center":           "1234",
+		"giantswarm.io/cluster": "al9qy",
+	}
+
+	var manyResources []taggedResource
+	var manyIDs []string
+	for i := 0; i < maxResourcesPerRequest+1; i++ {
+		id := fmt.Sprintf("vol-%04d", i)
+		manyResources = append(manyResources, taggedResource{ID: id, Current: map[string]string{}, Desired: desired})
+		manyIDs = append(manyIDs, id)
+	}
+
+	testCases := []struct {
+		name      string
+		resources []taggedResource
+		expected  []tagChange
+	}{
+		{
+			name: "case 0: no drift",
+			resources: []taggedResource{
+				{
+					ID:      "i-1",
+					Current: map[string]string{"cost-center": "1234", "giantswarm.io/cluster": "al9qy", "other": "tag"},
+					Desired: desired,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "case 1: missing and deviating tags",
+			resources: []taggedResource{
+				{
+					ID:      "i-1",
+					Current: map[string]string{"cost-center": "5678", "giantswarm.io/cluster": "al9qy"},
+					Desired: desired,
+				},
+				{
+					ID:      "eni-1",
+					Current: map[string]string{},
+					Desired: desired,
+				},
+				{
+					ID:      "vol-1",
+					Current: map[string]string{"giantswarm.io/cluster": "al9qy"},
+					Desired: desired,
+				},
+			},
+			expected: []tagChange{
+				{
+					IDs:  []string{"i-1", "vol-1"},
+					Tags: map[string]string{"cost-center": "1234"},
+				},
+				{
+					IDs:  []string{"eni-1"},
+					Tags: desired,
+				},
+			},
+		},
+		{
+			name:      "case 2: requests are split",
+			resources: manyResources,
+			expected: []tagChange{
+				{
+					IDs:  manyIDs[:maxResourcesPerRequest],
+					Tags: desired,
+				},
+				{
+					IDs:  manyIDs[maxResourcesPerRequest:],
+					Tags: desired,
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			changes := groupTagChanges(tc.resources, driftedResourceTags)
+
+			if !cmp.Equal(changes, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, changes))
+			}
+		})
+	}
+}
+
+func Test_Controller_Resource_TagDrift_removedTags(t *testing.T) {
+	managed := map[string]bool{
+		"cost-center":           true,
+		"giantswarm.io/cluster": true,
+		"team":                  true,
+	}
+
+	desired := map[string]string{
+		"cost-center":           "1234",
+		"giantswarm.io/cluster": "al9qy",
+	}
+
+	testCases := []struct {
+		name      string
+		resources []taggedResource
+		expected  []tagChange
+	}{
+		{
+			name: "case 0: unmanaged tags are kept",
+			resources: []taggedResource{
+				{
+					ID:      "i-1",
+					Current: map[string]string{"cost-center": "1234", "giantswarm.io/cluster": "al9qy", "other": "tag"},
+					Desired: desired,
+					Managed: managed,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "case 1: managed tags which are not desired anymore are removed",
+			resources: []taggedResource{
+				{
+					ID:      "i-1",
+					Current: map[string]string{"cost-center": "1234", "team": "a", "other": "tag"},
+					Desired: desired,
+					Managed: managed,
+				},
+				{
+					ID:      "vol-1",
+					Current: map[string]string{"team": "b"},
+					Desired: desired,
+					Managed: managed,
+				},
+				{
+					ID:      "eni-1",
+					Current: map[string]string{"team": "c"},
+					Desired: map[string]string{"team": "c"},
+					Managed: managed,
+				},
+				{
+					ID:      "eni-2",
+					Current: map[string]string{"team": "d"},
+					Desired: desired,
+					Managed: map[string]bool{},
+				},
+			},
+			expected: []tagChange{
+				{
+					IDs:  []string{"i-1", "vol-1"},
+					Tags: map[string]string{"team": ""},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			changes := groupTagChanges(tc.resources, removedResourceTags)
+
+			if !cmp.Equal(changes, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, changes))
+			}
+		})
+	}
+}
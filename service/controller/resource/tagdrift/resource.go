@@ -0,0 +1,68 @@
+package tagdrift
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
+	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
+)
+
+const (
+	Name = "tagdrift"
+)
+
+type Config struct {
+	CloudTags  cloudtags.Interface
+	CtrlClient client.Client
+	Encrypter  encrypter.Interface
+	Logger     micrologger.Logger
+}
+
+// Resource repairs tag drift of the tenant cluster's AWS resources which are
+// not fully managed by Cloud Formation. These are the EC2 instances, their
+// EBS volumes and network interfaces, volumes created for persistent volume
+// claims and the cluster's KMS key. Tags missing or deviating from the tag
+// policy are set again. Tags removed from the tag policy are removed from the
+// resources. The keys of the tag policy are therefore persisted in the
+// aws-operator.giantswarm.io/managed-tag-keys annotation of the AWSCluster
+// CR. Tags which never were part of the tag policy are left untouched.
+//
+// The cluster's S3 bucket is not checked, because the s3bucket resource
+// replaces the bucket's whole tag set according to the tag policy on every
+// reconciliation if tags are included.
+type Resource struct {
+	cloudTags  cloudtags.Interface
+	ctrlClient client.Client
+	encrypter  encrypter.Interface
+	logger     micrologger.Logger
+}
+
+func New(config Config) (*Resource, error) {
+	if config.CloudTags == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
+	}
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Encrypter == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Encrypter must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		cloudTags:  config.CloudTags,
+		ctrlClient: config.CtrlClient,
+		encrypter:  config.Encrypter,
+		logger:     config.Logger,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
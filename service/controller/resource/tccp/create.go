@@ -3,7 +3,9 @@ package tccp
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,6 +19,8 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp/template"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -74,6 +78,29 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		}
 	}
 
+	{
+		r.logger.Debugf(ctx, "finding the load balancer type of the tenant cluster's control plane nodes")
+
+		t, ok, err := r.findTCCPNLoadBalancerType(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// The load balancers provided by the TCCP stack depend on the load
+		// balancers the control plane nodes are attached to. While the TCCPN stack
+		// is transitioning we cannot tell which ones these are and must not remove
+		// any load balancer.
+		if !ok {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack is in transitioning state")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
+
+		cc.Status.TenantCluster.TCCPN.LoadBalancerType = t
+
+		r.logger.Debugf(ctx, "found the load balancer type %#q of the tenant cluster's control plane nodes", t)
+	}
+
 	{
 		r.logger.Debugf(ctx, "finding the tenant cluster's control plane cloud formation stack")
 
@@ -81,7 +108,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			StackName: aws.String(key.StackNameTCCP(&cr)),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeStacks(i)
+		stacks, err := awslist.Stacks(ctx, cc.Client.TenantCluster.AWS.CloudFormation, i)
+		if err == nil && len(stacks) == 1 {
+			stackmetrics.ReportStack(key.ClusterID(&cr), key.StackTCCP, stacks[0])
+		}
+
 		if IsNotExists(err) {
 			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane cloud formation stack")
 
@@ -95,23 +126,23 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		} else if err != nil {
 			return microerror.Mask(err)
 
-		} else if len(o.Stacks) != 1 {
-			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		} else if len(stacks) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(stacks))
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
-			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
-			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
-			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
+			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
+			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
+			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
 
-		} else if key.StackInProgress(*o.Stacks[0].StackStatus) {
-			r.logger.Debugf(ctx, "the tenant cluster's control plane cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus)
-			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackInProgress(*stacks[0].StackStatus) {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane cloud formation stack has stack status %#q", *stacks[0].StackStatus)
+			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
-		} else if key.StackComplete(*o.Stacks[0].StackStatus) {
-			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackComplete(*stacks[0].StackStatus) {
+			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 		}
 
 		r.logger.Debugf(ctx, "found the tenant cluster's control plane cloud formation stack")
@@ -128,6 +159,13 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			if err != nil {
 				return microerror.Mask(err)
 			}
+		} else {
+			// In case there is nothing to update anymore, a change set created
+			// in preview mode is not needed and gets removed.
+			err = r.changeSet.Cleanup(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCCP(&cr))
+			if err != nil {
+				return microerror.Mask(err)
+			}
 		}
 	}
 
@@ -188,16 +226,11 @@ func (r *Resource) createStack(ctx context.Context, cl apiv1beta1.Cluster, cr in
 }
 
 func (r *Resource) getCloudFormationTags(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) ([]*cloudformation.Tag, error) {
-	tags := key.AWSTags(&cr, r.installationName)
-	tags[key.TagStack] = key.StackTCCP
-
-	cloudtags, err := r.cloudtags.GetTagsByCluster(ctx, key.ClusterID(&cr))
+	tags, err := r.cloudtags.ClusterTags(ctx, &cr)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	for k, v := range cloudtags {
-		tags[k] = v
-	}
+	tags[key.TagStack] = key.StackTCCP
 
 	return awstags.NewCloudFormation(tags), nil
 }
@@ -241,6 +274,14 @@ func (r *Resource) newParamsMain(ctx context.Context, cl apiv1beta1.Cluster, cr
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
+		vpcEndpoints, err := r.newParamsMainVPCEndpoints(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		interruptionHandling, err := r.newParamsMainInterruptionHandling(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 
 		enableAWSCni := key.IsAWSCNINeeded(cl)
 		// If we have the aws-operator.giantswarm.io/legacy-aws-cni-pod-cidr, we still need to keep AWS cni subnets around.
@@ -248,23 +289,45 @@ func (r *Resource) newParamsMain(ctx context.Context, cl apiv1beta1.Cluster, cr
 			enableAWSCni = true
 		}
 
+		cc, err := controllercontext.FromContext(ctx)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
 		params = &template.ParamsMain{
-			EnableAWSCNI:    enableAWSCni,
-			InternetGateway: internetGateway,
-			LoadBalancers:   loadBalancers,
-			NATGateway:      natGateway,
-			Outputs:         outputs,
-			RecordSets:      recordSets,
-			RouteTables:     routeTables,
-			SecurityGroups:  securityGroups,
-			Subnets:         subnets,
-			VPC:             vpc,
+			EnableAWSCNI:         enableAWSCni,
+			EnableDualStack:      key.DualStackEnabled(cr, cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR),
+			InternetGateway:      internetGateway,
+			InterruptionHandling: interruptionHandling,
+			LoadBalancers:        loadBalancers,
+			NATGateway:           natGateway,
+			Outputs:              outputs,
+			RecordSets:           recordSets,
+			RouteTables:          routeTables,
+			SecurityGroups:       securityGroups,
+			Subnets:              subnets,
+			VPC:                  vpc,
+			VPCEndpoints:         vpcEndpoints,
 		}
 	}
 
 	return params, nil
 }
 
+func (r *Resource) newParamsMainInterruptionHandling(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (*template.ParamsMainInterruptionHandling, error) {
+	var interruptionHandling *template.ParamsMainInterruptionHandling
+	{
+		interruptionHandling = &template.ParamsMainInterruptionHandling{
+			AutoScalingGroupPrefix: key.StackNameTCNPPrefix(&cr),
+			ClusterID:              key.ClusterID(&cr),
+			Enabled:                key.InterruptionHandlingEnabled(cr, r.interruptionHandling),
+			QueueName:              key.InterruptionQueueName(cr),
+		}
+	}
+
+	return interruptionHandling, nil
+}
+
 func (r *Resource) newParamsMainInternetGateway(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (*template.ParamsMainInternetGateway, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
@@ -274,8 +337,9 @@ func (r *Resource) newParamsMainInternetGateway(ctx context.Context, cr infrastr
 	var internetGateways []template.ParamsMainInternetGatewayInternetGateway
 	for _, az := range cc.Spec.TenantCluster.TCCP.AvailabilityZones {
 		ig := template.ParamsMainInternetGatewayInternetGateway{
-			InternetGatewayRoute: key.SanitizeCFResourceName(key.PublicInternetGatewayRouteName(az.Name)),
-			RouteTable:           key.SanitizeCFResourceName(key.PublicRouteTableName(az.Name)),
+			InternetGatewayRoute:     key.SanitizeCFResourceName(key.PublicInternetGatewayRouteName(az.Name)),
+			InternetGatewayIPv6Route: key.SanitizeCFResourceName(key.PublicInternetGatewayIPv6RouteName(az.Name)),
+			RouteTable:               key.SanitizeCFResourceName(key.PublicRouteTableName(az.Name)),
 		}
 
 		internetGateways = append(internetGateways, ig)
@@ -314,6 +378,36 @@ func (r *Resource) newParamsMainLoadBalancers(ctx context.Context, cr infrastruc
 		privateSubnets = append(privateSubnets, key.SanitizeCFResourceName(key.PrivateSubnetName(az.Name)))
 	}
 
+	types := key.LoadBalancerTypesTCCP(key.LoadBalancerType(cr), cc.Status.TenantCluster.TCCPN.LoadBalancerType)
+
+	private := cc.Status.TenantCluster.TCCP.PrivateCluster
+
+	var network *template.ParamsMainLoadBalancersNetwork
+	if containsString(types, key.LoadBalancerTypeNetwork) {
+		// The static public IPs are only needed for the public API NLB, which
+		// private clusters do not have.
+		var eips []template.ParamsMainLoadBalancersNetworkEIP
+		if !private {
+			for _, az := range clusterAZs {
+				eips = append(eips, template.ParamsMainLoadBalancersNetworkEIP{
+					Name:         key.SanitizeCFResourceName(key.LoadBalancerEIPName(az.Name)),
+					PublicSubnet: key.SanitizeCFResourceName(key.PublicSubnetName(az.Name)),
+				})
+			}
+		}
+
+		network = &template.ParamsMainLoadBalancersNetwork{
+			APIInternalName: key.InternalNLBNameAPI(&cr),
+			APIName:         key.NLBNameAPI(&cr),
+			APIPort:         key.KubernetesSecurePort,
+			EIPs:            eips,
+			EtcdName:        key.NLBNameEtcd(&cr),
+			EtcdPort:        key.EtcdPort,
+			HealthCheckPath: "/healthz",
+			HealthCheckPort: key.KubernetesApiHealthCheckPort,
+		}
+	}
+
 	var loadBalancers *template.ParamsMainLoadBalancers
 	{
 		loadBalancers = &template.ParamsMainLoadBalancers{
@@ -326,6 +420,7 @@ func (r *Resource) newParamsMainLoadBalancers(ctx context.Context, cr infrastruc
 					PortInstance: key.KubernetesSecurePort,
 				},
 			},
+			Classic:                  containsString(types, key.LoadBalancerTypeClassic),
 			EtcdElbHealthCheckTarget: key.HealthCheckTCPTarget(key.EtcdPort),
 			EtcdElbName:              key.ELBNameEtcd(&cr),
 			EtcdElbPortsToOpen: []template.ParamsMainLoadBalancersPortPair{
@@ -335,6 +430,8 @@ func (r *Resource) newParamsMainLoadBalancers(ctx context.Context, cr infrastruc
 				},
 			},
 			MasterInstanceResourceName: key.MasterInstanceResourceName(cr, t),
+			Network:                    network,
+			Private:                    private,
 			PublicSubnets:              publicSubnets,
 			PrivateSubnets:             privateSubnets,
 		}
@@ -399,11 +496,38 @@ func (r *Resource) newParamsMainNATGateway(ctx context.Context, cl apiv1beta1.Cl
 }
 
 func (r *Resource) newParamsMainOutputs(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, t time.Time) (*template.ParamsMainOutputs, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	types := key.LoadBalancerTypesTCCP(key.LoadBalancerType(cr), cc.Status.TenantCluster.TCCPN.LoadBalancerType)
+
+	apiLoadBalancer := "ApiLoadBalancer"
+	apiInternalLoadBalancer := "ApiInternalLoadBalancer"
+	etcdLoadBalancer := "EtcdLoadBalancer"
+	if activeLoadBalancerType(cr, cc.Status.TenantCluster.TCCPN.LoadBalancerType) == key.LoadBalancerTypeNetwork {
+		apiLoadBalancer = "ApiNetworkLoadBalancer"
+		apiInternalLoadBalancer = "ApiInternalNetworkLoadBalancer"
+		etcdLoadBalancer = "EtcdNetworkLoadBalancer"
+	}
+
 	var outputs *template.ParamsMainOutputs
 	{
 		outputs = &template.ParamsMainOutputs{
-			OperatorVersion: key.OperatorVersion(&cr),
-			Route53Enabled:  r.route53Enabled,
+			APIInternalLoadBalancer: apiInternalLoadBalancer,
+			APILoadBalancer:         apiLoadBalancer,
+			APIWhitelistHash:        cc.Spec.TenantCluster.TCCP.APIWhitelist.Hash,
+			DualStack:               key.DualStackEnabled(cr, cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR),
+			EtcdLoadBalancer:        etcdLoadBalancer,
+			InterruptionHandling:    key.InterruptionHandlingEnabled(cr, r.interruptionHandling),
+			LoadBalancerDNSNames:    r.loadBalancerDNSNames,
+			LoadBalancerTypes:       strings.Join(types, ","),
+			OperatorVersion:         key.OperatorVersion(&cr),
+			Private:                 cc.Status.TenantCluster.TCCP.PrivateCluster,
+			Route53Enabled:          r.route53Enabled,
+			TargetGroups:            containsString(types, key.LoadBalancerTypeNetwork),
+			VPCEndpoints:            key.VPCEndpointsEnabled(cr, r.vpcEndpoints),
 		}
 	}
 
@@ -411,20 +535,50 @@ func (r *Resource) newParamsMainOutputs(ctx context.Context, cr infrastructurev1
 }
 
 func (r *Resource) newParamsMainRecordSets(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, t time.Time) (*template.ParamsMainRecordSets, error) {
-	_, err := controllercontext.FromContext(ctx)
+	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
+	// Classic ELBs and NLBs expose their canonical hosted zone under different
+	// attributes.
+	loadBalancers := template.ParamsMainRecordSetsLoadBalancers{
+		API:                   "ApiLoadBalancer",
+		APIInternal:           "ApiInternalLoadBalancer",
+		Etcd:                  "EtcdLoadBalancer",
+		HostedZoneIDAttribute: "CanonicalHostedZoneNameID",
+	}
+	if activeLoadBalancerType(cr, cc.Status.TenantCluster.TCCPN.LoadBalancerType) == key.LoadBalancerTypeNetwork {
+		loadBalancers = template.ParamsMainRecordSetsLoadBalancers{
+			API:                   "ApiNetworkLoadBalancer",
+			APIInternal:           "ApiInternalNetworkLoadBalancer",
+			Etcd:                  "EtcdNetworkLoadBalancer",
+			HostedZoneIDAttribute: "CanonicalHostedZoneID",
+		}
+	}
+
+	// Only the internal hosted zone of private clusters is associated with
+	// additional VPCs.
+	var vpcs []string
+	if cc.Status.TenantCluster.TCCP.PrivateCluster {
+		vpcs, err = key.PrivateHostedZoneVPCs(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var recordSets *template.ParamsMainRecordSets
 	{
 		recordSets = &template.ParamsMainRecordSets{
 			BaseDomain:                 key.ClusterBaseDomain(cr),
 			EtcdDomain:                 key.ClusterEtcdEndpoint(cr),
 			ClusterID:                  key.ClusterID(&cr),
+			LoadBalancers:              loadBalancers,
 			MasterInstanceResourceName: key.MasterInstanceResourceName(cr, t),
+			Private:                    cc.Status.TenantCluster.TCCP.PrivateCluster,
 			Route53Enabled:             r.route53Enabled,
 			VPCRegion:                  key.Region(cr),
+			VPCs:                       vpcs,
 		}
 	}
 
@@ -440,9 +594,10 @@ func (r *Resource) newParamsMainRouteTables(ctx context.Context, cr infrastructu
 	var awsCNIRouteTableNames []template.ParamsMainRouteTablesRouteTableName
 	for _, az := range cc.Spec.TenantCluster.TCCP.AvailabilityZones {
 		rtName := template.ParamsMainRouteTablesRouteTableName{
-			AvailabilityZone:       az.Name,
-			AvailabilityZoneRegion: key.AvailabilityZoneRegionSuffix(az.Name),
-			ResourceName:           key.SanitizeCFResourceName(key.AWSCNIRouteTableName(az.Name)),
+			AvailabilityZone:                   az.Name,
+			AvailabilityZoneRegion:             key.AvailabilityZoneRegionSuffix(az.Name),
+			EgressOnlyInternetGatewayRouteName: key.SanitizeCFResourceName(key.AWSCNIEgressOnlyInternetGatewayRouteName(az.Name)),
+			ResourceName:                       key.SanitizeCFResourceName(key.AWSCNIRouteTableName(az.Name)),
 		}
 		awsCNIRouteTableNames = append(awsCNIRouteTableNames, rtName)
 	}
@@ -460,10 +615,11 @@ func (r *Resource) newParamsMainRouteTables(ctx context.Context, cr infrastructu
 	var privateRouteTableNames []template.ParamsMainRouteTablesRouteTableName
 	for _, az := range cc.Spec.TenantCluster.TCCP.AvailabilityZones {
 		rtName := template.ParamsMainRouteTablesRouteTableName{
-			AvailabilityZone:       az.Name,
-			AvailabilityZoneRegion: key.AvailabilityZoneRegionSuffix(az.Name),
-			ResourceName:           key.SanitizeCFResourceName(key.PrivateRouteTableName(az.Name)),
-			VPCPeeringRouteName:    key.SanitizeCFResourceName(key.VPCPeeringRouteName(az.Name)),
+			AvailabilityZone:                   az.Name,
+			AvailabilityZoneRegion:             key.AvailabilityZoneRegionSuffix(az.Name),
+			EgressOnlyInternetGatewayRouteName: key.SanitizeCFResourceName(key.EgressOnlyInternetGatewayRouteName(az.Name)),
+			ResourceName:                       key.SanitizeCFResourceName(key.PrivateRouteTableName(az.Name)),
+			VPCPeeringRouteName:                key.SanitizeCFResourceName(key.VPCPeeringRouteName(az.Name)),
 		}
 		privateRouteTableNames = append(privateRouteTableNames, rtName)
 	}
@@ -499,12 +655,12 @@ func (r *Resource) newParamsMainSecurityGroups(ctx context.Context, cr infrastru
 		securityGroups = &template.ParamsMainSecurityGroups{
 			APIWhitelist: template.ParamsMainSecurityGroupsAPIWhitelist{
 				Private: template.ParamsMainSecurityGroupsAPIWhitelistSecurityGroup{
-					Enabled:    r.apiWhitelist.Private.Enabled,
-					SubnetList: r.apiWhitelist.Private.SubnetList,
+					Enabled:    cc.Spec.TenantCluster.TCCP.APIWhitelist.Private.Enabled,
+					SubnetList: cc.Spec.TenantCluster.TCCP.APIWhitelist.Private.SubnetList,
 				},
 				Public: template.ParamsMainSecurityGroupsAPIWhitelistSecurityGroup{
-					Enabled:    r.apiWhitelist.Public.Enabled,
-					SubnetList: r.apiWhitelist.Public.SubnetList,
+					Enabled:    cc.Spec.TenantCluster.TCCP.APIWhitelist.Public.Enabled,
+					SubnetList: cc.Spec.TenantCluster.TCCP.APIWhitelist.Public.SubnetList,
 				},
 			},
 			ClusterID:                       key.ClusterID(&cr),
@@ -530,12 +686,36 @@ func (r *Resource) newParamsMainSubnets(ctx context.Context, cr infrastructurev1
 		return zones[i].Name < zones[j].Name
 	})
 
+	// The IPv6 CIDR blocks of the subnets are derived from their offsets within
+	// the networks they are split from. See key.IPv6SubnetIndex.
+	var awsCNINetwork net.IPNet
+	var clusterNetwork net.IPNet
+	{
+		podSubnet := r.cidrBlockAWSCNI
+		if key.PodsCIDRBlock(cr) != "" {
+			podSubnet = key.PodsCIDRBlock(cr)
+		}
+
+		_, n, err := net.ParseCIDR(podSubnet)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		awsCNINetwork = *n
+
+		_, n, err = net.ParseCIDR(key.StatusClusterNetworkCIDR(cr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		clusterNetwork = *n
+	}
+
 	var awsCNISubnets []template.ParamsMainSubnetsSubnet
 	for _, az := range zones {
 		snetName := key.SanitizeCFResourceName(key.AWSCNISubnetName(az.Name))
 		snet := template.ParamsMainSubnetsSubnet{
 			AvailabilityZone: az.Name,
 			CIDR:             az.Subnet.AWSCNI.CIDR.String(),
+			IPv6CIDRIndex:    key.IPv6SubnetIndex(key.IPv6NetworkIndexAWSCNI, awsCNINetwork, az.Subnet.AWSCNI.CIDR),
 			Name:             snetName,
 			RouteTableAssociation: template.ParamsMainSubnetsSubnetRouteTableAssociation{
 				Name:           key.SanitizeCFResourceName(key.AWSCNISubnetRouteTableAssociationName(az.Name)),
@@ -552,6 +732,7 @@ func (r *Resource) newParamsMainSubnets(ctx context.Context, cr infrastructurev1
 		snet := template.ParamsMainSubnetsSubnet{
 			AvailabilityZone:    az.Name,
 			CIDR:                az.Subnet.Public.CIDR.String(),
+			IPv6CIDRIndex:       key.IPv6SubnetIndex(key.IPv6NetworkIndexTCCP, clusterNetwork, az.Subnet.Public.CIDR),
 			Name:                snetName,
 			MapPublicIPOnLaunch: false,
 			RouteTableAssociation: template.ParamsMainSubnetsSubnetRouteTableAssociation{
@@ -569,6 +750,7 @@ func (r *Resource) newParamsMainSubnets(ctx context.Context, cr infrastructurev1
 		snet := template.ParamsMainSubnetsSubnet{
 			AvailabilityZone:    az.Name,
 			CIDR:                az.Subnet.Private.CIDR.String(),
+			IPv6CIDRIndex:       key.IPv6SubnetIndex(key.IPv6NetworkIndexTCCP, clusterNetwork, az.Subnet.Private.CIDR),
 			Name:                snetName,
 			MapPublicIPOnLaunch: false,
 			RouteTableAssociation: template.ParamsMainSubnetsSubnetRouteTableAssociation{
@@ -651,6 +833,48 @@ func (r *Resource) newParamsMainVPC(ctx context.Context, cl apiv1beta1.Cluster,
 	return vpc, nil
 }
 
+func (r *Resource) newParamsMainVPCEndpoints(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (*template.ParamsMainVPCEndpoints, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	podSubnet := r.cidrBlockAWSCNI
+	if key.PodsCIDRBlock(cr) != "" {
+		podSubnet = key.PodsCIDRBlock(cr)
+	}
+
+	var interfaceEndpoints []template.ParamsMainVPCEndpointsInterfaceEndpoint
+	for _, s := range vpcEndpointServices {
+		e := template.ParamsMainVPCEndpointsInterfaceEndpoint{
+			ResourceName: key.SanitizeCFResourceName(key.VPCEndpointName(s)),
+			ServiceName:  key.VPCEndpointServiceName(key.Region(cr), s),
+		}
+		interfaceEndpoints = append(interfaceEndpoints, e)
+	}
+
+	var privateSubnets []string
+	for _, az := range cc.Spec.TenantCluster.TCCP.AvailabilityZones {
+		privateSubnets = append(privateSubnets, key.SanitizeCFResourceName(key.PrivateSubnetName(az.Name)))
+	}
+
+	var vpcEndpoints *template.ParamsMainVPCEndpoints
+	{
+		vpcEndpoints = &template.ParamsMainVPCEndpoints{
+			CIDRBlocks: []string{
+				key.StatusClusterNetworkCIDR(cr),
+				podSubnet,
+			},
+			ClusterID:          key.ClusterID(&cr),
+			Enabled:            key.VPCEndpointsEnabled(cr, r.vpcEndpoints),
+			InterfaceEndpoints: interfaceEndpoints,
+			PrivateSubnets:     privateSubnets,
+		}
+	}
+
+	return vpcEndpoints, nil
+}
+
 func (r *Resource) updateStack(ctx context.Context, cl apiv1beta1.Cluster, cr infrastructurev1alpha3.AWSCluster) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
@@ -691,9 +915,26 @@ func (r *Resource) updateStack(ctx context.Context, cl apiv1beta1.Cluster, cr in
 			TemplateBody: aws.String(templateBody),
 		}
 
-		_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
-		if err != nil {
-			return microerror.Mask(err)
+		if key.ChangeSetPreviewEnabled(&cr) {
+			approved, err := r.changeSet.Ensure(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			if !approved {
+				r.logger.Debugf(ctx, "not updating the tenant cluster's control plane cloud formation stack due to change set not being approved")
+				return nil
+			}
+
+			err = r.changeSet.Execute(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCCP(&cr))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		} else {
+			_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
 		}
 
 		r.logger.Debugf(ctx, "requested the update of the tenant cluster's control plane cloud formation stack")
@@ -702,3 +943,13 @@ func (r *Resource) updateStack(ctx context.Context, cl apiv1beta1.Cluster, cr in
 
 	return nil
 }
+
+func containsString(list []string, match string) bool {
+	for _, s := range list {
+		if s == match {
+			return true
+		}
+	}
+
+	return false
+}
@@ -15,8 +15,13 @@ import (
 	"github.com/giantswarm/micrologger/microloggertest"
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp/template"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpapiwhitelist"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
@@ -35,14 +40,15 @@ var update = flag.Bool("update", false, "update .golden CF template file")
 //	go test ./service/controller/resource/tccp -run Test_Controller_Resource_TCCP_Template_Render -update
 func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 	testCases := []struct {
-		name           string
-		cr             infrastructurev1alpha3.AWSCluster
-		ctx            context.Context
-		cpAzs          []string
-		cpReplicas     int
-		apiWhitelist   ConfigAPIWhitelistSecurityGroup
-		route53Enabled bool
-		errorMatcher   func(error) bool
+		name                 string
+		cr                   infrastructurev1alpha3.AWSCluster
+		ctx                  context.Context
+		cpAzs                []string
+		cpReplicas           int
+		apiWhitelist         controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup
+		loadBalancerDNSNames bool
+		route53Enabled       bool
+		errorMatcher         func(error) bool
 	}{
 		{
 			name:           "case 0: basic test, route53 enabled",
@@ -68,7 +74,7 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 			ctx:        unittest.DefaultContext(),
 			cpAzs:      []string{"eu-central-1c"},
 			cpReplicas: 1,
-			apiWhitelist: ConfigAPIWhitelistSecurityGroup{
+			apiWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
 				Enabled: true,
 				SubnetList: []string{
 					"172.10.10.10",
@@ -78,6 +84,120 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 			errorMatcher:   nil,
 			route53Enabled: false,
 		},
+		{
+			name:           "case 3: basic test with network load balancers",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.LoadBalancerType, key.LoadBalancerTypeNetwork),
+			ctx:            unittest.DefaultContext(),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 4: migration from classic to network load balancers",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.LoadBalancerType, key.LoadBalancerTypeNetwork),
+			ctx:            withTCCPNLoadBalancerType(unittest.DefaultContext(), key.LoadBalancerTypeClassic),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 5: basic test with vpc endpoints enabled",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.VPCEndpoints, "true"),
+			ctx:            unittest.DefaultContext(),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 6: basic test with dual-stack enabled",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.DualStack, "true"),
+			ctx:            unittest.DefaultContext(),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 7: basic test with interruption handling enabled",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.InterruptionHandling, "true"),
+			ctx:            unittest.DefaultContext(),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:       "case 8: basic test with cluster specific api whitelist",
+			cr:         unittest.DefaultCluster(),
+			ctx:        unittest.DefaultContext(),
+			cpAzs:      []string{"eu-central-1a"},
+			cpReplicas: 1,
+			apiWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+				Enabled: true,
+				SubnetList: []string{
+					"172.10.10.10/32",
+					"203.0.113.0/24",
+				},
+			},
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 9: private cluster",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.PrivateCluster, "true"),
+			ctx:            withPrivateCluster(unittest.DefaultContext()),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:           "case 10: existing public cluster keeps its mode",
+			cr:             withAnnotation(unittest.DefaultCluster(), annotation.PrivateCluster, "true"),
+			ctx:            unittest.DefaultContext(),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name: "case 11: private cluster with network load balancers and additional vpcs",
+			cr: withAnnotation(
+				withAnnotation(
+					withAnnotation(unittest.DefaultCluster(), annotation.PrivateCluster, "true"),
+					annotation.LoadBalancerType, key.LoadBalancerTypeNetwork,
+				),
+				annotation.PrivateHostedZoneVPCs, "vpc-0a1b2c3d, vpc-4e5f6a7b",
+			),
+			ctx:            withPrivateCluster(unittest.DefaultContext()),
+			cpAzs:          []string{"eu-central-1a"},
+			cpReplicas:     1,
+			errorMatcher:   nil,
+			route53Enabled: true,
+		},
+		{
+			name:                 "case 12: basic test with load balancer dns names",
+			cr:                   unittest.DefaultCluster(),
+			ctx:                  unittest.DefaultContext(),
+			cpAzs:                []string{"eu-central-1a"},
+			cpReplicas:           1,
+			errorMatcher:         nil,
+			loadBalancerDNSNames: true,
+			route53Enabled:       false,
+		},
+		{
+			name:                 "case 13: private cluster with network load balancers and load balancer dns names",
+			cr:                   withAnnotation(withAnnotation(unittest.DefaultCluster(), annotation.PrivateCluster, "true"), annotation.LoadBalancerType, key.LoadBalancerTypeNetwork),
+			ctx:                  withPrivateCluster(unittest.DefaultContext()),
+			cpAzs:                []string{"eu-central-1a"},
+			cpReplicas:           1,
+			errorMatcher:         nil,
+			loadBalancerDNSNames: true,
+			route53Enabled:       false,
+		},
 	}
 
 	var err error
@@ -89,11 +209,25 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 				ctx := unittest.DefaultContextControlPlane()
 				k := unittest.FakeK8sClient()
 
+				var e recorder.Interface
+				{
+					c := recorder.Config{
+						K8sClient: k,
+
+						Component: "dummy",
+					}
+
+					e = recorder.New(c)
+				}
+
 				var ct cloudtags.Interface
 				{
 					c := cloudtags.Config{
+						Event:     e,
 						K8sClient: k,
 						Logger:    microloggertest.New(),
+
+						InstallationName: "dummy",
 					}
 
 					ct, err = cloudtags.New(c)
@@ -102,22 +236,26 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 					}
 				}
 
-				var e recorder.Interface
+				var cs changeset.Interface
 				{
-					c := recorder.Config{
+					c := changeset.Config{
+						Event:     e,
 						K8sClient: k,
-
-						Component: "dummy",
+						Logger:    microloggertest.New(),
 					}
 
-					e = recorder.New(c)
+					cs, err = changeset.New(c)
+					if err != nil {
+						t.Fatal(err)
+					}
 				}
 
 				var d *changedetection.TCCP
 				{
 					c := changedetection.TCCPConfig{
-						Event:  e,
-						Logger: microloggertest.New(),
+						Event:     e,
+						K8sClient: k,
+						Logger:    microloggertest.New(),
 					}
 
 					d, err = changedetection.NewTCCP(c)
@@ -168,6 +306,7 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 				}
 
 				c := Config{
+					ChangeSet:  cs,
 					CloudTags:  ct,
 					CtrlClient: k.CtrlClient(),
 					Event:      e,
@@ -176,18 +315,9 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 					K8sClient:  k,
 					Logger:     microloggertest.New(),
 
-					APIWhitelist: ConfigAPIWhitelist{
-						Private: ConfigAPIWhitelistSecurityGroup{
-							Enabled:    tc.apiWhitelist.Enabled,
-							SubnetList: tc.apiWhitelist.SubnetList,
-						},
-						Public: ConfigAPIWhitelistSecurityGroup{
-							Enabled:    tc.apiWhitelist.Enabled,
-							SubnetList: tc.apiWhitelist.SubnetList,
-						},
-					},
-					CIDRBlockAWSCNI: "172.17.0.1/16",
-					Route53Enabled:  tc.route53Enabled,
+					CIDRBlockAWSCNI:             "172.17.0.1/16",
+					LoadBalancerDNSNamesEnabled: tc.loadBalancerDNSNames,
+					Route53Enabled:              tc.route53Enabled,
 				}
 
 				r, err = New(c)
@@ -196,6 +326,17 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 				}
 			}
 
+			{
+				cc, err := controllercontext.FromContext(tc.ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cc.Spec.TenantCluster.TCCP.APIWhitelist.Hash = tccpapiwhitelist.Hash(tc.apiWhitelist, tc.apiWhitelist, nil)
+				cc.Spec.TenantCluster.TCCP.APIWhitelist.Private = tc.apiWhitelist
+				cc.Spec.TenantCluster.TCCP.APIWhitelist.Public = tc.apiWhitelist
+			}
+
 			cl := unittest.DefaultCAPIClusterWithLabels(tc.cr.Name, map[string]string{})
 
 			params, err := r.newParamsMain(tc.ctx, cl, tc.cr, time.Time{})
@@ -231,3 +372,36 @@ func Test_Controller_Resource_TCCP_Template_Render(t *testing.T) {
 		})
 	}
 }
+
+func withAnnotation(cr infrastructurev1alpha3.AWSCluster, k string, v string) infrastructurev1alpha3.AWSCluster {
+	a := cr.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[k] = v
+	cr.SetAnnotations(a)
+
+	return cr
+}
+
+func withTCCPNLoadBalancerType(ctx context.Context, t string) context.Context {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cc.Status.TenantCluster.TCCPN.LoadBalancerType = t
+
+	return ctx
+}
+
+func withPrivateCluster(ctx context.Context) context.Context {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cc.Status.TenantCluster.TCCP.PrivateCluster = true
+
+	return ctx
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -91,6 +92,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 
 		} else if IsNotExists(err) {
 			r.logger.Debugf(ctx, "the tenant cluster's control plane cloud formation stack does not exist")
+			stackmetrics.DeleteStack(key.StackNameTCCP(&cr))
 			r.event.Emit(ctx, &cr, "CFDeleted", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", cloudformation.StackStatusDeleteComplete))
 			r.logger.Debugf(ctx, "canceling resource")
 
@@ -0,0 +1,67 @@
+package tccp
+
+import (
+	"context"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpnoutputs"
+	cloudformationutils "github.com/giantswarm/aws-operator/v16/service/internal/cloudformation"
+)
+
+// findTCCPNLoadBalancerType returns the load balancer type the control plane
+// nodes are currently attached to. The returned type is empty in case the
+// TCCPN stack does not exist yet. The returned bool is false in case the TCCPN
+// stack outputs are not accessible, because the stack is transitioning.
+func (r *Resource) findTCCPNLoadBalancerType(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (string, bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	var cloudFormation *cloudformationutils.CloudFormation
+	{
+		c := cloudformationutils.Config{
+			Client: cc.Client.TenantCluster.AWS.CloudFormation,
+		}
+
+		cloudFormation, err = cloudformationutils.New(c)
+		if err != nil {
+			return "", false, microerror.Mask(err)
+		}
+	}
+
+	o, _, err := cloudFormation.DescribeOutputsAndStatus(key.StackNameTCCPN(&cr))
+	if cloudformationutils.IsStackNotFound(err) {
+		return "", true, nil
+	} else if cloudformationutils.IsOutputsNotAccessible(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	v, err := cloudFormation.GetOutputValue(o, tccpnoutputs.LoadBalancerTypeKey)
+	if cloudformationutils.IsOutputNotFound(err) {
+		// TCCPN stacks created before NLB support do not have the output and are
+		// always attached to the classic ELBs.
+		return key.LoadBalancerTypeClassic, true, nil
+	} else if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	return v, true, nil
+}
+
+// activeLoadBalancerType returns the load balancer type the DNS records and
+// outputs of the TCCP stack point to. During a migration these keep pointing
+// to the load balancers the control plane nodes are currently attached to.
+func activeLoadBalancerType(cr infrastructurev1alpha3.AWSCluster, tccpn string) string {
+	if tccpn != "" {
+		return tccpn
+	}
+
+	return key.LoadBalancerType(cr)
+}
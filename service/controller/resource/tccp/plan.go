@@ -0,0 +1,42 @@
+package tccp
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp/template"
+)
+
+// Plan renders the template of the tenant cluster's control plane cloud
+// formation stack without creating or updating the stack. The zero time is
+// used for all time based resource names, so that plans of the same cluster
+// are comparable.
+func (r *Resource) Plan(ctx context.Context, obj interface{}) (string, error) {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	cluster := apiv1beta1.Cluster{}
+	err = r.ctrlClient.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Name}, &cluster)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	params, err := r.newParamsMain(ctx, cluster, cr, time.Time{})
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	templateBody, err := template.Render(params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return templateBody, nil
+}
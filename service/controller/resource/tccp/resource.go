@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
 	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
@@ -25,9 +26,21 @@ const (
 	namedIAMCapability = "CAPABILITY_NAMED_IAM"
 )
 
+// vpcEndpointServices are the AWS services the tenant cluster nodes talk to
+// frequently. Interface VPC endpoints for these services keep the traffic off
+// the NAT gateways. S3 is served by the gateway endpoint of the VPC.
+var vpcEndpointServices = []string{
+	"autoscaling",
+	"ec2",
+	"ecr.api",
+	"ecr.dkr",
+	"sts",
+}
+
 // Config represents the configuration used to create a new cloudformation
 // resource.
 type Config struct {
+	ChangeSet  changeset.Interface
 	CloudTags  cloudtags.Interface
 	Event      event.Interface
 	CtrlClient ctrlClient.Client
@@ -35,17 +48,27 @@ type Config struct {
 	K8sClient  k8sclient.Interface
 	Logger     micrologger.Logger
 
-	APIWhitelist       ConfigAPIWhitelist
 	CIDRBlockAWSCNI    string
 	Detection          *changedetection.TCCP
 	InstallationName   string
 	InstanceMonitoring bool
-	PublicRouteTables  string
-	Route53Enabled     bool
+	// InterruptionHandlingEnabled defines whether the interruption queue is
+	// created for clusters not configuring it via annotation.
+	InterruptionHandlingEnabled bool
+	// LoadBalancerDNSNamesEnabled defines whether the DNS names of the load
+	// balancers are exposed as stack outputs, so that DNS providers other than
+	// Route53 can point the records of the cluster to them.
+	LoadBalancerDNSNamesEnabled bool
+	PublicRouteTables           string
+	Route53Enabled              bool
+	// VPCEndpointsEnabled defines whether the interface VPC endpoints are
+	// created for clusters not configuring them via annotation.
+	VPCEndpointsEnabled bool
 }
 
 // Resource implements the cloudformation resource.
 type Resource struct {
+	changeSet  changeset.Interface
 	cloudtags  cloudtags.Interface
 	event      event.Interface
 	ctrlClient ctrlClient.Client
@@ -53,17 +76,22 @@ type Resource struct {
 	k8sClient  k8sclient.Interface
 	logger     micrologger.Logger
 
-	apiWhitelist       ConfigAPIWhitelist
-	cidrBlockAWSCNI    string
-	detection          *changedetection.TCCP
-	installationName   string
-	instanceMonitoring bool
-	publicRouteTables  string
-	route53Enabled     bool
+	cidrBlockAWSCNI      string
+	detection            *changedetection.TCCP
+	installationName     string
+	instanceMonitoring   bool
+	interruptionHandling bool
+	loadBalancerDNSNames bool
+	publicRouteTables    string
+	route53Enabled       bool
+	vpcEndpoints         bool
 }
 
 // New creates a new configured cloudformation resource.
 func New(config Config) (*Resource, error) {
+	if config.ChangeSet == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ChangeSet must not be empty", config)
+	}
 	if config.CloudTags == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
 	}
@@ -85,18 +113,12 @@ func New(config Config) (*Resource, error) {
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
-	if config.APIWhitelist.Private.Enabled && len(config.APIWhitelist.Private.SubnetList) == 0 {
-		return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Private.SubnetList must not be empty when %T.APIWhitelist.Private is enabled", config, config)
-	}
-	if config.APIWhitelist.Public.Enabled && len(config.APIWhitelist.Public.SubnetList) == 0 {
-		return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Public.SubnetList must not be empty when %T.APIWhitelist.Public is enabled", config, config)
-	}
-
 	if config.CIDRBlockAWSCNI == "" {
 		return nil, microerror.Maskf(invalidConfigError, "%T.CIDRBlockAWSCNI must not be empty", config)
 	}
 
 	r := &Resource{
+		changeSet:  config.ChangeSet,
 		cloudtags:  config.CloudTags,
 		event:      config.Event,
 		ctrlClient: config.CtrlClient,
@@ -105,12 +127,14 @@ func New(config Config) (*Resource, error) {
 		k8sClient:  config.K8sClient,
 		logger:     config.Logger,
 
-		apiWhitelist:       config.APIWhitelist,
-		cidrBlockAWSCNI:    config.CIDRBlockAWSCNI,
-		installationName:   config.InstallationName,
-		instanceMonitoring: config.InstanceMonitoring,
-		publicRouteTables:  config.PublicRouteTables,
-		route53Enabled:     config.Route53Enabled,
+		cidrBlockAWSCNI:      config.CIDRBlockAWSCNI,
+		installationName:     config.InstallationName,
+		instanceMonitoring:   config.InstanceMonitoring,
+		interruptionHandling: config.InterruptionHandlingEnabled,
+		loadBalancerDNSNames: config.LoadBalancerDNSNamesEnabled,
+		publicRouteTables:    config.PublicRouteTables,
+		route53Enabled:       config.Route53Enabled,
+		vpcEndpoints:         config.VPCEndpointsEnabled,
 	}
 
 	return r, nil
@@ -1,14 +1,17 @@
 package template
 
 type ParamsMain struct {
-	EnableAWSCNI    bool
-	InternetGateway *ParamsMainInternetGateway
-	LoadBalancers   *ParamsMainLoadBalancers
-	NATGateway      *ParamsMainNATGateway
-	Outputs         *ParamsMainOutputs
-	RecordSets      *ParamsMainRecordSets
-	RouteTables     *ParamsMainRouteTables
-	SecurityGroups  *ParamsMainSecurityGroups
-	Subnets         *ParamsMainSubnets
-	VPC             *ParamsMainVPC
+	EnableAWSCNI         bool
+	EnableDualStack      bool
+	InternetGateway      *ParamsMainInternetGateway
+	InterruptionHandling *ParamsMainInterruptionHandling
+	LoadBalancers        *ParamsMainLoadBalancers
+	NATGateway           *ParamsMainNATGateway
+	Outputs              *ParamsMainOutputs
+	RecordSets           *ParamsMainRecordSets
+	RouteTables          *ParamsMainRouteTables
+	SecurityGroups       *ParamsMainSecurityGroups
+	Subnets              *ParamsMainSubnets
+	VPC                  *ParamsMainVPC
+	VPCEndpoints         *ParamsMainVPCEndpoints
 }
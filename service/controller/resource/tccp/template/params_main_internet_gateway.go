@@ -6,6 +6,7 @@ type ParamsMainInternetGateway struct {
 }
 
 type ParamsMainInternetGatewayInternetGateway struct {
-	InternetGatewayRoute string
-	RouteTable           string
+	InternetGatewayRoute     string
+	InternetGatewayIPv6Route string
+	RouteTable               string
 }
@@ -0,0 +1,13 @@
+package template
+
+// ParamsMainInterruptionHandling holds the configuration of the SQS queue and
+// the EventBridge rules delivering interruption events of the tenant cluster's
+// nodes to the operator.
+type ParamsMainInterruptionHandling struct {
+	// AutoScalingGroupPrefix is the name prefix of the node pool ASGs the
+	// lifecycle events are forwarded for.
+	AutoScalingGroupPrefix string
+	ClusterID              string
+	Enabled                bool
+	QueueName              string
+}
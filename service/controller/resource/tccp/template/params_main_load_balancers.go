@@ -1,18 +1,45 @@
 package template
 
 type ParamsMainLoadBalancers struct {
-	APIElbHealthCheckTarget    string
-	APIElbName                 string
-	APIInternalElbName         string
-	APIElbPortsToOpen          []ParamsMainLoadBalancersPortPair
-	APIElbSecurityGroupID      string
+	APIElbHealthCheckTarget string
+	APIElbName              string
+	APIInternalElbName      string
+	APIElbPortsToOpen       []ParamsMainLoadBalancersPortPair
+	APIElbSecurityGroupID   string
+	// Classic defines whether the classic ELBs of the API and etcd endpoints are
+	// rendered.
+	Classic                    bool
 	EtcdElbHealthCheckTarget   string
 	EtcdElbName                string
 	EtcdElbPortsToOpen         []ParamsMainLoadBalancersPortPair
 	EtcdElbSecurityGroupID     string
 	MasterInstanceResourceName string
-	PublicSubnets              []string
-	PrivateSubnets             []string
+	// Network holds the configuration of the NLBs of the API and etcd endpoints.
+	// The NLBs are not rendered in case Network is nil.
+	Network *ParamsMainLoadBalancersNetwork
+	// Private defines whether the public API load balancer is omitted, so that
+	// the API is only reachable via the internal load balancer.
+	Private        bool
+	PublicSubnets  []string
+	PrivateSubnets []string
+}
+
+type ParamsMainLoadBalancersNetwork struct {
+	APIInternalName string
+	APIName         string
+	APIPort         int
+	// EIPs are the static public IPs of the public API NLB, one per
+	// availability zone.
+	EIPs            []ParamsMainLoadBalancersNetworkEIP
+	EtcdName        string
+	EtcdPort        int
+	HealthCheckPath string
+	HealthCheckPort int
+}
+
+type ParamsMainLoadBalancersNetworkEIP struct {
+	Name         string
+	PublicSubnet string
 }
 
 type ParamsMainLoadBalancersPortPair struct {
@@ -1,9 +1,37 @@
 package template
 
 type ParamsMainOutputs struct {
-	Master          ParamsMainOutputsMaster
-	OperatorVersion string
-	Route53Enabled  bool
+	// APIInternalLoadBalancer is the resource name of the internal API load
+	// balancer.
+	APIInternalLoadBalancer string
+	// APILoadBalancer is the resource name of the public API load balancer.
+	APILoadBalancer string
+	// APIWhitelistHash identifies the cluster specific API whitelist entries.
+	// It is empty in case the cluster only uses the installation wide
+	// whitelist.
+	APIWhitelistHash string
+	// DualStack defines whether the IPv6 networking outputs are exposed.
+	DualStack bool
+	// EtcdLoadBalancer is the resource name of the etcd load balancer.
+	EtcdLoadBalancer string
+	// InterruptionHandling defines whether the interruption queue is provided.
+	InterruptionHandling bool
+	// LoadBalancerDNSNames defines whether the DNS names of the load balancers
+	// are exposed. It is only used in case Route53Enabled is false.
+	LoadBalancerDNSNames bool
+	// LoadBalancerTypes is the comma separated list of the load balancer types
+	// provided by the stack.
+	LoadBalancerTypes string
+	Master            ParamsMainOutputsMaster
+	OperatorVersion   string
+	// Private defines whether the outputs of the public hosted zone and the
+	// public API load balancer are omitted.
+	Private        bool
+	Route53Enabled bool
+	// TargetGroups defines whether the target groups of the NLBs are exposed.
+	TargetGroups bool
+	// VPCEndpoints defines whether the interface VPC endpoints are provided.
+	VPCEndpoints bool
 }
 
 type ParamsMainOutputsMaster struct {
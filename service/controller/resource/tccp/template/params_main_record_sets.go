@@ -4,7 +4,24 @@ type ParamsMainRecordSets struct {
 	BaseDomain                 string
 	EtcdDomain                 string
 	ClusterID                  string
+	LoadBalancers              ParamsMainRecordSetsLoadBalancers
 	MasterInstanceResourceName string
-	Route53Enabled             bool
-	VPCRegion                  string
+	// Private defines whether only the internal hosted zone is rendered, which
+	// then holds all the records of the cluster.
+	Private        bool
+	Route53Enabled bool
+	VPCRegion      string
+	// VPCs are the IDs of the additional VPCs the internal hosted zone is
+	// associated with next to the VPC of the cluster.
+	VPCs []string
+}
+
+// ParamsMainRecordSetsLoadBalancers holds the resource names of the load
+// balancers the record sets point to. Classic ELBs and NLBs expose their
+// canonical hosted zone under different attributes.
+type ParamsMainRecordSetsLoadBalancers struct {
+	API                   string
+	APIInternal           string
+	Etcd                  string
+	HostedZoneIDAttribute string
 }
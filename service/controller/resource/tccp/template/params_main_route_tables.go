@@ -12,6 +12,9 @@ type ParamsMainRouteTables struct {
 type ParamsMainRouteTablesRouteTableName struct {
 	AvailabilityZone       string
 	AvailabilityZoneRegion string
-	ResourceName           string
-	VPCPeeringRouteName    string
+	// EgressOnlyInternetGatewayRouteName is the resource name of the IPv6
+	// default route via the egress-only internet gateway.
+	EgressOnlyInternetGatewayRouteName string
+	ResourceName                       string
+	VPCPeeringRouteName                string
 }
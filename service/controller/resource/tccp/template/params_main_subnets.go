@@ -7,8 +7,11 @@ type ParamsMainSubnets struct {
 }
 
 type ParamsMainSubnetsSubnet struct {
-	AvailabilityZone      string
-	CIDR                  string
+	AvailabilityZone string
+	CIDR             string
+	// IPv6CIDRIndex is the index of the subnet's /64 IPv6 CIDR block within
+	// the VPC's IPv6 CIDR block.
+	IPv6CIDRIndex         int
 	Name                  string
 	MapPublicIPOnLaunch   bool
 	RouteTableAssociation ParamsMainSubnetsSubnetRouteTableAssociation
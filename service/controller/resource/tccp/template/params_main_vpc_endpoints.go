@@ -0,0 +1,18 @@
+package template
+
+// ParamsMainVPCEndpoints holds the configuration of the interface VPC
+// endpoints. The S3 gateway endpoint is always part of the VPC.
+type ParamsMainVPCEndpoints struct {
+	// CIDRBlocks are the tenant cluster CIDRs allowed to access the interface
+	// VPC endpoints.
+	CIDRBlocks         []string
+	ClusterID          string
+	Enabled            bool
+	InterfaceEndpoints []ParamsMainVPCEndpointsInterfaceEndpoint
+	PrivateSubnets     []string
+}
+
+type ParamsMainVPCEndpointsInterfaceEndpoint struct {
+	ResourceName string
+	ServiceName  string
+}
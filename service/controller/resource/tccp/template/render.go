@@ -10,6 +10,7 @@ func Render(v interface{}) (string, error) {
 	l := []string{
 		TemplateMain,
 		TemplateMainInternetGateway,
+		TemplateMainInterruptionHandling,
 		TemplateMainLoadBalancers,
 		TemplateMainNatGateway,
 		TemplateMainOutputs,
@@ -18,6 +19,7 @@ func Render(v interface{}) (string, error) {
 		TemplateMainSecurityGroups,
 		TemplateMainSubnets,
 		TemplateMainVPC,
+		TemplateMainVPCEndpoints,
 	}
 
 	s, err := template.Render(l, v)
@@ -15,5 +15,7 @@ Resources:
   {{ template "security_groups" . }}
   {{ template "subnets" . }}
   {{ template "vpc" .}}
+  {{- template "vpc_endpoints" . }}
+  {{- template "interruption_handling" . }}
 {{- end -}}
 `
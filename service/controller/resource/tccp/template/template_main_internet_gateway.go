@@ -29,6 +29,18 @@ const TemplateMainInternetGateway = `
       DestinationCidrBlock: 0.0.0.0/0
       GatewayId:
         Ref: InternetGateway
+  {{- if $.EnableDualStack }}
+  {{ .InternetGatewayIPv6Route }}:
+    Type: AWS::EC2::Route
+    DependsOn:
+      - VPCGatewayAttachment
+      - VPCIPv6CIDRBlock
+    Properties:
+      RouteTableId: !Ref {{ .RouteTable }}
+      DestinationIpv6CidrBlock: ::/0
+      GatewayId:
+        Ref: InternetGateway
+  {{- end }}
   {{- end}}
 {{- end -}}
 `
@@ -0,0 +1,99 @@
+package template
+
+const TemplateMainInterruptionHandling = `
+{{- define "interruption_handling" -}}
+{{- $v := .InterruptionHandling }}
+{{- if $v.Enabled }}
+  InterruptionQueue:
+    Type: AWS::SQS::Queue
+    Properties:
+      MessageRetentionPeriod: 300
+      QueueName: {{ $v.QueueName }}
+      SqsManagedSseEnabled: true
+      Tags:
+        - Key: Name
+          Value: {{ $v.QueueName }}
+  InterruptionQueuePolicy:
+    Type: AWS::SQS::QueuePolicy
+    Properties:
+      PolicyDocument:
+        Version: "2012-10-17"
+        Statement:
+          - Effect: Allow
+            Principal:
+              Service:
+                - events.amazonaws.com
+            Action: sqs:SendMessage
+            Resource: !GetAtt InterruptionQueue.Arn
+            Condition:
+              ArnEquals:
+                aws:SourceArn:
+                  - !GetAtt InterruptionRuleSpotInterruption.Arn
+                  - !GetAtt InterruptionRuleRebalanceRecommendation.Arn
+                  - !GetAtt InterruptionRuleScheduledChange.Arn
+                  - !GetAtt InterruptionRuleInstanceTerminate.Arn
+      Queues:
+        - !Ref InterruptionQueue
+  InterruptionRuleSpotInterruption:
+    Type: AWS::Events::Rule
+    Properties:
+      Description: {{ $v.ClusterID }} EC2 spot instance interruption warnings
+      EventPattern:
+        source:
+          - aws.ec2
+        detail-type:
+          - EC2 Spot Instance Interruption Warning
+      State: ENABLED
+      Targets:
+        - Arn: !GetAtt InterruptionQueue.Arn
+          Id: InterruptionQueue
+  InterruptionRuleRebalanceRecommendation:
+    Type: AWS::Events::Rule
+    Properties:
+      Description: {{ $v.ClusterID }} EC2 instance rebalance recommendations
+      EventPattern:
+        source:
+          - aws.ec2
+        detail-type:
+          - EC2 Instance Rebalance Recommendation
+      State: ENABLED
+      Targets:
+        - Arn: !GetAtt InterruptionQueue.Arn
+          Id: InterruptionQueue
+  InterruptionRuleScheduledChange:
+    Type: AWS::Events::Rule
+    Properties:
+      Description: {{ $v.ClusterID }} EC2 scheduled maintenance events
+      EventPattern:
+        source:
+          - aws.health
+        detail-type:
+          - AWS Health Event
+        detail:
+          service:
+            - EC2
+          eventTypeCategory:
+            - scheduledChange
+      State: ENABLED
+      Targets:
+        - Arn: !GetAtt InterruptionQueue.Arn
+          Id: InterruptionQueue
+  InterruptionRuleInstanceTerminate:
+    Type: AWS::Events::Rule
+    Properties:
+      Description: {{ $v.ClusterID }} node pool instance terminate lifecycle actions
+      EventPattern:
+        source:
+          - aws.autoscaling
+        detail-type:
+          - EC2 Instance-terminate Lifecycle Action
+        detail:
+          AutoScalingGroupName:
+            - prefix: {{ $v.AutoScalingGroupPrefix }}
+      State: ENABLED
+      Targets:
+        - Arn: !GetAtt InterruptionQueue.Arn
+          Id: InterruptionQueue
+{{- end }}
+{{- end -}}
+`
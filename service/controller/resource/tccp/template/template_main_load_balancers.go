@@ -3,6 +3,7 @@ package template
 const TemplateMainLoadBalancers = `
 {{- define "load_balancers" -}}
 {{- $v := .LoadBalancers }}
+{{- if $v.Classic }}
   ApiInternalLoadBalancer:
     Type: AWS::ElasticLoadBalancing::LoadBalancer
     DependsOn:
@@ -31,6 +32,7 @@ const TemplateMainLoadBalancers = `
       {{- range $s := $v.PrivateSubnets }}
         - !Ref {{ $s }}
       {{- end }}
+{{- if not $v.Private }}
   ApiLoadBalancer:
     Type: AWS::ElasticLoadBalancing::LoadBalancer
     DependsOn:
@@ -59,6 +61,7 @@ const TemplateMainLoadBalancers = `
       {{- range $s := $v.PublicSubnets }}
         - !Ref {{ $s }}
       {{- end }}
+{{- end }}
 
   EtcdLoadBalancer:
     Type: AWS::ElasticLoadBalancing::LoadBalancer
@@ -86,5 +89,153 @@ const TemplateMainLoadBalancers = `
       {{- range $s := $v.PrivateSubnets }}
         - !Ref {{ $s }}
       {{- end }}
+{{- end }}
+{{- if $v.Network }}
+{{- $n := $v.Network }}
+{{- range $n.EIPs }}
+  {{ .Name }}:
+    Type: AWS::EC2::EIP
+    DependsOn:
+      - VPCGatewayAttachment
+    Properties:
+      Domain: vpc
+      Tags:
+        - Key: Name
+          Value: {{ $n.APIName }}
+{{- end }}
+  ApiInternalNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    DependsOn:
+      - VPCGatewayAttachment
+    Properties:
+      LoadBalancerAttributes:
+        - Key: load_balancing.cross_zone.enabled
+          Value: "true"
+      Name: {{ $n.APIInternalName }}
+      Scheme: internal
+      SecurityGroups:
+        - !Ref APIInternalELBSecurityGroup
+      Subnets:
+      {{- range $s := $v.PrivateSubnets }}
+        - !Ref {{ $s }}
+      {{- end }}
+      Type: network
+  ApiInternalTargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: 10
+      HealthCheckPath: {{ $n.HealthCheckPath }}
+      HealthCheckPort: {{ $n.HealthCheckPort }}
+      HealthCheckProtocol: HTTP
+      HealthyThresholdCount: 2
+      Port: {{ $n.APIPort }}
+      Protocol: TCP
+      TargetGroupAttributes:
+        - Key: deregistration_delay.timeout_seconds
+          Value: "30"
+        - Key: preserve_client_ip.enabled
+          Value: "false"
+      TargetType: instance
+      UnhealthyThresholdCount: 2
+      VpcId: !Ref VPC
+  ApiInternalListener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+        - TargetGroupArn: !Ref ApiInternalTargetGroup
+          Type: forward
+      LoadBalancerArn: !Ref ApiInternalNetworkLoadBalancer
+      Port: {{ $n.APIPort }}
+      Protocol: TCP
+{{- if not $v.Private }}
+  ApiNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    DependsOn:
+      - VPCGatewayAttachment
+    Properties:
+      LoadBalancerAttributes:
+        - Key: load_balancing.cross_zone.enabled
+          Value: "true"
+      Name: {{ $n.APIName }}
+      Scheme: internet-facing
+      SecurityGroups:
+        - !Ref MasterSecurityGroup
+      SubnetMappings:
+      {{- range $n.EIPs }}
+        - AllocationId: !GetAtt {{ .Name }}.AllocationId
+          SubnetId: !Ref {{ .PublicSubnet }}
+      {{- end }}
+      Type: network
+  ApiTargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: 10
+      HealthCheckPath: {{ $n.HealthCheckPath }}
+      HealthCheckPort: {{ $n.HealthCheckPort }}
+      HealthCheckProtocol: HTTP
+      HealthyThresholdCount: 2
+      Port: {{ $n.APIPort }}
+      Protocol: TCP
+      TargetGroupAttributes:
+        - Key: deregistration_delay.timeout_seconds
+          Value: "30"
+        # The public API keeps the source IPs of its clients so that they can
+        # be audited and matched against the API whitelist.
+        - Key: preserve_client_ip.enabled
+          Value: "true"
+      TargetType: instance
+      UnhealthyThresholdCount: 2
+      VpcId: !Ref VPC
+  ApiListener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+        - TargetGroupArn: !Ref ApiTargetGroup
+          Type: forward
+      LoadBalancerArn: !Ref ApiNetworkLoadBalancer
+      Port: {{ $n.APIPort }}
+      Protocol: TCP
+{{- end }}
+  EtcdNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    Properties:
+      LoadBalancerAttributes:
+        - Key: load_balancing.cross_zone.enabled
+          Value: "true"
+      Name: {{ $n.EtcdName }}
+      Scheme: internal
+      SecurityGroups:
+        - !Ref EtcdELBSecurityGroup
+      Subnets:
+      {{- range $s := $v.PrivateSubnets }}
+        - !Ref {{ $s }}
+      {{- end }}
+      Type: network
+  EtcdTargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: 10
+      HealthCheckProtocol: TCP
+      HealthyThresholdCount: 2
+      Port: {{ $n.EtcdPort }}
+      Protocol: TCP
+      TargetGroupAttributes:
+        - Key: deregistration_delay.timeout_seconds
+          Value: "30"
+        - Key: preserve_client_ip.enabled
+          Value: "false"
+      TargetType: instance
+      UnhealthyThresholdCount: 2
+      VpcId: !Ref VPC
+  EtcdListener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+        - TargetGroupArn: !Ref EtcdTargetGroup
+          Type: forward
+      LoadBalancerArn: !Ref EtcdNetworkLoadBalancer
+      Port: {{ $n.EtcdPort }}
+      Protocol: TCP
+{{- end }}
 {{- end -}}
 `
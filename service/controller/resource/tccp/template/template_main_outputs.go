@@ -3,17 +3,61 @@ package template
 const TemplateMainOutputs = `
 {{- define "outputs" -}}
   {{- if .Outputs.Route53Enabled -}}
+  {{- if not .Outputs.Private -}}
   APIServerPublicLoadBalancer:
-    Value: !GetAtt ApiLoadBalancer.DNSName
+    Value: !GetAtt {{ .Outputs.APILoadBalancer }}.DNSName
   HostedZoneID: 
     Value: !Ref HostedZone
+  {{ end -}}
   InternalHostedZoneID: 
     Value: !Ref InternalHostedZone
+  {{- if not .Outputs.Private }}
   HostedZoneNameServers:
     Value: !Join [ ',', !GetAtt 'HostedZone.NameServers' ]
+  {{- end }}
   {{ end -}}
+  InterruptionHandling:
+    Value: {{ .Outputs.InterruptionHandling }}
+  LoadBalancerTypes:
+    Value: {{ .Outputs.LoadBalancerTypes }}
+  {{- if and .Outputs.LoadBalancerDNSNames (not .Outputs.Route53Enabled) }}
+  APIServerInternalLoadBalancer:
+    Value: !GetAtt {{ .Outputs.APIInternalLoadBalancer }}.DNSName
+  {{- if not .Outputs.Private }}
+  APIServerPublicLoadBalancer:
+    Value: !GetAtt {{ .Outputs.APILoadBalancer }}.DNSName
+  {{- end }}
+  EtcdLoadBalancer:
+    Value: !GetAtt {{ .Outputs.EtcdLoadBalancer }}.DNSName
+  {{- end }}
+  {{- if .Outputs.TargetGroups }}
+  APIInternalTargetGroupARN:
+    Value: !Ref ApiInternalTargetGroup
+  {{- if not .Outputs.Private }}
+  APITargetGroupARN:
+    Value: !Ref ApiTargetGroup
+  {{- end }}
+  EtcdTargetGroupARN:
+    Value: !Ref EtcdTargetGroup
+  {{- end }}
+  {{- if .Outputs.DualStack }}
+  EgressOnlyInternetGatewayID:
+    Value: !Ref EgressOnlyInternetGateway
+  VPCIPv6CIDR:
+    Value: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+  {{- end }}
+  {{- if .Outputs.APIWhitelistHash }}
+  APIWhitelistHash:
+    Value: "{{ .Outputs.APIWhitelistHash }}"
+  {{- end }}
   OperatorVersion:
     Value: {{ .Outputs.OperatorVersion }}
+  {{- if .Outputs.Private }}
+  PrivateCluster:
+    Value: true
+  {{- end }}
+  VPCEndpoints:
+    Value: {{ .Outputs.VPCEndpoints }}
   VPCID:
     Value: !Ref VPC
   VPCPeeringConnectionID:
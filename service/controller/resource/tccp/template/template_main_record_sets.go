@@ -3,12 +3,17 @@ package template
 const TemplateMainRecordSets = `
 {{- define "record_sets" -}}
 {{- $v := .RecordSets }}
+{{- $lb := $v.LoadBalancers }}
 {{- if $v.Route53Enabled -}}
+{{- if not $v.Private -}}
   HostedZone:
     Type: 'AWS::Route53::HostedZone'
     Properties:
       Name: '{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
   InternalHostedZone:
+{{- else -}}
+  InternalHostedZone:
+{{- end }}
     Type: 'AWS::Route53::HostedZone'
     Properties:
       Name: '{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
@@ -17,12 +22,28 @@ const TemplateMainRecordSets = `
       VPCs:
         - VPCId: !Ref VPC
           VPCRegion: '{{ $v.VPCRegion }}'
+      {{- range $v.VPCs }}
+        - VPCId: {{ . }}
+          VPCRegion: '{{ $v.VPCRegion }}'
+      {{- end }}
+{{- if $v.Private }}
+  ApiInternalRecordSet:
+    Type: AWS::Route53::RecordSet
+    Properties:
+      AliasTarget:
+        DNSName: !GetAtt {{ $lb.APIInternal }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.APIInternal }}.{{ $lb.HostedZoneIDAttribute }}
+        EvaluateTargetHealth: false
+      Name: 'internal-api.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
+      HostedZoneId: !Ref 'InternalHostedZone'
+      Type: A
+{{- else }}
   ApiRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt ApiLoadBalancer.DNSName
-        HostedZoneId: !GetAtt ApiLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $lb.API }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.API }}.{{ $lb.HostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: 'api.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
       HostedZoneId: !Ref 'HostedZone'
@@ -31,18 +52,19 @@ const TemplateMainRecordSets = `
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt ApiInternalLoadBalancer.DNSName
-        HostedZoneId: !GetAtt ApiInternalLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $lb.APIInternal }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.APIInternal }}.{{ $lb.HostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: 'internal-api.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
       HostedZoneId: !Ref 'HostedZone'
       Type: A
+{{- end }}
   ApiPrivateInternalRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt ApiInternalLoadBalancer.DNSName
-        HostedZoneId: !GetAtt ApiInternalLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $lb.APIInternal }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.APIInternal }}.{{ $lb.HostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: 'api.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
       HostedZoneId: !Ref 'InternalHostedZone'
@@ -51,18 +73,19 @@ const TemplateMainRecordSets = `
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt EtcdLoadBalancer.DNSName
-        HostedZoneId: !GetAtt EtcdLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $lb.Etcd }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.Etcd }}.{{ $lb.HostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: '{{ $v.EtcdDomain }}.'
       HostedZoneId: !Ref 'InternalHostedZone'
       Type: A
+{{- if not $v.Private }}
   EtcdRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt EtcdLoadBalancer.DNSName
-        HostedZoneId: !GetAtt EtcdLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $lb.Etcd }}.DNSName
+        HostedZoneId: !GetAtt {{ $lb.Etcd }}.{{ $lb.HostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: '{{ $v.EtcdDomain }}.'
       HostedZoneId: !Ref 'HostedZone'
@@ -76,6 +99,7 @@ const TemplateMainRecordSets = `
       Type: CNAME
       ResourceRecords:
         - 'ingress.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
+{{- end }}
   IngressWildcardInternalRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
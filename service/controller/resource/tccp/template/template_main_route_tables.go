@@ -16,6 +16,15 @@ const TemplateMainRouteTables = `
         Value: {{ .AvailabilityZone }}
       - Key: giantswarm.io/route-table-type
         Value: aws-cni
+  {{- if $.EnableDualStack }}
+  {{ .EgressOnlyInternetGatewayRouteName }}:
+    Type: AWS::EC2::Route
+    DependsOn: VPCIPv6CIDRBlock
+    Properties:
+      RouteTableId: !Ref {{ .ResourceName }}
+      DestinationIpv6CidrBlock: ::/0
+      EgressOnlyInternetGatewayId: !Ref EgressOnlyInternetGateway
+  {{- end }}
   {{- end }}
   {{- end }}
   {{- range $v.PublicRouteTableNames }}
@@ -50,6 +59,15 @@ const TemplateMainRouteTables = `
       DestinationCidrBlock: {{ $v.HostClusterCIDR }}
       VpcPeeringConnectionId:
         Ref: VPCPeeringConnection
+  {{- if $.EnableDualStack }}
+  {{ .EgressOnlyInternetGatewayRouteName }}:
+    Type: AWS::EC2::Route
+    DependsOn: VPCIPv6CIDRBlock
+    Properties:
+      RouteTableId: !Ref {{ .ResourceName }}
+      DestinationIpv6CidrBlock: ::/0
+      EgressOnlyInternetGatewayId: !Ref EgressOnlyInternetGateway
+  {{- end }}
   {{- end }}
 {{- end -}}
 `
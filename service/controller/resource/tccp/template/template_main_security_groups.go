@@ -277,6 +277,55 @@ const TemplateMainSecurityGroups = `
       FromPort: 2379
       ToPort: 2379
       SourceSecurityGroupId: !Ref EtcdELBSecurityGroup
+  {{- if .EnableDualStack }}
+  MasterIPv6IngressRule:
+    Type: AWS::EC2::SecurityGroupIngress
+    DependsOn:
+      - MasterSecurityGroup
+      - VPCIPv6CIDRBlock
+    Properties:
+      GroupId: !Ref MasterSecurityGroup
+      IpProtocol: tcp
+      FromPort: 443
+      ToPort: 443
+      {{- if $v.APIWhitelist.Public.Enabled }}
+      Description: "Allow traffic from Tenant Cluster IPv6 CIDR."
+      CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+      {{- else }}
+      Description: "Allow all IPv6 traffic to the master instance."
+      CidrIpv6: ::/0
+      {{- end }}
+  MasterIPv6EgressRule:
+    Type: AWS::EC2::SecurityGroupEgress
+    DependsOn: MasterSecurityGroup
+    Properties:
+      Description: Allow all outbound IPv6 traffic.
+      GroupId: !Ref MasterSecurityGroup
+      IpProtocol: -1
+      CidrIpv6: ::/0
+  EtcdELBIPv6IngressRule:
+    Type: AWS::EC2::SecurityGroupIngress
+    DependsOn: EtcdELBSecurityGroup
+    Properties:
+      Description: "Allow all Etcd IPv6 traffic from the VPC to the Etcd load balancer."
+      GroupId: !Ref EtcdELBSecurityGroup
+      IpProtocol: tcp
+      FromPort: 2379
+      ToPort: 2379
+      CidrIpv6: ::/0
+  APIInternalELBIPv6IngressRule:
+    Type: AWS::EC2::SecurityGroupIngress
+    DependsOn:
+      - APIInternalELBSecurityGroup
+      - VPCIPv6CIDRBlock
+    Properties:
+      Description: "Allow traffic from Tenant Cluster IPv6 CIDR."
+      GroupId: !Ref APIInternalELBSecurityGroup
+      IpProtocol: tcp
+      FromPort: 443
+      ToPort: 443
+      CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+  {{- end }}
   VPCDefaultSecurityGroupEgress:
     Type: AWS::EC2::SecurityGroupEgress
     Properties:
@@ -9,9 +9,16 @@ const TemplateMainSubnets = `
     Type: AWS::EC2::Subnet
     DependsOn:
     - VPCCIDRBlockAWSCNI
+    {{- if $.EnableDualStack }}
+    - VPCIPv6CIDRBlock
+    {{- end }}
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if $.EnableDualStack }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ], 256, 64 ] ]
+      {{- end }}
       Tags:
       - Key: Name
         Value: {{ .Name }}
@@ -28,9 +35,17 @@ const TemplateMainSubnets = `
   {{- range $v.PublicSubnets }}
   {{ .Name }}:
     Type: AWS::EC2::Subnet
+    {{- if $.EnableDualStack }}
+    DependsOn:
+    - VPCIPv6CIDRBlock
+    {{- end }}
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if $.EnableDualStack }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ], 256, 64 ] ]
+      {{- end }}
       MapPublicIpOnLaunch: {{ .MapPublicIPOnLaunch }}
       Tags:
       - Key: Name
@@ -51,9 +66,17 @@ const TemplateMainSubnets = `
   {{- range $v.PrivateSubnets }}
   {{ .Name }}:
     Type: AWS::EC2::Subnet
+    {{- if $.EnableDualStack }}
+    DependsOn:
+    - VPCIPv6CIDRBlock
+    {{- end }}
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if $.EnableDualStack }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ], 256, 64 ] ]
+      {{- end }}
       MapPublicIpOnLaunch: {{ .MapPublicIPOnLaunch }}
       Tags:
       - Key: Name
@@ -0,0 +1,41 @@
+package template
+
+const TemplateMainVPCEndpoints = `
+{{- define "vpc_endpoints" -}}
+{{- $v := .VPCEndpoints }}
+{{- if $v.Enabled }}
+  VPCEndpointSecurityGroup:
+    Type: AWS::EC2::SecurityGroup
+    Properties:
+      GroupDescription: {{ $v.ClusterID }}-vpc-endpoints
+      VpcId: !Ref VPC
+      SecurityGroupIngress:
+      {{- range $v.CIDRBlocks }}
+      -
+        Description: "Allow HTTPS traffic from the Tenant Cluster to the VPC endpoints."
+        IpProtocol: tcp
+        FromPort: 443
+        ToPort: 443
+        CidrIp: {{ . }}
+      {{- end }}
+      Tags:
+        - Key: Name
+          Value: {{ $v.ClusterID }}-vpc-endpoints
+  {{- range $v.InterfaceEndpoints }}
+  {{ .ResourceName }}:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      PrivateDnsEnabled: true
+      SecurityGroupIds:
+        - !Ref VPCEndpointSecurityGroup
+      ServiceName: {{ .ServiceName }}
+      SubnetIds:
+      {{- range $v.PrivateSubnets }}
+        - !Ref {{ . }}
+      {{- end }}
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+  {{- end }}
+{{- end }}
+{{- end -}}
+`
@@ -0,0 +1,280 @@
+package tccpapiwhitelist
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"unicode"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+const (
+	// configMapKeyPrivate and configMapKeyPublic are the keys of the ConfigMap
+	// referenced by the cluster holding the cluster specific whitelist
+	// entries.
+	configMapKeyPrivate = "private"
+	configMapKeyPublic  = "public"
+)
+
+const (
+	// securityGroupRulesQuota is the default AWS quota of inbound rules per
+	// security group. The quota is enforced separately for IPv4 and IPv6
+	// rules, while rules referencing security groups count towards both.
+	securityGroupRulesQuota = 60
+	// apiInternalSecurityGroupRules is the number of IPv4 inbound rules the
+	// TCCP template defines for the APIInternalELBSecurityGroup next to the
+	// private API whitelist. These are the HTTPS rules for the control plane
+	// VPC CIDR, the tenant cluster VPC CIDR and the tenant cluster CNI CIDR.
+	apiInternalSecurityGroupRules = 3
+	// masterSecurityGroupRules is the number of IPv4 inbound rules the TCCP
+	// template defines for the MasterSecurityGroup next to the public API
+	// whitelist, the control plane NAT gateway addresses and the tenant
+	// cluster NAT gateway EIPs, of which there is one per availability zone.
+	//
+	//   - 2 HTTPS rules for the control plane VPC CIDR and the tenant cluster
+	//     VPC CIDR.
+	//   - 6 rules for cadvisor, etcd, kubelet, node-exporter,
+	//     kube-state-metrics and SSH from the control plane VPC CIDR.
+	//   - 4 rules referencing security groups, which are
+	//     MasterAllowCalicoIngressRule, MasterAllowAPIInternalELBHealthCheck,
+	//     MasterAllowEtcdIngressRule and MasterAllowPodsCNIIngressRule. The
+	//     latter only exists with AWS CNI and is always counted to be safe.
+	masterSecurityGroupRules = 2 + 6 + 4
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.Debugf(ctx, "resolving the tenant cluster's API whitelist")
+
+	// The NAT gateway addresses of the control plane are always whitelisted by
+	// the TCCP stack. Listing them again would result in duplicated security
+	// group rules, which Cloud Formation rejects.
+	var natGatewaySubnets []string
+	for _, a := range cc.Status.ControlPlane.NATGateway.Addresses {
+		if a.PublicIp != nil {
+			natGatewaySubnets = append(natGatewaySubnets, *a.PublicIp+"/32")
+		}
+	}
+	sort.Strings(natGatewaySubnets)
+
+	// Invalid cluster specific entries must not block the reconciliation of
+	// the cluster. They are reported and the installation wide whitelist is
+	// used instead until the entries are fixed.
+	apiWhitelist, err := r.resolve(ctx, cr, natGatewaySubnets, true)
+	if IsInvalidAPIWhitelist(err) {
+		r.logger.Debugf(ctx, "ignoring cluster specific API whitelist: %s", microerror.Pretty(err, false))
+		r.event.Emit(ctx, &cr, "APIWhitelistInvalid", fmt.Sprintf("ignored cluster specific API whitelist and fell back to the installation wide API whitelist: %s", microerror.Pretty(err, false)))
+
+		apiWhitelist, err = r.resolve(ctx, cr, natGatewaySubnets, false)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	cc.Spec.TenantCluster.TCCP.APIWhitelist = apiWhitelist
+
+	r.logger.Debugf(ctx, "resolved the tenant cluster's API whitelist")
+
+	return nil
+}
+
+// Hash identifies the given API whitelist together with the control plane NAT
+// gateway addresses whitelisted next to the public API whitelist, so that any
+// change of the security group rules results in a stack update. The hash is
+// empty if both whitelists are disabled so that clusters not using any
+// whitelist do not need a stack update.
+func Hash(private controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup, public controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup, natGatewaySubnets []string) string {
+	if !private.Enabled && !public.Enabled {
+		return ""
+	}
+
+	var nat []string
+	if public.Enabled {
+		nat = natGatewaySubnets
+	}
+
+	s := fmt.Sprintf(
+		"private=%t:%s;public=%t:%s;nat=%s",
+		private.Enabled, strings.Join(private.SubnetList, ","),
+		public.Enabled, strings.Join(public.SubnetList, ","),
+		strings.Join(nat, ","),
+	)
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// resolve returns the API whitelist of the given cluster. Cluster specific
+// entries are only considered if includeCluster is true.
+func (r *Resource) resolve(ctx context.Context, cr infrastructurev1alpha3.AWSCluster, natGatewaySubnets []string, includeCluster bool) (controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{}, microerror.Mask(err)
+	}
+
+	var private []string
+	var public []string
+	if includeCluster {
+		private, public, err = r.clusterSubnets(ctx, cr)
+		if err != nil {
+			return controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{}, microerror.Mask(err)
+		}
+	}
+
+	apiWhitelist := controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+		Private: merge(r.apiWhitelist.Private, private, nil),
+		Public:  merge(r.apiWhitelist.Public, public, natGatewaySubnets),
+	}
+	apiWhitelist.Hash = Hash(apiWhitelist.Private, apiWhitelist.Public, natGatewaySubnets)
+
+	if apiWhitelist.Private.Enabled {
+		rules := apiInternalSecurityGroupRules + len(apiWhitelist.Private.SubnetList)
+		if rules > securityGroupRulesQuota {
+			return controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{}, microerror.Maskf(invalidAPIWhitelistError, "private API whitelist requires %d inbound rules exceeding the security group quota of %d", rules, securityGroupRulesQuota)
+		}
+	}
+	if apiWhitelist.Public.Enabled {
+		rules := masterSecurityGroupRules + len(apiWhitelist.Public.SubnetList) + len(natGatewaySubnets) + len(cc.Spec.TenantCluster.TCCP.AvailabilityZones)
+		if rules > securityGroupRulesQuota {
+			return controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{}, microerror.Maskf(invalidAPIWhitelistError, "public API whitelist requires %d inbound rules exceeding the security group quota of %d", rules, securityGroupRulesQuota)
+		}
+	}
+
+	return apiWhitelist, nil
+}
+
+// clusterSubnets returns the cluster specific private and public whitelist
+// entries of the annotations and the ConfigMap referenced by the given
+// cluster. The entries are normalized, de-duplicated and sorted.
+func (r *Resource) clusterSubnets(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) ([]string, []string, error) {
+	private := split(cr.GetAnnotations()[annotation.APIWhitelistPrivate])
+	public := split(cr.GetAnnotations()[annotation.APIWhitelistPublic])
+
+	name := cr.GetAnnotations()[annotation.APIWhitelistConfigMap]
+	if name != "" {
+		var cm corev1.ConfigMap
+		err := r.k8sClient.CtrlClient().Get(ctx, client.ObjectKey{Namespace: cr.GetNamespace(), Name: name}, &cm)
+		if apierrors.IsNotFound(err) {
+			return nil, nil, microerror.Maskf(invalidAPIWhitelistError, "ConfigMap %#q referenced by annotation %#q not found", name, annotation.APIWhitelistConfigMap)
+		} else if err != nil {
+			return nil, nil, microerror.Mask(err)
+		}
+
+		private = append(private, split(cm.Data[configMapKeyPrivate])...)
+		public = append(public, split(cm.Data[configMapKeyPublic])...)
+	}
+
+	private, err := parseSubnets(private)
+	if err != nil {
+		return nil, nil, microerror.Maskf(invalidAPIWhitelistError, "private API whitelist must be valid: %s", err)
+	}
+	public, err = parseSubnets(public)
+	if err != nil {
+		return nil, nil, microerror.Maskf(invalidAPIWhitelistError, "public API whitelist must be valid: %s", err)
+	}
+
+	sort.Strings(private)
+	sort.Strings(public)
+
+	return private, public, nil
+}
+
+// merge returns the security group whitelist of the installation wide
+// whitelist and the given cluster specific entries. The whitelist is enabled
+// as soon as the cluster defines entries on its own. Entries of exclude are
+// removed.
+func merge(global ConfigAPIWhitelistSecurityGroup, cluster []string, exclude []string) controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup {
+	var subnets []string
+	if global.Enabled {
+		subnets = append(subnets, global.SubnetList...)
+	}
+	subnets = append(subnets, cluster...)
+
+	seen := map[string]bool{}
+	for _, e := range exclude {
+		seen[e] = true
+	}
+
+	var unique []string
+	for _, s := range subnets {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		unique = append(unique, s)
+	}
+
+	sg := controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+		Enabled:    global.Enabled || len(cluster) != 0,
+		SubnetList: unique,
+	}
+
+	return sg
+}
+
+// parseSubnets validates and normalizes the given whitelist entries. Entries
+// may be IPv4 CIDRs or IPv4 addresses, which are whitelisted as /32 CIDRs.
+// Empty entries are ignored and duplicates removed.
+func parseSubnets(list []string) ([]string, error) {
+	var subnets []string
+	seen := map[string]bool{}
+
+	for _, l := range list {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+
+		if !strings.Contains(l, "/") {
+			l += "/32"
+		}
+
+		ip, n, err := net.ParseCIDR(l)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if ip.To4() == nil {
+			return nil, microerror.Maskf(invalidAPIWhitelistError, "%#q is not an IPv4 CIDR", l)
+		}
+
+		s := n.String()
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		subnets = append(subnets, s)
+	}
+
+	return subnets, nil
+}
+
+// split returns the entries of the given list separated by commas or
+// whitespace.
+func split(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
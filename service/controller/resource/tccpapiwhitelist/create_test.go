@@ -0,0 +1,392 @@
+package tccpapiwhitelist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_EnsureCreated_APIWhitelist(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		cluster              infrastructurev1alpha3.AWSCluster
+		configMaps           []corev1.ConfigMap
+		apiWhitelist         ConfigAPIWhitelist
+		natGatewayAddresses  []string
+		expectedAPIWhitelist controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist
+		errorMatcher         func(error) bool
+	}{
+		{
+			name:    "case 0: installation wide whitelist disabled, no cluster specific entries",
+			cluster: unittest.DefaultCluster(),
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: "",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:    "case 1: installation wide whitelist enabled, no cluster specific entries",
+			cluster: unittest.DefaultCluster(),
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10", "172.20.20.0/24"},
+				},
+			},
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: Hash(
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{},
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"172.10.10.10/32", "172.20.20.0/24"},
+					},
+					nil,
+				),
+				Public: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10/32", "172.20.20.0/24"},
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 2: cluster specific entries merged with the installation wide whitelist and NAT gateway addresses",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistPublic: "203.0.113.0/24, 172.20.20.0/24,198.51.100.7 203.0.113.10/24",
+			}),
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10", "172.20.20.0/24"},
+				},
+			},
+			natGatewayAddresses: []string{"198.51.100.7"},
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: Hash(
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{},
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"172.10.10.10/32", "172.20.20.0/24", "203.0.113.0/24"},
+					},
+					[]string{"198.51.100.7/32"},
+				),
+				Public: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10/32", "172.20.20.0/24", "203.0.113.0/24"},
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 3: cluster specific entries of annotations and ConfigMap enable the whitelist",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistConfigMap: "8y5ck-api-whitelist",
+				annotation.APIWhitelistPrivate:   "10.10.0.0/16",
+			}),
+			configMaps: []corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "8y5ck-api-whitelist",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"private": "10.20.0.0/16\n10.10.0.0/16\n",
+						"public":  "203.0.113.0/24\n",
+					},
+				},
+			},
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: Hash(
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"10.10.0.0/16", "10.20.0.0/16"},
+					},
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"203.0.113.0/24"},
+					},
+					nil,
+				),
+				Private: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"10.10.0.0/16", "10.20.0.0/16"},
+				},
+				Public: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"203.0.113.0/24"},
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 4: referenced ConfigMap does not exist, fall back to the installation wide whitelist",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistConfigMap: "8y5ck-api-whitelist",
+				annotation.APIWhitelistPublic:    "203.0.113.0/24",
+			}),
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10"},
+				},
+			},
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: Hash(
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{},
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"172.10.10.10/32"},
+					},
+					nil,
+				),
+				Public: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10/32"},
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 5: invalid CIDR, fall back to the installation wide whitelist",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistPublic: "203.0.113.0/33",
+			}),
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: "",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 6: IPv6 CIDR, fall back to the installation wide whitelist",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistPrivate: "2001:db8::/32",
+			}),
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: "",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 7: security group quota exceeded, fall back to the installation wide whitelist",
+			cluster: withAnnotations(unittest.DefaultCluster(), map[string]string{
+				annotation.APIWhitelistPublic: subnets(50),
+			}),
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: "",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:    "case 8: security group quota exceeded by the installation wide whitelist",
+			cluster: unittest.DefaultCluster(),
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: strings.Split(subnets(50), ","),
+				},
+			},
+			errorMatcher: IsInvalidAPIWhitelist,
+		},
+		{
+			name:    "case 9: changed NAT gateway addresses change the hash",
+			cluster: unittest.DefaultCluster(),
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10"},
+				},
+			},
+			natGatewayAddresses: []string{"198.51.100.8", "198.51.100.7"},
+			expectedAPIWhitelist: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelist{
+				Hash: Hash(
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{},
+					controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+						Enabled:    true,
+						SubnetList: []string{"172.10.10.10/32"},
+					},
+					[]string{"198.51.100.7/32", "198.51.100.8/32"},
+				),
+				Public: controllercontext.ContextSpecTenantClusterTCCPAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"172.10.10.10/32"},
+				},
+			},
+			errorMatcher: nil,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			ctx := unittest.DefaultContext()
+
+			var cc *controllercontext.Context
+			{
+				var err error
+
+				cc, err = controllercontext.FromContext(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, a := range tc.natGatewayAddresses {
+					cc.Status.ControlPlane.NATGateway.Addresses = append(cc.Status.ControlPlane.NATGateway.Addresses, &ec2.Address{
+						PublicIp: aws.String(a),
+					})
+				}
+			}
+
+			k := unittest.FakeK8sClient()
+			for _, cm := range tc.configMaps {
+				cm := cm
+				err := k.CtrlClient().Create(ctx, &cm)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					Event:     recorder.New(recorder.Config{K8sClient: k}),
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+
+					APIWhitelist: tc.apiWhitelist,
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := r.EnsureCreated(ctx, &tc.cluster)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if !cmp.Equal(cc.Spec.TenantCluster.TCCP.APIWhitelist, tc.expectedAPIWhitelist) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedAPIWhitelist, cc.Spec.TenantCluster.TCCP.APIWhitelist))
+			}
+		})
+	}
+}
+
+func Test_New_APIWhitelist(t *testing.T) {
+	testCases := []struct {
+		name         string
+		apiWhitelist ConfigAPIWhitelist
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: disabled whitelist without subnets",
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    false,
+					SubnetList: []string{""},
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 1: enabled whitelist without subnets",
+			apiWhitelist: ConfigAPIWhitelist{
+				Public: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{""},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 2: enabled whitelist with invalid subnet",
+			apiWhitelist: ConfigAPIWhitelist{
+				Private: ConfigAPIWhitelistSecurityGroup{
+					Enabled:    true,
+					SubnetList: []string{"10.0.0.0/8", "invalid"},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			k := unittest.FakeK8sClient()
+
+			c := Config{
+				Event:     recorder.New(recorder.Config{K8sClient: k}),
+				K8sClient: k,
+				Logger:    microloggertest.New(),
+
+				APIWhitelist: tc.apiWhitelist,
+			}
+
+			_, err := New(c)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func subnets(n int) string {
+	var s []string
+	for i := 0; i < n; i++ {
+		s = append(s, fmt.Sprintf("203.0.113.%d", i))
+	}
+
+	return strings.Join(s, ",")
+}
+
+func withAnnotations(cr infrastructurev1alpha3.AWSCluster, annotations map[string]string) infrastructurev1alpha3.AWSCluster {
+	a := cr.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	for k, v := range annotations {
+		a[k] = v
+	}
+	cr.SetAnnotations(a)
+
+	return cr
+}
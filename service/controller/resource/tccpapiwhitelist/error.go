@@ -0,0 +1,23 @@
+package tccpapiwhitelist
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidAPIWhitelistError = &microerror.Error{
+	Kind: "invalidAPIWhitelistError",
+}
+
+// IsInvalidAPIWhitelist asserts invalidAPIWhitelistError.
+func IsInvalidAPIWhitelist(err error) bool {
+	return microerror.Cause(err) == invalidAPIWhitelistError
+}
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
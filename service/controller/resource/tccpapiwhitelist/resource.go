@@ -0,0 +1,105 @@
+// Package tccpapiwhitelist implements a resource to resolve the API whitelist
+// of a tenant cluster. The installation wide whitelist is merged with the
+// cluster specific entries given via annotations or a referenced ConfigMap.
+// Invalid cluster specific entries are reported as events on the AWSCluster
+// CR and the installation wide whitelist is used instead.
+package tccpapiwhitelist
+
+import (
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+)
+
+const (
+	Name = "tccpapiwhitelist"
+)
+
+// ConfigAPIWhitelist defines guest cluster k8s public/private api whitelisting.
+type ConfigAPIWhitelist struct {
+	Private ConfigAPIWhitelistSecurityGroup
+	Public  ConfigAPIWhitelistSecurityGroup
+}
+
+// ConfigAPIWhitelistSecurityGroup represents the structure required for
+// defining whitelisting for resource security group
+type ConfigAPIWhitelistSecurityGroup struct {
+	Enabled    bool
+	SubnetList []string
+}
+
+type Config struct {
+	Event     event.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
+
+	APIWhitelist ConfigAPIWhitelist
+}
+
+type Resource struct {
+	event     event.Interface
+	k8sClient k8sclient.Interface
+	logger    micrologger.Logger
+
+	apiWhitelist ConfigAPIWhitelist
+}
+
+func New(config Config) (*Resource, error) {
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	var err error
+
+	var private []string
+	if config.APIWhitelist.Private.Enabled {
+		private, err = parseSubnets(config.APIWhitelist.Private.SubnetList)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Private.SubnetList must be valid: %s", config, err)
+		}
+		if len(private) == 0 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Private.SubnetList must not be empty when %T.APIWhitelist.Private is enabled", config, config)
+		}
+	}
+	var public []string
+	if config.APIWhitelist.Public.Enabled {
+		public, err = parseSubnets(config.APIWhitelist.Public.SubnetList)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Public.SubnetList must be valid: %s", config, err)
+		}
+		if len(public) == 0 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.APIWhitelist.Public.SubnetList must not be empty when %T.APIWhitelist.Public is enabled", config, config)
+		}
+	}
+
+	r := &Resource{
+		event:     config.Event,
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+
+		apiWhitelist: ConfigAPIWhitelist{
+			Private: ConfigAPIWhitelistSecurityGroup{
+				Enabled:    config.APIWhitelist.Private.Enabled,
+				SubnetList: private,
+			},
+			Public: ConfigAPIWhitelistSecurityGroup{
+				Enabled:    config.APIWhitelist.Public.Enabled,
+				SubnetList: public,
+			},
+		},
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpf/template"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 const (
@@ -43,7 +45,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			StackName: aws.String(key.StackNameTCCPF(&cr)),
 		}
 
-		o, err := cc.Client.ControlPlane.AWS.CloudFormation.DescribeStacks(i)
+		stacks, err := awslist.Stacks(ctx, cc.Client.ControlPlane.AWS.CloudFormation, i)
+		if err == nil && len(stacks) == 1 {
+			stackmetrics.ReportStack(key.ClusterID(&cr), key.StackTCCPF, stacks[0])
+		}
+
 		if IsNotExists(err) {
 			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane finalizer cloud formation stack")
 			err = r.createStack(ctx, cr)
@@ -56,21 +62,21 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		} else if err != nil {
 			return microerror.Mask(err)
 
-		} else if len(o.Stacks) != 1 {
-			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		} else if len(stacks) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(stacks))
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
-			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
-			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
-			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
+			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
+			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
+			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateInProgress {
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateInProgress {
 			r.logger.Debugf(ctx, "the tenant cluster's control plane finalizer cloud formation stack has stack status %#q", cloudformation.StackStatusCreateInProgress)
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateInProgress {
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateInProgress {
 			r.logger.Debugf(ctx, "the tenant cluster's control plane finalizer cloud formation stack has stack status %#q", cloudformation.StackStatusUpdateInProgress)
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
@@ -122,13 +128,18 @@ func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AW
 	{
 		r.logger.Debugf(ctx, "requesting the creation of the tenant cluster's control plane finalizer cloud formation stack")
 
+		tags, err := r.getCloudFormationTags(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
 		i := &cloudformation.CreateStackInput{
 			Capabilities: []*string{
 				aws.String(capabilityNamesIAM),
 			},
 			EnableTerminationProtection: aws.Bool(true),
 			StackName:                   aws.String(key.StackNameTCCPF(&cr)),
-			Tags:                        r.getCloudFormationTags(cr),
+			Tags:                        tags,
 			TemplateBody:                aws.String(templateBody),
 		}
 
@@ -158,10 +169,13 @@ func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AW
 	return nil
 }
 
-func (r *Resource) getCloudFormationTags(cr infrastructurev1alpha3.AWSCluster) []*cloudformation.Tag {
-	tags := key.AWSTags(&cr, r.installationName)
+func (r *Resource) getCloudFormationTags(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) ([]*cloudformation.Tag, error) {
+	tags, err := r.cloudTags.ClusterTags(ctx, &cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 	tags[key.TagStack] = key.StackTCCPF
-	return awstags.NewCloudFormation(tags)
+	return awstags.NewCloudFormation(tags), nil
 }
 
 func (r *Resource) newRecordSetsParams(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (*template.ParamsMainRecordSets, error) {
@@ -175,6 +189,12 @@ func (r *Resource) newRecordSetsParams(ctx context.Context, cr infrastructurev1a
 		return nil, microerror.Mask(err)
 	}
 
+	// Private clusters are neither delegated to from the control plane's public
+	// hosted zone nor resolved via the control plane's internal hosted zone. The
+	// control plane resolves their records via the cluster's internal hosted
+	// zone in case its VPC got associated with it.
+	route53Enabled := r.route53Enabled && !cc.Status.TenantCluster.TCCP.PrivateCluster
+
 	var recordSets *template.ParamsMainRecordSets
 	{
 		recordSets = &template.ParamsMainRecordSets{
@@ -184,7 +204,7 @@ func (r *Resource) newRecordSetsParams(ctx context.Context, cr infrastructurev1a
 			ControlPlaneHostedZoneID:         external,
 			TenantAPIPublicLoadBalancer:      cc.Status.TenantCluster.DNS.APIPublicLoadBalancer,
 			TenantHostedZoneNameServers:      cc.Status.TenantCluster.DNS.HostedZoneNameServers,
-			Route53Enabled:                   r.route53Enabled,
+			Route53Enabled:                   route53Enabled,
 		}
 	}
 
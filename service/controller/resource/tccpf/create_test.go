@@ -14,9 +14,12 @@ import (
 	"github.com/giantswarm/micrologger/microloggertest"
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpf/template"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cphostedzone"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
 )
@@ -50,6 +53,12 @@ func Test_Controller_Resource_TCCPF_Template_Render(t *testing.T) {
 			cr:             unittest.DefaultCluster(),
 			route53Enabled: false,
 		},
+		{
+			name:           "case 2: private cluster",
+			ctx:            withPrivateCluster(unittest.DefaultContext()),
+			cr:             unittest.DefaultCluster(),
+			route53Enabled: true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -58,18 +67,6 @@ func Test_Controller_Resource_TCCPF_Template_Render(t *testing.T) {
 
 			k := unittest.FakeK8sClient()
 
-			var d *changedetection.TCCPF
-			{
-				c := changedetection.TCCPFConfig{
-					Logger: microloggertest.New(),
-				}
-
-				d, err = changedetection.NewTCCPF(c)
-				if err != nil {
-					t.Fatal(err)
-				}
-			}
-
 			var e recorder.Interface
 			{
 				c := recorder.Config{
@@ -81,12 +78,25 @@ func Test_Controller_Resource_TCCPF_Template_Render(t *testing.T) {
 				e = recorder.New(c)
 			}
 
+			var d *changedetection.TCCPF
+			{
+				c := changedetection.TCCPFConfig{
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+				}
+
+				d, err = changedetection.NewTCCPF(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			var h *cphostedzone.HostedZone
 			{
 				c := cphostedzone.Config{
+					DNS:    dns.NewFakeDNS(dns.FakeDNSConfig{Provider: dns.NewFakeProvider()}),
 					Logger: microloggertest.New(),
-
-					Route53Enabled: false,
 				}
 
 				h, err = cphostedzone.New(c)
@@ -98,13 +108,13 @@ func Test_Controller_Resource_TCCPF_Template_Render(t *testing.T) {
 			var r *Resource
 			{
 				c := Config{
+					CloudTags:  &cloudtags.Mock{},
 					Detection:  d,
 					Event:      e,
 					HostedZone: h,
 					Logger:     microloggertest.New(),
 
-					InstallationName: "dummy",
-					Route53Enabled:   tc.route53Enabled,
+					Route53Enabled: tc.route53Enabled,
 				}
 
 				r, err = New(c)
@@ -149,3 +159,14 @@ func Test_Controller_Resource_TCCPF_Template_Render(t *testing.T) {
 		})
 	}
 }
+
+func withPrivateCluster(ctx context.Context) context.Context {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cc.Status.TenantCluster.TCCP.PrivateCluster = true
+
+	return ctx
+}
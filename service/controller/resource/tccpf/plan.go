@@ -0,0 +1,31 @@
+package tccpf
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpf/template"
+)
+
+// Plan renders the template of the tenant cluster's control plane finalizer
+// cloud formation stack without creating or updating the stack.
+func (r *Resource) Plan(ctx context.Context, obj interface{}) (string, error) {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	params, err := r.newTemplateParams(ctx, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	templateBody, err := template.Render(params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return templateBody, nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cphostedzone"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 )
@@ -15,29 +16,32 @@ const (
 )
 
 type Config struct {
+	CloudTags  cloudtags.Interface
 	Detection  *changedetection.TCCPF
 	Event      recorder.Interface
 	HostedZone *cphostedzone.HostedZone
 	Logger     micrologger.Logger
 
-	InstallationName string
-	Route53Enabled   bool
+	Route53Enabled bool
 }
 
 // Resource implements the TCCPF resource, which stands for Tenant Cluster
 // Control Plane Finalizer. This was formerly known as the host main stack. We
 // manage a dedicated CF stack for the record sets and routing tables setup.
 type Resource struct {
+	cloudTags  cloudtags.Interface
 	detection  *changedetection.TCCPF
 	event      recorder.Interface
 	hostedZone *cphostedzone.HostedZone
 	logger     micrologger.Logger
 
-	installationName string
-	route53Enabled   bool
+	route53Enabled bool
 }
 
 func New(config Config) (*Resource, error) {
+	if config.CloudTags == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
+	}
 	if config.Detection == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Detection must not be empty", config)
 	}
@@ -52,13 +56,13 @@ func New(config Config) (*Resource, error) {
 	}
 
 	r := &Resource{
+		cloudTags:  config.CloudTags,
 		detection:  config.Detection,
 		event:      config.Event,
 		hostedZone: config.HostedZone,
 		logger:     config.Logger,
 
-		installationName: config.InstallationName,
-		route53Enabled:   config.Route53Enabled,
+		route53Enabled: config.Route53Enabled,
 	}
 
 	return r, nil
@@ -11,6 +11,8 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpi/template"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 const (
@@ -34,22 +36,26 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			StackName: aws.String(key.StackNameTCCPI(&cr)),
 		}
 
-		o, err := cc.Client.ControlPlane.AWS.CloudFormation.DescribeStacks(i)
+		stacks, err := awslist.Stacks(ctx, cc.Client.ControlPlane.AWS.CloudFormation, i)
+		if err == nil && len(stacks) == 1 {
+			stackmetrics.ReportStack(key.ClusterID(&cr), key.StackTCCPI, stacks[0])
+		}
+
 		if IsNotExists(err) {
 			// fall through
 
 		} else if err != nil {
 			return microerror.Mask(err)
 
-		} else if len(o.Stacks) != 1 {
-			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		} else if len(stacks) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(stacks))
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
-			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
-			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
-			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
+			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
+			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
+			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
 
 		} else {
 			r.logger.Debugf(ctx, "found the tenant cluster's control plane initializer cloud formation stack already exists")
@@ -81,13 +87,18 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	{
 		r.logger.Debugf(ctx, "requesting the creation of the tenant cluster's control plane initializer cloud formation stack")
 
+		tags, err := r.getCloudFormationTags(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
 		i := &cloudformation.CreateStackInput{
 			Capabilities: []*string{
 				aws.String(capabilityNamesIAM),
 			},
 			EnableTerminationProtection: aws.Bool(true),
 			StackName:                   aws.String(key.StackNameTCCPI(&cr)),
-			Tags:                        r.getCloudFormationTags(cr),
+			Tags:                        tags,
 			TemplateBody:                aws.String(templateBody),
 		}
 
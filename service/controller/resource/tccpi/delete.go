@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -55,6 +56,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 
 		} else if IsNotExists(err) {
 			r.logger.Debugf(ctx, "the tenant cluster's control plane initializer cloud formation stack does not exist")
+			stackmetrics.DeleteStack(key.StackNameTCCPI(&cr))
 			r.logger.Debugf(ctx, "canceling resource")
 
 			return nil
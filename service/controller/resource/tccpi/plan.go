@@ -0,0 +1,31 @@
+package tccpi
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpi/template"
+)
+
+// Plan renders the template of the tenant cluster's control plane initializer
+// cloud formation stack without creating or updating the stack.
+func (r *Resource) Plan(ctx context.Context, obj interface{}) (string, error) {
+	cr, err := key.ToCluster(ctx, obj)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	params, err := newTemplateParams(ctx, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	templateBody, err := template.Render(params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return templateBody, nil
+}
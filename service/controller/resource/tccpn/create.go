@@ -20,8 +20,10 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpn/template"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter/kms"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 const (
@@ -97,7 +99,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			StackName: aws.String(key.StackNameTCCPN(&cr)),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeStacks(i)
+		stacks, err := awslist.Stacks(ctx, cc.Client.TenantCluster.AWS.CloudFormation, i)
+		if err == nil && len(stacks) == 1 {
+			stackmetrics.ReportStack(key.ClusterID(&cr), key.StackTCCPN, stacks[0])
+		}
+
 		if IsNotExists(err) {
 			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane nodes cloud formation stack")
 			err = r.createStack(ctx, cr)
@@ -120,23 +126,23 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		} else if err != nil {
 			return microerror.Mask(err)
 
-		} else if len(o.Stacks) != 1 {
-			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		} else if len(stacks) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(stacks))
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
-			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
-			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
-			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
+			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
+			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
+			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
 
-		} else if key.StackInProgress(*o.Stacks[0].StackStatus) {
-			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus)
-			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackInProgress(*stacks[0].StackStatus) {
+			r.logger.Debugf(ctx, "the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *stacks[0].StackStatus)
+			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
-		} else if key.StackComplete(*o.Stacks[0].StackStatus) {
-			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackComplete(*stacks[0].StackStatus) {
+			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane nodes cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 		}
 
 		r.logger.Debugf(ctx, "found the tenant cluster's control plane nodes cloud formation stack already exists")
@@ -163,12 +169,42 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			} else if err != nil {
 				return microerror.Mask(err)
 			}
+		} else {
+			// In case there is nothing to update anymore, a change set created
+			// in preview mode is not needed and gets removed.
+			err = r.changeSet.Cleanup(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCCPN(&cr))
+			if err != nil {
+				return microerror.Mask(err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// changeSetPreviewEnabled returns true when stack updates should be previewed
+// via change sets, either because the AWSControlPlane CR or the AWSCluster CR
+// of the tenant cluster is annotated accordingly.
+func (r *Resource) changeSetPreviewEnabled(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) (bool, error) {
+	if key.ChangeSetPreviewEnabled(&cr) {
+		return true, nil
+	}
+
+	var cl infrastructurev1alpha3.AWSCluster
+	{
+		err := r.k8sClient.CtrlClient().Get(
+			ctx,
+			types.NamespacedName{Name: key.ClusterID(&cr), Namespace: cr.Namespace},
+			&cl,
+		)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+	}
+
+	return key.ChangeSetPreviewEnabled(&cl), nil
+}
+
 func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
@@ -223,19 +259,14 @@ func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AW
 }
 
 func (r *Resource) getCloudFormationTags(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) ([]*cloudformation.Tag, error) {
-	tags := key.AWSTags(&cr, r.installationName)
-	if cp := key.ControlPlaneID(&cr); cp != "" {
-		tags[key.TagControlPlane] = cp
-	}
-	tags[key.TagStack] = key.StackTCCPN
-
-	cloudtags, err := r.cloudTags.GetTagsByCluster(ctx, key.ClusterID(&cr))
+	tags, err := r.cloudTags.ClusterTags(ctx, &cr)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	for k, v := range cloudtags {
-		tags[k] = v
+	if cp := key.ControlPlaneID(&cr); cp != "" {
+		tags[key.TagControlPlane] = cp
 	}
+	tags[key.TagStack] = key.StackTCCPN
 
 	return awstags.NewCloudFormation(tags), nil
 }
@@ -280,11 +311,33 @@ func (r *Resource) updateStack(ctx context.Context, cr infrastructurev1alpha3.AW
 			TemplateBody: aws.String(templateBody),
 		}
 
-		_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
+		preview, err := r.changeSetPreviewEnabled(ctx, cr)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
+		if preview {
+			approved, err := r.changeSet.Ensure(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			if !approved {
+				r.logger.Debugf(ctx, "not updating the tenant cluster's control plane nodes cloud formation stack due to change set not being approved")
+				return nil
+			}
+
+			err = r.changeSet.Execute(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCCPN(&cr))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		} else {
+			_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
 		r.logger.Debugf(ctx, "requested the update of the tenant cluster's control plane nodes cloud formation stack")
 		r.event.Emit(ctx, &cr, "CFUpdateRequested", "requested the update of the tenant cluster's control plane nodes cloud formation stack")
 	}
@@ -313,10 +366,40 @@ func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1a
 		}
 	}
 
+	// In case the control plane nodes are attached to the NLBs of the TCCP
+	// stack, they get registered with their target groups instead of the
+	// classic ELBs.
+	//
+	// Private clusters do not have a public API load balancer the control plane
+	// nodes could be attached to.
+	private := cc.Status.TenantCluster.TCCP.PrivateCluster
+
+	var targetGroupARNs []string
+	if desiredLoadBalancerType(cc) == key.LoadBalancerTypeNetwork {
+		targetGroupARNs = append(targetGroupARNs, cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.APIInternal)
+		if !private {
+			targetGroupARNs = append(targetGroupARNs, cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.API)
+		}
+		targetGroupARNs = append(targetGroupARNs, cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.Etcd)
+	}
+
+	apiName := key.ELBNameAPI(&cr)
+	if private {
+		apiName = ""
+	}
+
+	// While the etcd volumes get restored, only the first masters of the
+	// mapping are running, so that the control plane nodes are brought back one
+	// at a time.
+	running, restoring, err := key.ControlPlaneEtcdRestoreRunning(cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	autoScalingGroup := &template.ParamsMainAutoScalingGroup{
 		HAMasters: haMastersEnabled,
 	}
-	for _, m := range mappings {
+	for i, m := range mappings {
 		dependsOn := []string{key.ControlPlaneENIResourceName(m.ID), key.ControlPlaneVolumeResourceName(m.ID)}
 		// ASG for second and third master will have chain dependency on the previous one
 		// to have rolling update of one ASG after the previous one.
@@ -332,13 +415,19 @@ func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1a
 			},
 			LoadBalancers: template.ParamsMainAutoScalingGroupItemLoadBalancers{
 				ApiInternalName: key.InternalELBNameAPI(&cr),
-				ApiName:         key.ELBNameAPI(&cr),
+				ApiName:         apiName,
 				EtcdName:        key.ELBNameEtcd(&cr),
+				TargetGroupARNs: targetGroupARNs,
 			},
+			Replicas: 1,
 			Resource: key.ControlPlaneASGResourceName(&cr, m.ID),
 			SubnetID: idFromSubnets(cc.Status.TenantCluster.TCCP.Subnets, key.SanitizeCFResourceName(key.PrivateSubnetName(m.AZ))),
 		}
 
+		if restoring && i >= running {
+			item.Replicas = 0
+		}
+
 		autoScalingGroup.List = append(autoScalingGroup.List, item)
 	}
 
@@ -389,6 +478,13 @@ func (r *Resource) newEtcdVolume(ctx context.Context, cr infrastructurev1alpha3.
 		}
 	}
 
+	// Etcd volumes restored from a snapshot have to keep referencing it.
+	// Otherwise Cloud Formation would replace them on the next stack update.
+	restored, err := key.ControlPlaneEtcdRestoreSnapshotIDs(cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	etcdVolumes := &template.ParamsMainEtcdVolume{}
 	for _, m := range mappings {
 		item := template.ParamsMainEtcdVolumeItem{
@@ -400,6 +496,10 @@ func (r *Resource) newEtcdVolume(ctx context.Context, cr infrastructurev1alpha3.
 			Throughput:       key.ControlPlaneVolumeThroughput(cr),
 		}
 
+		if id, ok := restored[m.ID]; ok {
+			item.SnapshotID = id
+		}
+
 		etcdVolumes.List = append(etcdVolumes.List, item)
 	}
 
@@ -507,7 +607,7 @@ func (r *Resource) newLaunchTemplate(ctx context.Context, cr infrastructurev1alp
 
 	var ami string
 	{
-		ami, err = r.images.AMI(ctx, &cr, "")
+		ami, err = r.images.AMI(ctx, &cr, "", key.ArchitectureAMD64)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -575,11 +675,18 @@ func (r *Resource) newOutputs(ctx context.Context, cr infrastructurev1alpha3.AWS
 		}
 	}
 
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	outputs := &template.ParamsMainOutputs{
-		InstanceType:    key.ControlPlaneInstanceType(cr),
-		MasterReplicas:  rep,
-		OperatorVersion: key.OperatorVersion(&cr),
-		ReleaseVersion:  key.ReleaseVersion(&cr),
+		EtcdRestore:      key.ControlPlaneEtcdRestoreState(cr),
+		InstanceType:     key.ControlPlaneInstanceType(cr),
+		LoadBalancerType: desiredLoadBalancerType(cc),
+		MasterReplicas:   rep,
+		OperatorVersion:  key.OperatorVersion(&cr),
+		ReleaseVersion:   key.ReleaseVersion(&cr),
 	}
 
 	return outputs, nil
@@ -695,6 +802,13 @@ func (r *Resource) newTemplateParams(ctx context.Context, cr infrastructurev1alp
 	return params, nil
 }
 
+// desiredLoadBalancerType returns the load balancer type the control plane
+// nodes have to be attached to, based on the load balancers provided by the
+// TCCP stack.
+func desiredLoadBalancerType(cc *controllercontext.Context) string {
+	return key.LoadBalancerTypeTCCPN(cc.Status.TenantCluster.TCCP.LoadBalancers.Types, cc.Status.TenantCluster.TCCPN.LoadBalancerType)
+}
+
 func idFromGroups(groups []*ec2.SecurityGroup, name string) string {
 	for _, g := range groups {
 		if awstags.ValueForKey(g.Tags, "Name") == name {
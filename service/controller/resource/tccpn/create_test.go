@@ -14,9 +14,13 @@ import (
 	"github.com/giantswarm/micrologger/microloggertest"
 	"github.com/google/go-cmp/cmp"
 
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpn/template"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
@@ -38,13 +42,15 @@ var update = flag.Bool("update", false, "update .golden CF template file")
 //	go test ./service/controller/resource/tccpn -run Test_Controller_Resource_TCCPN_Template_Render -update
 func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 	testCases := []struct {
-		name           string
-		azs            []string
-		irsaAnnotation bool
-		replicas       int
-		releaseVersion string
-		route53Enabled bool
-		annotations    map[string]string
+		name              string
+		azs               []string
+		irsaAnnotation    bool
+		loadBalancerTypes []string
+		privateCluster    bool
+		replicas          int
+		releaseVersion    string
+		route53Enabled    bool
+		annotations       map[string]string
 	}{
 		{
 			name:           "case 0: basic test with encrypter backend KMS, route53 enabled",
@@ -75,6 +81,32 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 			route53Enabled: true,
 			annotations:    map[string]string{annotation.AWSEBSVolumeIops: "16000", annotation.AWSEBSVolumeThroughput: "1000"},
 		},
+		{
+			name:              "case 4: basic test with network load balancers",
+			azs:               []string{"eu-central-1b"},
+			loadBalancerTypes: []string{key.LoadBalancerTypeClassic, key.LoadBalancerTypeNetwork},
+			releaseVersion:    "18.0.0",
+			replicas:          1,
+			route53Enabled:    true,
+		},
+		{
+			name:           "case 5: basic test with ha masters restoring etcd volumes",
+			azs:            []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"},
+			replicas:       3,
+			route53Enabled: true,
+			annotations: map[string]string{
+				awsoperatorannotation.EtcdRestoreRunning:   "1",
+				awsoperatorannotation.EtcdRestoreSnapshots: "1=snap-1,2=snap-2,3=snap-3",
+			},
+		},
+		{
+			name:           "case 6: basic test with private cluster",
+			azs:            []string{"eu-central-1b"},
+			privateCluster: true,
+			releaseVersion: "18.0.0",
+			replicas:       1,
+			route53Enabled: true,
+		},
 	}
 
 	data := `{
@@ -133,22 +165,26 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 			ctx := unittest.DefaultContextControlPlane()
 			k := unittest.FakeK8sClient()
 
-			var ct cloudtags.Interface
-			{
-				c := cloudtags.Config{
-					K8sClient: k,
-					Logger:    microloggertest.New(),
+			if tc.loadBalancerTypes != nil {
+				cc, err := controllercontext.FromContext(ctx)
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				ct, err = cloudtags.New(c)
+				cc.Status.TenantCluster.TCCP.LoadBalancers.Types = tc.loadBalancerTypes
+				cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.API = "arn:aws:elasticloadbalancing:eu-central-1:tccp:targetgroup/8y5ck-api/1"
+				cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.APIInternal = "arn:aws:elasticloadbalancing:eu-central-1:tccp:targetgroup/8y5ck-api-internal/1"
+				cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.Etcd = "arn:aws:elasticloadbalancing:eu-central-1:tccp:targetgroup/8y5ck-etcd/1"
+				cc.Status.TenantCluster.TCCPN.LoadBalancerType = key.LoadBalancerTypeClassic
+			}
+
+			if tc.privateCluster {
+				cc, err := controllercontext.FromContext(ctx)
 				if err != nil {
 					t.Fatal(err)
 				}
-			}
 
-			var m encrypter.Interface
-			{
-				m = &encrypter.Mock{}
+				cc.Status.TenantCluster.TCCP.PrivateCluster = true
 			}
 
 			var e recorder.Interface
@@ -162,6 +198,27 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 				e = recorder.New(c)
 			}
 
+			var ct cloudtags.Interface
+			{
+				c := cloudtags.Config{
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+
+					InstallationName: "dummy",
+				}
+
+				ct, err = cloudtags.New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var m encrypter.Interface
+			{
+				m = &encrypter.Mock{}
+			}
+
 			var h hamaster.Interface
 			{
 				c := hamaster.Config{
@@ -186,13 +243,28 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 				}
 			}
 
+			var cs changeset.Interface
+			{
+				c := changeset.Config{
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+				}
+
+				cs, err = changeset.New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			var d *changedetection.TCCPN
 			{
 				c := changedetection.TCCPNConfig{
-					Event:    e,
-					HAMaster: h,
-					Logger:   microloggertest.New(),
-					Releases: rel,
+					Event:     e,
+					HAMaster:  h,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+					Releases:  rel,
 				}
 
 				d, err = changedetection.NewTCCPN(c)
@@ -264,6 +336,7 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 			var r *Resource
 			{
 				c := Config{
+					ChangeSet: cs,
 					CloudTags: ct,
 					Encrypter: m,
 					Event:     e,
@@ -273,8 +346,7 @@ func Test_Controller_Resource_TCCPN_Template_Render(t *testing.T) {
 					Images:    i,
 					Logger:    microloggertest.New(),
 
-					InstallationName: "dummy",
-					Route53Enabled:   tc.route53Enabled,
+					Route53Enabled: tc.route53Enabled,
 				}
 
 				r, err = New(c)
@@ -0,0 +1,32 @@
+package tccpn
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpn/template"
+)
+
+// Plan renders the template of the tenant cluster's control plane nodes cloud
+// formation stack without creating or updating the stack. The template is
+// rendered the way it is for stack creation.
+func (r *Resource) Plan(ctx context.Context, obj interface{}) (string, error) {
+	cr, err := key.ToControlPlane(obj)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	params, err := r.newTemplateParams(ctx, cr, true)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	templateBody, err := template.Render(params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return templateBody, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
@@ -19,6 +20,7 @@ const (
 )
 
 type Config struct {
+	ChangeSet changeset.Interface
 	CloudTags cloudtags.Interface
 	Detection *changedetection.TCCPN
 	Encrypter encrypter.Interface
@@ -28,14 +30,14 @@ type Config struct {
 	K8sClient k8sclient.Interface
 	Logger    micrologger.Logger
 
-	InstallationName string
-	Route53Enabled   bool
+	Route53Enabled bool
 }
 
 // Resource implements the TCCPN resource, which stands for Tenant Cluster
 // Control Plane Node. We manage a dedicated Cloud Formation stack for each node
 // pool.
 type Resource struct {
+	changeSet changeset.Interface
 	cloudTags cloudtags.Interface
 	detection *changedetection.TCCPN
 	encrypter encrypter.Interface
@@ -45,11 +47,13 @@ type Resource struct {
 	k8sClient k8sclient.Interface
 	logger    micrologger.Logger
 
-	installationName string
-	route53Enabled   bool
+	route53Enabled bool
 }
 
 func New(config Config) (*Resource, error) {
+	if config.ChangeSet == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ChangeSet must not be empty", config)
+	}
 	if config.CloudTags == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
 	}
@@ -75,11 +79,8 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
-	if config.InstallationName == "" {
-		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", config)
-	}
-
 	r := &Resource{
+		changeSet: config.ChangeSet,
 		cloudTags: config.CloudTags,
 		detection: config.Detection,
 		encrypter: config.Encrypter,
@@ -89,8 +90,7 @@ func New(config Config) (*Resource, error) {
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
 
-		installationName: config.InstallationName,
-		route53Enabled:   config.Route53Enabled,
+		route53Enabled: config.Route53Enabled,
 	}
 
 	return r, nil
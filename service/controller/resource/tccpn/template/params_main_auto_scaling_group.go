@@ -11,8 +11,11 @@ type ParamsMainAutoScalingGroupItem struct {
 	DependsOn        []string
 	LaunchTemplate   ParamsMainAutoScalingGroupItemLaunchTemplate
 	LoadBalancers    ParamsMainAutoScalingGroupItemLoadBalancers
-	Resource         string
-	SubnetID         string
+	// Replicas is the number of control plane nodes of the ASG. It is only 0
+	// while the master is stopped for restoring its etcd volume.
+	Replicas int
+	Resource string
+	SubnetID string
 }
 
 type ParamsMainAutoScalingGroupItemLaunchTemplate struct {
@@ -21,6 +24,12 @@ type ParamsMainAutoScalingGroupItemLaunchTemplate struct {
 
 type ParamsMainAutoScalingGroupItemLoadBalancers struct {
 	ApiInternalName string
-	ApiName         string
-	EtcdName        string
+	// ApiName is the name of the public API ELB. It is empty for private
+	// clusters, which do not have a public API load balancer.
+	ApiName  string
+	EtcdName string
+	// TargetGroupARNs are the NLB target groups the control plane nodes get
+	// registered with. The classic ELBs are used in case no target group is
+	// given.
+	TargetGroupARNs []string
 }
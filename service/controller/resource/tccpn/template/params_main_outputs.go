@@ -1,8 +1,13 @@
 package template
 
 type ParamsMainOutputs struct {
-	InstanceType    string
-	MasterReplicas  int
-	OperatorVersion string
-	ReleaseVersion  string
+	// EtcdRestore is the restore state of the etcd volumes as computed by
+	// key.ControlPlaneEtcdRestoreState. The output is omitted in case the etcd
+	// volumes were never restored.
+	EtcdRestore      string
+	InstanceType     string
+	LoadBalancerType string
+	MasterReplicas   int
+	OperatorVersion  string
+	ReleaseVersion   string
 }
@@ -15,18 +15,27 @@ const TemplateMainAutoScalingGroup = `
         - {{ .SubnetID }}
       AvailabilityZones:
         - {{ .AvailabilityZone }}
-      DesiredCapacity: 1
-      MinSize: 1
-      MaxSize: 1
+      DesiredCapacity: {{ .Replicas }}
+      MinSize: {{ .Replicas }}
+      MaxSize: {{ .Replicas }}
       MixedInstancesPolicy:
         LaunchTemplate:
           LaunchTemplateSpecification:
             LaunchTemplateId: !Ref {{ .LaunchTemplate.Resource }}
             Version: !GetAtt {{ .LaunchTemplate.Resource }}.LatestVersionNumber
+      {{- if .LoadBalancers.TargetGroupARNs }}
+      TargetGroupARNs:
+      {{- range .LoadBalancers.TargetGroupARNs }}
+      - {{ . }}
+      {{- end }}
+      {{- else }}
       LoadBalancerNames:
       - {{ .LoadBalancers.ApiInternalName }}
+      {{- if .LoadBalancers.ApiName }}
       - {{ .LoadBalancers.ApiName }}
+      {{- end }}
       - {{ .LoadBalancers.EtcdName }}
+      {{- end }}
 
       {{- if $HAMasters }}
       # We define lifecycle hook only in case of HA masters. In case of 1 masters
@@ -2,8 +2,14 @@ package template
 
 const TemplateMainOutputs = `
 {{- define "outputs" -}}
+  {{ if .Outputs.EtcdRestore -}}
+  EtcdRestore:
+    Value: "{{ .Outputs.EtcdRestore }}"
+  {{ end -}}
   InstanceType:
     Value: {{ .Outputs.InstanceType }}
+  LoadBalancerType:
+    Value: {{ .Outputs.LoadBalancerType }}
   MasterReplicas:
     Value: {{ .Outputs.MasterReplicas }}
   OperatorVersion:
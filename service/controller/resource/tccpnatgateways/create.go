@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -55,13 +56,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				},
 			},
 		}
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeNatGateways(i)
+		natgateways, err = awslist.NatGateways(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		natgateways = o.NatGateways
-
 		r.logger.Debugf(ctx, "found natgateways")
 	}
 
@@ -12,10 +12,12 @@ import (
 )
 
 const (
-	InstanceTypeKey    = "InstanceType"
-	OperatorVersionKey = "OperatorVersion"
-	MasterReplicasKey  = "MasterReplicas"
-	ReleaseVersionKey  = "ReleaseVersion"
+	EtcdRestoreKey      = "EtcdRestore"
+	InstanceTypeKey     = "InstanceType"
+	LoadBalancerTypeKey = "LoadBalancerType"
+	OperatorVersionKey  = "OperatorVersion"
+	MasterReplicasKey   = "MasterReplicas"
+	ReleaseVersionKey   = "ReleaseVersion"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -67,6 +69,17 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		r.logger.Debugf(ctx, "found the tenant cluster's control plane nodes cloud formation stack outputs")
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, EtcdRestoreKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// The output is only rendered once the etcd volumes got restored.
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCPN.EtcdRestore = v
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, InstanceTypeKey)
 		if err != nil {
@@ -75,6 +88,19 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.TCCPN.InstanceType = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, LoadBalancerTypeKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCCPN stacks created before NLB support do not have the output and
+			// are always attached to the classic ELBs.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane nodes LoadBalancerType output")
+			v = key.LoadBalancerTypeClassic
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCPN.LoadBalancerType = v
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, OperatorVersionKey)
 		if err != nil {
@@ -2,6 +2,7 @@ package tccpoutputs
 
 import (
 	"context"
+	"strings"
 
 	"github.com/giantswarm/microerror"
 
@@ -11,13 +12,25 @@ import (
 )
 
 const (
-	APIServerPublicLoadBalancerKey = "APIServerPublicLoadBalancer"
-	HostedZoneID                   = "HostedZoneID"
-	HostedZoneNameServersKey       = "HostedZoneNameServers"
-	InternalHostedZoneID           = "InternalHostedZoneID"
-	OperatorVersion                = "OperatorVersion"
-	VPCIDKey                       = "VPCID"
-	VPCPeeringConnectionIDKey      = "VPCPeeringConnectionID"
+	APIInternalTargetGroupARNKey     = "APIInternalTargetGroupARN"
+	APIServerInternalLoadBalancerKey = "APIServerInternalLoadBalancer"
+	APIServerPublicLoadBalancerKey   = "APIServerPublicLoadBalancer"
+	APIWhitelistHashKey              = "APIWhitelistHash"
+	APITargetGroupARNKey             = "APITargetGroupARN"
+	EgressOnlyInternetGatewayIDKey   = "EgressOnlyInternetGatewayID"
+	EtcdLoadBalancerKey              = "EtcdLoadBalancer"
+	EtcdTargetGroupARNKey            = "EtcdTargetGroupARN"
+	HostedZoneID                     = "HostedZoneID"
+	HostedZoneNameServersKey         = "HostedZoneNameServers"
+	InternalHostedZoneID             = "InternalHostedZoneID"
+	InterruptionHandlingKey          = "InterruptionHandling"
+	LoadBalancerTypesKey             = "LoadBalancerTypes"
+	OperatorVersion                  = "OperatorVersion"
+	PrivateClusterKey                = "PrivateCluster"
+	VPCEndpointsKey                  = "VPCEndpoints"
+	VPCIDKey                         = "VPCID"
+	VPCIPv6CIDRKey                   = "VPCIPv6CIDR"
+	VPCPeeringConnectionIDKey        = "VPCPeeringConnectionID"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -51,6 +64,10 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane cloud formation stack outputs")
 			r.logger.Debugf(ctx, "the tenant cluster's control plane cloud formation stack does not exist")
 			r.logger.Debugf(ctx, "canceling resource")
+			// The private cluster mode can only be chosen on cluster creation.
+			// Existing stacks keep the mode they got created with, see the
+			// PrivateCluster output below.
+			cc.Status.TenantCluster.TCCP.PrivateCluster = key.PrivateClusterEnabled(cr)
 			return nil
 
 		} else if cloudformation.IsOutputsNotAccessible(err) {
@@ -69,37 +86,78 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		r.logger.Debugf(ctx, "found the tenant cluster's control plane cloud formation stack outputs")
 	}
 
-	if r.route53Enabled {
-		{
-			v, err := cloudFormation.GetOutputValue(outputs, APIServerPublicLoadBalancerKey)
-			// migration code to dont throw error  when the old CF Stack dont yet have the new output value
-			// TODO https://github.com/giantswarm/giantswarm/issues/13851
-			// Related: https://github.com/giantswarm/giantswarm/issues/10139
-			// after migration we can remove the check for IsOutputNotFound
-			if cloudformation.IsOutputNotFound(err) {
-				r.logger.Debugf(ctx, "did not find the tenant cluster's control plane APIServerPublicLoadBalancer output")
-			} else {
-				if err != nil {
-					return microerror.Mask(err)
-				}
-				cc.Status.TenantCluster.DNS.APIPublicLoadBalancer = v
-			}
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, PrivateClusterKey)
+		// The output only exists in case the TCCP stack is private.
+		if cloudformation.IsOutputNotFound(err) {
+			v = "false"
+		} else if err != nil {
+			return microerror.Mask(err)
 		}
+		cc.Status.TenantCluster.TCCP.PrivateCluster = v == "true"
+	}
 
-		{
-			v, err := cloudFormation.GetOutputValue(outputs, HostedZoneID)
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, APIServerPublicLoadBalancerKey)
+		// migration code to dont throw error  when the old CF Stack dont yet have the new output value
+		// TODO https://github.com/giantswarm/giantswarm/issues/13851
+		// Related: https://github.com/giantswarm/giantswarm/issues/10139
+		// after migration we can remove the check for IsOutputNotFound
+		//
+		// The output also does not exist in case Route53 and the load balancer
+		// DNS names are disabled or the cluster is private.
+		if cloudformation.IsOutputNotFound(err) {
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane APIServerPublicLoadBalancer output")
+		} else {
 			if err != nil {
 				return microerror.Mask(err)
 			}
-			cc.Status.TenantCluster.DNS.HostedZoneID = v
+			cc.Status.TenantCluster.DNS.APIPublicLoadBalancer = v
 		}
+	}
 
-		{
-			v, err := cloudFormation.GetOutputValue(outputs, HostedZoneNameServersKey)
-			if err != nil {
-				return microerror.Mask(err)
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, APIServerInternalLoadBalancerKey)
+		// The output only exists in case the load balancer DNS names are
+		// exposed for DNS providers other than Route53.
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.DNS.APIInternalLoadBalancer = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, EtcdLoadBalancerKey)
+		// The output only exists in case the load balancer DNS names are
+		// exposed for DNS providers other than Route53.
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.DNS.EtcdLoadBalancer = v
+	}
+
+	if r.route53Enabled {
+		// Private clusters do not have a public hosted zone.
+		if !cc.Status.TenantCluster.TCCP.PrivateCluster {
+			{
+				v, err := cloudFormation.GetOutputValue(outputs, HostedZoneID)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+				cc.Status.TenantCluster.DNS.HostedZoneID = v
+			}
+
+			{
+				v, err := cloudFormation.GetOutputValue(outputs, HostedZoneNameServersKey)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+				cc.Status.TenantCluster.DNS.HostedZoneNameServers = v
 			}
-			cc.Status.TenantCluster.DNS.HostedZoneNameServers = v
 		}
 
 		{
@@ -119,6 +177,90 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, APIWhitelistHashKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCCP stacks of clusters only using the installation wide API
+			// whitelist do not have the output.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane APIWhitelistHash output")
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.APIWhitelistHash = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, LoadBalancerTypesKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCCP stacks created before NLB support do not have the output and
+			// only provide classic ELBs.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane LoadBalancerTypes output")
+			v = key.LoadBalancerTypeClassic
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.LoadBalancers.Types = strings.Split(v, ",")
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, APIInternalTargetGroupARNKey)
+		// The target group outputs only exist in case the TCCP stack provides
+		// NLBs.
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.APIInternal = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, APITargetGroupARNKey)
+		// Private clusters do not have the public API NLB and its target group.
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.API = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, EtcdTargetGroupARNKey)
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.LoadBalancers.TargetGroupARNs.Etcd = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, EgressOnlyInternetGatewayIDKey)
+		// The IPv6 networking outputs only exist in case the TCCP stack is
+		// dual-stack.
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.VPC.EgressOnlyInternetGatewayID = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, InterruptionHandlingKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCCP stacks created before the interruption handling was introduced
+			// do not have the output and do not provide the interruption queue.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane InterruptionHandling output")
+			v = "false"
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.InterruptionHandling = v == "true"
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, OperatorVersion)
 		if err != nil {
@@ -127,6 +269,19 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.OperatorVersion = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, VPCEndpointsKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCCP stacks created before the interface VPC endpoints were
+			// introduced do not have the output and do not provide them.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's control plane VPCEndpoints output")
+			v = "false"
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.VPCEndpoints = v == "true"
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, VPCIDKey)
 		if err != nil {
@@ -135,6 +290,16 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.TCCP.VPC.ID = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, VPCIPv6CIDRKey)
+		if cloudformation.IsOutputNotFound(err) {
+			v = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR = v
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, VPCPeeringConnectionIDKey)
 		if err != nil {
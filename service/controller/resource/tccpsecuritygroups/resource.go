@@ -14,6 +14,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -97,13 +98,11 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		groups, err = awslist.SecurityGroups(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		groups = o.SecurityGroups
-
 		expectedLength := 2
 		if enableAWSCNI {
 			expectedLength = 3
@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -72,12 +73,12 @@ func (r *Resource) addSubnetsToContext(ctx context.Context) error {
 			},
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSubnets(i)
+		subnets, err := awslist.Subnets(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		cc.Status.TenantCluster.TCCP.Subnets = o.Subnets
+		cc.Status.TenantCluster.TCCP.Subnets = subnets
 	}
 
 	return nil
@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -47,13 +48,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				},
 			},
 		}
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeVpcs(i)
+		vpcs, err = awslist.Vpcs(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		vpcs = o.Vpcs
-
 		r.logger.Debugf(ctx, "found vpcs")
 	}
 
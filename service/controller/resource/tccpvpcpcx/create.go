@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -62,13 +63,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				},
 			},
 		}
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeVpcPeeringConnections(i)
+		vpcPCXs, err = awslist.VpcPeeringConnections(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		vpcPCXs = o.VpcPeeringConnections
-
 		r.logger.Debugf(ctx, "found vpc peering connections")
 	}
 
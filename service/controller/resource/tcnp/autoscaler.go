@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 func (r *Resource) getASGName(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (string, error) {
@@ -40,20 +41,19 @@ func (r *Resource) getASGName(ctx context.Context, cr infrastructurev1alpha3.AWS
 				},
 			},
 		},
-		MaxRecords: aws.Int64(2),
 	}
 
 	// get ASG name
-	asgs, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(&find)
+	asgs, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, &find)
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
 
-	if len(asgs.AutoScalingGroups) != 1 {
-		return "", microerror.Maskf(asgLookupError, "Expected to find exactly 1 ASG, got %d", len(asgs.AutoScalingGroups))
+	if len(asgs) != 1 {
+		return "", microerror.Maskf(asgLookupError, "Expected to find exactly 1 ASG, got %d", len(asgs))
 	}
 
-	return *asgs.AutoScalingGroups[0].AutoScalingGroupName, nil
+	return *asgs[0].AutoScalingGroupName, nil
 }
 
 func (r *Resource) ensureAutoscalerTag(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) error {
@@ -3,6 +3,8 @@ package tcnp
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -14,14 +16,18 @@ import (
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpnoutputs"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 	cloudformationutils "github.com/giantswarm/aws-operator/v16/service/internal/cloudformation"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter/kms"
+	"github.com/giantswarm/aws-operator/v16/service/internal/instancetypes"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 const (
@@ -82,6 +88,15 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
 		}
+
+		// The IPv6 networking information of the tenant cluster's VPC is only
+		// available as long as the TCCP stack is not transitioning. Rendering
+		// the TCNP stack without it would remove the node pool's IPv6 setup.
+		if cc.Status.TenantCluster.TCCP.IsTransitioning {
+			r.logger.Debugf(ctx, "control plane cloud formation stack outputs not available yet")
+			r.logger.Debugf(ctx, "canceling resource")
+			return nil
+		}
 	}
 
 	{
@@ -91,7 +106,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			StackName: aws.String(key.StackNameTCNP(&cr)),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeStacks(i)
+		stacks, err := awslist.Stacks(ctx, cc.Client.TenantCluster.AWS.CloudFormation, i)
+		if err == nil && len(stacks) == 1 {
+			stackmetrics.ReportStack(key.ClusterID(&cr), key.StackTCNP, stacks[0])
+		}
+
 		if IsNotExists(err) {
 			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool cloud formation stack")
 
@@ -105,23 +124,23 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		} else if err != nil {
 			return microerror.Mask(err)
 
-		} else if len(o.Stacks) != 1 {
-			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		} else if len(stacks) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one stack, got %d", len(stacks))
 
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
-			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
-			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
-		} else if *o.Stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
-			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *o.Stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusCreateFailed {
+			return microerror.Maskf(eventCFCreateError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusRollbackFailed {
+			return microerror.Maskf(eventCFRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
+		} else if *stacks[0].StackStatus == cloudformation.StackStatusUpdateRollbackFailed {
+			return microerror.Maskf(eventCFUpdateRollbackError, "expected successful status, got %#q", *stacks[0].StackStatus)
 
-		} else if key.StackInProgress(*o.Stacks[0].StackStatus) {
-			r.logger.Debugf(ctx, "the tenant cluster's node pool cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus)
-			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's node pool cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackInProgress(*stacks[0].StackStatus) {
+			r.logger.Debugf(ctx, "the tenant cluster's node pool cloud formation stack has stack status %#q", *stacks[0].StackStatus)
+			r.event.Emit(ctx, &cr, "CFInProgress", fmt.Sprintf("the tenant cluster's node pool cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 			r.logger.Debugf(ctx, "canceling resource")
 			return nil
-		} else if key.StackComplete(*o.Stacks[0].StackStatus) {
-			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *o.Stacks[0].StackStatus))
+		} else if key.StackComplete(*stacks[0].StackStatus) {
+			r.event.Emit(ctx, &cr, "CFCompleted", fmt.Sprintf("the tenant cluster's control plane cloud formation stack has stack status %#q", *stacks[0].StackStatus))
 		}
 
 		r.logger.Debugf(ctx, "found the tenant cluster's node pool cloud formation stack already exists")
@@ -136,9 +155,19 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		if err != nil {
 			return microerror.Mask(err)
 		}
+		preview, err := r.changeSetPreviewEnabled(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
 
-		if scale {
-			err = r.updateStack(ctx, cr)
+		// Scaling renders the complete desired template. In preview mode we must
+		// not scale directly while an update is pending, because this would apply
+		// the update without approval. The pending change set covers the scaling
+		// as well. During an operator driven rollout the capacity of the ASG is
+		// managed by the rollout, which is why we do not scale then either.
+		_, rollingOut := cr.GetAnnotations()[awsoperatorannotation.RolloutDesiredCapacity]
+		if scale && !(update && preview) && !rollingOut {
+			err = r.updateStack(ctx, cr, false)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -156,29 +185,83 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			}
 
 			if tccpnUpdated {
-				// Disable autoscaler for this node pool.
-				err = r.removeAutoscalerTag(ctx, cr)
-				if err != nil {
-					return microerror.Mask(err)
+				// Disable autoscaler for this node pool. In preview mode this
+				// happens only once the change set got approved.
+				if !preview {
+					err = r.removeAutoscalerTag(ctx, cr)
+					if err != nil {
+						return microerror.Mask(err)
+					}
 				}
 
-				err = r.updateStack(ctx, cr)
+				err = r.updateStack(ctx, cr, preview)
 				if err != nil {
 					return microerror.Mask(err)
 				}
 			}
 		} else {
-			// Enable autoscaler for this node pool.
-			err = r.ensureAutoscalerTag(ctx, cr)
+			// In case there is nothing to update anymore, a change set created
+			// in preview mode is not needed and gets removed.
+			err = r.changeSet.Cleanup(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCNP(&cr))
 			if err != nil {
 				return microerror.Mask(err)
 			}
+
+			// In case the operator replaces the instances of this node pool, the
+			// rollout has to be done before the autoscaler gets enabled again.
+			rolledOut := true
+			{
+				cl, err := r.findCluster(ctx, cr)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				if key.MachineDeploymentUpdateStrategy(cl, cr) == key.UpdateStrategyOperator || rollingOut {
+					rolledOut, err = r.ensureRollout(ctx, cl, cr)
+					if err != nil {
+						return microerror.Mask(err)
+					}
+				}
+			}
+
+			// Enable autoscaler for this node pool.
+			if rolledOut {
+				err = r.ensureAutoscalerTag(ctx, cr)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+			} else {
+				r.logger.Debugf(ctx, "not enabling autoscaler due to rollout of instances in progress")
+			}
 		}
 	}
 
 	return nil
 }
 
+// changeSetPreviewEnabled returns true when stack updates should be previewed
+// via change sets, either because the AWSMachineDeployment CR or the AWSCluster
+// CR of the tenant cluster is annotated accordingly.
+func (r *Resource) changeSetPreviewEnabled(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (bool, error) {
+	if key.ChangeSetPreviewEnabled(&cr) {
+		return true, nil
+	}
+
+	var cl infrastructurev1alpha3.AWSCluster
+	{
+		err := r.k8sClient.CtrlClient().Get(
+			ctx,
+			client.ObjectKey{Name: key.ClusterID(&cr), Namespace: cr.Namespace},
+			&cl,
+		)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+	}
+
+	return key.ChangeSetPreviewEnabled(&cl), nil
+}
+
 func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
@@ -233,22 +316,17 @@ func (r *Resource) createStack(ctx context.Context, cr infrastructurev1alpha3.AW
 }
 
 func (r *Resource) getCloudFormationTags(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) ([]*cloudformation.Tag, error) {
-	tags := key.AWSTags(&cr, r.installationName)
-	tags[key.TagStack] = key.StackTCNP
-	tags[key.TagMachineDeployment] = key.MachineDeploymentID(&cr)
-
-	cloudtags, err := r.cloudtags.GetTagsByCluster(ctx, key.ClusterID(&cr))
+	tags, err := r.cloudtags.NodePoolTags(ctx, cr)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	for k, v := range cloudtags {
-		tags[k] = v
-	}
+	tags[key.TagStack] = key.StackTCNP
+	tags[key.TagMachineDeployment] = key.MachineDeploymentID(&cr)
 
 	return awstags.NewCloudFormation(tags), nil
 }
 
-func (r *Resource) updateStack(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) error {
+func (r *Resource) updateStack(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment, preview bool) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
 		return microerror.Mask(err)
@@ -287,9 +365,33 @@ func (r *Resource) updateStack(ctx context.Context, cr infrastructurev1alpha3.AW
 			Tags:         tags,
 			TemplateBody: aws.String(templateBody),
 		}
-		_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
-		if err != nil {
-			return microerror.Mask(err)
+
+		if preview {
+			approved, err := r.changeSet.Ensure(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			if !approved {
+				r.logger.Debugf(ctx, "not updating the tenant cluster's node pool cloud formation stack due to change set not being approved")
+				return nil
+			}
+
+			// Disable autoscaler for this node pool.
+			err = r.removeAutoscalerTag(ctx, cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			err = r.changeSet.Execute(ctx, &cr, cc.Client.TenantCluster.AWS.CloudFormation, key.StackNameTCNP(&cr))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		} else {
+			_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
 		}
 
 		r.logger.Debugf(ctx, "requested the update of the tenant cluster's node pool cloud formation stack")
@@ -323,16 +425,23 @@ func (r *Resource) updateStack(ctx context.Context, cr infrastructurev1alpha3.AW
 // Now end user decides that it must be scaled down so maximum size is decreased
 // to 7. When desired number of instances is temporarily bigger than maximum
 // number of instances, it must be fixed to be maximum number of instances.
+//
+// Example 3:
+// Node pools may be scaled down to zero when their minimum is 0. Then the
+// cluster-autoscaler scales the ASG up from zero based on its node-template
+// tags. Until the ASG got its first instance, its Desired value is 0, so that
+// the minimum number of running instances is 0 as well. The result is never
+// negative.
 func minDesiredWorkers(minWorkers, maxWorkers, statusDesiredCapacity int) int {
 	if statusDesiredCapacity > maxWorkers {
-		return maxWorkers
+		return max(maxWorkers, 0)
 	}
 
 	if statusDesiredCapacity > minWorkers {
 		return statusDesiredCapacity
 	}
 
-	return minWorkers
+	return max(minWorkers, 0)
 }
 
 func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (*template.ParamsMainAutoScalingGroup, error) {
@@ -367,34 +476,77 @@ func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1a
 
 	minDesiredNodes := minDesiredWorkers(key.MachineDeploymentScalingMin(cr), key.MachineDeploymentScalingMax(cr), cc.Status.TenantCluster.ASG.DesiredCapacity)
 
-	var launchTemplateOverride []template.LaunchTemplateOverride
+	var warmPool *template.ParamsMainAutoScalingGroupWarmPool
 	{
-		val, ok := r.alikeInstances[key.MachineDeploymentInstanceType(cr)]
+		w, err := key.MachineDeploymentWarmPool(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 
-		if cr.Spec.Provider.Worker.UseAlikeInstanceTypes && ok {
-			launchTemplateOverride = val
+		if w != nil {
+			warmPool = &template.ParamsMainAutoScalingGroupWarmPool{
+				MaxGroupPreparedCapacity: w.MaxPreparedCapacity,
+				MinSize:                  w.MinSize,
+				PoolState:                w.State,
+			}
 		}
 	}
 
-	var maxBatchSize string
-	var minInstancesInService string
+	var spotAllocation key.SpotAllocation
 	{
-		// try read the value from cluster CR
-		if val, ok := cl.Annotations[annotation.AWSUpdateMaxBatchSize]; ok {
-			maxBatchSize = key.MachineDeploymentParseMaxBatchSize(val, minDesiredNodes)
+		spotAllocation, err = key.MachineDeploymentSpotAllocation(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
 
-			r.logger.Debugf(ctx, "value of MaxBatchSize for ASG updates set by annotation from AWSCluster CR")
+	var instanceRequirements *template.ParamsMainAutoScalingGroupInstanceRequirements
+	{
+		ir, err := key.MachineDeploymentInstanceRequirements(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
 		}
-		// override the value with machine deployment value if its set
-		if val, ok := cr.Annotations[annotation.AWSUpdateMaxBatchSize]; ok {
-			maxBatchSize = key.MachineDeploymentParseMaxBatchSize(val, minDesiredNodes)
 
-			r.logger.Debugf(ctx, "value of MaxBatchSize for ASG updates overridden by annotation from AWSMachineDeployment CR")
+		if ir != nil {
+			instanceRequirements = &template.ParamsMainAutoScalingGroupInstanceRequirements{
+				AllowedInstanceTypes:  key.InstanceFamilyPatterns(ir.AllowedFamilies),
+				BurstablePerformance:  ir.Burstable,
+				CPUManufacturers:      key.InstanceRequirementsCPUManufacturers(key.InstanceTypeArchitecture(key.MachineDeploymentInstanceType(cr))),
+				ExcludedInstanceTypes: key.InstanceFamilyPatterns(ir.ExcludedFamilies),
+				InstanceGenerations:   ir.Generations,
+				MemoryMiB: template.ParamsMainAutoScalingGroupRange{
+					Min: ir.MemoryMiB.Min,
+					Max: ir.MemoryMiB.Max,
+				},
+				VCPUCount: template.ParamsMainAutoScalingGroupRange{
+					Min: ir.VCPUs.Min,
+					Max: ir.VCPUs.Max,
+				},
+			}
+
+			if ir.GPUs != nil {
+				instanceRequirements.AcceleratorCount = &template.ParamsMainAutoScalingGroupRange{
+					Min: ir.GPUs.Min,
+					Max: ir.GPUs.Max,
+				}
+			}
 		}
-		// if nothing is set use the default
-		if maxBatchSize == "" {
-			maxBatchSize = key.MachineDeploymentWorkerCountRatio(minDesiredNodes, 0.1)
+	}
+
+	var launchTemplateOverride []template.LaunchTemplateOverride
+	if warmPool == nil {
+		launchTemplateOverride = r.launchTemplateOverrides(cr)
+
+		if spotAllocation.OnDemandAllocationStrategy == key.OnDemandAllocationStrategyPrioritized {
+			launchTemplateOverride = key.MachineDeploymentPrioritizedOverrides(cr, launchTemplateOverride)
 		}
+	}
+
+	var maxBatchSize string
+	var minInstancesInService string
+	{
+		maxBatchSize = r.maxBatchSize(ctx, cl, cr, minDesiredNodes)
+
 		// set minInstancesInService based on the maxBatchSize value
 		minInstancesInService, err = key.MachineDeploymentMinInstanceInServiceFromMaxBatchSize(maxBatchSize, minDesiredNodes)
 		if err != nil {
@@ -426,15 +578,73 @@ func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1a
 		}
 	}
 
+	var nodeTemplate template.ParamsMainAutoScalingGroupNodeTemplate
+	{
+		c, err := key.MachineDeploymentNodeConfig(cr, cc.Spec.TenantCluster.TCNP.NodeConfigData)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		it, err := r.smallestInstanceType(ctx, cr, launchTemplateOverride, instanceRequirements)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		// The cluster autoscaler needs to know the resources, labels and taints
+		// of the nodes an ASG would launch in order to scale it up from zero,
+		// since there is no existing node it could take them from. The ASG may
+		// launch any of its instance types, so we describe the smallest one in
+		// order to not overestimate the capacity of a new node.
+		nodeTemplate.Resources = map[string]string{
+			"cpu":               strconv.FormatInt(it.VCPUs, 10),
+			"ephemeral-storage": fmt.Sprintf("%sGi", key.MachineDeploymentKubeletVolumeSizeGB(cr)),
+			"memory":            fmt.Sprintf("%dMi", it.MemoryMiB),
+		}
+		if it.GPUs > 0 {
+			nodeTemplate.Resources["nvidia.com/gpu"] = strconv.FormatInt(it.GPUs, 10)
+		}
+
+		nodeTemplate.Labels = map[string]string{
+			"kubernetes.io/arch": key.InstanceTypeArchitecture(key.MachineDeploymentInstanceType(cr)),
+		}
+		for _, l := range strings.Split(key.KubeletLabelsTCNP(&cr), ",") {
+			k, v, _ := strings.Cut(l, "=")
+			// The machine deployment label is always part of the ASG tags.
+			if k == "" || k == label.MachineDeployment {
+				continue
+			}
+			nodeTemplate.Labels[k] = v
+		}
+		for k, v := range c.Labels {
+			nodeTemplate.Labels[k] = v
+		}
+
+		for _, t := range c.Taints {
+			if nodeTemplate.Taints == nil {
+				nodeTemplate.Taints = map[string]string{}
+			}
+			nodeTemplate.Taints[t.Key] = fmt.Sprintf("%s:%s", t.Value, t.Effect)
+		}
+	}
+
+	// Instance requirements leave the number of spot instance pools to AWS.
+	var spotInstancePools int
+	if instanceRequirements == nil {
+		spotInstancePools = key.MachineDeploymentSpotInstancePools(launchTemplateOverride)
+	}
+
 	autoScalingGroup := &template.ParamsMainAutoScalingGroup{
 		AvailabilityZones: key.MachineDeploymentAvailabilityZones(cr),
+		CapacityRebalance: spotAllocation.CapacityRebalance,
 		Cluster: template.ParamsMainAutoScalingGroupCluster{
 			ID: key.ClusterID(&cr),
 		},
 		NodePool: template.ParamsMainIAMPoliciesNodePool{
 			ID: key.MachineDeploymentID(&cr),
 		},
+		NodeTemplate:                        nodeTemplate,
 		DesiredCapacity:                     minDesiredNodes,
+		InstanceRequirements:                instanceRequirements,
 		MaxBatchSize:                        maxBatchSize,
 		MaxSize:                             key.MachineDeploymentScalingMax(cr),
 		MinInstancesInService:               minInstancesInService,
@@ -443,15 +653,109 @@ func (r *Resource) newAutoScalingGroup(ctx context.Context, cr infrastructurev1a
 		PauseTime:                           pauseTime,
 		OnDemandPercentageAboveBaseCapacity: key.MachineDeploymentOnDemandPercentageAboveBaseCapacity(cr),
 		OnDemandBaseCapacity:                key.MachineDeploymentOnDemandBaseCapacity(cr),
-		SpotInstancePools:                   key.MachineDeploymentSpotInstancePools(launchTemplateOverride),
-		SpotAllocationStrategy:              "lowest-price",
+		OnDemandAllocationStrategy:          spotAllocation.OnDemandAllocationStrategy,
+		OperatorRollout:                     key.MachineDeploymentUpdateStrategy(cl, cr) == key.UpdateStrategyOperator,
+		SpotInstancePools:                   spotInstancePools,
+		SpotAllocationStrategy:              spotAllocation.SpotAllocationStrategy,
+		SpotMaxPrice:                        spotAllocation.SpotMaxPrice,
 		LaunchTemplateOverrides:             launchTemplateOverride,
 		LifeCycleHookName:                   key.LifeCycleHookNodePool,
+		WarmPool:                            warmPool,
 	}
 
 	return autoScalingGroup, nil
 }
 
+// launchTemplateOverrides returns the instance types the node pool's ASG may
+// launch in addition to the configured instance type. Node pools with instance
+// requirements have no launch template overrides.
+func (r *Resource) launchTemplateOverrides(cr infrastructurev1alpha3.AWSMachineDeployment) []template.LaunchTemplateOverride {
+	// Instance requirements replace the explicitly listed alike instance types.
+	if _, ok := cr.GetAnnotations()[awsoperatorannotation.InstanceRequirements]; ok {
+		return nil
+	}
+
+	val, ok := r.alikeInstances[key.MachineDeploymentInstanceType(cr)]
+	if cr.Spec.Provider.Worker.UseAlikeInstanceTypes && ok {
+		return val
+	}
+
+	return nil
+}
+
+// smallestInstanceType returns the minimum resources of all instance types the
+// node pool's ASG may launch. These are either the minimums of the given
+// instance requirements or the minimums over the node pool's instance type and
+// the given launch template overrides.
+func (r *Resource) smallestInstanceType(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment, overrides []template.LaunchTemplateOverride, requirements *template.ParamsMainAutoScalingGroupInstanceRequirements) (instancetypes.InstanceType, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return instancetypes.InstanceType{}, microerror.Mask(err)
+	}
+
+	if requirements != nil {
+		it := instancetypes.InstanceType{
+			MemoryMiB: int64(requirements.MemoryMiB.Min),
+			VCPUs:     int64(requirements.VCPUCount.Min),
+		}
+		if requirements.AcceleratorCount != nil {
+			it.GPUs = int64(requirements.AcceleratorCount.Min)
+		}
+
+		return it, nil
+	}
+
+	names := []string{key.MachineDeploymentInstanceType(cr)}
+	for _, o := range overrides {
+		names = append(names, o.InstanceType)
+	}
+
+	var smallest instancetypes.InstanceType
+	for i, n := range names {
+		it, err := r.instanceTypes.InstanceType(ctx, cc.Client.TenantCluster.AWS.EC2, cc.Status.TenantCluster.AWS.Region, n)
+		if err != nil {
+			return instancetypes.InstanceType{}, microerror.Mask(err)
+		}
+
+		if i == 0 || it.GPUs < smallest.GPUs {
+			smallest.GPUs = it.GPUs
+		}
+		if i == 0 || it.MemoryMiB < smallest.MemoryMiB {
+			smallest.MemoryMiB = it.MemoryMiB
+		}
+		if i == 0 || it.VCPUs < smallest.VCPUs {
+			smallest.VCPUs = it.VCPUs
+		}
+	}
+
+	return smallest, nil
+}
+
+// maxBatchSize returns the maximum number of instances replaced at the same
+// time during updates of the node pool.
+func (r *Resource) maxBatchSize(ctx context.Context, cl infrastructurev1alpha3.AWSCluster, cr infrastructurev1alpha3.AWSMachineDeployment, workers int) string {
+	var maxBatchSize string
+
+	// try read the value from cluster CR
+	if val, ok := cl.Annotations[annotation.AWSUpdateMaxBatchSize]; ok {
+		maxBatchSize = key.MachineDeploymentParseMaxBatchSize(val, workers)
+
+		r.logger.Debugf(ctx, "value of MaxBatchSize for ASG updates set by annotation from AWSCluster CR")
+	}
+	// override the value with machine deployment value if its set
+	if val, ok := cr.Annotations[annotation.AWSUpdateMaxBatchSize]; ok {
+		maxBatchSize = key.MachineDeploymentParseMaxBatchSize(val, workers)
+
+		r.logger.Debugf(ctx, "value of MaxBatchSize for ASG updates overridden by annotation from AWSMachineDeployment CR")
+	}
+	// if nothing is set use the default
+	if maxBatchSize == "" {
+		maxBatchSize = key.MachineDeploymentWorkerCountRatio(workers, 0.1)
+	}
+
+	return maxBatchSize
+}
+
 func (r *Resource) newIAMPolicies(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (*template.ParamsMainIAMPolicies, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
@@ -524,39 +828,109 @@ func (r *Resource) newLaunchTemplate(ctx context.Context, cr infrastructurev1alp
 
 	var ami string
 	{
-		ami, err = r.images.AMI(ctx, &cr, key.MachineDeploymentFlatcarReleaseVersion(cr))
+		architecture, err := key.MachineDeploymentArchitecture(cr, r.launchTemplateOverrides(cr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ami, err = r.images.AMI(ctx, &cr, key.MachineDeploymentFlatcarReleaseVersion(cr), architecture)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var volumeProfiles map[string]key.VolumeProfile
+	{
+		cl, err := r.findCluster(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		volumeProfiles, err = key.MachineDeploymentVolumeProfiles(cl, cr)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 	}
 
+	var warmPool *key.WarmPool
+	{
+		warmPool, err = key.MachineDeploymentWarmPool(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	// Hibernation writes the contents of the instance's RAM to its root volume,
+	// which has to be encrypted and large enough to hold them.
+	hibernation := warmPool != nil && warmPool.State == key.WarmPoolStateHibernated
+	var rootVolumeSize int
+	if hibernation {
+		it, err := r.instanceTypes.InstanceType(ctx, cc.Client.TenantCluster.AWS.EC2, cc.Status.TenantCluster.AWS.Region, key.MachineDeploymentInstanceType(cr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if it.MemoryMiB > key.HibernationMaxMemoryMiB {
+			return nil, microerror.Maskf(invalidConfigError, "instance type %#q with %d MiB of memory cannot hibernate", key.MachineDeploymentInstanceType(cr), it.MemoryMiB)
+		}
+
+		rootVolumeSize = key.HibernationRootVolumeBaseSizeGB + int((it.MemoryMiB+1023)/1024)
+	}
+
+	containerd := volumeProfiles[key.VolumeDeviceContainerd]
+	docker := volumeProfiles[key.VolumeDeviceDocker]
+	kubelet := volumeProfiles[key.VolumeDeviceKubelet]
+	logging := volumeProfiles[key.VolumeDeviceLogging]
+
 	launchTemplate := &template.ParamsMainLaunchTemplate{
 		BlockDeviceMapping: template.ParamsMainLaunchTemplateBlockDeviceMapping{
 			Containerd: template.ParamsMainLaunchTemplateBlockDeviceMappingContainerd{
 				Volume: template.ParamsMainLaunchTemplateBlockDeviceMappingContainerdVolume{
-					Size: key.MachineDeploymentContainerdVolumeSizeGB(cr),
+					IOPS:       containerd.IOPS,
+					KMSKeyARN:  containerd.KMSKeyARN,
+					Size:       key.MachineDeploymentContainerdVolumeSizeGB(cr),
+					Throughput: containerd.Throughput,
+					Type:       containerd.Type,
 				},
 			},
 			Docker: template.ParamsMainLaunchTemplateBlockDeviceMappingDocker{
 				Volume: template.ParamsMainLaunchTemplateBlockDeviceMappingDockerVolume{
-					Size: key.MachineDeploymentDockerVolumeSizeGB(cr),
+					IOPS:       docker.IOPS,
+					KMSKeyARN:  docker.KMSKeyARN,
+					Size:       key.MachineDeploymentDockerVolumeSizeGB(cr),
+					Throughput: docker.Throughput,
+					Type:       docker.Type,
 				},
 			},
 			Kubelet: template.ParamsMainLaunchTemplateBlockDeviceMappingKubelet{
 				Volume: template.ParamsMainLaunchTemplateBlockDeviceMappingKubeletVolume{
-					Size: key.MachineDeploymentKubeletVolumeSizeGB(cr),
+					IOPS:       kubelet.IOPS,
+					KMSKeyARN:  kubelet.KMSKeyARN,
+					Size:       key.MachineDeploymentKubeletVolumeSizeGB(cr),
+					Throughput: kubelet.Throughput,
+					Type:       kubelet.Type,
 				},
 			},
 			Logging: template.ParamsMainLaunchTemplateBlockDeviceMappingLogging{
 				Volume: template.ParamsMainLaunchTemplateBlockDeviceMappingLoggingVolume{
-					Size: key.MachineDeploymentLoggingVolumeSizeGB(cr),
+					IOPS:       logging.IOPS,
+					KMSKeyARN:  logging.KMSKeyARN,
+					Size:       key.MachineDeploymentLoggingVolumeSizeGB(cr),
+					Throughput: logging.Throughput,
+					Type:       logging.Type,
+				},
+			},
+			Root: template.ParamsMainLaunchTemplateBlockDeviceMappingRoot{
+				Volume: template.ParamsMainLaunchTemplateBlockDeviceMappingRootVolume{
+					Size: rootVolumeSize,
 				},
 			},
 		},
 		Instance: template.ParamsMainLaunchTemplateInstance{
-			Image:      ami,
-			Monitoring: true,
-			Type:       key.MachineDeploymentInstanceType(cr),
+			Hibernation: hibernation,
+			Image:       ami,
+			Monitoring:  true,
+			Type:        key.MachineDeploymentInstanceType(cr),
 		},
 		Metadata: template.ParamsMainLaunchTemplateMetadata{
 			HttpTokens: key.MachineDeploymentMetadataV2(cr),
@@ -572,11 +946,64 @@ func (r *Resource) newLaunchTemplate(ctx context.Context, cr infrastructurev1alp
 }
 
 func (r *Resource) newOutputs(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (*template.ParamsMainOutputs, error) {
-	var err error
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
 	var ami string
 	{
-		ami, err = r.images.AMI(ctx, &cr, key.MachineDeploymentFlatcarReleaseVersion(cr))
+		architecture, err := key.MachineDeploymentArchitecture(cr, r.launchTemplateOverrides(cr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ami, err = r.images.AMI(ctx, &cr, key.MachineDeploymentFlatcarReleaseVersion(cr), architecture)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var volumeProfiles map[string]key.VolumeProfile
+	{
+		cl, err := r.findCluster(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		volumeProfiles, err = key.MachineDeploymentVolumeProfiles(cl, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var instanceRequirements *key.InstanceRequirements
+	{
+		instanceRequirements, err = key.MachineDeploymentInstanceRequirements(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var nodeConfig key.NodeConfig
+	{
+		nodeConfig, err = key.MachineDeploymentNodeConfig(cr, cc.Spec.TenantCluster.TCNP.NodeConfigData)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var spotAllocation key.SpotAllocation
+	{
+		spotAllocation, err = key.MachineDeploymentSpotAllocation(cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var warmPool *key.WarmPool
+	{
+		warmPool, err = key.MachineDeploymentWarmPool(cr)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -584,12 +1011,18 @@ func (r *Resource) newOutputs(ctx context.Context, cr infrastructurev1alpha3.AWS
 
 	outputs := &template.ParamsMainOutputs{
 		DockerVolumeSizeGB: key.MachineDeploymentDockerVolumeSizeGB(cr),
+		DualStack:          cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR != "",
 		Instance: template.ParamsMainOutputsInstance{
 			Image: ami,
 			Type:  key.MachineDeploymentInstanceType(cr),
 		},
-		OperatorVersion: key.OperatorVersion(&cr),
-		ReleaseVersion:  key.ReleaseVersion(&cr),
+		InstanceRequirements: key.MachineDeploymentInstanceRequirementsValue(instanceRequirements),
+		NodeConfig:           key.MachineDeploymentNodeConfigValue(nodeConfig),
+		OperatorVersion:      key.OperatorVersion(&cr),
+		ReleaseVersion:       key.ReleaseVersion(&cr),
+		SpotAllocation:       key.MachineDeploymentSpotAllocationValue(spotAllocation),
+		VolumeProfiles:       key.MachineDeploymentVolumeProfilesValue(volumeProfiles),
+		WarmPool:             key.MachineDeploymentWarmPoolValue(warmPool),
 	}
 
 	return outputs, nil
@@ -609,10 +1042,16 @@ func (r *Resource) newRouteTables(ctx context.Context, cr infrastructurev1alpha3
 			ClusterID:        key.ClusterID(&cr),
 			NodePoolID:       cr.GetName(),
 			Name:             key.SanitizeCFResourceName(key.PrivateRouteTableName(a.Name)),
+			IPv6Route: template.ParamsMainRouteTablesListItemRoute{
+				Name: key.SanitizeCFResourceName(key.EgressOnlyInternetGatewayRouteName(a.Name)),
+			},
 			Route: template.ParamsMainRouteTablesListItemRoute{
 				Name: key.SanitizeCFResourceName(key.NATRouteName(a.Name)),
 			},
 			TCCP: template.ParamsMainRouteTablesListItemTCCP{
+				EgressOnlyInternetGateway: template.ParamsMainRouteTablesListItemTCCPEgressOnlyInternetGateway{
+					ID: cc.Status.TenantCluster.TCCP.VPC.EgressOnlyInternetGatewayID,
+				},
 				NATGateway: template.ParamsMainRouteTablesListItemTCCPNATGateway{
 					ID: a.NATGateway.ID,
 				},
@@ -700,8 +1139,9 @@ func (r *Resource) newSecurityGroups(ctx context.Context, cr infrastructurev1alp
 			},
 			NodePools: nodePools,
 			VPC: template.ParamsMainSecurityGroupsTenantClusterVPC{
-				ID:   cc.Status.TenantCluster.TCCP.VPC.ID,
-				CIDR: networkCIDR,
+				ID:       cc.Status.TenantCluster.TCCP.VPC.ID,
+				CIDR:     networkCIDR,
+				IPv6CIDR: cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR,
 			},
 		},
 	}
@@ -730,6 +1170,7 @@ func (r *Resource) newSubnets(ctx context.Context, cr infrastructurev1alpha3.AWS
 		s := template.ParamsMainSubnetsListItem{
 			AvailabilityZone: a.Name,
 			CIDR:             a.Subnet.Private.CIDR.String(),
+			IPv6CIDRIndex:    a.Subnet.Private.IPv6CIDRIndex,
 			Name:             key.SanitizeCFResourceName(key.PrivateSubnetName(a.Name)),
 			RouteTable: template.ParamsMainSubnetsListItemRouteTable{
 				Name: key.SanitizeCFResourceName(key.PrivateRouteTableName(a.Name)),
@@ -740,7 +1181,8 @@ func (r *Resource) newSubnets(ctx context.Context, cr infrastructurev1alpha3.AWS
 			TagInternalELB: tagInternalELB,
 			TCCP: template.ParamsMainSubnetsListItemTCCP{
 				VPC: template.ParamsMainSubnetsListItemTCCPVPC{
-					ID: cc.Status.TenantCluster.TCCP.VPC.ID,
+					ID:       cc.Status.TenantCluster.TCCP.VPC.ID,
+					IPv6CIDR: cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR,
 				},
 			},
 		}
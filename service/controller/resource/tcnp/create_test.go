@@ -2,6 +2,7 @@ package tcnp
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"os"
 	"path/filepath"
@@ -14,11 +15,16 @@ import (
 	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/images"
+	"github.com/giantswarm/aws-operator/v16/service/internal/instancetypes"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 	"github.com/giantswarm/aws-operator/v16/service/internal/releases"
 	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
@@ -36,18 +42,109 @@ var update = flag.Bool("update", false, "update .golden CF template file")
 //	go test ./service/controller/resource/tcnp -run Test_Controller_Resource_TCNP_Template_Render -update
 func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 	testCases := []struct {
-		name string
-		cr   infrastructurev1alpha3.AWSMachineDeployment
-		re   releasev1alpha1.Release
+		name               string
+		clusterAnnotations map[string]string
+		cr                 infrastructurev1alpha3.AWSMachineDeployment
+		ctx                context.Context
+		re                 releasev1alpha1.Release
 	}{
 		{
 			name: "case 0: basic test",
 			cr:   unittest.DefaultMachineDeployment(),
+			ctx:  unittest.DefaultContext(),
 			re:   unittest.DefaultRelease(),
 		},
 		{
 			name: "case 1: disk test",
 			cr:   unittest.MachineDeploymentWithDisks(unittest.DefaultMachineDeployment(), "10", 11, 12, "13"),
+			ctx:  unittest.DefaultContext(),
+			re:   unittest.DefaultRelease(),
+		},
+		{
+			name: "case 2: dual-stack test",
+			cr:   unittest.DefaultMachineDeployment(),
+			ctx:  withDualStack(unittest.DefaultContext(), "2a05:d014:1f9:7c00::/56", "eigw-0123456789abcdef0"),
+			re:   unittest.DefaultRelease(),
+		},
+		{
+			name: "case 3: operator rollout test",
+			cr:   withAnnotation(unittest.DefaultMachineDeployment(), annotation.UpdateStrategy, key.UpdateStrategyOperator),
+			ctx:  unittest.DefaultContext(),
+			re:   unittest.DefaultRelease(),
+		},
+		{
+			name: "case 4: volume profile test",
+			clusterAnnotations: map[string]string{
+				annotation.VolumeKMSKeyARN: "arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			},
+			cr: withAnnotation(
+				withAnnotation(unittest.DefaultMachineDeployment(), annotation.VolumeProfileContainerd, "type=io2,iops=10000"),
+				annotation.VolumeProfileDocker, "type=gp3,iops=6000,throughput=500",
+			),
+			ctx: unittest.DefaultContext(),
+			re:  unittest.DefaultRelease(),
+		},
+		{
+			name: "case 5: warm pool test",
+			cr: withAnnotation(
+				withAnnotation(
+					withAnnotation(withUseAlikeInstanceTypes(unittest.DefaultMachineDeployment(), false), annotation.WarmPool, "true"),
+					annotation.WarmPoolMinSize, "2",
+				),
+				annotation.WarmPoolState, key.WarmPoolStateHibernated,
+			),
+			ctx: unittest.DefaultContext(),
+			re:  unittest.DefaultRelease(),
+		},
+		{
+			name: "case 6: arm64 test",
+			cr:   withInstanceType(unittest.DefaultMachineDeployment(), "m6g.2xlarge"),
+			ctx:  unittest.DefaultContext(),
+			re:   unittest.DefaultRelease(),
+		},
+		{
+			name: "case 7: spot allocation test",
+			cr: withAnnotation(
+				withAnnotation(
+					withAnnotation(withOnDemandPercentage(unittest.DefaultMachineDeployment(), 20), annotation.SpotAllocation, key.SpotAllocationStrategyPriceCapacityOptimized),
+					annotation.SpotCapacityRebalance, "true",
+				),
+				annotation.SpotMaxPrice, "0.25",
+			),
+			ctx: unittest.DefaultContext(),
+			re:  unittest.DefaultRelease(),
+		},
+		{
+			name: "case 8: instance requirements test",
+			cr: withAnnotation(
+				withOnDemandPercentage(unittest.DefaultMachineDeployment(), 50),
+				annotation.InstanceRequirements, "vcpu=4-16,memoryMiB=16384-,excludedFamilies=t2;t3,generations=current,burstable=excluded,gpus=0",
+			),
+			ctx: unittest.DefaultContext(),
+			re:  unittest.DefaultRelease(),
+		},
+		{
+			name: "case 9: node config test",
+			cr: withAnnotation(
+				withAnnotation(
+					withAnnotation(unittest.DefaultMachineDeployment(), annotation.NodeLabels, "team=a,node.kubernetes.io/tier=b"),
+					annotation.NodeTaints, "dedicated=a:NoSchedule",
+				),
+				annotation.KubeletMaxPods, "50",
+			),
+			ctx: unittest.DefaultContext(),
+			re:  unittest.DefaultRelease(),
+		},
+		{
+			name: "case 10: scale from zero test",
+			cr:   withScalingMin(withInstanceType(unittest.DefaultMachineDeployment(), "p3.2xlarge"), 0),
+			ctx:  unittest.DefaultContext(),
+			re:   unittest.DefaultRelease(),
+		},
+		{
+			name: "case 11: scale from zero alike instances test",
+			cr:   withScalingMin(withInstanceType(unittest.DefaultMachineDeployment(), "m5.4xlarge"), 0),
+			ctx:  unittest.DefaultContext(),
 			re:   unittest.DefaultRelease(),
 		},
 	}
@@ -83,7 +180,10 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
     "ca-central-1": "ami-09afcf2e90761d6e6",
     "cn-north-1": "ami-019174dba14053d2a",
     "cn-northwest-1": "ami-004e81bc53b1e6ffa",
-    "eu-central-1": "ami-0a9a5d2b65cce04eb",
+    "eu-central-1": {
+      "amd64": "ami-0a9a5d2b65cce04eb",
+      "arm64": "ami-0d1e6b3c8f2a47951"
+    },
     "eu-north-1": "ami-0bbfc19aa4c355fe2",
     "eu-west-1": "ami-002db020452770c0f",
     "eu-west-2": "ami-024928e37dcc18a42",
@@ -105,14 +205,28 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			var err error
 
-			ctx := unittest.DefaultContext()
+			ctx := tc.ctx
 			k := unittest.FakeK8sClient()
 
+			var e recorder.Interface
+			{
+				c := recorder.Config{
+					K8sClient: k,
+
+					Component: "dummy",
+				}
+
+				e = recorder.New(c)
+			}
+
 			var ct cloudtags.Interface
 			{
 				c := cloudtags.Config{
+					Event:     e,
 					K8sClient: k,
 					Logger:    microloggertest.New(),
+
+					InstallationName: "dummy",
 				}
 
 				ct, err = cloudtags.New(c)
@@ -138,23 +252,27 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 				m = &encrypter.Mock{}
 			}
 
-			var e recorder.Interface
+			var cs changeset.Interface
 			{
-				c := recorder.Config{
+				c := changeset.Config{
+					Event:     e,
 					K8sClient: k,
-
-					Component: "dummy",
+					Logger:    microloggertest.New(),
 				}
 
-				e = recorder.New(c)
+				cs, err = changeset.New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
 			}
 
 			var d *changedetection.TCNP
 			{
 				c := changedetection.TCNPConfig{
-					Event:    e,
-					Logger:   microloggertest.New(),
-					Releases: rel,
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+					Releases:  rel,
 				}
 
 				d, err = changedetection.NewTCNP(c)
@@ -179,6 +297,7 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 
 			{
 				awsCl := unittest.DefaultCluster()
+				awsCl.SetAnnotations(tc.clusterAnnotations)
 				err = k.CtrlClient().Create(ctx, &awsCl)
 				if err != nil {
 					t.Fatal(err)
@@ -201,18 +320,49 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 				}
 			}
 
+			var it instancetypes.Interface
+			{
+				it = &instancetypes.Mock{
+					InstanceTypes: map[string]instancetypes.InstanceType{
+						"m4.2xlarge": {
+							MemoryMiB: 32768,
+							VCPUs:     8,
+						},
+						"m5.2xlarge": {
+							MemoryMiB: 32768,
+							VCPUs:     8,
+						},
+						"m5.4xlarge": {
+							MemoryMiB: 65536,
+							VCPUs:     16,
+						},
+						"m6g.2xlarge": {
+							MemoryMiB: 32768,
+							VCPUs:     8,
+						},
+						"p3.2xlarge": {
+							GPUs:      1,
+							MemoryMiB: 62464,
+							VCPUs:     8,
+						},
+					},
+				}
+			}
+
 			var r *Resource
 			{
 				c := Config{
-					CloudTags: ct,
-					Detection: d,
-					Encrypter: m,
-					Event:     e,
-					Images:    i,
-					K8sClient: k,
-					Logger:    microloggertest.New(),
-
-					AlikeInstances:   `{"m5.2xlarge":[{"InstanceType":"m5.2xlarge","WeightedCapacity":1},{"InstanceType":"m4.2xlarge","WeightedCapacity":1}]}`,
+					ChangeSet:     cs,
+					CloudTags:     ct,
+					Detection:     d,
+					Encrypter:     m,
+					Event:         e,
+					Images:        i,
+					InstanceTypes: it,
+					K8sClient:     k,
+					Logger:        microloggertest.New(),
+
+					AlikeInstances:   `{"m5.2xlarge":[{"InstanceType":"m5.2xlarge","WeightedCapacity":1},{"InstanceType":"m4.2xlarge","WeightedCapacity":1}],"m5.4xlarge":[{"InstanceType":"m5.4xlarge","WeightedCapacity":2},{"InstanceType":"m5.2xlarge","WeightedCapacity":1}]}`,
 					InstallationName: "dummy",
 				}
 
@@ -256,3 +406,113 @@ func Test_Controller_Resource_TCNP_Template_Render(t *testing.T) {
 
 	_ = os.Remove("/tmp/ami.json")
 }
+
+func Test_Controller_Resource_TCNP_minDesiredWorkers(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		minWorkers            int
+		maxWorkers            int
+		statusDesiredCapacity int
+		expected              int
+	}{
+		{
+			name:                  "case 0: desired capacity below minimum",
+			minWorkers:            3,
+			maxWorkers:            10,
+			statusDesiredCapacity: 1,
+			expected:              3,
+		},
+		{
+			name:                  "case 1: desired capacity scaled by cluster-autoscaler",
+			minWorkers:            3,
+			maxWorkers:            10,
+			statusDesiredCapacity: 5,
+			expected:              5,
+		},
+		{
+			name:                  "case 2: desired capacity above maximum",
+			minWorkers:            3,
+			maxWorkers:            7,
+			statusDesiredCapacity: 10,
+			expected:              7,
+		},
+		{
+			name:                  "case 3: node pool scaled to zero",
+			minWorkers:            0,
+			maxWorkers:            10,
+			statusDesiredCapacity: 0,
+			expected:              0,
+		},
+		{
+			name:                  "case 4: node pool scaled up from zero",
+			minWorkers:            0,
+			maxWorkers:            10,
+			statusDesiredCapacity: 2,
+			expected:              2,
+		},
+		{
+			name:                  "case 5: negative values",
+			minWorkers:            -1,
+			maxWorkers:            10,
+			statusDesiredCapacity: -1,
+			expected:              0,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			desired := minDesiredWorkers(tc.minWorkers, tc.maxWorkers, tc.statusDesiredCapacity)
+
+			if desired != tc.expected {
+				t.Fatalf("expected %d got %d", tc.expected, desired)
+			}
+		})
+	}
+}
+
+func withDualStack(ctx context.Context, ipv6CIDR string, egressOnlyInternetGatewayID string) context.Context {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	cc.Status.TenantCluster.TCCP.VPC.EgressOnlyInternetGatewayID = egressOnlyInternetGatewayID
+	cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR = ipv6CIDR
+
+	return ctx
+}
+
+func withAnnotation(cr infrastructurev1alpha3.AWSMachineDeployment, k string, v string) infrastructurev1alpha3.AWSMachineDeployment {
+	if cr.Annotations == nil {
+		cr.Annotations = map[string]string{}
+	}
+	cr.Annotations[k] = v
+
+	return cr
+}
+
+func withInstanceType(cr infrastructurev1alpha3.AWSMachineDeployment, instanceType string) infrastructurev1alpha3.AWSMachineDeployment {
+	cr.Spec.Provider.Worker.InstanceType = instanceType
+
+	return cr
+}
+
+func withOnDemandPercentage(cr infrastructurev1alpha3.AWSMachineDeployment, percentage int) infrastructurev1alpha3.AWSMachineDeployment {
+	cr.Spec.Provider.InstanceDistribution.OnDemandPercentageAboveBaseCapacity = &percentage
+
+	return cr
+}
+
+func withUseAlikeInstanceTypes(cr infrastructurev1alpha3.AWSMachineDeployment, useAlikeInstanceTypes bool) infrastructurev1alpha3.AWSMachineDeployment {
+	cr.Spec.Provider.Worker.UseAlikeInstanceTypes = useAlikeInstanceTypes
+
+	return cr
+}
+
+func withScalingMin(cr infrastructurev1alpha3.AWSMachineDeployment, scalingMin int) infrastructurev1alpha3.AWSMachineDeployment {
+	cr.Spec.NodePool.Scaling.Min = scalingMin
+
+	return cr
+}
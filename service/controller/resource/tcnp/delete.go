@@ -11,6 +11,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
 )
 
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
@@ -56,6 +57,7 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 
 		} else if IsNotExists(err) {
 			r.logger.Debugf(ctx, "the tenant cluster's node pool cloud formation stack does not exist")
+			stackmetrics.DeleteStack(key.StackNameTCNP(&cr))
 			r.event.Emit(ctx, &cr, "CFDeleted", fmt.Sprintf("the tenant cluster's node pool cloud formation stack has stack status %#q", cloudformation.StackStatusDeleteComplete))
 			r.logger.Debugf(ctx, "canceling resource")
 
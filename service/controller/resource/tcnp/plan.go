@@ -0,0 +1,31 @@
+package tcnp
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
+)
+
+// Plan renders the template of the tenant cluster's node pool cloud formation
+// stack without creating or updating the stack.
+func (r *Resource) Plan(ctx context.Context, obj interface{}) (string, error) {
+	cr, err := key.ToMachineDeployment(obj)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	params, err := r.newTemplateParams(ctx, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	templateBody, err := template.Render(params)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return templateBody, nil
+}
@@ -9,9 +9,11 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp/template"
 	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
 	"github.com/giantswarm/aws-operator/v16/service/internal/images"
+	"github.com/giantswarm/aws-operator/v16/service/internal/instancetypes"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 )
 
@@ -21,13 +23,15 @@ const (
 )
 
 type Config struct {
-	CloudTags cloudtags.Interface
-	Detection *changedetection.TCNP
-	Encrypter encrypter.Interface
-	Event     recorder.Interface
-	Images    images.Interface
-	K8sClient k8sclient.Interface
-	Logger    micrologger.Logger
+	ChangeSet     changeset.Interface
+	CloudTags     cloudtags.Interface
+	Detection     *changedetection.TCNP
+	Encrypter     encrypter.Interface
+	Event         recorder.Interface
+	Images        images.Interface
+	InstanceTypes instancetypes.Interface
+	K8sClient     k8sclient.Interface
+	Logger        micrologger.Logger
 
 	AlikeInstances   string
 	InstallationName string
@@ -36,19 +40,24 @@ type Config struct {
 // Resource implements the TCNP resource, which stands for Tenant Cluster Data
 // Plane. We manage a dedicated Cloud Formation stack for each node pool.
 type Resource struct {
-	cloudtags cloudtags.Interface
-	detection *changedetection.TCNP
-	encrypter encrypter.Interface
-	event     recorder.Interface
-	images    images.Interface
-	k8sClient k8sclient.Interface
-	logger    micrologger.Logger
+	changeSet     changeset.Interface
+	cloudtags     cloudtags.Interface
+	detection     *changedetection.TCNP
+	encrypter     encrypter.Interface
+	event         recorder.Interface
+	images        images.Interface
+	instanceTypes instancetypes.Interface
+	k8sClient     k8sclient.Interface
+	logger        micrologger.Logger
 
 	alikeInstances   map[string][]template.LaunchTemplateOverride
 	installationName string
 }
 
 func New(config Config) (*Resource, error) {
+	if config.ChangeSet == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ChangeSet must not be empty", config)
+	}
 	if config.CloudTags == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
 	}
@@ -64,6 +73,9 @@ func New(config Config) (*Resource, error) {
 	if config.Images == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Images must not be empty", config)
 	}
+	if config.InstanceTypes == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstanceTypes must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -87,13 +99,15 @@ func New(config Config) (*Resource, error) {
 	}
 
 	r := &Resource{
-		cloudtags: config.CloudTags,
-		detection: config.Detection,
-		encrypter: config.Encrypter,
-		event:     config.Event,
-		images:    config.Images,
-		k8sClient: config.K8sClient,
-		logger:    config.Logger,
+		changeSet:     config.ChangeSet,
+		cloudtags:     config.CloudTags,
+		detection:     config.Detection,
+		encrypter:     config.Encrypter,
+		event:         config.Event,
+		images:        config.Images,
+		instanceTypes: config.InstanceTypes,
+		k8sClient:     config.K8sClient,
+		logger:        config.Logger,
 
 		alikeInstances:   alikeInstances,
 		installationName: config.InstallationName,
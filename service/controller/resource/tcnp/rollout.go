@@ -0,0 +1,374 @@
+package tcnp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsoperatorannotation "github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+)
+
+const (
+	rolloutConditionType = "InstancesUpToDate"
+
+	rolloutReasonCompleted  = "Completed"
+	rolloutReasonRollingOut = "RollingOut"
+)
+
+// rolloutPlan is the next step of replacing the instances of a node pool which
+// run an outdated launch template version.
+type rolloutPlan struct {
+	// Done is true once all instances run the desired launch template version.
+	Done bool
+	// Launch is the number of replacement instances to launch.
+	Launch int
+	// Outdated is the number of instances running an outdated launch template
+	// version.
+	Outdated int
+	// Terminate are the IDs of the outdated instances to drain and terminate.
+	Terminate []string
+}
+
+// planRollout computes the next step of the rollout. Replacement instances are
+// launched in batches on top of the desired capacity the rollout started with.
+// Outdated instances are only terminated once all replacements are in service
+// and their nodes are ready. Nothing is done while instances are launching or
+// terminating, which includes the draining of terminating instances.
+func planRollout(group *autoscaling.Group, ready map[string]bool, desiredCapacity int, batch int) rolloutPlan {
+	version := launchTemplateVersion(group)
+
+	var busy bool
+	var outdated []string
+	for _, i := range group.Instances {
+		if aws.StringValue(i.LifecycleState) != autoscaling.LifecycleStateInService {
+			busy = true
+			continue
+		}
+
+		if i.LaunchTemplate == nil || aws.StringValue(i.LaunchTemplate.Version) != version {
+			outdated = append(outdated, aws.StringValue(i.InstanceId))
+			continue
+		}
+
+		if !ready[aws.StringValue(i.InstanceId)] {
+			busy = true
+		}
+	}
+	sort.Strings(outdated)
+
+	plan := rolloutPlan{
+		Outdated: len(outdated),
+	}
+
+	if busy {
+		return plan
+	}
+
+	if len(outdated) == 0 {
+		plan.Done = true
+		return plan
+	}
+
+	surplus := int(aws.Int64Value(group.DesiredCapacity)) - desiredCapacity
+	if surplus > 0 {
+		plan.Terminate = outdated[:min(surplus, len(outdated))]
+		return plan
+	}
+
+	plan.Launch = min(max(batch, 1), len(outdated))
+
+	return plan
+}
+
+// ensureRollout executes the next step of the operator driven rollout of the
+// node pool's instances. The returned bool is true in case all instances run
+// the desired launch template version.
+func (r *Resource) ensureRollout(ctx context.Context, cl infrastructurev1alpha3.AWSCluster, cr infrastructurev1alpha3.AWSMachineDeployment) (bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if cc.Status.TenantCluster.ASG.Name == "" {
+		r.logger.Debugf(ctx, "auto scaling group name not available yet")
+		return true, nil
+	}
+
+	if cc.Client.TenantCluster.K8s == nil {
+		r.logger.Debugf(ctx, "tenant cluster k8s client not available yet")
+		return false, nil
+	}
+
+	var group *autoscaling.Group
+	{
+		i := &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: []*string{
+				aws.String(cc.Status.TenantCluster.ASG.Name),
+			},
+		}
+
+		groups, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		if len(groups) != 1 {
+			return false, microerror.Maskf(executionFailedError, "expected one auto scaling group, got %d", len(groups))
+		}
+
+		group = groups[0]
+	}
+
+	ready, err := r.readyNodes(ctx, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	desiredCapacity := int(aws.Int64Value(group.DesiredCapacity))
+	v, rollingOut := cr.GetAnnotations()[awsoperatorannotation.RolloutDesiredCapacity]
+	if rollingOut {
+		desiredCapacity, err = strconv.Atoi(v)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+	}
+
+	batch, err := strconv.Atoi(r.maxBatchSize(ctx, cl, cr, desiredCapacity))
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	plan := planRollout(group, ready, desiredCapacity, batch)
+
+	if plan.Done {
+		if !rollingOut {
+			return true, nil
+		}
+
+		// The max size of the ASG got increased to make room for the
+		// replacement instances and is reset once the rollout is done.
+		maxSize := key.MachineDeploymentScalingMax(cr)
+		if int(aws.Int64Value(group.MaxSize)) > maxSize && int(aws.Int64Value(group.DesiredCapacity)) <= maxSize {
+			i := &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: group.AutoScalingGroupName,
+				MaxSize:              aws.Int64(int64(maxSize)),
+			}
+
+			_, err = cc.Client.TenantCluster.AWS.AutoScaling.UpdateAutoScalingGroup(i)
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+		}
+
+		err = r.updateRolloutCondition(ctx, cr, metav1.ConditionTrue, rolloutReasonCompleted, "all instances run the desired launch template version", nil, []string{awsoperatorannotation.RolloutDesiredCapacity})
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return true, nil
+	}
+
+	if !rollingOut {
+		message := fmt.Sprintf("replacing %d instances running an outdated launch template version", plan.Outdated)
+		set := map[string]string{
+			awsoperatorannotation.RolloutDesiredCapacity: strconv.Itoa(desiredCapacity),
+		}
+
+		err = r.updateRolloutCondition(ctx, cr, metav1.ConditionFalse, rolloutReasonRollingOut, message, set, nil)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return false, nil
+	}
+
+	if plan.Launch > 0 {
+		capacity := aws.Int64Value(group.DesiredCapacity) + int64(plan.Launch)
+
+		i := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: group.AutoScalingGroupName,
+			DesiredCapacity:      aws.Int64(capacity),
+			MaxSize:              aws.Int64(max(aws.Int64Value(group.MaxSize), capacity)),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.AutoScaling.UpdateAutoScalingGroup(i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		message := fmt.Sprintf("launching %d replacement instances, %d instances running an outdated launch template version left", plan.Launch, plan.Outdated)
+		err = r.updateRolloutCondition(ctx, cr, metav1.ConditionFalse, rolloutReasonRollingOut, message, nil, nil)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+	}
+
+	if len(plan.Terminate) > 0 {
+		// Terminating the instances puts them into the Terminating:Wait state of
+		// the node pool's lifecycle hook, so that they get drained via drainer
+		// configs before the termination proceeds.
+		for _, id := range plan.Terminate {
+			i := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+				InstanceId:                     aws.String(id),
+				ShouldDecrementDesiredCapacity: aws.Bool(true),
+			}
+
+			_, err = cc.Client.TenantCluster.AWS.AutoScaling.TerminateInstanceInAutoScalingGroup(i)
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+		}
+
+		message := fmt.Sprintf("draining and terminating instances %s, %d instances running an outdated launch template version left", strings.Join(plan.Terminate, ", "), plan.Outdated-len(plan.Terminate))
+		err = r.updateRolloutCondition(ctx, cr, metav1.ConditionFalse, rolloutReasonRollingOut, message, nil, nil)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+	}
+
+	if plan.Launch == 0 && len(plan.Terminate) == 0 {
+		r.logger.Debugf(ctx, "waiting for instances to launch, become ready or terminate")
+	}
+
+	return false, nil
+}
+
+// readyNodes returns the IDs of the EC2 instances backing the ready nodes of
+// the given node pool.
+func (r *Resource) readyNodes(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (map[string]bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	nodes := &corev1.NodeList{}
+	err = cc.Client.TenantCluster.K8s.CtrlClient().List(ctx, nodes, client.MatchingLabels{label.MachineDeployment: key.MachineDeploymentID(&cr)})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	ready := map[string]bool{}
+	for _, n := range nodes.Items {
+		// The provider ID of AWS nodes is formatted as
+		// aws:///<availability-zone>/<instance-id>.
+		id := n.Spec.ProviderID[strings.LastIndex(n.Spec.ProviderID, "/")+1:]
+
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+				ready[id] = true
+			}
+		}
+	}
+
+	return ready, nil
+}
+
+// updateRolloutCondition records the progress of the rollout as condition in
+// the annotations of the AWSMachineDeployment CR and emits it as event. The CR
+// status schema is owned upstream, which is why we use an annotation here.
+func (r *Resource) updateRolloutCondition(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment, status metav1.ConditionStatus, reason string, message string, set map[string]string, remove []string) error {
+	var current metav1.Condition
+	if v, ok := cr.GetAnnotations()[awsoperatorannotation.RolloutCondition]; ok {
+		err := json.Unmarshal([]byte(v), &current)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if current.Message == message && len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	r.logger.Debugf(ctx, "%s", message)
+
+	c := metav1.Condition{
+		Type:               rolloutConditionType,
+		Status:             status,
+		LastTransitionTime: current.LastTransitionTime,
+		Reason:             reason,
+		Message:            message,
+	}
+	if current.Status != status {
+		c.LastTransitionTime = metav1.NewTime(time.Now())
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	latest := &infrastructurev1alpha3.AWSMachineDeployment{}
+	err = r.k8sClient.CtrlClient().Get(ctx, client.ObjectKey{Name: cr.GetName(), Namespace: cr.GetNamespace()}, latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	for k, v := range set {
+		latest.Annotations[k] = v
+	}
+	for _, k := range remove {
+		delete(latest.Annotations, k)
+	}
+	latest.Annotations[awsoperatorannotation.RolloutCondition] = string(b)
+
+	err = r.k8sClient.CtrlClient().Update(ctx, latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.event.Emit(ctx, latest, "Rollout"+reason, message)
+
+	return nil
+}
+
+// launchTemplateVersion returns the launch template version new instances of
+// the given ASG are launched with.
+func launchTemplateVersion(group *autoscaling.Group) string {
+	if group.MixedInstancesPolicy != nil && group.MixedInstancesPolicy.LaunchTemplate != nil && group.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification != nil {
+		return aws.StringValue(group.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification.Version)
+	}
+	if group.LaunchTemplate != nil {
+		return aws.StringValue(group.LaunchTemplate.Version)
+	}
+
+	return ""
+}
+
+// findCluster returns the AWSCluster CR of the given node pool.
+func (r *Resource) findCluster(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (infrastructurev1alpha3.AWSCluster, error) {
+	var list infrastructurev1alpha3.AWSClusterList
+	err := r.k8sClient.CtrlClient().List(
+		ctx,
+		&list,
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{label.Cluster: key.ClusterID(&cr)},
+	)
+	if err != nil {
+		return infrastructurev1alpha3.AWSCluster{}, microerror.Mask(err)
+	}
+
+	if len(list.Items) != 1 {
+		return infrastructurev1alpha3.AWSCluster{}, microerror.Maskf(executionFailedError, "expected 1 CR got %d", len(list.Items))
+	}
+
+	return list.Items[0], nil
+}
@@ -0,0 +1,127 @@
+package tcnp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Controller_Resource_TCNP_planRollout(t *testing.T) {
+	instance := func(id string, state string, version string) *autoscaling.Instance {
+		return &autoscaling.Instance{
+			InstanceId:     aws.String(id),
+			LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String(version)},
+			LifecycleState: aws.String(state),
+		}
+	}
+
+	group := func(desiredCapacity int64, instances ...*autoscaling.Instance) *autoscaling.Group {
+		return &autoscaling.Group{
+			DesiredCapacity: aws.Int64(desiredCapacity),
+			Instances:       instances,
+			MixedInstancesPolicy: &autoscaling.MixedInstancesPolicy{
+				LaunchTemplate: &autoscaling.LaunchTemplate{
+					LaunchTemplateSpecification: &autoscaling.LaunchTemplateSpecification{Version: aws.String("2")},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		group           *autoscaling.Group
+		ready           map[string]bool
+		desiredCapacity int
+		batch           int
+		expected        rolloutPlan
+	}{
+		{
+			name: "case 0: all instances up to date",
+			group: group(2,
+				instance("i-1", autoscaling.LifecycleStateInService, "2"),
+				instance("i-2", autoscaling.LifecycleStateInService, "2"),
+			),
+			ready:           map[string]bool{"i-1": true, "i-2": true},
+			desiredCapacity: 2,
+			batch:           1,
+			expected:        rolloutPlan{Done: true},
+		},
+		{
+			name: "case 1: launch first batch",
+			group: group(3,
+				instance("i-1", autoscaling.LifecycleStateInService, "1"),
+				instance("i-2", autoscaling.LifecycleStateInService, "1"),
+				instance("i-3", autoscaling.LifecycleStateInService, "1"),
+			),
+			desiredCapacity: 3,
+			batch:           2,
+			expected:        rolloutPlan{Launch: 2, Outdated: 3},
+		},
+		{
+			name: "case 2: wait for replacements to become ready",
+			group: group(5,
+				instance("i-1", autoscaling.LifecycleStateInService, "1"),
+				instance("i-2", autoscaling.LifecycleStateInService, "1"),
+				instance("i-3", autoscaling.LifecycleStateInService, "1"),
+				instance("i-4", autoscaling.LifecycleStateInService, "2"),
+				instance("i-5", autoscaling.LifecycleStatePending, "2"),
+			),
+			ready:           map[string]bool{"i-4": true},
+			desiredCapacity: 3,
+			batch:           2,
+			expected:        rolloutPlan{Outdated: 3},
+		},
+		{
+			name: "case 3: terminate outdated instances once replacements are ready",
+			group: group(5,
+				instance("i-3", autoscaling.LifecycleStateInService, "1"),
+				instance("i-1", autoscaling.LifecycleStateInService, "1"),
+				instance("i-2", autoscaling.LifecycleStateInService, "1"),
+				instance("i-4", autoscaling.LifecycleStateInService, "2"),
+				instance("i-5", autoscaling.LifecycleStateInService, "2"),
+			),
+			ready:           map[string]bool{"i-4": true, "i-5": true},
+			desiredCapacity: 3,
+			batch:           2,
+			expected:        rolloutPlan{Outdated: 3, Terminate: []string{"i-1", "i-2"}},
+		},
+		{
+			name: "case 4: wait for outdated instances to be drained",
+			group: group(3,
+				instance("i-1", autoscaling.LifecycleStateTerminatingWait, "1"),
+				instance("i-3", autoscaling.LifecycleStateInService, "1"),
+				instance("i-4", autoscaling.LifecycleStateInService, "2"),
+				instance("i-5", autoscaling.LifecycleStateInService, "2"),
+			),
+			ready:           map[string]bool{"i-4": true, "i-5": true},
+			desiredCapacity: 3,
+			batch:           2,
+			expected:        rolloutPlan{Outdated: 1},
+		},
+		{
+			name: "case 5: last batch smaller than batch size",
+			group: group(3,
+				instance("i-3", autoscaling.LifecycleStateInService, "1"),
+				instance("i-4", autoscaling.LifecycleStateInService, "2"),
+				instance("i-5", autoscaling.LifecycleStateInService, "2"),
+			),
+			ready:           map[string]bool{"i-4": true, "i-5": true},
+			desiredCapacity: 3,
+			batch:           2,
+			expected:        rolloutPlan{Launch: 1, Outdated: 1},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			plan := planRollout(tc.group, tc.ready, tc.desiredCapacity, tc.batch)
+
+			if !cmp.Equal(plan, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, plan))
+			}
+		})
+	}
+}
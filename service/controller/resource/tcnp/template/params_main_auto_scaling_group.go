@@ -1,16 +1,25 @@
 package template
 
 type ParamsMainAutoScalingGroup struct {
-	AvailabilityZones     []string
-	Cluster               ParamsMainAutoScalingGroupCluster
-	DesiredCapacity       int
+	AvailabilityZones []string
+	// CapacityRebalance lets the ASG proactively replace spot instances which
+	// received a rebalance recommendation.
+	CapacityRebalance bool
+	Cluster           ParamsMainAutoScalingGroupCluster
+	DesiredCapacity   int
+	// InstanceRequirements is optional and replaces LaunchTemplateOverrides with
+	// the attributes of the instance types the ASG may launch.
+	InstanceRequirements  *ParamsMainAutoScalingGroupInstanceRequirements
 	LifeCycleHookName     string
 	MaxBatchSize          string
 	MaxSize               int
 	MinInstancesInService string
 	MinSize               int
 	NodePool              ParamsMainIAMPoliciesNodePool
-	Subnets               []string
+	// NodeTemplate describes the nodes of the ASG to the cluster autoscaler, so
+	// that it can scale the ASG up from zero.
+	NodeTemplate ParamsMainAutoScalingGroupNodeTemplate
+	Subnets      []string
 	// OnDemandPercentageAboveBaseCapacity controls the percentages of On-Demand
 	// Instances and Spot Instances for your additional capacity beyond
 	// OnDemandBaseCapacity.
@@ -19,6 +28,13 @@ type ParamsMainAutoScalingGroup struct {
 	// capacity that must be fulfilled by On-Demand Instances. This base portion is
 	// provisioned first as your group scales.
 	OnDemandBaseCapacity int
+	// OnDemandAllocationStrategy is either prioritized, in which case the ASG
+	// launches on-demand instances in the order of LaunchTemplateOverrides, or
+	// lowest-price.
+	OnDemandAllocationStrategy string
+	// OperatorRollout disables the rolling update of the ASG. Instances running
+	// an outdated launch template version are then replaced by the operator.
+	OperatorRollout bool
 	// PauseTime is defining the pause time between each batch in the ASG update
 	PauseTime string
 	// SpotAllocationStrategy If the allocation strategy is lowest-price, the Auto
@@ -26,24 +42,71 @@ type ParamsMainAutoScalingGroup struct {
 	// and evenly allocates your instances across the number of Spot pools that you
 	// specify. If the allocation strategy is capacity-optimized, the Auto Scaling
 	// group launches instances using Spot pools that are optimally chosen based on
-	// the available Spot capacity.
+	// the available Spot capacity. The price-capacity-optimized strategy
+	// considers both.
 	SpotAllocationStrategy string
+	// SpotMaxPrice is the maximum price per hour for spot instances. It is
+	// optional and defaults to the on-demand price.
+	SpotMaxPrice string
 	// SpotInstancePools The number of Spot pools to use to allocate your Spot
 	// capacity. The Spot pools are determined from the different instance types
 	// in the Overrides array of LaunchTemplate. The range is 1–20. The default
-	// value is 2.
+	// value is 2. It is only used with the lowest-price allocation strategy and
+	// left to AWS when it is 0.
 	SpotInstancePools int
 	// LaunchTemplateOverrides is an optional setting. Any parameters that you
 	// specify override the same parameters in the launch template. Currently,
 	// the only supported override is instance type. You can specify between 1 and
 	// 20 instance types.
 	LaunchTemplateOverrides []LaunchTemplateOverride
+	// WarmPool is optional and defines the pool of pre-initialized instances
+	// the ASG launches instances from on scale-ups.
+	WarmPool *ParamsMainAutoScalingGroupWarmPool
 }
 
 type ParamsMainAutoScalingGroupCluster struct {
 	ID string
 }
 
+type ParamsMainAutoScalingGroupInstanceRequirements struct {
+	// AcceleratorCount is optional and restricts the number of GPUs.
+	AcceleratorCount      *ParamsMainAutoScalingGroupRange
+	AllowedInstanceTypes  []string
+	BurstablePerformance  string
+	CPUManufacturers      []string
+	ExcludedInstanceTypes []string
+	InstanceGenerations   []string
+	MemoryMiB             ParamsMainAutoScalingGroupRange
+	VCPUCount             ParamsMainAutoScalingGroupRange
+}
+
+// ParamsMainAutoScalingGroupRange is an inclusive range. A Max of -1 leaves the
+// range unbounded.
+type ParamsMainAutoScalingGroupRange struct {
+	Min int
+	Max int
+}
+
+type ParamsMainAutoScalingGroupNodeTemplate struct {
+	Labels map[string]string
+	// Resources maps resource names like "cpu" or "memory" to the quantity a
+	// node of the ASG provides.
+	Resources map[string]string
+	// Taints maps taint keys to their value and effect in the "value:Effect"
+	// format.
+	Taints map[string]string
+}
+
+type ParamsMainAutoScalingGroupWarmPool struct {
+	// MaxGroupPreparedCapacity is the maximum number of instances in the ASG
+	// and its warm pool together. -1 means the ASG's max size.
+	MaxGroupPreparedCapacity int
+	MinSize                  int
+	// PoolState is the state of instances in the warm pool, either Stopped or
+	// Hibernated.
+	PoolState string
+}
+
 type LaunchTemplateOverride struct {
 	InstanceType string
 	// WeightedCapacity defines the number of capacity units, which gives the
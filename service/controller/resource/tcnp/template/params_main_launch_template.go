@@ -14,12 +14,15 @@ type ParamsMainLaunchTemplateBlockDeviceMapping struct {
 	Docker     ParamsMainLaunchTemplateBlockDeviceMappingDocker
 	Kubelet    ParamsMainLaunchTemplateBlockDeviceMappingKubelet
 	Logging    ParamsMainLaunchTemplateBlockDeviceMappingLogging
+	// Root is only rendered for hibernating instances.
+	Root ParamsMainLaunchTemplateBlockDeviceMappingRoot
 }
 
 type ParamsMainLaunchTemplateInstance struct {
-	Image      string
-	Monitoring bool
-	Type       string
+	Hibernation bool
+	Image       string
+	Monitoring  bool
+	Type        string
 }
 
 type ParamsMainLaunchTemplateMetadata struct {
@@ -31,7 +34,11 @@ type ParamsMainLaunchTemplateBlockDeviceMappingContainerd struct {
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingContainerdVolume struct {
-	Size string
+	IOPS       int
+	KMSKeyARN  string
+	Size       string
+	Throughput int
+	Type       string
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingDocker struct {
@@ -39,7 +46,11 @@ type ParamsMainLaunchTemplateBlockDeviceMappingDocker struct {
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingDockerVolume struct {
-	Size string
+	IOPS       int
+	KMSKeyARN  string
+	Size       string
+	Throughput int
+	Type       string
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingKubelet struct {
@@ -47,7 +58,11 @@ type ParamsMainLaunchTemplateBlockDeviceMappingKubelet struct {
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingKubeletVolume struct {
-	Size string
+	IOPS       int
+	KMSKeyARN  string
+	Size       string
+	Throughput int
+	Type       string
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingLogging struct {
@@ -55,6 +70,18 @@ type ParamsMainLaunchTemplateBlockDeviceMappingLogging struct {
 }
 
 type ParamsMainLaunchTemplateBlockDeviceMappingLoggingVolume struct {
+	IOPS       int
+	KMSKeyARN  string
+	Size       int
+	Throughput int
+	Type       string
+}
+
+type ParamsMainLaunchTemplateBlockDeviceMappingRoot struct {
+	Volume ParamsMainLaunchTemplateBlockDeviceMappingRootVolume
+}
+
+type ParamsMainLaunchTemplateBlockDeviceMappingRootVolume struct {
 	Size int
 }
 
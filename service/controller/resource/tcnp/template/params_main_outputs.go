@@ -1,10 +1,16 @@
 package template
 
 type ParamsMainOutputs struct {
-	DockerVolumeSizeGB string
-	Instance           ParamsMainOutputsInstance
-	OperatorVersion    string
-	ReleaseVersion     string
+	DockerVolumeSizeGB   string
+	DualStack            bool
+	Instance             ParamsMainOutputsInstance
+	InstanceRequirements string
+	NodeConfig           string
+	OperatorVersion      string
+	ReleaseVersion       string
+	SpotAllocation       string
+	VolumeProfiles       string
+	WarmPool             string
 }
 
 type ParamsMainOutputsInstance struct {
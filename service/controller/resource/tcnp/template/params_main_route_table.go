@@ -9,6 +9,7 @@ type ParamsMainRouteTablesListItem struct {
 	ClusterID        string
 	NodePoolID       string
 	Name             string
+	IPv6Route        ParamsMainRouteTablesListItemRoute
 	Route            ParamsMainRouteTablesListItemRoute
 	TCCP             ParamsMainRouteTablesListItemTCCP
 }
@@ -18,8 +19,13 @@ type ParamsMainRouteTablesListItemRoute struct {
 }
 
 type ParamsMainRouteTablesListItemTCCP struct {
-	NATGateway ParamsMainRouteTablesListItemTCCPNATGateway
-	VPC        ParamsMainRouteTablesListItemTCCPVPC
+	EgressOnlyInternetGateway ParamsMainRouteTablesListItemTCCPEgressOnlyInternetGateway
+	NATGateway                ParamsMainRouteTablesListItemTCCPNATGateway
+	VPC                       ParamsMainRouteTablesListItemTCCPVPC
+}
+
+type ParamsMainRouteTablesListItemTCCPEgressOnlyInternetGateway struct {
+	ID string
 }
 
 type ParamsMainRouteTablesListItemTCCPNATGateway struct {
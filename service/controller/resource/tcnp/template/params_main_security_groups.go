@@ -46,6 +46,7 @@ type ParamsMainSecurityGroupsTenantClusterNodePool struct {
 }
 
 type ParamsMainSecurityGroupsTenantClusterVPC struct {
-	ID   string
-	CIDR string
+	ID       string
+	CIDR     string
+	IPv6CIDR string
 }
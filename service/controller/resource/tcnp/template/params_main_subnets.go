@@ -7,6 +7,7 @@ type ParamsMainSubnets struct {
 type ParamsMainSubnetsListItem struct {
 	AvailabilityZone      string
 	CIDR                  string
+	IPv6CIDRIndex         int
 	Name                  string
 	RouteTable            ParamsMainSubnetsListItemRouteTable
 	RouteTableAssociation ParamsMainSubnetsListItemRouteTableAssociation
@@ -27,5 +28,6 @@ type ParamsMainSubnetsListItemTCCP struct {
 }
 
 type ParamsMainSubnetsListItemTCCPVPC struct {
-	ID string
+	ID       string
+	IPv6CIDR string
 }
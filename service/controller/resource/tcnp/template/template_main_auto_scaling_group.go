@@ -16,12 +16,69 @@ const TemplateMainAutoScalingGroup = `
       DesiredCapacity: {{ .AutoScalingGroup.DesiredCapacity }}
       MinSize: {{ .AutoScalingGroup.MinSize }}
       MaxSize: {{ .AutoScalingGroup.MaxSize }}
+      {{- if .AutoScalingGroup.WarmPool }}
+      # Warm pools cannot be used with mixed instances policies.
+      LaunchTemplate:
+        LaunchTemplateId: !Ref NodePoolLaunchTemplate
+        Version: !GetAtt NodePoolLaunchTemplate.LatestVersionNumber
+      {{- else }}
       MixedInstancesPolicy:
         LaunchTemplate:
           LaunchTemplateSpecification:
             LaunchTemplateId: !Ref NodePoolLaunchTemplate
             Version: !GetAtt NodePoolLaunchTemplate.LatestVersionNumber
-          {{- if  .AutoScalingGroup.LaunchTemplateOverrides }}
+          {{- if .AutoScalingGroup.InstanceRequirements }}
+          {{- with .AutoScalingGroup.InstanceRequirements }}
+          Overrides:
+            - InstanceRequirements:
+                VCpuCount:
+                  Min: {{ .VCPUCount.Min }}
+                  {{- if ne .VCPUCount.Max -1 }}
+                  Max: {{ .VCPUCount.Max }}
+                  {{- end }}
+                MemoryMiB:
+                  Min: {{ .MemoryMiB.Min }}
+                  {{- if ne .MemoryMiB.Max -1 }}
+                  Max: {{ .MemoryMiB.Max }}
+                  {{- end }}
+                CpuManufacturers:
+                {{- range $m := .CPUManufacturers }}
+                  - {{ $m }}
+                {{- end }}
+                {{- if .AllowedInstanceTypes }}
+                AllowedInstanceTypes:
+                {{- range $t := .AllowedInstanceTypes }}
+                  - "{{ $t }}"
+                {{- end }}
+                {{- end }}
+                {{- if .ExcludedInstanceTypes }}
+                ExcludedInstanceTypes:
+                {{- range $t := .ExcludedInstanceTypes }}
+                  - "{{ $t }}"
+                {{- end }}
+                {{- end }}
+                {{- if .InstanceGenerations }}
+                InstanceGenerations:
+                {{- range $g := .InstanceGenerations }}
+                  - {{ $g }}
+                {{- end }}
+                {{- end }}
+                {{- if .BurstablePerformance }}
+                BurstablePerformance: {{ .BurstablePerformance }}
+                {{- end }}
+                {{- if .AcceleratorCount }}
+                AcceleratorCount:
+                  Min: {{ .AcceleratorCount.Min }}
+                  {{- if ne .AcceleratorCount.Max -1 }}
+                  Max: {{ .AcceleratorCount.Max }}
+                  {{- end }}
+                {{- if ne .AcceleratorCount.Max 0 }}
+                AcceleratorTypes:
+                  - gpu
+                {{- end }}
+                {{- end }}
+          {{- end }}
+          {{- else if .AutoScalingGroup.LaunchTemplateOverrides }}
           Overrides:
           {{- range $s := .AutoScalingGroup.LaunchTemplateOverrides }}
             - InstanceType: {{ $s.InstanceType }}
@@ -31,8 +88,18 @@ const TemplateMainAutoScalingGroup = `
         InstancesDistribution:
           OnDemandBaseCapacity: {{ .AutoScalingGroup.OnDemandBaseCapacity }}
           OnDemandPercentageAboveBaseCapacity: {{ .AutoScalingGroup.OnDemandPercentageAboveBaseCapacity }}
+          OnDemandAllocationStrategy: {{ .AutoScalingGroup.OnDemandAllocationStrategy }}
           SpotAllocationStrategy: {{ .AutoScalingGroup.SpotAllocationStrategy }}
+          {{- if and (eq .AutoScalingGroup.SpotAllocationStrategy "lowest-price") .AutoScalingGroup.SpotInstancePools }}
           SpotInstancePools: {{ .AutoScalingGroup.SpotInstancePools }}
+          {{- end }}
+          {{- if .AutoScalingGroup.SpotMaxPrice }}
+          SpotMaxPrice: "{{ .AutoScalingGroup.SpotMaxPrice }}"
+          {{- end }}
+      {{- if .AutoScalingGroup.CapacityRebalance }}
+      CapacityRebalance: true
+      {{- end }}
+      {{- end }}
       # We define a lifecycle hook as part of the ASG in order to drain nodes
       # properly on Node Pool deletion. Earlier we defined a separate lifecycle
       # hook referencing the ASG name. In this setting when deleting a Node Pool
@@ -60,6 +127,22 @@ const TemplateMainAutoScalingGroup = `
         - Key: k8s.io/cluster-autoscaler/node-template/label/giantswarm.io/machine-deployment
           Value: {{ .AutoScalingGroup.NodePool.ID }}
           PropagateAtLaunch: false
+        {{- range $k, $v := .AutoScalingGroup.NodeTemplate.Labels }}
+        - Key: k8s.io/cluster-autoscaler/node-template/label/{{ $k }}
+          Value: "{{ $v }}"
+          PropagateAtLaunch: false
+        {{- end }}
+        {{- range $k, $v := .AutoScalingGroup.NodeTemplate.Resources }}
+        - Key: k8s.io/cluster-autoscaler/node-template/resources/{{ $k }}
+          Value: "{{ $v }}"
+          PropagateAtLaunch: false
+        {{- end }}
+        {{- range $k, $v := .AutoScalingGroup.NodeTemplate.Taints }}
+        - Key: k8s.io/cluster-autoscaler/node-template/taint/{{ $k }}
+          Value: "{{ $v }}"
+          PropagateAtLaunch: false
+        {{- end }}
+    {{- if not .AutoScalingGroup.OperatorRollout }}
     UpdatePolicy:
       AutoScalingRollingUpdate:
 
@@ -73,5 +156,15 @@ const TemplateMainAutoScalingGroup = `
         # After creating a new instance, pause the rolling update on the ASG for
         # specified time.
         PauseTime: {{ .AutoScalingGroup.PauseTime }}
+    {{- end }}
+  {{- if .AutoScalingGroup.WarmPool }}
+  NodePoolWarmPool:
+    Type: AWS::AutoScaling::WarmPool
+    Properties:
+      AutoScalingGroupName: !Ref NodePoolAutoScalingGroup
+      MaxGroupPreparedCapacity: {{ .AutoScalingGroup.WarmPool.MaxGroupPreparedCapacity }}
+      MinSize: {{ .AutoScalingGroup.WarmPool.MinSize }}
+      PoolState: {{ .AutoScalingGroup.WarmPool.PoolState }}
+  {{- end }}
 {{- end -}}
 `
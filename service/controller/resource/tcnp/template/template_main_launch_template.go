@@ -12,26 +12,74 @@ const TemplateMainLaunchTemplate = `
           Ebs:
             DeleteOnTermination: true
             Encrypted: true
+            {{- if .LaunchTemplate.BlockDeviceMapping.Docker.Volume.IOPS }}
+            Iops: {{ .LaunchTemplate.BlockDeviceMapping.Docker.Volume.IOPS }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Docker.Volume.KMSKeyARN }}
+            KmsKeyId: {{ .LaunchTemplate.BlockDeviceMapping.Docker.Volume.KMSKeyARN }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Docker.Volume.Throughput }}
+            Throughput: {{ .LaunchTemplate.BlockDeviceMapping.Docker.Volume.Throughput }}
+            {{- end }}
             VolumeSize: {{ .LaunchTemplate.BlockDeviceMapping.Docker.Volume.Size }}
-            VolumeType: gp3
+            VolumeType: {{ .LaunchTemplate.BlockDeviceMapping.Docker.Volume.Type }}
         - DeviceName: /dev/xvdg
           Ebs:
             DeleteOnTermination: true
             Encrypted: true
+            {{- if .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.IOPS }}
+            Iops: {{ .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.IOPS }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.KMSKeyARN }}
+            KmsKeyId: {{ .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.KMSKeyARN }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.Throughput }}
+            Throughput: {{ .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.Throughput }}
+            {{- end }}
             VolumeSize: {{ .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.Size }}
-            VolumeType: gp3
+            VolumeType: {{ .LaunchTemplate.BlockDeviceMapping.Kubelet.Volume.Type }}
         - DeviceName: /dev/xvdf
           Ebs:
             DeleteOnTermination: true
             Encrypted: true
+            {{- if .LaunchTemplate.BlockDeviceMapping.Logging.Volume.IOPS }}
+            Iops: {{ .LaunchTemplate.BlockDeviceMapping.Logging.Volume.IOPS }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Logging.Volume.KMSKeyARN }}
+            KmsKeyId: {{ .LaunchTemplate.BlockDeviceMapping.Logging.Volume.KMSKeyARN }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Logging.Volume.Throughput }}
+            Throughput: {{ .LaunchTemplate.BlockDeviceMapping.Logging.Volume.Throughput }}
+            {{- end }}
             VolumeSize: {{ .LaunchTemplate.BlockDeviceMapping.Logging.Volume.Size }}
-            VolumeType: gp3
+            VolumeType: {{ .LaunchTemplate.BlockDeviceMapping.Logging.Volume.Type }}
         - DeviceName: /dev/xvdi
           Ebs:
             DeleteOnTermination: true
             Encrypted: true
+            {{- if .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.IOPS }}
+            Iops: {{ .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.IOPS }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.KMSKeyARN }}
+            KmsKeyId: {{ .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.KMSKeyARN }}
+            {{- end }}
+            {{- if .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.Throughput }}
+            Throughput: {{ .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.Throughput }}
+            {{- end }}
             VolumeSize: {{ .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.Size }}
+            VolumeType: {{ .LaunchTemplate.BlockDeviceMapping.Containerd.Volume.Type }}
+        {{- if .LaunchTemplate.Instance.Hibernation }}
+        - DeviceName: /dev/xvda
+          Ebs:
+            DeleteOnTermination: true
+            Encrypted: true
+            VolumeSize: {{ .LaunchTemplate.BlockDeviceMapping.Root.Volume.Size }}
             VolumeType: gp3
+        {{- end }}
+        {{- if .LaunchTemplate.Instance.Hibernation }}
+        HibernationOptions:
+          Configured: true
+        {{- end }}
         IamInstanceProfile:
           Name: !Ref NodePoolInstanceProfile
         ImageId: {{ .LaunchTemplate.Instance.Image }}
@@ -4,13 +4,25 @@ const TemplateMainOutputs = `
 {{- define "outputs" -}}
   DockerVolumeSizeGB:
     Value: {{ .Outputs.DockerVolumeSizeGB }}
+  DualStack:
+    Value: {{ .Outputs.DualStack }}
   InstanceImage:
     Value: {{ .Outputs.Instance.Image }}
+  InstanceRequirements:
+    Value: "{{ .Outputs.InstanceRequirements }}"
   InstanceType:
     Value: {{ .Outputs.Instance.Type }}
+  NodeConfig:
+    Value: "{{ .Outputs.NodeConfig }}"
   OperatorVersion:
     Value: {{ .Outputs.OperatorVersion }}
   ReleaseVersion:
     Value: {{ .Outputs.ReleaseVersion }}
+  SpotAllocation:
+    Value: "{{ .Outputs.SpotAllocation }}"
+  VolumeProfiles:
+    Value: "{{ .Outputs.VolumeProfiles }}"
+  WarmPool:
+    Value: "{{ .Outputs.WarmPool }}"
 {{- end -}}
 `
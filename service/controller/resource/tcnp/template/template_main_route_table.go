@@ -20,6 +20,14 @@ const TemplateMainRouteTables = `
       RouteTableId: !Ref {{ .Name }}
       DestinationCidrBlock: 0.0.0.0/0
       NatGatewayId: {{ .TCCP.NATGateway.ID }}
+  {{- if .TCCP.EgressOnlyInternetGateway.ID }}
+  {{ .IPv6Route.Name }}:
+    Type: AWS::EC2::Route
+    Properties:
+      RouteTableId: !Ref {{ .Name }}
+      DestinationIpv6CidrBlock: ::/0
+      EgressOnlyInternetGatewayId: {{ .TCCP.EgressOnlyInternetGateway.ID }}
+  {{- end }}
   {{- end }}
 {{- end -}}
 `
@@ -19,6 +19,14 @@ const TemplateMainSecurityGroups = `
         FromPort: 2049
         ToPort: 2049
         CidrIp: {{ .SecurityGroups.TenantCluster.VPC.CIDR }}
+      {{- if .SecurityGroups.TenantCluster.VPC.IPv6CIDR }}
+      -
+        Description: Allow traffic from tenant cluster IPv6 CIDR to 2049 for NFS access.
+        IpProtocol: tcp
+        FromPort: 2049
+        ToPort: 2049
+        CidrIpv6: {{ .SecurityGroups.TenantCluster.VPC.IPv6CIDR }}
+      {{- end }}
       -
         Description: Allow traffic from control plane CIDR to 4194 for cadvisor scraping.
         IpProtocol: tcp
@@ -47,6 +55,16 @@ const TemplateMainSecurityGroups = `
         - Key: Name
           Value: {{ .SecurityGroups.ClusterID }}-worker
       VpcId: {{ .SecurityGroups.TenantCluster.VPC.ID }}
+  {{- if .SecurityGroups.TenantCluster.VPC.IPv6CIDR }}
+  GeneralIPv6EgressRule:
+    Type: AWS::EC2::SecurityGroupEgress
+    DependsOn: GeneralSecurityGroup
+    Properties:
+      Description: Allow all outbound IPv6 traffic.
+      GroupId: !Ref GeneralSecurityGroup
+      IpProtocol: -1
+      CidrIpv6: ::/0
+  {{- end }}
   GeneralInternalAPIIngressRule:
     Type: AWS::EC2::SecurityGroupIngress
     DependsOn: GeneralSecurityGroup
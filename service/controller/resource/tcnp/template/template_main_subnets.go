@@ -8,6 +8,10 @@ const TemplateMainSubnets = `
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if .TCCP.VPC.IPv6CIDR }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ "{{ .TCCP.VPC.IPv6CIDR }}", 256, 64 ] ]
+      {{- end }}
       MapPublicIpOnLaunch: false
       Tags:
       - Key: Name
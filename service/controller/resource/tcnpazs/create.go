@@ -36,14 +36,26 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 
 	// Split the node pool subnet by the number of availability zones for further
 	// mapping below.
+	var network net.IPNet
 	var subnets []net.IPNet
 	{
 		_, netip, err := net.ParseCIDR(key.MachineDeploymentSubnet(cr))
 		if err != nil {
 			return microerror.Mask(err)
 		}
+		network = *netip
 
-		subnets, err = ipam.Split(*netip, uint(len(key.MachineDeploymentAvailabilityZones(cr))))
+		subnets, err = ipam.Split(network, uint(len(key.MachineDeploymentAvailabilityZones(cr))))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	// The IPv6 CIDR blocks of the node pool's subnets are only needed in case
+	// the tenant cluster's VPC is dual-stack.
+	var ipv6NetworkIndex int
+	if cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR != "" {
+		ipv6NetworkIndex, err = r.ensureIPv6NetworkIndex(ctx, cr)
 		if err != nil {
 			return microerror.Mask(err)
 		}
@@ -79,7 +91,8 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				},
 				Subnet: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnet{
 					Private: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate{
-						CIDR: subnets[i],
+						CIDR:          subnets[i],
+						IPv6CIDRIndex: key.IPv6SubnetIndex(ipv6NetworkIndex, network, subnets[i]),
 					},
 				},
 			}
@@ -10,3 +10,12 @@ var invalidConfigError = &microerror.Error{
 func IsInvalidConfig(err error) bool {
 	return microerror.Cause(err) == invalidConfigError
 }
+
+var ipv6NetworksExhaustedError = &microerror.Error{
+	Kind: "ipv6NetworksExhaustedError",
+}
+
+// IsIPv6NetworksExhausted asserts ipv6NetworksExhaustedError.
+func IsIPv6NetworksExhausted(err error) bool {
+	return microerror.Cause(err) == ipv6NetworksExhaustedError
+}
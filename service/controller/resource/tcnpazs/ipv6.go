@@ -0,0 +1,94 @@
+package tcnpazs
+
+import (
+	"context"
+	"strconv"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+// ensureIPv6NetworkIndex returns the index of the range of IPv6 CIDR blocks of
+// the given node pool. All node pools of a cluster share the IPv6 CIDR block of
+// the VPC while living in separate stacks. That is why the index is allocated
+// once, as the lowest index not used by any other node pool of the cluster, and
+// persisted in the CR annotations. Node pools are reconciled one at a time, so
+// that concurrent allocations of the same index do not happen.
+func (r *Resource) ensureIPv6NetworkIndex(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (int, error) {
+	if key.MachineDeploymentIPv6NetworkIndex(cr) != "" {
+		index, err := strconv.Atoi(key.MachineDeploymentIPv6NetworkIndex(cr))
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+
+		return index, nil
+	}
+
+	used := map[int]bool{}
+	{
+		var list infrastructurev1alpha3.AWSMachineDeploymentList
+
+		err := r.ctrlClient.List(
+			ctx,
+			&list,
+			ctrlClient.InNamespace(cr.GetNamespace()),
+			ctrlClient.MatchingLabels{label.Cluster: key.ClusterID(&cr)},
+		)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+
+		for _, md := range list.Items {
+			if md.GetName() == cr.GetName() || key.MachineDeploymentIPv6NetworkIndex(md) == "" {
+				continue
+			}
+
+			index, err := strconv.Atoi(key.MachineDeploymentIPv6NetworkIndex(md))
+			if err != nil {
+				return 0, microerror.Mask(err)
+			}
+
+			used[index] = true
+		}
+	}
+
+	index := -1
+	for i := key.IPv6NetworkIndexNodePoolMin; i < key.IPv6NetworkCount; i++ {
+		if !used[i] {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return 0, microerror.Maskf(ipv6NetworksExhaustedError, "all %d ipv6 networks of cluster %#q are allocated", key.IPv6NetworkCount-key.IPv6NetworkIndexNodePoolMin, key.ClusterID(&cr))
+	}
+
+	{
+		r.logger.Debugf(ctx, "allocating ipv6 network index %d", index)
+
+		var md infrastructurev1alpha3.AWSMachineDeployment
+		err := r.ctrlClient.Get(ctx, ctrlClient.ObjectKey{Name: cr.GetName(), Namespace: cr.GetNamespace()}, &md)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+
+		if md.Annotations == nil {
+			md.Annotations = map[string]string{}
+		}
+		md.Annotations[annotation.IPv6NetworkIndex] = strconv.Itoa(index)
+
+		err = r.ctrlClient.Update(ctx, &md)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+
+		r.logger.Debugf(ctx, "allocated ipv6 network index %d", index)
+	}
+
+	return index, nil
+}
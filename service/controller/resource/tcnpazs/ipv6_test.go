@@ -0,0 +1,130 @@
+package tcnpazs
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/micrologger/microloggertest"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_Controller_Resource_TCNPAZs_ensureIPv6NetworkIndex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		index         string
+		otherIndexes  []string
+		expectedIndex int
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:          "case 0: the first node pool gets the first node pool index",
+			expectedIndex: key.IPv6NetworkIndexNodePoolMin,
+		},
+		{
+			name:          "case 1: an allocated index is kept",
+			index:         "7",
+			otherIndexes:  []string{"2", "3"},
+			expectedIndex: 7,
+		},
+		{
+			name:          "case 2: the lowest free index is allocated",
+			otherIndexes:  []string{"2", "4", ""},
+			expectedIndex: 3,
+		},
+		{
+			name:         "case 3: allocation fails when all indexes are allocated",
+			otherIndexes: allIndexes(),
+			errorMatcher: IsIPv6NetworksExhausted,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			cr := unittest.DefaultMachineDeployment()
+			if tc.index != "" {
+				cr.Annotations[annotation.IPv6NetworkIndex] = tc.index
+			}
+
+			k8sClient := unittest.FakeK8sClient()
+
+			err = k8sClient.CtrlClient().Create(context.Background(), cr.DeepCopy())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j, index := range tc.otherIndexes {
+				md := unittest.DefaultMachineDeployment()
+				md.Name = "np" + strconv.Itoa(j)
+				if index != "" {
+					md.Annotations[annotation.IPv6NetworkIndex] = index
+				}
+
+				err = k8sClient.CtrlClient().Create(context.Background(), &md)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					CtrlClient: k8sClient.CtrlClient(),
+					Logger:     microloggertest.New(),
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			index, err := r.ensureIPv6NetworkIndex(context.Background(), cr)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if index != tc.expectedIndex {
+				t.Fatalf("expected index %d, got %d", tc.expectedIndex, index)
+			}
+
+			var md infrastructurev1alpha3.AWSMachineDeployment
+			err = k8sClient.CtrlClient().Get(context.Background(), ctrlClient.ObjectKey{Name: cr.Name, Namespace: cr.Namespace}, &md)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key.MachineDeploymentIPv6NetworkIndex(md) != strconv.Itoa(tc.expectedIndex) {
+				t.Fatalf("expected persisted index %d, got %#q", tc.expectedIndex, key.MachineDeploymentIPv6NetworkIndex(md))
+			}
+		})
+	}
+}
+
+func allIndexes() []string {
+	var indexes []string
+	for i := key.IPv6NetworkIndexNodePoolMin; i < key.IPv6NetworkCount; i++ {
+		indexes = append(indexes, strconv.Itoa(i))
+	}
+	return indexes
+}
@@ -1,6 +1,8 @@
 package tcnpf
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 )
 
@@ -17,10 +20,9 @@ const (
 )
 
 type Config struct {
-	Event  recorder.Interface
-	Logger micrologger.Logger
-
-	InstallationName string
+	CloudTags cloudtags.Interface
+	Event     recorder.Interface
+	Logger    micrologger.Logger
 }
 
 // Resource implements the TCNPF resource, which stands for Tenant Cluster Node
@@ -28,22 +30,23 @@ type Config struct {
 // Connections made between the AWS Control Plane Accounts and the AWS Tenant
 // Cluster Accounts.
 type Resource struct {
-	event  recorder.Interface
-	logger micrologger.Logger
-
-	installationName string
+	cloudTags cloudtags.Interface
+	event     recorder.Interface
+	logger    micrologger.Logger
 }
 
 func New(config Config) (*Resource, error) {
+	if config.CloudTags == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	r := &Resource{
-		event:  config.Event,
-		logger: config.Logger,
-
-		installationName: config.InstallationName,
+		cloudTags: config.CloudTags,
+		event:     config.Event,
+		logger:    config.Logger,
 	}
 
 	return r, nil
@@ -53,8 +56,11 @@ func (r *Resource) Name() string {
 	return Name
 }
 
-func (r *Resource) getCloudFormationTags(cr infrastructurev1alpha3.AWSMachineDeployment) []*cloudformation.Tag {
-	tags := key.AWSTags(&cr, r.installationName)
+func (r *Resource) getCloudFormationTags(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) ([]*cloudformation.Tag, error) {
+	tags, err := r.cloudTags.NodePoolTags(ctx, cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 	tags[key.TagStack] = key.StackTCNPF
-	return awstags.NewCloudFormation(tags)
+	return awstags.NewCloudFormation(tags), nil
 }
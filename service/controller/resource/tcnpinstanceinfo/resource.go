@@ -3,14 +3,18 @@ package tcnpinstanceinfo
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -51,6 +55,23 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
+	var matchingInstanceTypes []string
+	{
+		ir, err := key.MachineDeploymentInstanceRequirements(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if ir != nil {
+			matchingInstanceTypes, err = r.matchingInstanceTypes(ctx, cr, ir)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		cc.Status.TenantCluster.TCNP.Instances.MatchingInstanceTypes = matchingInstanceTypes
+	}
+
 	i := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			{
@@ -83,7 +104,7 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 		},
 	}
 
-	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+	instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 	if IsNotFound(err) {
 		r.logger.Debugf(ctx, "worker asg not available yet")
 		r.logger.Debugf(ctx, "canceling resource")
@@ -92,12 +113,7 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	if len(o.Reservations) == 0 {
-		r.logger.Debugf(ctx, "worker asg not available yet")
-		r.logger.Debugf(ctx, "canceling resource")
-		return nil
-	}
-	if len(o.Reservations[0].Instances) == 0 {
+	if len(instances) == 0 {
 		r.logger.Debugf(ctx, "worker asg not available yet")
 		r.logger.Debugf(ctx, "canceling resource")
 		return nil
@@ -106,25 +122,150 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 	numberOfSpotInstances := 0
 	instanceTypes := []string{}
 
-	for _, reservation := range o.Reservations {
-		for _, instance := range reservation.Instances {
-			if instance.InstanceLifecycle != nil && *instance.InstanceLifecycle == "spot" {
-				numberOfSpotInstances++
-			}
-			if instance.InstanceType != nil && !containsString(instanceTypes, *instance.InstanceType) {
-				instanceTypes = append(instanceTypes, *instance.InstanceType)
-			}
+	for _, instance := range instances {
+		if instance.InstanceLifecycle != nil && *instance.InstanceLifecycle == "spot" {
+			numberOfSpotInstances++
+		}
+		if instance.InstanceType != nil && !containsString(instanceTypes, *instance.InstanceType) {
+			instanceTypes = append(instanceTypes, *instance.InstanceType)
+		}
+	}
+
+	var spotAllocation string
+	if cc.Status.TenantCluster.ASG.Name != "" {
+		i := &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: []*string{
+				aws.String(cc.Status.TenantCluster.ASG.Name),
+			},
+		}
+
+		groups, err := awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if len(groups) == 1 {
+			spotAllocation = key.MachineDeploymentSpotAllocationValue(groupSpotAllocation(groups[0]))
 		}
 	}
 
 	{
 		cc.Status.TenantCluster.TCNP.Instances.InstanceTypes = instanceTypes
 		cc.Status.TenantCluster.TCNP.Instances.NumberOfSpotInstances = numberOfSpotInstances
+		cc.Status.TenantCluster.TCNP.Instances.SpotAllocation = spotAllocation
+	}
+
+	if spotAllocation != "" {
+		r.logger.Debugf(ctx, "worker asg uses spot allocation %#q", spotAllocation)
 	}
 
 	return nil
 }
 
+// matchingInstanceTypes returns the sorted instance types currently matching
+// the given instance requirements in the tenant cluster's region. Like the
+// node pool's ASG they are restricted to instance types of the node pool's CPU
+// architecture.
+func (r *Resource) matchingInstanceTypes(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment, ir *key.InstanceRequirements) ([]string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	architecture := key.InstanceTypeArchitecture(key.MachineDeploymentInstanceType(cr))
+
+	req := &ec2.InstanceRequirementsRequest{
+		AllowedInstanceTypes:  aws.StringSlice(key.InstanceFamilyPatterns(ir.AllowedFamilies)),
+		CpuManufacturers:      aws.StringSlice(key.InstanceRequirementsCPUManufacturers(architecture)),
+		ExcludedInstanceTypes: aws.StringSlice(key.InstanceFamilyPatterns(ir.ExcludedFamilies)),
+		InstanceGenerations:   aws.StringSlice(ir.Generations),
+		MemoryMiB: &ec2.MemoryMiBRequest{
+			Min: aws.Int64(int64(ir.MemoryMiB.Min)),
+			Max: rangeMax(ir.MemoryMiB),
+		},
+		VCpuCount: &ec2.VCpuCountRangeRequest{
+			Min: aws.Int64(int64(ir.VCPUs.Min)),
+			Max: rangeMax(ir.VCPUs),
+		},
+	}
+	if ir.Burstable != "" {
+		req.BurstablePerformance = aws.String(ir.Burstable)
+	}
+	if ir.GPUs != nil {
+		req.AcceleratorCount = &ec2.AcceleratorCountRequest{
+			Min: aws.Int64(int64(ir.GPUs.Min)),
+			Max: rangeMax(*ir.GPUs),
+		}
+		if ir.GPUs.Max != 0 {
+			req.AcceleratorTypes = aws.StringSlice([]string{ec2.AcceleratorTypeGpu})
+		}
+	}
+
+	architectureType := ec2.ArchitectureTypeX8664
+	if architecture == key.ArchitectureARM64 {
+		architectureType = ec2.ArchitectureTypeArm64
+	}
+
+	i := &ec2.GetInstanceTypesFromInstanceRequirementsInput{
+		ArchitectureTypes:    aws.StringSlice([]string{architectureType}),
+		InstanceRequirements: req,
+		VirtualizationTypes:  aws.StringSlice([]string{ec2.VirtualizationTypeHvm}),
+	}
+
+	var instanceTypes []string
+	err = cc.Client.TenantCluster.AWS.EC2.GetInstanceTypesFromInstanceRequirementsPages(i, func(o *ec2.GetInstanceTypesFromInstanceRequirementsOutput, lastPage bool) bool {
+		for _, t := range o.InstanceTypes {
+			instanceTypes = append(instanceTypes, aws.StringValue(t.InstanceType))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	sort.Strings(instanceTypes)
+
+	r.logger.Debugf(ctx, "found %d instance types matching the instance requirements", len(instanceTypes))
+
+	return instanceTypes, nil
+}
+
+// rangeMax returns the upper bound of the given range, which is nil for
+// unbounded ranges.
+func rangeMax(r key.IntRange) *int64 {
+	if r.Max == -1 {
+		return nil
+	}
+
+	return aws.Int64(int64(r.Max))
+}
+
+// groupSpotAllocation returns the allocation settings applied to the given
+// ASG. ASGs without mixed instances policy, e.g. ones with warm pool, only
+// launch on-demand instances of the launch template's instance type.
+func groupSpotAllocation(asg *autoscaling.Group) key.SpotAllocation {
+	a := key.SpotAllocation{
+		CapacityRebalance:          aws.BoolValue(asg.CapacityRebalance),
+		OnDemandAllocationStrategy: key.OnDemandAllocationStrategyPrioritized,
+		SpotAllocationStrategy:     key.SpotAllocationStrategyLowestPrice,
+	}
+
+	if asg.MixedInstancesPolicy == nil || asg.MixedInstancesPolicy.InstancesDistribution == nil {
+		return a
+	}
+
+	d := asg.MixedInstancesPolicy.InstancesDistribution
+	if d.OnDemandAllocationStrategy != nil {
+		a.OnDemandAllocationStrategy = *d.OnDemandAllocationStrategy
+	}
+	if d.SpotAllocationStrategy != nil {
+		a.SpotAllocationStrategy = *d.SpotAllocationStrategy
+	}
+	a.SpotMaxPrice = aws.StringValue(d.SpotMaxPrice)
+
+	return a
+}
+
 func containsString(list []string, match string) bool {
 	for _, s := range list {
 		if s == match {
@@ -0,0 +1,51 @@
+package tcnpnodeconfig
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToMachineDeployment(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var data map[string]string
+	if name := key.MachineDeploymentNodeConfigMap(cr); name != "" {
+		r.logger.Debugf(ctx, "finding node config ConfigMap %#q", name)
+
+		var cm corev1.ConfigMap
+		err := r.k8sClient.CtrlClient().Get(ctx, client.ObjectKey{Namespace: cr.GetNamespace(), Name: name}, &cm)
+		if apierrors.IsNotFound(err) {
+			return microerror.Maskf(notFoundError, "ConfigMap %#q referenced by annotation %#q not found", name, annotation.NodeConfigMap)
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		data = cm.Data
+
+		r.logger.Debugf(ctx, "found node config ConfigMap %#q", name)
+	}
+
+	_, err = key.MachineDeploymentNodeConfig(cr, data)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	cc.Spec.TenantCluster.TCNP.NodeConfigData = data
+
+	return nil
+}
@@ -11,11 +11,17 @@ import (
 )
 
 const (
-	DockerVolumeSizeGBKey = "DockerVolumeSizeGB"
-	InstanceImageKey      = "InstanceImage"
-	InstanceTypeKey       = "InstanceType"
-	OperatorVersionKey    = "OperatorVersion"
-	ReleaseVersionKey     = "ReleaseVersion"
+	DockerVolumeSizeGBKey   = "DockerVolumeSizeGB"
+	DualStackKey            = "DualStack"
+	InstanceImageKey        = "InstanceImage"
+	InstanceTypeKey         = "InstanceType"
+	InstanceRequirementsKey = "InstanceRequirements"
+	NodeConfigKey           = "NodeConfig"
+	OperatorVersionKey      = "OperatorVersion"
+	ReleaseVersionKey       = "ReleaseVersion"
+	SpotAllocationKey       = "SpotAllocation"
+	VolumeProfilesKey       = "VolumeProfiles"
+	WarmPoolKey             = "WarmPool"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -75,6 +81,19 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, DualStackKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before dual-stack support do not have the
+			// output and do not provide IPv6 networking.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool DualStack output")
+			v = "false"
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.DualStack = v == "true"
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, InstanceImageKey)
 		if err != nil {
@@ -109,5 +128,73 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.ReleaseVersion = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, VolumeProfilesKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before volume profile support do not have the
+			// output and use the default volume profiles.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool VolumeProfiles output")
+			v = key.MachineDeploymentVolumeProfilesValue(nil)
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.WorkerInstance.VolumeProfiles = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, InstanceRequirementsKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before instance requirements support do not have
+			// the output and do not have instance requirements.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool InstanceRequirements output")
+			v = key.InstanceRequirementsDisabled
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.InstanceRequirements = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, NodeConfigKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before node config support do not have the output
+			// and use the default node config.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool NodeConfig output")
+			v = key.NodeConfigDefault
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.NodeConfig = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, SpotAllocationKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before configurable spot allocation do not have
+			// the output and use the default allocation settings.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool SpotAllocation output")
+			v = key.MachineDeploymentSpotAllocationValue(key.SpotAllocation{
+				OnDemandAllocationStrategy: key.OnDemandAllocationStrategyPrioritized,
+				SpotAllocationStrategy:     key.SpotAllocationStrategyLowestPrice,
+			})
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.SpotAllocation = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, WarmPoolKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// TCNP stacks created before warm pool support do not have the output
+			// and do not have a warm pool.
+			r.logger.Debugf(ctx, "did not find the tenant cluster's node pool WarmPool output")
+			v = key.WarmPoolDisabled
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.TCNP.WarmPool = v
+	}
+
 	return nil
 }
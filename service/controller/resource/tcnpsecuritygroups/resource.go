@@ -13,6 +13,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -69,7 +70,7 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 				},
 			},
 		}
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		groups, err := awslist.SecurityGroups(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
@@ -77,7 +78,7 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 		// Check if the Node Pool is being deleted. Therefore we check for the EC2
 		// instances' state and ignore all security groups of Node Pools that do not
 		// have any instance running.
-		for _, sg := range o.SecurityGroups {
+		for _, sg := range groups {
 			var machineDeploymentID string
 			{
 				for _, tag := range sg.Tags {
@@ -114,7 +115,7 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 				},
 			}
 
-			o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+			instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -123,7 +124,7 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 			// running instances. If there are no running or pending instances, the
 			// Node Pool might be deleted and we want to remove the security group
 			// rules as well.
-			if len(o.Reservations) > 0 && len(o.Reservations[0].Instances) > 0 {
+			if len(instances) > 0 {
 				desired = append(desired, *sg.GroupId)
 			}
 		}
@@ -162,23 +163,23 @@ func (r *Resource) addInfoToCtx(ctx context.Context, cr infrastructurev1alpha3.A
 				},
 			},
 		}
-		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		groups, err := awslist.SecurityGroups(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		if len(o.SecurityGroups) > 1 {
-			return microerror.Maskf(executionFailedError, "expected one security group, got %d", len(o.SecurityGroups))
+		if len(groups) > 1 {
+			return microerror.Maskf(executionFailedError, "expected one security group, got %d", len(groups))
 		}
 
-		if len(o.SecurityGroups) < 1 {
+		if len(groups) < 1 {
 			r.logger.Debugf(ctx, "did not find current node pool security group for machine deployment %#q yet", key.MachineDeploymentID(&cr))
 			r.logger.Debugf(ctx, "canceling resource")
 
 			return nil
 		}
 
-		sg = o.SecurityGroups[0]
+		sg = groups[0]
 
 		// Iterate over all security group ingress rules in this Node Pool. We are
 		// only interested in ingress rules that reference the security group of
@@ -3,12 +3,14 @@ package tcnpstatus
 import (
 	"context"
 	"reflect"
+	"strings"
 
 	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	"github.com/giantswarm/operatorkit/v7/pkg/controller/context/reconciliationcanceledcontext"
 
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 )
@@ -57,6 +59,37 @@ func (r *Resource) ensure(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
+	// The AWSMachineDeployment CR status has no field for the instance types
+	// matching the node pool's instance requirements, so that they are exposed
+	// via annotation instead.
+	{
+		matches := strings.Join(cc.Status.TenantCluster.TCNP.Instances.MatchingInstanceTypes, ",")
+		current, ok := cr.GetAnnotations()[annotation.InstanceRequirementsMatches]
+
+		if matches != current || (matches == "" && ok) {
+			r.logger.Debugf(ctx, "updating cr annotation %#q", annotation.InstanceRequirementsMatches)
+
+			if matches == "" {
+				delete(cr.Annotations, annotation.InstanceRequirementsMatches)
+			} else {
+				if cr.Annotations == nil {
+					cr.Annotations = map[string]string{}
+				}
+				cr.Annotations[annotation.InstanceRequirementsMatches] = matches
+			}
+
+			err := r.k8sClient.CtrlClient().Update(ctx, &cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.Debugf(ctx, "updated cr annotation %#q", annotation.InstanceRequirementsMatches)
+			r.logger.Debugf(ctx, "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+			return nil
+		}
+	}
+
 	{
 		instanceTypesEqual := reflect.DeepEqual(cr.Status.Provider.Worker.InstanceTypes, cc.Status.TenantCluster.TCNP.Instances.InstanceTypes)
 		numberInstancesEqual := cr.Status.Provider.Worker.SpotInstances == cc.Status.TenantCluster.TCNP.Instances.NumberOfSpotInstances
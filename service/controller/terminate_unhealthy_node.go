@@ -32,10 +32,11 @@ import (
 )
 
 type TerminateUnhealthyNodeConfig struct {
-	K8sClient k8sclient.Interface
-	Event     event.Interface
-	Locker    locker.Interface
-	Logger    micrologger.Logger
+	AWSClientPool *aws.Pool
+	K8sClient     k8sclient.Interface
+	Event         event.Interface
+	Locker        locker.Interface
+	Logger        micrologger.Logger
 
 	HostAWSConfig aws.Config
 }
@@ -155,6 +156,7 @@ func newTerminateUnhealthyNodeResources(config TerminateUnhealthyNodeConfig) ([]
 	var awsClientResource resource.Interface
 	{
 		c := awsclient.Config{
+			ClientPool:    config.AWSClientPool,
 			K8sClient:     config.K8sClient.K8sClient(),
 			Logger:        config.Logger,
 			ToClusterFunc: key.ToCluster,
@@ -17,6 +17,7 @@ import (
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/internal/asg/internal/cache"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 type Config struct {
@@ -170,12 +171,10 @@ func (a *ASG) lookupASGs(ctx context.Context, names []string) ([]*autoscaling.Gr
 			AutoScalingGroupNames: toPtrList(names),
 		}
 
-		o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(i)
+		asgs, err = awslist.AutoScalingGroups(ctx, cc.Client.TenantCluster.AWS.AutoScaling, i)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
-
-		asgs = o.AutoScalingGroups
 	}
 
 	if len(asgs) == 0 {
@@ -223,16 +222,11 @@ func (a *ASG) lookupInstances(ctx context.Context, cr metav1.Object) ([]*ec2.Ins
 		},
 	}
 
-	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+	instances, err := awslist.Instances(ctx, cc.Client.TenantCluster.AWS.EC2, i)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	var instances []*ec2.Instance
-	for _, r := range o.Reservations {
-		instances = append(instances, r.Instances...)
-	}
-
 	if len(instances) == 0 {
 		return nil, microerror.Mask(noASGError)
 	}
@@ -0,0 +1,40 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/giantswarm/microerror"
+)
+
+// AutoScalingGroups returns the auto scaling groups of all pages of
+// DescribeAutoScalingGroups.
+func AutoScalingGroups(ctx context.Context, client AutoScalingGroupsAPI, i *autoscaling.DescribeAutoScalingGroupsInput) ([]*autoscaling.Group, error) {
+	return defaultLister.AutoScalingGroups(ctx, client, i)
+}
+
+func (l *Lister) AutoScalingGroups(ctx context.Context, client AutoScalingGroupsAPI, i *autoscaling.DescribeAutoScalingGroupsInput) ([]*autoscaling.Group, error) {
+	in := *i
+
+	var groups []*autoscaling.Group
+	for {
+		var o *autoscaling.DescribeAutoScalingGroupsOutput
+		err := l.do(ctx, client, serviceAutoScaling, func() error {
+			var err error
+			o, err = client.DescribeAutoScalingGroups(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		groups = append(groups, o.AutoScalingGroups...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return groups, nil
+}
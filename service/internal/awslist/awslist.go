@@ -0,0 +1,231 @@
+// Package awslist lists AWS resources across all pages of the paginated AWS
+// APIs. Single page lookups silently ignore resources once the number of
+// results exceeds the page size of the API, e.g. record sets of large hosted
+// zones. Requests are rate limited per AWS account, region and service on the
+// client side and retried with jittered exponential backoff in case AWS
+// throttles them anyway.
+package awslist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/giantswarm/backoff"
+	"github.com/giantswarm/microerror"
+	"golang.org/x/time/rate"
+)
+
+const (
+	serviceAutoScaling    = "autoscaling"
+	serviceCloudFormation = "cloudformation"
+	serviceEC2            = "ec2"
+	serviceELB            = "elasticloadbalancing"
+	serviceIAM            = "iam"
+	serviceKMS            = "kms"
+	serviceRoute53        = "route53"
+)
+
+const (
+	// defaultBurst is the number of requests per AWS service which may be made
+	// at once before the rate limit applies.
+	defaultBurst = 10
+	// defaultLimit is the number of requests per second made against an AWS
+	// service.
+	defaultLimit rate.Limit = 20
+	// defaultRoute53Limit is the number of requests per second made against
+	// Route53. The Route53 API only allows 5 requests per second and AWS
+	// account.
+	defaultRoute53Limit rate.Limit = 5
+)
+
+const (
+	// limiterIdleTTL is the duration after which the rate limiters of an AWS
+	// account and region not requested anymore are dropped.
+	limiterIdleTTL = time.Hour
+)
+
+const (
+	// maxWait is the maximum time spent on retrying a single throttled request.
+	maxWait = 30 * time.Second
+	// maxInterval is the maximum time waited between retries of a single
+	// throttled request.
+	maxInterval = 5 * time.Second
+)
+
+var defaultLister = mustNew(Config{
+	Burst: defaultBurst,
+	Limits: map[string]rate.Limit{
+		serviceAutoScaling:    defaultLimit,
+		serviceCloudFormation: defaultLimit,
+		serviceEC2:            defaultLimit,
+		serviceELB:            defaultLimit,
+		serviceIAM:            defaultLimit,
+		serviceKMS:            defaultLimit,
+		serviceRoute53:        defaultRoute53Limit,
+	},
+	NewBackOff: func() backoff.BackOff {
+		return backoff.NewExponential(maxWait, maxInterval)
+	},
+})
+
+type Config struct {
+	// Burst is the number of requests per AWS service which may be made at
+	// once before the rate limit applies.
+	Burst int
+	// Limits are the number of requests per second allowed for each AWS
+	// service per AWS account and region.
+	Limits map[string]rate.Limit
+	// NewBackOff returns the backoff used to retry a throttled request. The
+	// default exponential backoff randomizes its intervals so that concurrent
+	// reconciliations do not retry in lockstep.
+	NewBackOff func() backoff.BackOff
+}
+
+// Lister executes the paginated AWS API calls of the package. Resources use
+// the package level functions which share a single Lister, so that the rate
+// limits apply across all of them.
+type Lister struct {
+	burst      int
+	limits     map[string]rate.Limit
+	newBackOff func() backoff.BackOff
+	now        func() time.Time
+
+	mutex    sync.Mutex
+	limiters map[limiterKey]*limiter
+}
+
+type limiter struct {
+	lastUsed time.Time
+	limiter  *rate.Limiter
+}
+
+// limiterKey identifies the rate limiter of an AWS service for an AWS account
+// and region. AWS clients are pooled per region and role ARN, see the Pool of
+// the client/aws package, so that all clients of an AWS account and region
+// share the same credentials. These credentials therefore identify the AWS
+// account. Clients not exposing their config share the limiter with the zero
+// account and region.
+type limiterKey struct {
+	credentials *credentials.Credentials
+	region      string
+	service     string
+}
+
+func New(config Config) (*Lister, error) {
+	if config.Burst <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Burst must be greater than 0", config)
+	}
+	if len(config.Limits) == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Limits must not be empty", config)
+	}
+	if config.NewBackOff == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NewBackOff must not be empty", config)
+	}
+
+	for _, s := range []string{serviceAutoScaling, serviceCloudFormation, serviceEC2, serviceELB, serviceIAM, serviceKMS, serviceRoute53} {
+		_, ok := config.Limits[s]
+		if !ok {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Limits must contain %#q", config, s)
+		}
+	}
+
+	l := &Lister{
+		burst:      config.Burst,
+		limits:     config.Limits,
+		newBackOff: config.NewBackOff,
+		now:        time.Now,
+
+		limiters: map[limiterKey]*limiter{},
+	}
+
+	return l, nil
+}
+
+// do executes the request o against the given AWS service once the rate limit
+// of the client's AWS account and region allows for it. Throttled requests
+// are retried. All other errors are returned right away.
+func (l *Lister) do(ctx context.Context, client interface{}, service string, o func() error) error {
+	rl := l.limiter(newLimiterKey(client, service))
+
+	op := func() error {
+		err := rl.Wait(ctx)
+		if err != nil {
+			return backoff.Permanent(microerror.Mask(err))
+		}
+
+		err = o()
+		if request.IsErrorThrottle(err) {
+			return err
+		} else if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		return nil
+	}
+
+	err := backoff.Retry(op, l.newBackOff())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// limiter returns the rate limiter of the given key, creating it if
+// necessary. Limiters not used for limiterIdleTTL are dropped, so that the
+// limiters of AWS accounts not reconciled anymore do not pile up.
+func (l *Lister) limiter(k limiterKey) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+
+	for lk, rl := range l.limiters {
+		if now.Sub(rl.lastUsed) > limiterIdleTTL {
+			delete(l.limiters, lk)
+		}
+	}
+
+	rl, ok := l.limiters[k]
+	if !ok {
+		rl = &limiter{
+			limiter: rate.NewLimiter(l.limits[k.service], l.burst),
+		}
+		l.limiters[k] = rl
+	}
+	rl.lastUsed = now
+
+	return rl.limiter
+}
+
+// newLimiterKey returns the limiter key of the given client and AWS service.
+// The AWS SDK clients expose their config via the requests they create.
+func newLimiterKey(client interface{}, service string) limiterKey {
+	k := limiterKey{
+		service: service,
+	}
+
+	c, ok := client.(interface {
+		NewRequest(operation *request.Operation, params interface{}, data interface{}) *request.Request
+	})
+	if ok {
+		r := c.NewRequest(&request.Operation{}, nil, nil)
+		k.credentials = r.Config.Credentials
+		k.region = aws.StringValue(r.Config.Region)
+	}
+
+	return k
+}
+
+func mustNew(config Config) *Lister {
+	l, err := New(config)
+	if err != nil {
+		panic(err)
+	}
+
+	return l
+}
@@ -0,0 +1,561 @@
+package awslist
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/backoff"
+	"github.com/giantswarm/microerror"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+)
+
+var executionFailedError = &microerror.Error{
+	Kind: "executionFailedError",
+}
+
+func isExecutionFailed(err error) bool {
+	return microerror.Cause(err) == executionFailedError
+}
+
+func isThrottle(err error) bool {
+	return request.IsErrorThrottle(microerror.Cause(err))
+}
+
+func Test_AWSList_ResourceRecordSets(t *testing.T) {
+	testCases := []struct {
+		name               string
+		ctx                context.Context
+		pages              [][]string
+		throttled          int
+		err                error
+		expectedRecordSets []string
+		expectedCalls      int
+		errorMatcher       func(error) bool
+	}{
+		{
+			name:               "case 0: single page",
+			ctx:                context.Background(),
+			pages:              [][]string{{"a.example.com.", "b.example.com."}},
+			expectedRecordSets: []string{"a.example.com.", "b.example.com."},
+			expectedCalls:      1,
+			errorMatcher:       nil,
+		},
+		{
+			name:               "case 1: multiple pages are followed",
+			ctx:                context.Background(),
+			pages:              [][]string{{"a.example.com."}, {"b.example.com.", "c.example.com."}, {"d.example.com."}},
+			expectedRecordSets: []string{"a.example.com.", "b.example.com.", "c.example.com.", "d.example.com."},
+			expectedCalls:      3,
+			errorMatcher:       nil,
+		},
+		{
+			name:               "case 2: throttled requests are retried",
+			ctx:                context.Background(),
+			pages:              [][]string{{"a.example.com."}, {"b.example.com."}},
+			throttled:          2,
+			expectedRecordSets: []string{"a.example.com.", "b.example.com."},
+			expectedCalls:      4,
+			errorMatcher:       nil,
+		},
+		{
+			name:          "case 3: requests throttled too often are not retried forever",
+			ctx:           context.Background(),
+			pages:         [][]string{{"a.example.com."}},
+			throttled:     5,
+			expectedCalls: 3,
+			errorMatcher:  isThrottle,
+		},
+		{
+			name:          "case 4: other errors are not retried",
+			ctx:           context.Background(),
+			pages:         [][]string{{"a.example.com."}},
+			err:           microerror.Mask(executionFailedError),
+			expectedCalls: 1,
+			errorMatcher:  isExecutionFailed,
+		},
+		{
+			name:          "case 5: canceled context stops rate limited requests",
+			ctx:           canceledContext(),
+			pages:         [][]string{{"a.example.com."}},
+			expectedCalls: 0,
+			errorMatcher:  func(err error) bool { return err != nil },
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			client := &fakeRoute53{
+				err:       tc.err,
+				pages:     tc.pages,
+				throttled: tc.throttled,
+			}
+
+			recordSets, err := newTestLister(t).ResourceRecordSets(tc.ctx, client, &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String("Z1")})
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if client.calls != tc.expectedCalls {
+				t.Fatalf("calls == %d, want %d", client.calls, tc.expectedCalls)
+			}
+
+			var names []string
+			for _, r := range recordSets {
+				names = append(names, aws.StringValue(r.Name))
+			}
+			if !cmp.Equal(names, tc.expectedRecordSets) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedRecordSets, names))
+			}
+		})
+	}
+}
+
+func Test_AWSList_HostedZonesByName(t *testing.T) {
+	client := &fakeRoute53{
+		pages: [][]string{{"a.example.com."}, {"b.example.com."}},
+	}
+
+	hostedZones, err := newTestLister(t).HostedZonesByName(context.Background(), client, &route53.ListHostedZonesByNameInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, z := range hostedZones {
+		names = append(names, aws.StringValue(z.Name))
+	}
+
+	expected := []string{"a.example.com.", "b.example.com."}
+	if !cmp.Equal(names, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, names))
+	}
+}
+
+func Test_AWSList_Instances(t *testing.T) {
+	client := &fakeEC2{
+		pages: [][]string{{"i-1", "i-2"}, {"i-3"}},
+	}
+
+	i := &ec2.DescribeInstancesInput{}
+	instances, err := newTestLister(t).Instances(context.Background(), client, i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, i := range instances {
+		ids = append(ids, aws.StringValue(i.InstanceId))
+	}
+
+	expected := []string{"i-1", "i-2", "i-3"}
+	if !cmp.Equal(ids, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, ids))
+	}
+	if i.NextToken != nil {
+		t.Fatalf("NextToken == %#q, want nil", aws.StringValue(i.NextToken))
+	}
+}
+
+func Test_AWSList_IpamPoolAllocations(t *testing.T) {
+	client := &fakeEC2{
+		pages: [][]string{{"10.1.0.0/24"}, {"10.1.1.0/24", "10.1.2.0/24"}},
+	}
+
+	i := &ec2.GetIpamPoolAllocationsInput{IpamPoolId: aws.String("ipam-pool-1")}
+	allocations, err := newTestLister(t).IpamPoolAllocations(context.Background(), client, i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cidrs []string
+	for _, a := range allocations {
+		cidrs = append(cidrs, aws.StringValue(a.Cidr))
+	}
+
+	expected := []string{"10.1.0.0/24", "10.1.1.0/24", "10.1.2.0/24"}
+	if !cmp.Equal(cidrs, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, cidrs))
+	}
+	if i.NextToken != nil {
+		t.Fatalf("NextToken == %#q, want nil", aws.StringValue(i.NextToken))
+	}
+}
+
+func Test_AWSList_ClassicLoadBalancers(t *testing.T) {
+	client := &fakeELB{
+		pages: [][]string{{"lb-1"}, {"lb-2"}, {"lb-3"}},
+	}
+
+	loadBalancers, err := newTestLister(t).ClassicLoadBalancers(context.Background(), client, &elb.DescribeLoadBalancersInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, lb := range loadBalancers {
+		names = append(names, aws.StringValue(lb.LoadBalancerName))
+	}
+
+	expected := []string{"lb-1", "lb-2", "lb-3"}
+	if !cmp.Equal(names, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, names))
+	}
+}
+
+func Test_AWSList_AttachedRolePolicies(t *testing.T) {
+	client := &fakeIAM{
+		pages: [][]string{{"arn:aws:iam::aws:policy/a"}, {"arn:aws:iam::aws:policy/b"}},
+	}
+
+	policies, err := newTestLister(t).AttachedRolePolicies(context.Background(), client, &iam.ListAttachedRolePoliciesInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var arns []string
+	for _, p := range policies {
+		arns = append(arns, aws.StringValue(p.PolicyArn))
+	}
+
+	expected := []string{"arn:aws:iam::aws:policy/a", "arn:aws:iam::aws:policy/b"}
+	if !cmp.Equal(arns, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, arns))
+	}
+}
+
+func Test_AWSList_ResourceTags(t *testing.T) {
+	client := &fakeKMS{
+		pages: [][]string{{"a", "b"}, {"c"}},
+	}
+
+	tags, err := newTestLister(t).ResourceTags(context.Background(), client, &kms.ListResourceTagsInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, t := range tags {
+		keys = append(keys, aws.StringValue(t.TagKey))
+	}
+
+	expected := []string{"a", "b", "c"}
+	if !cmp.Equal(keys, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, keys))
+	}
+}
+
+func Test_AWSList_limiter(t *testing.T) {
+	accountA := newTestSession("eu-central-1")
+	accountB := newTestSession("eu-central-1")
+	otherRegion := accountA.Copy(&aws.Config{Region: aws.String("eu-west-1")})
+
+	l := newTestLister(t)
+
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	a := l.limiter(newLimiterKey(ec2.New(accountA), serviceEC2))
+
+	if l.limiter(newLimiterKey(ec2.New(accountA), serviceEC2)) != a {
+		t.Fatalf("expected clients of the same account and region to share the limiter")
+	}
+	if l.limiter(newLimiterKey(ec2.New(accountB), serviceEC2)) == a {
+		t.Fatalf("expected clients of different accounts to use different limiters")
+	}
+	if l.limiter(newLimiterKey(ec2.New(otherRegion), serviceEC2)) == a {
+		t.Fatalf("expected clients of different regions to use different limiters")
+	}
+	if l.limiter(newLimiterKey(elb.New(accountA), serviceELB)) == a {
+		t.Fatalf("expected clients of different services to use different limiters")
+	}
+	if len(l.limiters) != 4 {
+		t.Fatalf("expected 4 limiters, got %d", len(l.limiters))
+	}
+
+	now = now.Add(limiterIdleTTL + time.Second)
+
+	if l.limiter(newLimiterKey(ec2.New(accountA), serviceEC2)) == a {
+		t.Fatalf("expected idle limiter to be dropped")
+	}
+	if len(l.limiters) != 1 {
+		t.Fatalf("expected 1 limiter, got %d", len(l.limiters))
+	}
+}
+
+func Test_AWSList_New(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: valid config",
+			config:       testConfig(),
+			errorMatcher: nil,
+		},
+		{
+			name: "case 1: missing service limit",
+			config: func() Config {
+				c := testConfig()
+				delete(c.Limits, serviceRoute53)
+				return c
+			}(),
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 2: missing burst",
+			config: func() Config {
+				c := testConfig()
+				c.Burst = 0
+				return c
+			}(),
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := New(tc.config)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+// newTestSession returns a session with its own credentials, as the clients of
+// different AWS accounts have.
+func newTestSession(region string) *session.Session {
+	c := &aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		Region:      aws.String(region),
+	}
+
+	return session.Must(session.NewSession(c))
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func newTestLister(t *testing.T) *Lister {
+	l, err := New(testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l
+}
+
+// testConfig returns a config allowing three attempts per request without
+// waiting in between.
+func testConfig() Config {
+	return Config{
+		Burst: 1,
+		Limits: map[string]rate.Limit{
+			serviceAutoScaling:    rate.Inf,
+			serviceCloudFormation: rate.Inf,
+			serviceEC2:            rate.Inf,
+			serviceELB:            rate.Inf,
+			serviceIAM:            rate.Inf,
+			serviceKMS:            rate.Inf,
+			serviceRoute53:        rate.Inf,
+		},
+		NewBackOff: func() backoff.BackOff {
+			return backoff.NewMaxRetries(3, 0)
+		},
+	}
+}
+
+// page returns the index of the page requested by the given token, which is
+// empty for the first page.
+func page(token *string) int {
+	if token == nil {
+		return 0
+	}
+
+	p, err := strconv.Atoi(*token)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// nextToken returns the token of the page following page p, or nil if p is
+// the last one.
+func nextToken(p int, pages [][]string) *string {
+	if p+1 >= len(pages) {
+		return nil
+	}
+
+	return aws.String(strconv.Itoa(p + 1))
+}
+
+type fakeEC2 struct {
+	pages [][]string
+}
+
+func (f *fakeEC2) DescribeInstances(i *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	p := page(i.NextToken)
+
+	r := &ec2.Reservation{}
+	for _, id := range f.pages[p] {
+		r.Instances = append(r.Instances, &ec2.Instance{InstanceId: aws.String(id)})
+	}
+
+	o := &ec2.DescribeInstancesOutput{
+		NextToken:    nextToken(p, f.pages),
+		Reservations: []*ec2.Reservation{r},
+	}
+
+	return o, nil
+}
+
+func (f *fakeEC2) GetIpamPoolAllocations(i *ec2.GetIpamPoolAllocationsInput) (*ec2.GetIpamPoolAllocationsOutput, error) {
+	p := page(i.NextToken)
+
+	o := &ec2.GetIpamPoolAllocationsOutput{
+		NextToken: nextToken(p, f.pages),
+	}
+	for _, cidr := range f.pages[p] {
+		o.IpamPoolAllocations = append(o.IpamPoolAllocations, &ec2.IpamPoolAllocation{Cidr: aws.String(cidr)})
+	}
+
+	return o, nil
+}
+
+type fakeELB struct {
+	pages [][]string
+}
+
+func (f *fakeELB) DescribeLoadBalancers(i *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	p := page(i.Marker)
+
+	o := &elb.DescribeLoadBalancersOutput{
+		NextMarker: nextToken(p, f.pages),
+	}
+	for _, n := range f.pages[p] {
+		o.LoadBalancerDescriptions = append(o.LoadBalancerDescriptions, &elb.LoadBalancerDescription{LoadBalancerName: aws.String(n)})
+	}
+
+	return o, nil
+}
+
+type fakeIAM struct {
+	pages [][]string
+}
+
+func (f *fakeIAM) ListAttachedRolePolicies(i *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	p := page(i.Marker)
+
+	o := &iam.ListAttachedRolePoliciesOutput{
+		IsTruncated: aws.Bool(nextToken(p, f.pages) != nil),
+		Marker:      nextToken(p, f.pages),
+	}
+	for _, a := range f.pages[p] {
+		o.AttachedPolicies = append(o.AttachedPolicies, &iam.AttachedPolicy{PolicyArn: aws.String(a)})
+	}
+
+	return o, nil
+}
+
+type fakeKMS struct {
+	pages [][]string
+}
+
+func (f *fakeKMS) ListResourceTags(i *kms.ListResourceTagsInput) (*kms.ListResourceTagsOutput, error) {
+	p := page(i.Marker)
+
+	o := &kms.ListResourceTagsOutput{
+		NextMarker: nextToken(p, f.pages),
+		Truncated:  aws.Bool(nextToken(p, f.pages) != nil),
+	}
+	for _, k := range f.pages[p] {
+		o.Tags = append(o.Tags, &kms.Tag{TagKey: aws.String(k), TagValue: aws.String("value")})
+	}
+
+	return o, nil
+}
+
+type fakeRoute53 struct {
+	calls     int
+	err       error
+	pages     [][]string
+	throttled int
+}
+
+func (f *fakeRoute53) ListHostedZonesByName(i *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	p := page(i.HostedZoneId)
+
+	o := &route53.ListHostedZonesByNameOutput{
+		IsTruncated:      aws.Bool(nextToken(p, f.pages) != nil),
+		NextDNSName:      aws.String(fmt.Sprintf("next-%d.", p)),
+		NextHostedZoneId: nextToken(p, f.pages),
+	}
+	for _, n := range f.pages[p] {
+		o.HostedZones = append(o.HostedZones, &route53.HostedZone{Name: aws.String(n)})
+	}
+
+	return o, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(i *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	f.calls++
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.throttled > 0 {
+		f.throttled--
+		return nil, awserr.New("Throttling", "Rate exceeded", nil)
+	}
+
+	p := page(i.StartRecordIdentifier)
+
+	o := &route53.ListResourceRecordSetsOutput{
+		IsTruncated:          aws.Bool(nextToken(p, f.pages) != nil),
+		NextRecordIdentifier: nextToken(p, f.pages),
+	}
+	for _, n := range f.pages[p] {
+		o.ResourceRecordSets = append(o.ResourceRecordSets, &route53.ResourceRecordSet{Name: aws.String(n)})
+	}
+
+	return o, nil
+}
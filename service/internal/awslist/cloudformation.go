@@ -0,0 +1,39 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/microerror"
+)
+
+// Stacks returns the stacks of all pages of DescribeStacks.
+func Stacks(ctx context.Context, client StacksAPI, i *cloudformation.DescribeStacksInput) ([]*cloudformation.Stack, error) {
+	return defaultLister.Stacks(ctx, client, i)
+}
+
+func (l *Lister) Stacks(ctx context.Context, client StacksAPI, i *cloudformation.DescribeStacksInput) ([]*cloudformation.Stack, error) {
+	in := *i
+
+	var stacks []*cloudformation.Stack
+	for {
+		var o *cloudformation.DescribeStacksOutput
+		err := l.do(ctx, client, serviceCloudFormation, func() error {
+			var err error
+			o, err = client.DescribeStacks(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		stacks = append(stacks, o.Stacks...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return stacks, nil
+}
@@ -0,0 +1,353 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/microerror"
+)
+
+// Instances returns the instances of all reservations of all pages of
+// DescribeInstances.
+func Instances(ctx context.Context, client InstancesAPI, i *ec2.DescribeInstancesInput) ([]*ec2.Instance, error) {
+	return defaultLister.Instances(ctx, client, i)
+}
+
+// IpamPoolAllocations returns the IPAM pool allocations of all pages of
+// GetIpamPoolAllocations.
+func IpamPoolAllocations(ctx context.Context, client IpamPoolAllocationsAPI, i *ec2.GetIpamPoolAllocationsInput) ([]*ec2.IpamPoolAllocation, error) {
+	return defaultLister.IpamPoolAllocations(ctx, client, i)
+}
+
+// NatGateways returns the NAT gateways of all pages of DescribeNatGateways.
+func NatGateways(ctx context.Context, client NatGatewaysAPI, i *ec2.DescribeNatGatewaysInput) ([]*ec2.NatGateway, error) {
+	return defaultLister.NatGateways(ctx, client, i)
+}
+
+// NetworkInterfaces returns the network interfaces of all pages of DescribeNetworkInterfaces.
+func NetworkInterfaces(ctx context.Context, client NetworkInterfacesAPI, i *ec2.DescribeNetworkInterfacesInput) ([]*ec2.NetworkInterface, error) {
+	return defaultLister.NetworkInterfaces(ctx, client, i)
+}
+
+// RouteTables returns the route tables of all pages of DescribeRouteTables.
+func RouteTables(ctx context.Context, client RouteTablesAPI, i *ec2.DescribeRouteTablesInput) ([]*ec2.RouteTable, error) {
+	return defaultLister.RouteTables(ctx, client, i)
+}
+
+// SecurityGroups returns the security groups of all pages of DescribeSecurityGroups.
+func SecurityGroups(ctx context.Context, client SecurityGroupsAPI, i *ec2.DescribeSecurityGroupsInput) ([]*ec2.SecurityGroup, error) {
+	return defaultLister.SecurityGroups(ctx, client, i)
+}
+
+// Snapshots returns the snapshots of all pages of DescribeSnapshots.
+func Snapshots(ctx context.Context, client SnapshotsAPI, i *ec2.DescribeSnapshotsInput) ([]*ec2.Snapshot, error) {
+	return defaultLister.Snapshots(ctx, client, i)
+}
+
+// Subnets returns the subnets of all pages of DescribeSubnets.
+func Subnets(ctx context.Context, client SubnetsAPI, i *ec2.DescribeSubnetsInput) ([]*ec2.Subnet, error) {
+	return defaultLister.Subnets(ctx, client, i)
+}
+
+// Volumes returns the volumes of all pages of DescribeVolumes.
+func Volumes(ctx context.Context, client VolumesAPI, i *ec2.DescribeVolumesInput) ([]*ec2.Volume, error) {
+	return defaultLister.Volumes(ctx, client, i)
+}
+
+// VpcPeeringConnections returns the VPC peering connections of all pages of DescribeVpcPeeringConnections.
+func VpcPeeringConnections(ctx context.Context, client VpcPeeringConnectionsAPI, i *ec2.DescribeVpcPeeringConnectionsInput) ([]*ec2.VpcPeeringConnection, error) {
+	return defaultLister.VpcPeeringConnections(ctx, client, i)
+}
+
+// Vpcs returns the VPCs of all pages of DescribeVpcs.
+func Vpcs(ctx context.Context, client VpcsAPI, i *ec2.DescribeVpcsInput) ([]*ec2.Vpc, error) {
+	return defaultLister.Vpcs(ctx, client, i)
+}
+
+func (l *Lister) Instances(ctx context.Context, client InstancesAPI, i *ec2.DescribeInstancesInput) ([]*ec2.Instance, error) {
+	in := *i
+
+	var instances []*ec2.Instance
+	for {
+		var o *ec2.DescribeInstancesOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeInstances(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, r := range o.Reservations {
+			instances = append(instances, r.Instances...)
+		}
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return instances, nil
+}
+
+func (l *Lister) IpamPoolAllocations(ctx context.Context, client IpamPoolAllocationsAPI, i *ec2.GetIpamPoolAllocationsInput) ([]*ec2.IpamPoolAllocation, error) {
+	in := *i
+
+	var allocations []*ec2.IpamPoolAllocation
+	for {
+		var o *ec2.GetIpamPoolAllocationsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.GetIpamPoolAllocations(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		allocations = append(allocations, o.IpamPoolAllocations...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return allocations, nil
+}
+
+func (l *Lister) NatGateways(ctx context.Context, client NatGatewaysAPI, i *ec2.DescribeNatGatewaysInput) ([]*ec2.NatGateway, error) {
+	in := *i
+
+	var natGateways []*ec2.NatGateway
+	for {
+		var o *ec2.DescribeNatGatewaysOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeNatGateways(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		natGateways = append(natGateways, o.NatGateways...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return natGateways, nil
+}
+
+func (l *Lister) NetworkInterfaces(ctx context.Context, client NetworkInterfacesAPI, i *ec2.DescribeNetworkInterfacesInput) ([]*ec2.NetworkInterface, error) {
+	in := *i
+
+	var networkInterfaces []*ec2.NetworkInterface
+	for {
+		var o *ec2.DescribeNetworkInterfacesOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeNetworkInterfaces(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		networkInterfaces = append(networkInterfaces, o.NetworkInterfaces...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return networkInterfaces, nil
+}
+
+func (l *Lister) RouteTables(ctx context.Context, client RouteTablesAPI, i *ec2.DescribeRouteTablesInput) ([]*ec2.RouteTable, error) {
+	in := *i
+
+	var routeTables []*ec2.RouteTable
+	for {
+		var o *ec2.DescribeRouteTablesOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeRouteTables(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		routeTables = append(routeTables, o.RouteTables...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return routeTables, nil
+}
+
+func (l *Lister) SecurityGroups(ctx context.Context, client SecurityGroupsAPI, i *ec2.DescribeSecurityGroupsInput) ([]*ec2.SecurityGroup, error) {
+	in := *i
+
+	var securityGroups []*ec2.SecurityGroup
+	for {
+		var o *ec2.DescribeSecurityGroupsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeSecurityGroups(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		securityGroups = append(securityGroups, o.SecurityGroups...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return securityGroups, nil
+}
+
+func (l *Lister) Snapshots(ctx context.Context, client SnapshotsAPI, i *ec2.DescribeSnapshotsInput) ([]*ec2.Snapshot, error) {
+	in := *i
+
+	var snapshots []*ec2.Snapshot
+	for {
+		var o *ec2.DescribeSnapshotsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeSnapshots(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		snapshots = append(snapshots, o.Snapshots...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return snapshots, nil
+}
+
+func (l *Lister) Subnets(ctx context.Context, client SubnetsAPI, i *ec2.DescribeSubnetsInput) ([]*ec2.Subnet, error) {
+	in := *i
+
+	var subnets []*ec2.Subnet
+	for {
+		var o *ec2.DescribeSubnetsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeSubnets(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		subnets = append(subnets, o.Subnets...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return subnets, nil
+}
+
+func (l *Lister) Volumes(ctx context.Context, client VolumesAPI, i *ec2.DescribeVolumesInput) ([]*ec2.Volume, error) {
+	in := *i
+
+	var volumes []*ec2.Volume
+	for {
+		var o *ec2.DescribeVolumesOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeVolumes(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		volumes = append(volumes, o.Volumes...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return volumes, nil
+}
+
+func (l *Lister) VpcPeeringConnections(ctx context.Context, client VpcPeeringConnectionsAPI, i *ec2.DescribeVpcPeeringConnectionsInput) ([]*ec2.VpcPeeringConnection, error) {
+	in := *i
+
+	var vpcPeeringConnections []*ec2.VpcPeeringConnection
+	for {
+		var o *ec2.DescribeVpcPeeringConnectionsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeVpcPeeringConnections(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		vpcPeeringConnections = append(vpcPeeringConnections, o.VpcPeeringConnections...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return vpcPeeringConnections, nil
+}
+
+func (l *Lister) Vpcs(ctx context.Context, client VpcsAPI, i *ec2.DescribeVpcsInput) ([]*ec2.Vpc, error) {
+	in := *i
+
+	var vpcs []*ec2.Vpc
+	for {
+		var o *ec2.DescribeVpcsOutput
+		err := l.do(ctx, client, serviceEC2, func() error {
+			var err error
+			o, err = client.DescribeVpcs(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		vpcs = append(vpcs, o.Vpcs...)
+
+		if o.NextToken == nil {
+			break
+		}
+		in.NextToken = o.NextToken
+	}
+
+	return vpcs, nil
+}
@@ -0,0 +1,104 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/giantswarm/microerror"
+)
+
+// ClassicLoadBalancers returns the load balancers of all pages of the classic
+// ELB API's DescribeLoadBalancers.
+func ClassicLoadBalancers(ctx context.Context, client ClassicLoadBalancersAPI, i *elb.DescribeLoadBalancersInput) ([]*elb.LoadBalancerDescription, error) {
+	return defaultLister.ClassicLoadBalancers(ctx, client, i)
+}
+
+// LoadBalancers returns the load balancers of all pages of the ELBv2 API's
+// DescribeLoadBalancers.
+func LoadBalancers(ctx context.Context, client LoadBalancersAPI, i *elbv2.DescribeLoadBalancersInput) ([]*elbv2.LoadBalancer, error) {
+	return defaultLister.LoadBalancers(ctx, client, i)
+}
+
+// TargetGroups returns the target groups of all pages of DescribeTargetGroups.
+func TargetGroups(ctx context.Context, client TargetGroupsAPI, i *elbv2.DescribeTargetGroupsInput) ([]*elbv2.TargetGroup, error) {
+	return defaultLister.TargetGroups(ctx, client, i)
+}
+
+func (l *Lister) ClassicLoadBalancers(ctx context.Context, client ClassicLoadBalancersAPI, i *elb.DescribeLoadBalancersInput) ([]*elb.LoadBalancerDescription, error) {
+	in := *i
+
+	var loadBalancers []*elb.LoadBalancerDescription
+	for {
+		var o *elb.DescribeLoadBalancersOutput
+		err := l.do(ctx, client, serviceELB, func() error {
+			var err error
+			o, err = client.DescribeLoadBalancers(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		loadBalancers = append(loadBalancers, o.LoadBalancerDescriptions...)
+
+		if o.NextMarker == nil {
+			break
+		}
+		in.Marker = o.NextMarker
+	}
+
+	return loadBalancers, nil
+}
+
+func (l *Lister) LoadBalancers(ctx context.Context, client LoadBalancersAPI, i *elbv2.DescribeLoadBalancersInput) ([]*elbv2.LoadBalancer, error) {
+	in := *i
+
+	var loadBalancers []*elbv2.LoadBalancer
+	for {
+		var o *elbv2.DescribeLoadBalancersOutput
+		err := l.do(ctx, client, serviceELB, func() error {
+			var err error
+			o, err = client.DescribeLoadBalancers(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		loadBalancers = append(loadBalancers, o.LoadBalancers...)
+
+		if o.NextMarker == nil {
+			break
+		}
+		in.Marker = o.NextMarker
+	}
+
+	return loadBalancers, nil
+}
+
+func (l *Lister) TargetGroups(ctx context.Context, client TargetGroupsAPI, i *elbv2.DescribeTargetGroupsInput) ([]*elbv2.TargetGroup, error) {
+	in := *i
+
+	var targetGroups []*elbv2.TargetGroup
+	for {
+		var o *elbv2.DescribeTargetGroupsOutput
+		err := l.do(ctx, client, serviceELB, func() error {
+			var err error
+			o, err = client.DescribeTargetGroups(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		targetGroups = append(targetGroups, o.TargetGroups...)
+
+		if o.NextMarker == nil {
+			break
+		}
+		in.Marker = o.NextMarker
+	}
+
+	return targetGroups, nil
+}
@@ -0,0 +1,41 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/giantswarm/microerror"
+)
+
+// AttachedRolePolicies returns the attached policies of all pages of
+// ListAttachedRolePolicies.
+func AttachedRolePolicies(ctx context.Context, client AttachedRolePoliciesAPI, i *iam.ListAttachedRolePoliciesInput) ([]*iam.AttachedPolicy, error) {
+	return defaultLister.AttachedRolePolicies(ctx, client, i)
+}
+
+func (l *Lister) AttachedRolePolicies(ctx context.Context, client AttachedRolePoliciesAPI, i *iam.ListAttachedRolePoliciesInput) ([]*iam.AttachedPolicy, error) {
+	in := *i
+
+	var policies []*iam.AttachedPolicy
+	for {
+		var o *iam.ListAttachedRolePoliciesOutput
+		err := l.do(ctx, client, serviceIAM, func() error {
+			var err error
+			o, err = client.ListAttachedRolePolicies(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		policies = append(policies, o.AttachedPolicies...)
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+		in.Marker = o.Marker
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,40 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/giantswarm/microerror"
+)
+
+// ResourceTags returns the tags of all pages of ListResourceTags.
+func ResourceTags(ctx context.Context, client ResourceTagsAPI, i *kms.ListResourceTagsInput) ([]*kms.Tag, error) {
+	return defaultLister.ResourceTags(ctx, client, i)
+}
+
+func (l *Lister) ResourceTags(ctx context.Context, client ResourceTagsAPI, i *kms.ListResourceTagsInput) ([]*kms.Tag, error) {
+	in := *i
+
+	var tags []*kms.Tag
+	for {
+		var o *kms.ListResourceTagsOutput
+		err := l.do(ctx, client, serviceKMS, func() error {
+			var err error
+			o, err = client.ListResourceTags(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		tags = append(tags, o.Tags...)
+
+		if !aws.BoolValue(o.Truncated) {
+			break
+		}
+		in.Marker = o.NextMarker
+	}
+
+	return tags, nil
+}
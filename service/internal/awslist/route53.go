@@ -0,0 +1,76 @@
+package awslist
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+)
+
+// HostedZonesByName returns the hosted zones of all pages of
+// ListHostedZonesByName.
+func HostedZonesByName(ctx context.Context, client HostedZonesByNameAPI, i *route53.ListHostedZonesByNameInput) ([]*route53.HostedZone, error) {
+	return defaultLister.HostedZonesByName(ctx, client, i)
+}
+
+// ResourceRecordSets returns the record sets of all pages of
+// ListResourceRecordSets.
+func ResourceRecordSets(ctx context.Context, client ResourceRecordSetsAPI, i *route53.ListResourceRecordSetsInput) ([]*route53.ResourceRecordSet, error) {
+	return defaultLister.ResourceRecordSets(ctx, client, i)
+}
+
+func (l *Lister) HostedZonesByName(ctx context.Context, client HostedZonesByNameAPI, i *route53.ListHostedZonesByNameInput) ([]*route53.HostedZone, error) {
+	in := *i
+
+	var hostedZones []*route53.HostedZone
+	for {
+		var o *route53.ListHostedZonesByNameOutput
+		err := l.do(ctx, client, serviceRoute53, func() error {
+			var err error
+			o, err = client.ListHostedZonesByName(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		hostedZones = append(hostedZones, o.HostedZones...)
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+		in.DNSName = o.NextDNSName
+		in.HostedZoneId = o.NextHostedZoneId
+	}
+
+	return hostedZones, nil
+}
+
+func (l *Lister) ResourceRecordSets(ctx context.Context, client ResourceRecordSetsAPI, i *route53.ListResourceRecordSetsInput) ([]*route53.ResourceRecordSet, error) {
+	in := *i
+
+	var recordSets []*route53.ResourceRecordSet
+	for {
+		var o *route53.ListResourceRecordSetsOutput
+		err := l.do(ctx, client, serviceRoute53, func() error {
+			var err error
+			o, err = client.ListResourceRecordSets(&in)
+			return err
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		recordSets = append(recordSets, o.ResourceRecordSets...)
+
+		if !aws.BoolValue(o.IsTruncated) {
+			break
+		}
+		in.StartRecordIdentifier = o.NextRecordIdentifier
+		in.StartRecordName = o.NextRecordName
+		in.StartRecordType = o.NextRecordType
+	}
+
+	return recordSets, nil
+}
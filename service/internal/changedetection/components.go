@@ -30,19 +30,36 @@ func releaseComponentsEqual(currentRelease releasev1alpha1.Release, targetReleas
 func componentsDiff(currentRelease releasev1alpha1.Release, targetRelease releasev1alpha1.Release) []string {
 	var diff []string
 
+	for _, c := range componentChanges(currentRelease, targetRelease) {
+		diff = append(diff, fmt.Sprintf("%s changed from %s to %s", c.Field, c.Old, c.New))
+	}
+
+	return diff
+}
+
+// componentChanges returns a typed change for every tracked component which
+// version differs between the given releases.
+func componentChanges(currentRelease releasev1alpha1.Release, targetRelease releasev1alpha1.Release) []Change {
+	var changes []Change
+
 	for _, current := range currentRelease.Spec.Components {
 		if findComponent(current.Name) {
 			for _, target := range targetRelease.Spec.Components {
 				if current.Name == target.Name {
 					if current.Version != target.Version {
-						diff = append(diff, fmt.Sprintf("%s version changed from %s to %s", current.Name, current.Version, target.Version))
+						changes = append(changes, Change{
+							Reason: ReasonComponentVersion,
+							Field:  fmt.Sprintf("%s version", current.Name),
+							Old:    current.Version,
+							New:    target.Version,
+						})
 					}
 				}
 			}
 		}
 	}
 
-	return diff
+	return changes
 }
 
 func findComponent(val string) bool {
@@ -0,0 +1,19 @@
+package changedetection
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	stackUpdateReasons = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aws_operator_stack_update_reasons_total",
+			Help: "Counter representing the detected reasons for Cloud Formation stack updates.",
+		},
+		[]string{"stack", "reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(stackUpdateReasons)
+}
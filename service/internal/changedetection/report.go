@@ -0,0 +1,241 @@
+package changedetection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+	"github.com/giantswarm/aws-operator/v16/service/internal/stackmetrics"
+)
+
+const (
+	ReasonAMI                  = "AMIChanged"
+	ReasonAPIWhitelist         = "APIWhitelistChanged"
+	ReasonAvailabilityZones    = "AvailabilityZonesChanged"
+	ReasonComponentVersion     = "ComponentVersionChanged"
+	ReasonDockerVolumeSize     = "DockerVolumeSizeChanged"
+	ReasonDualStack            = "DualStackChanged"
+	ReasonEtcdRestore          = "EtcdRestoreChanged"
+	ReasonInstanceRequirements = "InstanceRequirementsChanged"
+	ReasonInstanceType         = "InstanceTypeChanged"
+	ReasonInterruptionHandling = "InterruptionHandlingChanged"
+	ReasonLoadBalancerType     = "LoadBalancerTypeChanged"
+	ReasonMasterReplicas       = "MasterReplicasChanged"
+	ReasonNodeConfig           = "NodeConfigChanged"
+	ReasonOperatorVersion      = "OperatorVersionChanged"
+	ReasonSecurityGroups       = "SecurityGroupsChanged"
+	ReasonSpotAllocation       = "SpotAllocationChanged"
+	ReasonVolumeProfiles       = "VolumeProfilesChanged"
+	ReasonVPCEndpoints         = "VPCEndpointsChanged"
+	ReasonWarmPool             = "WarmPoolChanged"
+)
+
+const (
+	conditionReasonMultipleChanges = "MultipleChanges"
+	conditionTypeSuffix            = "StackUpdateRequired"
+)
+
+// Change is a single difference between the current and the desired state of
+// a Cloud Formation stack.
+type Change struct {
+	Reason string `json:"reason"`
+	Field  string `json:"field"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	if c.Old == "" && c.New == "" {
+		return fmt.Sprintf("%s changed", c.Field)
+	}
+
+	return fmt.Sprintf("%s changed from %#q to %#q", c.Field, c.Old, c.New)
+}
+
+// Report is the typed result of a change detection. It lists every detected
+// difference of a Cloud Formation stack instead of only the first one.
+type Report struct {
+	Stack   string   `json:"stack"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Reasons returns the reasons of all changes of the report.
+func (r Report) Reasons() []string {
+	var reasons []string
+	for _, c := range r.Changes {
+		reasons = append(reasons, c.Reason)
+	}
+
+	return reasons
+}
+
+// ShouldUpdate returns true in case the report contains at least one change.
+func (r Report) ShouldUpdate() bool {
+	return len(r.Changes) != 0
+}
+
+func (r Report) String() string {
+	var s []string
+	for _, c := range r.Changes {
+		s = append(s, c.String())
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func (r *Report) add(reason string, field string, old string, new string) {
+	r.Changes = append(r.Changes, Change{
+		Reason: reason,
+		Field:  field,
+		Old:    old,
+		New:    new,
+	})
+}
+
+// addComponentChanges adds a change for every differing component version of
+// the given releases. In case the releases differ without any tracked
+// component version change, e.g. because the current release is not known
+// yet, the change of the release version itself is added.
+func (r *Report) addComponentChanges(currentVersion string, targetVersion string, currentRelease releasev1alpha1.Release, targetRelease releasev1alpha1.Release) {
+	changes := componentChanges(currentRelease, targetRelease)
+	if len(changes) == 0 {
+		r.add(ReasonComponentVersion, "release version", currentVersion, targetVersion)
+		return
+	}
+
+	r.Changes = append(r.Changes, changes...)
+}
+
+// reporter surfaces change reports of the change detection implementations.
+// Reports are logged, exported as the stack's last update reasons and recorded
+// as condition in the annotations of the reconciled CR. Events and metrics are
+// only emitted when the recorded condition changes, so that a stack waiting
+// for its update is not counted again on every reconciliation.
+type reporter struct {
+	event     recorder.Interface
+	k8sClient k8sclient.Interface
+	logger    micrologger.Logger
+}
+
+func (r *reporter) Report(ctx context.Context, obj client.Object, report Report) error {
+	name := strings.ToUpper(report.Stack)
+
+	if report.ShouldUpdate() {
+		r.logger.LogCtx(ctx,
+			"level", "debug",
+			"message", fmt.Sprintf("detected %s stack should update", name),
+			"reason", report.String(),
+		)
+		stackmetrics.ReportUpdateReasons(key.ClusterID(obj), report.Stack, key.StackName(obj, report.Stack), report.Reasons())
+	}
+
+	changed, err := r.updateCondition(ctx, obj, report)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if changed && report.ShouldUpdate() {
+		r.event.Emit(ctx, obj, "CFUpdateRequested", fmt.Sprintf("detected %s stack should update: %s", name, report.String()))
+
+		for _, reason := range report.Reasons() {
+			stackUpdateReasons.WithLabelValues(report.Stack, reason).Inc()
+		}
+	}
+
+	return nil
+}
+
+// updateCondition records the report as condition in the annotations of the
+// given object and returns whether the condition changed. The condition is
+// removed once the stack does not need to be updated anymore. The CR status
+// schemas are owned upstream, which is why we use an annotation here.
+func (r *reporter) updateCondition(ctx context.Context, obj client.Object, report Report) (bool, error) {
+	annotation := key.StackUpdateConditionAnnotation(report.Stack)
+
+	_, ok := obj.GetAnnotations()[annotation]
+	if !ok && !report.ShouldUpdate() {
+		return false, nil
+	}
+
+	// The given object may be outdated in case it got reconciled before, e.g.
+	// by another change detection of the same controller. So we compare with
+	// the latest condition in order to not record the same change twice.
+	latest, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, microerror.Maskf(wrongTypeError, "expected %T to implement client.Object", obj)
+	}
+
+	err := r.k8sClient.CtrlClient().Get(ctx, client.ObjectKeyFromObject(obj), latest)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	current, ok := latest.GetAnnotations()[annotation]
+	if !ok && !report.ShouldUpdate() {
+		return false, nil
+	}
+
+	var desired string
+	if report.ShouldUpdate() {
+		var c metav1.Condition
+		if current != "" {
+			err := json.Unmarshal([]byte(current), &c)
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+		}
+
+		message := report.String()
+		if c.Message == message {
+			return false, nil
+		}
+
+		reason := conditionReasonMultipleChanges
+		if len(report.Changes) == 1 {
+			reason = report.Changes[0].Reason
+		}
+
+		c = metav1.Condition{
+			Type:               strings.ToUpper(report.Stack) + conditionTypeSuffix,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now()),
+			Reason:             reason,
+			Message:            message,
+		}
+
+		b, err := json.Marshal(c)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		desired = string(b)
+	}
+
+	a := latest.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	if desired == "" {
+		delete(a, annotation)
+	} else {
+		a[annotation] = desired
+	}
+	latest.SetAnnotations(a)
+
+	err = r.k8sClient.CtrlClient().Update(ctx, latest)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return true, nil
+}
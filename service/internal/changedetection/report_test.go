@@ -0,0 +1,170 @@
+package changedetection
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+type recorderMock struct {
+	messages []string
+}
+
+func (r *recorderMock) Emit(ctx context.Context, obj runtime.Object, reason, message string) {
+	r.messages = append(r.messages, message)
+}
+
+func Test_ChangeDetection_Report_String(t *testing.T) {
+	testCases := []struct {
+		name   string
+		report Report
+		result string
+	}{
+		{
+			name:   "case 0: empty report",
+			report: Report{Stack: key.StackTCNP},
+			result: "",
+		},
+		{
+			name: "case 1: all changes are listed",
+			report: Report{
+				Stack: key.StackTCNP,
+				Changes: []Change{
+					{Reason: ReasonAMI, Field: "ami", Old: "ami-1", New: "ami-2"},
+					{Reason: ReasonComponentVersion, Field: "kubernetes version", Old: "1.24.0", New: "1.25.0"},
+					{Reason: ReasonSecurityGroups, Field: "security groups"},
+				},
+			},
+			result: "ami changed from `ami-1` to `ami-2`, kubernetes version changed from `1.24.0` to `1.25.0`, security groups changed",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			result := tc.report.String()
+
+			if result != tc.result {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.result, result))
+			}
+		})
+	}
+}
+
+func Test_ChangeDetection_TCCP_ShouldUpdate(t *testing.T) {
+	testCases := []struct {
+		name              string
+		operatorVersion   string
+		expectedChanges   []Change
+		expectedCondition bool
+		expectedEvents    int
+	}{
+		{
+			name:              "case 0: unchanged stack does not update",
+			operatorVersion:   "6.3.0",
+			expectedChanges:   nil,
+			expectedCondition: false,
+			expectedEvents:    0,
+		},
+		{
+			name:            "case 1: changed operator version updates",
+			operatorVersion: "7.3.0",
+			expectedChanges: []Change{
+				{Reason: ReasonOperatorVersion, Field: "operator version", Old: "6.3.0", New: "7.3.0"},
+			},
+			expectedCondition: true,
+			expectedEvents:    1,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var err error
+
+			ctx := unittest.DefaultContext()
+			k := unittest.FakeK8sClient()
+			e := &recorderMock{}
+
+			cr := unittest.DefaultCluster()
+			cr.Labels[label.OperatorVersion] = tc.operatorVersion
+			err = k.CtrlClient().Create(ctx, &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var d *TCCP
+			{
+				c := TCCPConfig{
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+				}
+
+				d, err = NewTCCP(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := d.Detect(ctx, cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(report.Changes, tc.expectedChanges) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedChanges, report.Changes))
+			}
+
+			counter := stackUpdateReasons.WithLabelValues(key.StackTCCP, ReasonOperatorVersion)
+			before := testutil.ToFloat64(counter)
+
+			// Reconciling twice must neither emit events nor count reasons again
+			// as long as the detected changes stay the same.
+			for j := 0; j < 2; j++ {
+				update, err := d.ShouldUpdate(ctx, cr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if update != (len(tc.expectedChanges) != 0) {
+					t.Fatalf("expected %t got %t", len(tc.expectedChanges) != 0, update)
+				}
+			}
+			if len(e.messages) != tc.expectedEvents {
+				t.Fatalf("expected %d events got %d", tc.expectedEvents, len(e.messages))
+			}
+			if testutil.ToFloat64(counter)-before != float64(tc.expectedEvents) {
+				t.Fatalf("expected counter increase %d got %f", tc.expectedEvents, testutil.ToFloat64(counter)-before)
+			}
+
+			err = k.CtrlClient().Get(ctx, client.ObjectKeyFromObject(&cr), &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, ok := cr.Annotations[key.StackUpdateConditionAnnotation(key.StackTCCP)]
+			if ok != tc.expectedCondition {
+				t.Fatalf("expected condition %t got %t", tc.expectedCondition, ok)
+			}
+			if ok {
+				var c metav1.Condition
+				err = json.Unmarshal([]byte(v), &c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.Reason != ReasonOperatorVersion {
+					t.Fatalf("expected reason %#q got %#q", ReasonOperatorVersion, c.Reason)
+				}
+			}
+		})
+	}
+}
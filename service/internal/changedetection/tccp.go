@@ -2,9 +2,11 @@ package changedetection
 
 import (
 	"context"
-	"fmt"
+	"strconv"
+	"strings"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
@@ -14,64 +16,111 @@ import (
 )
 
 type TCCPConfig struct {
-	Event  recorder.Interface
-	Logger micrologger.Logger
+	Event     recorder.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
+
+	InterruptionHandlingEnabled bool
+	VPCEndpointsEnabled         bool
 }
 
 // TCCP is a detection service implementation deciding if the TCCP stack should
 // be updated.
 type TCCP struct {
-	event  recorder.Interface
-	logger micrologger.Logger
+	reporter *reporter
+
+	interruptionHandlingEnabled bool
+	vpcEndpointsEnabled         bool
 }
 
 func NewTCCP(config TCCPConfig) (*TCCP, error) {
 	if config.Event == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
 	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	t := &TCCP{
-		event:  config.Event,
-		logger: config.Logger,
+		reporter: &reporter{
+			event:     config.Event,
+			k8sClient: config.K8sClient,
+			logger:    config.Logger,
+		},
+
+		interruptionHandlingEnabled: config.InterruptionHandlingEnabled,
+		vpcEndpointsEnabled:         config.VPCEndpointsEnabled,
 	}
 
 	return t, nil
 }
 
-// ShouldUpdate determines whether the reconciled TCCP stack should be updated.
+// Detect computes the change report of the reconciled TCCP stack.
 //
 //	The node pool's combined availability zone configuration changes.
+//	The load balancer types of the API and etcd endpoints change.
 //	The operator's version changes.
-func (t *TCCP) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (bool, error) {
+//	The interface VPC endpoints get enabled or disabled.
+//	Dual-stack networking gets enabled.
+//	The interruption handling gets enabled or disabled.
+//	The cluster specific API whitelist changes.
+func (t *TCCP) Detect(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (Report, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
-		return false, microerror.Mask(err)
+		return Report{}, microerror.Mask(err)
 	}
 
-	azsEqual := availabilityZonesEqual(cc.Spec.TenantCluster.TCCP.AvailabilityZones, cc.Status.TenantCluster.TCCP.AvailabilityZones)
-	operatorVersionEqual := cc.Status.TenantCluster.OperatorVersion == key.OperatorVersion(&cr)
-
-	if !azsEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCP stack should update",
-			"reason", "availability zones changed",
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", "detected TCCP stack should update: availability zones changed")
-		return true, nil
+	report := Report{
+		Stack: key.StackTCCP,
+	}
+
+	if !availabilityZonesEqual(cc.Spec.TenantCluster.TCCP.AvailabilityZones, cc.Status.TenantCluster.TCCP.AvailabilityZones) {
+		report.add(ReasonAvailabilityZones, "availability zones", "", "")
+	}
+	{
+		current := cc.Status.TenantCluster.TCCP.LoadBalancers.Types
+		desired := key.LoadBalancerTypesTCCP(key.LoadBalancerType(cr), cc.Status.TenantCluster.TCCPN.LoadBalancerType)
+
+		// The current load balancer types are only known once the TCCP stack
+		// outputs got fetched.
+		if len(current) != 0 && strings.Join(current, ",") != strings.Join(desired, ",") {
+			report.add(ReasonLoadBalancerType, "load balancer types", strings.Join(current, ","), strings.Join(desired, ","))
+		}
+	}
+	if cc.Status.TenantCluster.OperatorVersion != key.OperatorVersion(&cr) {
+		report.add(ReasonOperatorVersion, "operator version", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr))
+	}
+	if cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR == "" && key.DualStackEnabled(cr, "") {
+		report.add(ReasonDualStack, "dual-stack", "false", "true")
+	}
+	if cc.Status.TenantCluster.TCCP.VPCEndpoints != key.VPCEndpointsEnabled(cr, t.vpcEndpointsEnabled) {
+		report.add(ReasonVPCEndpoints, "vpc endpoints", strconv.FormatBool(cc.Status.TenantCluster.TCCP.VPCEndpoints), strconv.FormatBool(key.VPCEndpointsEnabled(cr, t.vpcEndpointsEnabled)))
 	}
-	if !operatorVersionEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCP stack should update",
-			"reason", fmt.Sprintf("operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCCP stack should update: operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)))
-		return true, nil
+	if cc.Status.TenantCluster.TCCP.InterruptionHandling != key.InterruptionHandlingEnabled(cr, t.interruptionHandlingEnabled) {
+		report.add(ReasonInterruptionHandling, "interruption handling", strconv.FormatBool(cc.Status.TenantCluster.TCCP.InterruptionHandling), strconv.FormatBool(key.InterruptionHandlingEnabled(cr, t.interruptionHandlingEnabled)))
+	}
+	if cc.Status.TenantCluster.TCCP.APIWhitelistHash != cc.Spec.TenantCluster.TCCP.APIWhitelist.Hash {
+		report.add(ReasonAPIWhitelist, "api whitelist", cc.Status.TenantCluster.TCCP.APIWhitelistHash, cc.Spec.TenantCluster.TCCP.APIWhitelist.Hash)
+	}
+
+	return report, nil
+}
+
+// ShouldUpdate determines whether the reconciled TCCP stack should be updated.
+// The detected change report is surfaced as event, metric and condition.
+func (t *TCCP) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (bool, error) {
+	report, err := t.Detect(ctx, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	err = t.reporter.Report(ctx, &cr, report)
+	if err != nil {
+		return false, microerror.Mask(err)
 	}
 
-	return false, nil
+	return report.ShouldUpdate(), nil
 }
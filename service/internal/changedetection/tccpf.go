@@ -2,67 +2,88 @@ package changedetection
 
 import (
 	"context"
-	"fmt"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 )
 
 type TCCPFConfig struct {
-	Logger micrologger.Logger
+	Event     recorder.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
 }
 
 // TCCPF is a detection service implementation deciding if the TCCPF stack
 // should be updated.
 type TCCPF struct {
-	logger micrologger.Logger
+	reporter *reporter
 }
 
 func NewTCCPF(config TCCPFConfig) (*TCCPF, error) {
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	t := &TCCPF{
-		logger: config.Logger,
+		reporter: &reporter{
+			event:     config.Event,
+			k8sClient: config.K8sClient,
+			logger:    config.Logger,
+		},
 	}
 
 	return t, nil
 }
 
-// ShouldUpdate determines whether the reconciled TCCPF stack should be updated.
+// Detect computes the change report of the reconciled TCCPF stack.
 //
 //	The node pool's combined availability zone configuration changes.
 //	The operator's version changes.
-func (t *TCCPF) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (bool, error) {
+func (t *TCCPF) Detect(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (Report, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
-		return false, microerror.Mask(err)
+		return Report{}, microerror.Mask(err)
+	}
+
+	report := Report{
+		Stack: key.StackTCCPF,
+	}
+
+	if !availabilityZonesEqual(cc.Spec.TenantCluster.TCCP.AvailabilityZones, cc.Status.TenantCluster.TCCP.AvailabilityZones) {
+		report.add(ReasonAvailabilityZones, "availability zones", "", "")
+	}
+	if cc.Status.TenantCluster.OperatorVersion != key.OperatorVersion(&cr) {
+		report.add(ReasonOperatorVersion, "operator version", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr))
 	}
 
-	azsEqual := availabilityZonesEqual(cc.Spec.TenantCluster.TCCP.AvailabilityZones, cc.Status.TenantCluster.TCCP.AvailabilityZones)
-	operatorVersionEqual := cc.Status.TenantCluster.OperatorVersion == key.OperatorVersion(&cr)
+	return report, nil
+}
 
-	if !azsEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCPF stack should update",
-			"reason", "availability zones changed",
-		)
-		return true, nil
+// ShouldUpdate determines whether the reconciled TCCPF stack should be
+// updated. The detected change report is surfaced as event, metric and
+// condition.
+func (t *TCCPF) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSCluster) (bool, error) {
+	report, err := t.Detect(ctx, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
 	}
-	if !operatorVersionEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCPF stack should update",
-			"reason", fmt.Sprintf("operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)),
-		)
-		return true, nil
+
+	err = t.reporter.Report(ctx, &cr, report)
+	if err != nil {
+		return false, microerror.Mask(err)
 	}
 
-	return false, nil
+	return report.ShouldUpdate(), nil
 }
@@ -2,10 +2,10 @@ package changedetection
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"strconv"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
@@ -18,19 +18,19 @@ import (
 )
 
 type TCCPNConfig struct {
-	Event    recorder.Interface
-	HAMaster hamaster.Interface
-	Logger   micrologger.Logger
-	Releases releases.Interface
+	Event     recorder.Interface
+	HAMaster  hamaster.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
+	Releases  releases.Interface
 }
 
 // TCCPN is a detection service implementation deciding if the TCCPN stack
 // should be updated.
 type TCCPN struct {
-	event    recorder.Interface
 	haMaster hamaster.Interface
-	logger   micrologger.Logger
 	releases releases.Interface
+	reporter *reporter
 }
 
 func NewTCCPN(config TCCPNConfig) (*TCCPN, error) {
@@ -40,6 +40,9 @@ func NewTCCPN(config TCCPNConfig) (*TCCPN, error) {
 	if config.HAMaster == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.HAMaster must not be empty", config)
 	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
@@ -48,30 +51,37 @@ func NewTCCPN(config TCCPNConfig) (*TCCPN, error) {
 	}
 
 	t := &TCCPN{
-		event:    config.Event,
 		haMaster: config.HAMaster,
-		logger:   config.Logger,
 		releases: config.Releases,
+		reporter: &reporter{
+			event:     config.Event,
+			k8sClient: config.K8sClient,
+			logger:    config.Logger,
+		},
 	}
 
 	return t, nil
 }
 
-// ShouldUpdate determines whether the reconciled TCCPN stack should be updated.
+// Detect computes the change report of the reconciled TCCPN stack.
 //
+//	The release's component versions change.
 //	The master node's instance type changes.
+//	The load balancer type the master nodes are attached to changes.
+//	The master node's replicas change.
 //	The operator's version changes.
-func (t *TCCPN) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) (bool, error) {
+//	The restore state of the etcd volumes changes.
+func (t *TCCPN) Detect(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) (Report, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
-		return false, microerror.Mask(err)
+		return Report{}, microerror.Mask(err)
 	}
 
 	var rep int
 	{
 		rep, err = t.haMaster.Replicas(ctx, &cr)
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
@@ -79,7 +89,7 @@ func (t *TCCPN) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSC
 	{
 		currentRelease, err = t.releases.Release(ctx, cc.Status.TenantCluster.ReleaseVersion)
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
@@ -87,53 +97,56 @@ func (t *TCCPN) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSC
 	{
 		targetRelease, err = t.releases.Release(ctx, key.ReleaseVersion(&cr))
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
-	componentVersionsEqual := releaseComponentsEqual(currentRelease, targetRelease)
-	masterInstanceEqual := cc.Status.TenantCluster.TCCPN.InstanceType == key.ControlPlaneInstanceType(cr)
-	masterReplicasEqual := cc.Status.TenantCluster.TCCPN.MasterReplicas == rep
-	operatorVersionEqual := cc.Status.TenantCluster.OperatorVersion == key.OperatorVersion(&cr)
-
-	if !componentVersionsEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCPN stack should update",
-			"reason", strings.Join(componentsDiff(currentRelease, targetRelease), ", "),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCCPN stack should update: %s", strings.Join(componentsDiff(currentRelease, targetRelease), ", ")))
-		return true, nil
-	}
-	if !masterInstanceEqual {
-		t.logger.LogCtx(
-			ctx,
-			"level", "debug",
-			"message", "detected TCCPN stack should update",
-			"reason", fmt.Sprintf("master instance type changed from %#q to %#q", cc.Status.TenantCluster.TCCPN.InstanceType, key.ControlPlaneInstanceType(cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCCPN stack should update: master instance type changed from %#q to %#q", cc.Status.TenantCluster.TCCPN.InstanceType, key.ControlPlaneInstanceType(cr)))
-		return true, nil
-	}
-	if !masterReplicasEqual {
-		t.logger.LogCtx(
-			ctx,
-			"level", "debug",
-			"message", "detected TCCPN stack should update",
-			"reason", fmt.Sprintf("master replicas changed from %d to %d", cc.Status.TenantCluster.TCCPN.MasterReplicas, rep),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCCPN stack should update: master replicas changed from %d to %d", cc.Status.TenantCluster.TCCPN.MasterReplicas, rep))
-		return true, nil
-	}
-	if !operatorVersionEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCCPN stack should update",
-			"reason", fmt.Sprintf("operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCCPN stack should update: operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)))
-		return true, nil
-	}
-
-	return false, nil
+	report := Report{
+		Stack: key.StackTCCPN,
+	}
+
+	if !releaseComponentsEqual(currentRelease, targetRelease) {
+		report.addComponentChanges(cc.Status.TenantCluster.ReleaseVersion, key.ReleaseVersion(&cr), currentRelease, targetRelease)
+	}
+	if cc.Status.TenantCluster.TCCPN.InstanceType != key.ControlPlaneInstanceType(cr) {
+		report.add(ReasonInstanceType, "master instance type", cc.Status.TenantCluster.TCCPN.InstanceType, key.ControlPlaneInstanceType(cr))
+	}
+	{
+		current := cc.Status.TenantCluster.TCCPN.LoadBalancerType
+		desired := key.LoadBalancerTypeTCCPN(cc.Status.TenantCluster.TCCP.LoadBalancers.Types, current)
+
+		// The current load balancer type is only known once the TCCPN stack
+		// outputs got fetched.
+		if current != "" && current != desired {
+			report.add(ReasonLoadBalancerType, "load balancer type", current, desired)
+		}
+	}
+	if cc.Status.TenantCluster.TCCPN.MasterReplicas != rep {
+		report.add(ReasonMasterReplicas, "master replicas", strconv.Itoa(cc.Status.TenantCluster.TCCPN.MasterReplicas), strconv.Itoa(rep))
+	}
+	if cc.Status.TenantCluster.OperatorVersion != key.OperatorVersion(&cr) {
+		report.add(ReasonOperatorVersion, "operator version", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr))
+	}
+	if cc.Status.TenantCluster.TCCPN.EtcdRestore != key.ControlPlaneEtcdRestoreState(cr) {
+		report.add(ReasonEtcdRestore, "etcd restore", cc.Status.TenantCluster.TCCPN.EtcdRestore, key.ControlPlaneEtcdRestoreState(cr))
+	}
+
+	return report, nil
+}
+
+// ShouldUpdate determines whether the reconciled TCCPN stack should be
+// updated. The detected change report is surfaced as event, metric and
+// condition.
+func (t *TCCPN) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSControlPlane) (bool, error) {
+	report, err := t.Detect(ctx, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	err = t.reporter.Report(ctx, &cr, report)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return report.ShouldUpdate(), nil
 }
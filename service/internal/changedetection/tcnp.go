@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
-	"strings"
+	"strconv"
 
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
@@ -19,23 +22,28 @@ import (
 )
 
 type TCNPConfig struct {
-	Event    recorder.Interface
-	Logger   micrologger.Logger
-	Releases releases.Interface
+	Event     recorder.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
+	Releases  releases.Interface
 }
 
 // TCNP is a detection service implementation deciding if the TCNP stack should
 // be updated.
 type TCNP struct {
-	event    recorder.Interface
-	logger   micrologger.Logger
-	releases releases.Interface
+	k8sClient k8sclient.Interface
+	logger    micrologger.Logger
+	releases  releases.Interface
+	reporter  *reporter
 }
 
 func NewTCNP(config TCNPConfig) (*TCNP, error) {
 	if config.Event == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
 	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
@@ -44,9 +52,14 @@ func NewTCNP(config TCNPConfig) (*TCNP, error) {
 	}
 
 	t := &TCNP{
-		event:    config.Event,
-		logger:   config.Logger,
-		releases: config.Releases,
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+		releases:  config.Releases,
+		reporter: &reporter{
+			event:     config.Event,
+			k8sClient: config.K8sClient,
+			logger:    config.Logger,
+		},
 	}
 
 	return t, nil
@@ -86,24 +99,31 @@ func (t *TCNP) ShouldScale(ctx context.Context, cr infrastructurev1alpha3.AWSMac
 	return false, nil
 }
 
-// ShouldUpdate determines whether the reconciled TCNP stack should be updated.
+// Detect computes the change report of the reconciled TCNP stack.
 //
+//	The AMI version changes.
+//	The release's component versions change.
 //	The worker node's docker volume size changes.
+//	Dual-stack networking of the tenant cluster's VPC gets enabled.
 //	The worker node's instance type changes.
+//	The node pool's instance requirements change.
+//	The node pool's node labels, taints or kubelet settings change.
 //	The operator's version changes.
 //	The composition of security groups changes.
-//	The AMI version changes.
-func (t *TCNP) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (bool, error) {
+//	The worker node's volume profiles change.
+//	The node pool's spot allocation settings change.
+//	The node pool's warm pool changes.
+func (t *TCNP) Detect(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (Report, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
-		return false, microerror.Mask(err)
+		return Report{}, microerror.Mask(err)
 	}
 
 	var currentRelease releasev1alpha1.Release
 	{
 		currentRelease, err = t.releases.Release(ctx, cc.Status.TenantCluster.ReleaseVersion)
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
@@ -111,81 +131,141 @@ func (t *TCNP) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSMa
 	{
 		targetRelease, err = t.releases.Release(ctx, key.ReleaseVersion(&cr))
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
 	var ami string
 	{
-		ami, err = key.AMI(cc.Status.TenantCluster.AWS.Region, currentRelease, key.MachineDeploymentFlatcarReleaseVersion(cr))
+		architecture, err := key.MachineDeploymentArchitecture(cr, nil)
 		if err != nil {
-			return false, microerror.Mask(err)
+			return Report{}, microerror.Mask(err)
+		}
+
+		ami, err = key.AMI(cc.Status.TenantCluster.AWS.Region, currentRelease, key.MachineDeploymentFlatcarReleaseVersion(cr), architecture)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
 		}
 	}
 
-	amiEqual := cc.Status.TenantCluster.TCNP.WorkerInstance.Image == ami
-	componentVersionsEqual := releaseComponentsEqual(currentRelease, targetRelease)
-	dockerVolumeEqual := cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB == key.MachineDeploymentDockerVolumeSizeGB(cr)
-	instanceTypeEqual := cc.Status.TenantCluster.TCNP.WorkerInstance.Type == key.MachineDeploymentInstanceType(cr)
-	operatorVersionEqual := cc.Status.TenantCluster.OperatorVersion == key.OperatorVersion(&cr)
-	securityGroupsEqual := securityGroupsEqual(cc.Status.TenantCluster.TCNP.SecurityGroupIDs, cc.Spec.TenantCluster.TCNP.SecurityGroupIDs)
+	var instanceRequirements string
+	{
+		r, err := key.MachineDeploymentInstanceRequirements(cr)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+		instanceRequirements = key.MachineDeploymentInstanceRequirementsValue(r)
+	}
 
-	if !amiEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", fmt.Sprintf("ami image changed from %s to %s", cc.Status.TenantCluster.TCNP.WorkerInstance.Image, ami),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCNP stack should update: ami changed from %s to %s", cc.Status.TenantCluster.TCNP.WorkerInstance.Image, ami))
-		return true, nil
+	var nodeConfig string
+	{
+		c, err := key.MachineDeploymentNodeConfig(cr, cc.Spec.TenantCluster.TCNP.NodeConfigData)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+		nodeConfig = key.MachineDeploymentNodeConfigValue(c)
 	}
-	if !componentVersionsEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", strings.Join(componentsDiff(currentRelease, targetRelease), ", "),
+
+	var volumeProfiles string
+	{
+		var list infrastructurev1alpha3.AWSClusterList
+		err := t.k8sClient.CtrlClient().List(
+			ctx,
+			&list,
+			client.InNamespace(cr.Namespace),
+			client.MatchingLabels{label.Cluster: key.ClusterID(&cr)},
 		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCNP stack should update: %s", strings.Join(componentsDiff(currentRelease, targetRelease), ", ")))
-		return true, nil
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+
+		if len(list.Items) != 1 {
+			return Report{}, microerror.Maskf(executionFailedError, "expected 1 CR got %d", len(list.Items))
+		}
+
+		p, err := key.MachineDeploymentVolumeProfiles(list.Items[0], cr)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+		volumeProfiles = key.MachineDeploymentVolumeProfilesValue(p)
 	}
-	if !dockerVolumeEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", fmt.Sprintf("worker instance docker volume size changed from %#q to %#q", cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB, key.MachineDeploymentDockerVolumeSizeGB(cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCNP stack should update: worker instance docker volume size changed from %#q to %#q", cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB, key.MachineDeploymentDockerVolumeSizeGB(cr)))
-		return true, nil
+
+	var spotAllocation string
+	{
+		a, err := key.MachineDeploymentSpotAllocation(cr)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+		spotAllocation = key.MachineDeploymentSpotAllocationValue(a)
 	}
-	if !instanceTypeEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", fmt.Sprintf("worker instance type changed from %#q to %#q", cc.Status.TenantCluster.TCNP.WorkerInstance.Type, key.MachineDeploymentInstanceType(cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCNP stack should update: worker instance type changed from %#q to %#q", cc.Status.TenantCluster.TCNP.WorkerInstance.Type, key.MachineDeploymentInstanceType(cr)))
-		return true, nil
+
+	var warmPool string
+	{
+		w, err := key.MachineDeploymentWarmPool(cr)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+		warmPool = key.MachineDeploymentWarmPoolValue(w)
 	}
-	if !operatorVersionEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", fmt.Sprintf("operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)),
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", fmt.Sprintf("detected TCNP stack should update: operator version changed from %#q to %#q", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr)))
-		return true, nil
+
+	report := Report{
+		Stack: key.StackTCNP,
 	}
-	if !securityGroupsEqual {
-		t.logger.LogCtx(ctx,
-			"level", "debug",
-			"message", "detected TCNP stack should update",
-			"reason", "security groups changed",
-		)
-		t.event.Emit(ctx, &cr, "CFUpdateRequested", "detected TCNP stack should update: security groups changed")
-		return true, nil
+
+	if cc.Status.TenantCluster.TCNP.WorkerInstance.Image != ami {
+		report.add(ReasonAMI, "ami", cc.Status.TenantCluster.TCNP.WorkerInstance.Image, ami)
+	}
+	if !releaseComponentsEqual(currentRelease, targetRelease) {
+		report.addComponentChanges(cc.Status.TenantCluster.ReleaseVersion, key.ReleaseVersion(&cr), currentRelease, targetRelease)
+	}
+	if cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB != key.MachineDeploymentDockerVolumeSizeGB(cr) {
+		report.add(ReasonDockerVolumeSize, "worker instance docker volume size", cc.Status.TenantCluster.TCNP.WorkerInstance.DockerVolumeSizeGB, key.MachineDeploymentDockerVolumeSizeGB(cr))
+	}
+	if cc.Status.TenantCluster.TCNP.WorkerInstance.Type != key.MachineDeploymentInstanceType(cr) {
+		report.add(ReasonInstanceType, "worker instance type", cc.Status.TenantCluster.TCNP.WorkerInstance.Type, key.MachineDeploymentInstanceType(cr))
+	}
+	if cc.Status.TenantCluster.TCNP.InstanceRequirements != instanceRequirements {
+		report.add(ReasonInstanceRequirements, "instance requirements", cc.Status.TenantCluster.TCNP.InstanceRequirements, instanceRequirements)
+	}
+	if cc.Status.TenantCluster.TCNP.DualStack != (cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR != "") {
+		report.add(ReasonDualStack, "dual-stack", strconv.FormatBool(cc.Status.TenantCluster.TCNP.DualStack), strconv.FormatBool(cc.Status.TenantCluster.TCCP.VPC.IPv6CIDR != ""))
+	}
+	if cc.Status.TenantCluster.TCNP.NodeConfig != nodeConfig {
+		report.add(ReasonNodeConfig, "node config", cc.Status.TenantCluster.TCNP.NodeConfig, nodeConfig)
+	}
+	if cc.Status.TenantCluster.OperatorVersion != key.OperatorVersion(&cr) {
+		report.add(ReasonOperatorVersion, "operator version", cc.Status.TenantCluster.OperatorVersion, key.OperatorVersion(&cr))
+	}
+	if !securityGroupsEqual(cc.Status.TenantCluster.TCNP.SecurityGroupIDs, cc.Spec.TenantCluster.TCNP.SecurityGroupIDs) {
+		report.add(ReasonSecurityGroups, "security groups", "", "")
+	}
+	if cc.Status.TenantCluster.TCNP.WorkerInstance.VolumeProfiles != volumeProfiles {
+		report.add(ReasonVolumeProfiles, "worker instance volume profiles", cc.Status.TenantCluster.TCNP.WorkerInstance.VolumeProfiles, volumeProfiles)
+	}
+	if cc.Status.TenantCluster.TCNP.SpotAllocation != spotAllocation {
+		report.add(ReasonSpotAllocation, "spot allocation", cc.Status.TenantCluster.TCNP.SpotAllocation, spotAllocation)
+	}
+	if cc.Status.TenantCluster.TCNP.WarmPool != warmPool {
+		report.add(ReasonWarmPool, "warm pool", cc.Status.TenantCluster.TCNP.WarmPool, warmPool)
 	}
 
-	return false, nil
+	return report, nil
+}
+
+// ShouldUpdate determines whether the reconciled TCNP stack should be updated.
+// The detected change report is surfaced as event, metric and condition.
+func (t *TCNP) ShouldUpdate(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (bool, error) {
+	report, err := t.Detect(ctx, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	err = t.reporter.Report(ctx, &cr, report)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return report.ShouldUpdate(), nil
 }
 
 func securityGroupsEqual(cur []string, des []string) bool {
@@ -0,0 +1,374 @@
+package changeset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+)
+
+const (
+	// emptyChangeSetReason is the status reason CloudFormation reports for
+	// change sets which would not change anything.
+	emptyChangeSetReason = "didn't contain changes"
+	// hashLength is the number of hex characters of the desired state's hash
+	// used as suffix of the change set name.
+	hashLength = 16
+)
+
+type Config struct {
+	Event     recorder.Interface
+	K8sClient k8sclient.Interface
+	Logger    micrologger.Logger
+}
+
+type ChangeSet struct {
+	event     recorder.Interface
+	k8sClient k8sclient.Interface
+	logger    micrologger.Logger
+}
+
+func New(config Config) (*ChangeSet, error) {
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	c := &ChangeSet{
+		event:     config.Event,
+		k8sClient: config.K8sClient,
+		logger:    config.Logger,
+	}
+
+	return c, nil
+}
+
+func (c *ChangeSet) Ensure(ctx context.Context, obj client.Object, cf CF, input *cloudformation.UpdateStackInput) (bool, error) {
+	stackName := aws.StringValue(input.StackName)
+	desired := Name(stackName, aws.StringValue(input.TemplateBody), input.Tags)
+	pending := obj.GetAnnotations()[annotation.ChangeSetPending]
+
+	// In case the desired state changed since the pending change set got
+	// created, the pending change set is outdated and must not be executed
+	// anymore. We delete it and create a new one below.
+	if pending != "" && pending != desired {
+		c.logger.Debugf(ctx, "found outdated change set %#q for cloud formation stack %#q", pending, stackName)
+
+		err := c.Cleanup(ctx, obj, cf, stackName)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		pending = ""
+	}
+
+	if pending == "" {
+		c.logger.Debugf(ctx, "creating change set %#q for cloud formation stack %#q", desired, stackName)
+
+		i := &cloudformation.CreateChangeSetInput{
+			Capabilities:  input.Capabilities,
+			ChangeSetName: aws.String(desired),
+			ChangeSetType: aws.String(cloudformation.ChangeSetTypeUpdate),
+			Parameters:    input.Parameters,
+			StackName:     input.StackName,
+			Tags:          input.Tags,
+			TemplateBody:  input.TemplateBody,
+		}
+
+		_, err := cf.CreateChangeSet(i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		err = c.updateAnnotations(ctx, obj, func(a map[string]string) {
+			a[annotation.ChangeSetPending] = desired
+			delete(a, annotation.ChangeSetChanges)
+		})
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		c.logger.Debugf(ctx, "created change set %#q for cloud formation stack %#q", desired, stackName)
+		c.logger.Debugf(ctx, "canceling resource")
+
+		return false, nil
+	}
+
+	var o *cloudformation.DescribeChangeSetOutput
+	var changes []Change
+	{
+		i := &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(pending),
+			StackName:     aws.String(stackName),
+		}
+
+		for {
+			var err error
+			o, err = cf.DescribeChangeSet(i)
+			if IsChangeSetNotFound(err) {
+				c.logger.Debugf(ctx, "did not find change set %#q for cloud formation stack %#q", pending, stackName)
+
+				err = c.clearAnnotations(ctx, obj)
+				if err != nil {
+					return false, microerror.Mask(err)
+				}
+
+				return false, nil
+			} else if err != nil {
+				return false, microerror.Mask(err)
+			}
+
+			changes = append(changes, toChanges(o.Changes)...)
+
+			if o.NextToken == nil {
+				break
+			}
+			i.NextToken = o.NextToken
+		}
+	}
+
+	status := aws.StringValue(o.Status)
+	reason := aws.StringValue(o.StatusReason)
+
+	switch {
+	case status == cloudformation.ChangeSetStatusCreatePending || status == cloudformation.ChangeSetStatusCreateInProgress:
+		c.logger.Debugf(ctx, "change set %#q for cloud formation stack %#q has status %#q", pending, stackName, status)
+		c.logger.Debugf(ctx, "canceling resource")
+		return false, nil
+
+	case status == cloudformation.ChangeSetStatusFailed && strings.Contains(reason, emptyChangeSetReason):
+		c.logger.Debugf(ctx, "change set %#q for cloud formation stack %#q does not contain any changes", pending, stackName)
+		c.event.Emit(ctx, obj, "CFChangeSetEmpty", fmt.Sprintf("the change set %#q for the cloud formation stack %#q does not contain any changes", pending, stackName))
+
+		err := c.Cleanup(ctx, obj, cf, stackName)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return false, nil
+
+	case status == cloudformation.ChangeSetStatusFailed:
+		return false, microerror.Maskf(changeSetFailedError, "change set %#q for cloud formation stack %#q has status %#q with reason %#q", pending, stackName, status, reason)
+
+	case aws.StringValue(o.ExecutionStatus) == cloudformation.ExecutionStatusObsolete:
+		c.logger.Debugf(ctx, "change set %#q for cloud formation stack %#q is obsolete", pending, stackName)
+
+		err := c.Cleanup(ctx, obj, cf, stackName)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return false, nil
+	}
+
+	{
+		b, err := json.Marshal(changes)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		if obj.GetAnnotations()[annotation.ChangeSetChanges] != string(b) {
+			err = c.updateAnnotations(ctx, obj, func(a map[string]string) {
+				a[annotation.ChangeSetChanges] = string(b)
+			})
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+
+			c.event.Emit(ctx, obj, "CFChangeSetCreated", fmt.Sprintf("the change set %#q for the cloud formation stack %#q contains %d changes: %s", pending, stackName, len(changes), summary(changes)))
+
+			if hasReplacement(changes) {
+				c.event.Emit(ctx, obj, "CFChangeSetReplacement", fmt.Sprintf("the change set %#q for the cloud formation stack %#q replaces resources", pending, stackName))
+			}
+		}
+	}
+
+	if obj.GetAnnotations()[annotation.ChangeSetApproved] != pending {
+		c.logger.Debugf(ctx, "change set %#q for cloud formation stack %#q is not approved yet", pending, stackName)
+		return false, nil
+	}
+
+	c.logger.Debugf(ctx, "change set %#q for cloud formation stack %#q is approved", pending, stackName)
+
+	return true, nil
+}
+
+func (c *ChangeSet) Execute(ctx context.Context, obj client.Object, cf CF, stackName string) error {
+	pending := obj.GetAnnotations()[annotation.ChangeSetPending]
+	if pending == "" {
+		return nil
+	}
+
+	{
+		c.logger.Debugf(ctx, "executing change set %#q for cloud formation stack %#q", pending, stackName)
+
+		i := &cloudformation.ExecuteChangeSetInput{
+			ChangeSetName: aws.String(pending),
+			StackName:     aws.String(stackName),
+		}
+
+		_, err := cf.ExecuteChangeSet(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		c.logger.Debugf(ctx, "executed change set %#q for cloud formation stack %#q", pending, stackName)
+		c.event.Emit(ctx, obj, "CFChangeSetExecuteRequested", fmt.Sprintf("requested the execution of the change set %#q for the cloud formation stack %#q", pending, stackName))
+	}
+
+	err := c.clearAnnotations(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (c *ChangeSet) Cleanup(ctx context.Context, obj client.Object, cf CF, stackName string) error {
+	a := obj.GetAnnotations()
+	pending := a[annotation.ChangeSetPending]
+	if pending == "" && a[annotation.ChangeSetChanges] == "" && a[annotation.ChangeSetApproved] == "" {
+		return nil
+	}
+
+	if pending != "" {
+		c.logger.Debugf(ctx, "deleting change set %#q for cloud formation stack %#q", pending, stackName)
+
+		i := &cloudformation.DeleteChangeSetInput{
+			ChangeSetName: aws.String(pending),
+			StackName:     aws.String(stackName),
+		}
+
+		_, err := cf.DeleteChangeSet(i)
+		if IsChangeSetNotFound(err) {
+			c.logger.Debugf(ctx, "did not find change set %#q for cloud formation stack %#q", pending, stackName)
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			c.logger.Debugf(ctx, "deleted change set %#q for cloud formation stack %#q", pending, stackName)
+			c.event.Emit(ctx, obj, "CFChangeSetDeleted", fmt.Sprintf("deleted the change set %#q for the cloud formation stack %#q", pending, stackName))
+		}
+	}
+
+	err := c.clearAnnotations(ctx, obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (c *ChangeSet) clearAnnotations(ctx context.Context, obj client.Object) error {
+	return c.updateAnnotations(ctx, obj, func(a map[string]string) {
+		delete(a, annotation.ChangeSetApproved)
+		delete(a, annotation.ChangeSetChanges)
+		delete(a, annotation.ChangeSetPending)
+	})
+}
+
+// updateAnnotations applies the given mutation to the annotations of the
+// latest version of obj in the Kubernetes API and writes them back. The
+// annotations of the given obj are updated accordingly so that callers work
+// with the current state within the same reconciliation loop.
+func (c *ChangeSet) updateAnnotations(ctx context.Context, obj client.Object, mutate func(a map[string]string)) error {
+	latest, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return microerror.Maskf(invalidConfigError, "%T must implement client.Object", obj)
+	}
+
+	err := c.k8sClient.CtrlClient().Get(ctx, client.ObjectKeyFromObject(obj), latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	a := latest.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	mutate(a)
+	latest.SetAnnotations(a)
+
+	err = c.k8sClient.CtrlClient().Update(ctx, latest)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	obj.SetAnnotations(a)
+
+	return nil
+}
+
+// Name returns the name of the change set representing the given desired
+// state of the stack. The name is stable as long as the template and tags do
+// not change, which allows to detect outdated change sets.
+func Name(stackName string, templateBody string, tags []*cloudformation.Tag) string {
+	var t []string
+	for _, tag := range tags {
+		t = append(t, fmt.Sprintf("%s=%s", aws.StringValue(tag.Key), aws.StringValue(tag.Value)))
+	}
+	sort.Strings(t)
+
+	h := sha256.New()
+	h.Write([]byte(templateBody))
+	h.Write([]byte(strings.Join(t, ",")))
+
+	return fmt.Sprintf("%s-%x", stackName, h.Sum(nil))[:len(stackName)+1+hashLength]
+}
+
+func hasReplacement(changes []Change) bool {
+	for _, c := range changes {
+		if c.Replacement == cloudformation.ReplacementTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func summary(changes []Change) string {
+	var s []string
+	for _, c := range changes {
+		e := fmt.Sprintf("%s %s (%s)", c.Action, c.LogicalID, c.ResourceType)
+		if c.Replacement != "" {
+			e += fmt.Sprintf(" replacement %s", c.Replacement)
+		}
+		s = append(s, e)
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func toChanges(list []*cloudformation.Change) []Change {
+	var changes []Change
+	for _, c := range list {
+		if c.ResourceChange == nil {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Action:       aws.StringValue(c.ResourceChange.Action),
+			LogicalID:    aws.StringValue(c.ResourceChange.LogicalResourceId),
+			Replacement:  aws.StringValue(c.ResourceChange.Replacement),
+			ResourceType: aws.StringValue(c.ResourceChange.ResourceType),
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,200 @@
+package changeset
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+type cfMock struct {
+	created  int
+	deleted  int
+	executed int
+
+	describe *cloudformation.DescribeChangeSetOutput
+}
+
+func (m *cfMock) CreateChangeSet(*cloudformation.CreateChangeSetInput) (*cloudformation.CreateChangeSetOutput, error) {
+	m.created++
+	return &cloudformation.CreateChangeSetOutput{}, nil
+}
+
+func (m *cfMock) DeleteChangeSet(*cloudformation.DeleteChangeSetInput) (*cloudformation.DeleteChangeSetOutput, error) {
+	m.deleted++
+	return &cloudformation.DeleteChangeSetOutput{}, nil
+}
+
+func (m *cfMock) DescribeChangeSet(*cloudformation.DescribeChangeSetInput) (*cloudformation.DescribeChangeSetOutput, error) {
+	if m.describe == nil {
+		return nil, awserr.New(cloudformation.ErrCodeChangeSetNotFoundException, "not found", nil)
+	}
+	return m.describe, nil
+}
+
+func (m *cfMock) ExecuteChangeSet(*cloudformation.ExecuteChangeSetInput) (*cloudformation.ExecuteChangeSetOutput, error) {
+	m.executed++
+	return &cloudformation.ExecuteChangeSetOutput{}, nil
+}
+
+type recorderMock struct{}
+
+func (r *recorderMock) Emit(ctx context.Context, obj runtime.Object, reason, message string) {}
+
+func Test_ChangeSet_Ensure(t *testing.T) {
+	input := &cloudformation.UpdateStackInput{
+		StackName:    aws.String("cluster-8y5ck-tcnp-al9qy"),
+		TemplateBody: aws.String("{}"),
+	}
+	name := Name("cluster-8y5ck-tcnp-al9qy", "{}", nil)
+
+	replacement := &cloudformation.DescribeChangeSetOutput{
+		Changes: []*cloudformation.Change{
+			{
+				ResourceChange: &cloudformation.ResourceChange{
+					Action:            aws.String(cloudformation.ChangeActionModify),
+					LogicalResourceId: aws.String("NodePoolAutoScalingGroup"),
+					Replacement:       aws.String(cloudformation.ReplacementTrue),
+					ResourceType:      aws.String("AWS::AutoScaling::AutoScalingGroup"),
+				},
+			},
+		},
+		ExecutionStatus: aws.String(cloudformation.ExecutionStatusAvailable),
+		Status:          aws.String(cloudformation.ChangeSetStatusCreateComplete),
+	}
+
+	testCases := []struct {
+		name                string
+		annotations         map[string]string
+		describe            *cloudformation.DescribeChangeSetOutput
+		expectedApproved    bool
+		expectedCreated     int
+		expectedDeleted     int
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:             "case 0: no pending change set results in the creation of a change set",
+			annotations:      map[string]string{},
+			expectedApproved: false,
+			expectedCreated:  1,
+			expectedAnnotations: map[string]string{
+				annotation.ChangeSetPending: name,
+			},
+		},
+		{
+			name: "case 1: outdated change set gets replaced",
+			annotations: map[string]string{
+				annotation.ChangeSetPending: "cluster-8y5ck-tcnp-al9qy-0000000000000000",
+			},
+			expectedApproved: false,
+			expectedCreated:  1,
+			expectedDeleted:  1,
+			expectedAnnotations: map[string]string{
+				annotation.ChangeSetPending: name,
+			},
+		},
+		{
+			name: "case 2: created change set gets recorded without approval",
+			annotations: map[string]string{
+				annotation.ChangeSetPending: name,
+			},
+			describe:         replacement,
+			expectedApproved: false,
+			expectedAnnotations: map[string]string{
+				annotation.ChangeSetChanges: `[{"action":"Modify","logicalID":"NodePoolAutoScalingGroup","replacement":"True","resourceType":"AWS::AutoScaling::AutoScalingGroup"}]`,
+				annotation.ChangeSetPending: name,
+			},
+		},
+		{
+			name: "case 3: approved change set is ready for execution",
+			annotations: map[string]string{
+				annotation.ChangeSetApproved: name,
+				annotation.ChangeSetPending:  name,
+			},
+			describe:         replacement,
+			expectedApproved: true,
+			expectedAnnotations: map[string]string{
+				annotation.ChangeSetApproved: name,
+				annotation.ChangeSetChanges:  `[{"action":"Modify","logicalID":"NodePoolAutoScalingGroup","replacement":"True","resourceType":"AWS::AutoScaling::AutoScalingGroup"}]`,
+				annotation.ChangeSetPending:  name,
+			},
+		},
+		{
+			name: "case 4: empty change set gets removed",
+			annotations: map[string]string{
+				annotation.ChangeSetPending: name,
+			},
+			describe: &cloudformation.DescribeChangeSetOutput{
+				Status:       aws.String(cloudformation.ChangeSetStatusFailed),
+				StatusReason: aws.String("The submitted information didn't contain changes. Submit different information to create a change set."),
+			},
+			expectedApproved:    false,
+			expectedDeleted:     1,
+			expectedAnnotations: map[string]string{},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var err error
+
+			ctx := context.Background()
+			k := unittest.FakeK8sClient()
+
+			cr := unittest.DefaultMachineDeployment()
+			cr.Annotations = tc.annotations
+			err = k.CtrlClient().Create(ctx, &cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var c *ChangeSet
+			{
+				cc := Config{
+					Event:     &recorderMock{},
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+				}
+
+				c, err = New(cc)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cf := &cfMock{describe: tc.describe}
+
+			approved, err := c.Ensure(ctx, &cr, cf, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if approved != tc.expectedApproved {
+				t.Fatalf("expected %t got %t", tc.expectedApproved, approved)
+			}
+			if cf.created != tc.expectedCreated {
+				t.Fatalf("expected %d created change sets got %d", tc.expectedCreated, cf.created)
+			}
+			if cf.deleted != tc.expectedDeleted {
+				t.Fatalf("expected %d deleted change sets got %d", tc.expectedDeleted, cf.deleted)
+			}
+
+			a := cr.GetAnnotations()
+			if a == nil {
+				a = map[string]string{}
+			}
+			if !cmp.Equal(a, tc.expectedAnnotations) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedAnnotations, a))
+			}
+		})
+	}
+}
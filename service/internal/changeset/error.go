@@ -0,0 +1,53 @@
+package changeset
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/microerror"
+)
+
+var changeSetFailedError = &microerror.Error{
+	Kind: "CFChangeSetFailed",
+	Desc: "The CloudFormation change set for the stack update could not be created.",
+}
+
+// IsChangeSetFailed asserts changeSetFailedError.
+func IsChangeSetFailed(err error) bool {
+	return microerror.Cause(err) == changeSetFailedError
+}
+
+var changeSetNotFoundError = &microerror.Error{
+	Kind: "changeSetNotFoundError",
+}
+
+// IsChangeSetNotFound asserts changeSetNotFoundError and change set not found
+// errors from the upstream's API code.
+func IsChangeSetNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	c := microerror.Cause(err)
+	if c == changeSetNotFoundError {
+		return true
+	}
+
+	aerr, ok := c.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if aerr.Code() == cloudformation.ErrCodeChangeSetNotFoundException {
+		return true
+	}
+
+	return false
+}
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
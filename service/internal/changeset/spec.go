@@ -0,0 +1,44 @@
+package changeset
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CF provides the set of methods required to work with CloudFormation change
+// sets. *CloudFormation struct from
+// "github.com/aws/aws-sdk-go/service/cloudformation" fulfils this interface.
+type CF interface {
+	CreateChangeSet(input *cloudformation.CreateChangeSetInput) (*cloudformation.CreateChangeSetOutput, error)
+	DeleteChangeSet(input *cloudformation.DeleteChangeSetInput) (*cloudformation.DeleteChangeSetOutput, error)
+	DescribeChangeSet(input *cloudformation.DescribeChangeSetInput) (*cloudformation.DescribeChangeSetOutput, error)
+	ExecuteChangeSet(input *cloudformation.ExecuteChangeSetInput) (*cloudformation.ExecuteChangeSetOutput, error)
+}
+
+type Interface interface {
+	// Ensure creates a change set for the given stack update instead of
+	// updating the stack directly. Once the change set got created, its
+	// resource level changes are recorded in the annotations of obj and emitted
+	// as events. Ensure returns true as soon as the pending change set is ready
+	// for execution and got approved by setting the approval annotation on obj
+	// to the name of the pending change set.
+	Ensure(ctx context.Context, obj client.Object, cf CF, input *cloudformation.UpdateStackInput) (bool, error)
+	// Execute executes the pending change set of obj and removes all change set
+	// annotations afterwards.
+	Execute(ctx context.Context, obj client.Object, cf CF, stackName string) error
+	// Cleanup deletes the pending change set of obj, if any, and removes all
+	// change set annotations. This is used when the stack does not need to be
+	// updated anymore or when the desired state changed since the pending change
+	// set got created.
+	Cleanup(ctx context.Context, obj client.Object, cf CF, stackName string) error
+}
+
+// Change is a single resource level change of a CloudFormation change set.
+type Change struct {
+	Action       string `json:"action"`
+	LogicalID    string `json:"logicalID"`
+	Replacement  string `json:"replacement,omitempty"`
+	ResourceType string `json:"resourceType"`
+}
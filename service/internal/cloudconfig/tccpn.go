@@ -120,7 +120,7 @@ func (t *TCCPN) newTemplate(ctx context.Context, obj interface{}, mapping hamast
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
-	im, err := t.config.Images.CC(ctx, obj)
+	im, err := t.config.Images.CC(ctx, obj, key.ArchitectureAMD64)
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
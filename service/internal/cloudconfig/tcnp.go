@@ -3,6 +3,7 @@ package cloudconfig
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -65,7 +66,11 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	im, err := t.config.Images.CC(ctx, obj)
+	architecture, err := key.MachineDeploymentArchitecture(cr, nil)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	im, err := t.config.Images.CC(ctx, obj, architecture)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
@@ -176,6 +181,16 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 		}
 	}
 
+	warmPool, err := key.MachineDeploymentWarmPool(cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	nodeConfig, err := key.MachineDeploymentNodeConfig(cr, cc.Spec.TenantCluster.TCNP.NodeConfigData)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	var kubeletExtraArgs []string
 	{
 		if t.config.PodInfraContainerImage != "" {
@@ -183,6 +198,7 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 		}
 
 		kubeletExtraArgs = append(kubeletExtraArgs, t.config.KubeletExtraArgs...)
+		kubeletExtraArgs = append(kubeletExtraArgs, nodeConfigKubeletArgs(nodeConfig)...)
 	}
 
 	// Allow the actual externalSNAT to be set by the CR.
@@ -246,6 +262,7 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 			encryptionKey:  ek,
 			externalSNAT:   externalSNAT,
 			registryDomain: t.config.RegistryDomain,
+			warmPool:       warmPool != nil,
 		}
 		params.ExternalCloudControllerManager = false
 		params.ForceCGroupsV1 = forceCGroupsV1
@@ -262,6 +279,7 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 		labels := params.Cluster.Kubernetes.Kubelet.Labels
 		splitted := strings.Split(labels, ",")
 		splitted = append(splitted, fmt.Sprintf("%s=%s", label.CGroupVersion, cgroupsLabelValue))
+		splitted = append(splitted, nodeConfigLabels(nodeConfig)...)
 		params.Cluster.Kubernetes.Kubelet.Labels = strings.Join(splitted, ",")
 
 		ignitionPath := k8scloudconfig.GetIgnitionPath(t.config.IgnitionPath)
@@ -293,3 +311,59 @@ func (t *TCNP) NewTemplates(ctx context.Context, obj interface{}) ([]string, err
 
 	return []string{templateBody}, nil
 }
+
+// nodeConfigKubeletArgs returns the kubelet flags applying the given node
+// config. Flags take precedence over the kubelet configuration file, so that
+// configured eviction thresholds and reserved resources replace the defaults
+// as a whole.
+func nodeConfigKubeletArgs(c key.NodeConfig) []string {
+	var args []string
+
+	if len(c.EvictionHard) != 0 {
+		args = append(args, fmt.Sprintf("--eviction-hard=%s", joinKeyValuePairs(c.EvictionHard)))
+	}
+	if c.ImageGCHighThreshold != 0 {
+		args = append(args, fmt.Sprintf("--image-gc-high-threshold=%d", c.ImageGCHighThreshold))
+		args = append(args, fmt.Sprintf("--image-gc-low-threshold=%d", c.ImageGCLowThreshold))
+	}
+	if len(c.KubeReserved) != 0 {
+		args = append(args, fmt.Sprintf("--kube-reserved=%s", joinKeyValuePairs(c.KubeReserved)))
+	}
+	if c.MaxPods != 0 {
+		args = append(args, fmt.Sprintf("--max-pods=%d", c.MaxPods))
+	}
+	if len(c.SystemReserved) != 0 {
+		args = append(args, fmt.Sprintf("--system-reserved=%s", joinKeyValuePairs(c.SystemReserved)))
+	}
+	if len(c.Taints) != 0 {
+		var taints []string
+		for _, t := range c.Taints {
+			taints = append(taints, key.TaintValue(t))
+		}
+		args = append(args, fmt.Sprintf("--register-with-taints=%s", strings.Join(taints, ",")))
+	}
+
+	return args
+}
+
+// nodeConfigLabels returns the node labels of the given node config sorted by
+// key in the "key=value" format.
+func nodeConfigLabels(c key.NodeConfig) []string {
+	var labels []string
+	for k, v := range c.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(labels)
+
+	return labels
+}
+
+func joinKeyValuePairs(pairs map[string]string) string {
+	var joined []string
+	for k, v := range pairs {
+		joined = append(joined, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(joined)
+
+	return strings.Join(joined, ",")
+}
@@ -30,6 +30,7 @@ type TCNPExtension struct {
 	encryptionKey  string
 	externalSNAT   bool
 	registryDomain string
+	warmPool       bool
 }
 
 func (e *TCNPExtension) Files() ([]k8scloudconfig.FileAsset, error) {
@@ -79,6 +80,22 @@ func (e *TCNPExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 		},
 	}
 
+	if e.warmPool {
+		filesMeta = append(filesMeta, k8scloudconfig.FileMetadata{
+			AssetContent: template.WarmPoolWait,
+			Path:         "/opt/bin/warm-pool-wait",
+			Owner: k8scloudconfig.Owner{
+				Group: k8scloudconfig.Group{
+					Name: FileOwnerGroupName,
+				},
+				User: k8scloudconfig.User{
+					Name: FileOwnerUserName,
+				},
+			},
+			Permissions: 0700,
+		})
+	}
+
 	certsMeta := []k8scloudconfig.FileMetadata{}
 	{
 		for _, f := range e.clusterCerts {
@@ -189,6 +206,14 @@ func (e *TCNPExtension) Units() ([]k8scloudconfig.UnitAsset, error) {
 		},
 	}
 
+	if e.warmPool {
+		unitsMeta = append(unitsMeta, k8scloudconfig.UnitMetadata{
+			AssetContent: template.WarmPoolWaitService,
+			Name:         "warm-pool-wait.service",
+			Enabled:      true,
+		})
+	}
+
 	var newUnits []k8scloudconfig.UnitAsset
 
 	for _, m := range unitsMeta {
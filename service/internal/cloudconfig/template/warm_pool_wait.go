@@ -0,0 +1,56 @@
+package template
+
+// WarmPoolWaitService blocks the kubelet from starting while the instance is
+// prepared for the warm pool of its ASG. Instances in the warm pool get stopped
+// or hibernated once prepared and must only join the cluster once the ASG moved
+// them into service.
+const WarmPoolWaitService = `
+[Unit]
+Description=wait for the instance to leave the warm pool of its ASG
+Requires=network.target
+After=network.target
+Before=k8s-kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+TimeoutStartSec=infinity
+ExecStart=/opt/bin/warm-pool-wait
+
+[Install]
+WantedBy=multi-user.target
+RequiredBy=k8s-kubelet.service
+`
+
+// WarmPoolWait waits as long as the target lifecycle state of the instance
+// indicates the warm pool. Instances launched into service and instances moved
+// from the warm pool into service report InService instead.
+const WarmPoolWait = `#!/bin/bash
+set -o nounset
+set -o pipefail
+
+retries=0
+
+while true; do
+    state=$(/opt/imds-client /latest/meta-data/autoscaling/target-lifecycle-state 2>/dev/null || true)
+
+    case "${state}" in
+        Warmed:*)
+            echo "Instance is in the warm pool with target lifecycle state ${state}. Not joining the cluster yet."
+            sleep 5
+            ;;
+        "")
+            if [ ${retries} -ge 12 ]; then
+                echo "Failed to find the target lifecycle state of the instance. Joining the cluster."
+                exit 0
+            fi
+            retries=$((retries+1))
+            sleep 5
+            ;;
+        *)
+            echo "Instance has target lifecycle state ${state}. Joining the cluster."
+            exit 0
+            ;;
+    esac
+done
+`
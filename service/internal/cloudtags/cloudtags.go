@@ -2,30 +2,52 @@ package cloudtags
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
 	"github.com/giantswarm/aws-operator/v16/pkg/label"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	event "github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 )
 
 type Config struct {
+	Event     event.Interface
 	K8sClient k8sclient.Interface
 	Logger    micrologger.Logger
+
+	InstallationName string
+	// InstallationTags are the tags applied to the AWS resources of all tenant
+	// clusters of the installation. They have the lowest precedence.
+	InstallationTags map[string]string
 }
 
 type CloudTags struct {
+	event     event.Interface
 	k8sClient k8sclient.Interface
 	logger    micrologger.Logger
+
+	installationName string
+	installationTags map[string]string
 }
 
 // New CloudTags object
 func New(config Config) (*CloudTags, error) {
+	if config.Event == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Event must not be empty", config)
+	}
 	if config.K8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
@@ -33,14 +55,40 @@ func New(config Config) (*CloudTags, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
+	if config.InstallationName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", config)
+	}
+	for k, v := range config.InstallationTags {
+		err := validateTag(k, v)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.InstallationTags must be valid: %s", config, err)
+		}
+	}
+	if len(config.InstallationTags) > maxUserTags {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationTags must not contain more than %d tags", config, maxUserTags)
+	}
+
 	l := &CloudTags{
+		event:     config.Event,
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
+
+		installationName: config.InstallationName,
+		installationTags: config.InstallationTags,
 	}
 
 	return l, nil
 }
 
+func (ct *CloudTags) ClusterTags(ctx context.Context, getter key.LabelsGetter) (map[string]string, error) {
+	tags, err := ct.policyTags(ctx, getter, key.ClusterID(getter), nil)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return tags, nil
+}
+
 // GetTagsByCluster the cloud tags from CAPI Cluster CR
 func (ct *CloudTags) GetTagsByCluster(ctx context.Context, clusterID string) (map[string]string, error) {
 	var err error
@@ -53,9 +101,26 @@ func (ct *CloudTags) GetTagsByCluster(ctx context.Context, clusterID string) (ma
 	return tags, nil
 }
 
+func (ct *CloudTags) NodePoolTags(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (map[string]string, error) {
+	tags, err := ct.policyTags(ctx, &cr, key.MachineDeploymentID(&cr), cloudTagsFromLabels(cr.GetLabels()))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return tags, nil
+}
+
 func (ct *CloudTags) lookupCloudTags(ctx context.Context, clusterID string) (map[string]string, error) {
+	cl, err := ct.lookupCluster(ctx, clusterID)
+	if err != nil {
+		return map[string]string{}, microerror.Mask(err)
+	}
+
+	return cloudTagsFromLabels(cl.GetLabels()), nil
+}
+
+func (ct *CloudTags) lookupCluster(ctx context.Context, clusterID string) (apiv1beta1.Cluster, error) {
 	var list apiv1beta1.ClusterList
-	tags := map[string]string{}
 
 	err := ct.k8sClient.CtrlClient().List(
 		ctx,
@@ -63,26 +128,174 @@ func (ct *CloudTags) lookupCloudTags(ctx context.Context, clusterID string) (map
 		client.MatchingLabels{label.Cluster: clusterID},
 	)
 	if err != nil {
-		return tags, microerror.Mask(err)
+		return apiv1beta1.Cluster{}, microerror.Mask(err)
 	}
 	if len(list.Items) == 0 {
-		return tags, microerror.Mask(notFoundError)
+		return apiv1beta1.Cluster{}, microerror.Mask(notFoundError)
 	}
 	if len(list.Items) > 1 {
-		return tags, microerror.Mask(tooManyCRsError)
+		return apiv1beta1.Cluster{}, microerror.Mask(tooManyCRsError)
 	}
 
-	labels := list.Items[0].GetLabels()
-	for lkey, lvalue := range labels {
-		if isCloudTagKey(lkey) {
-			nkey := trimCloudTagKey(lkey)
-			tags[nkey] = lvalue
+	return list.Items[0], nil
+}
+
+// lookupOrganizationTags returns the user defined tags of the given
+// organization namespace. Clusters in namespaces which do not exist anymore
+// simply have no organization tags.
+func (ct *CloudTags) lookupOrganizationTags(ctx context.Context, namespace string) (map[string]string, error) {
+	var ns corev1.Namespace
+
+	err := ct.k8sClient.CtrlClient().Get(ctx, client.ObjectKey{Name: namespace}, &ns)
+	if apierrors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return cloudTagsFromLabels(ns.GetLabels()), nil
+}
+
+// policyTags merges the tags of all sources of the tag policy in the order
+// of their precedence. See Interface for the sources. Invalid tags are
+// skipped. Tags exceeding maxUserTags are dropped deterministically. Tags of
+// more specific sources are kept first and tags of the same source are kept
+// in the alphabetical order of their keys. Skipped and dropped tags are
+// reported as events on the Cluster CR, since they are defined by users and
+// must not block the reconciliation of the cluster. The given scope identifies
+// the set of tags, e.g. the cluster or one of its node pools, in order to only
+// report changes of the skipped and dropped tags.
+func (ct *CloudTags) policyTags(ctx context.Context, getter key.LabelsGetter, scope string, nodePool map[string]string) (map[string]string, error) {
+	cl, err := ct.lookupCluster(ctx, key.ClusterID(getter))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	organization, err := ct.lookupOrganizationTags(ctx, cl.GetNamespace())
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	sources := []map[string]string{
+		nodePool,
+		cloudTagsFromLabels(cl.GetLabels()),
+		organization,
+		ct.installationTags,
+	}
+
+	var issues []issue
+	tags := map[string]string{}
+	dropped := map[string]bool{}
+	for _, s := range sources {
+		var keys []string
+		for k := range s {
+			keys = append(keys, k)
 		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			v := s[k]
+
+			_, ok := tags[k]
+			if ok {
+				continue
+			}
+
+			err := validateTag(k, v)
+			if IsInvalidTag(err) {
+				ct.logger.Debugf(ctx, "skipping tag %#q: %s", k, err)
+				issues = append(issues, issue{Reason: "CloudTagSkipped", Message: fmt.Sprintf("skipped invalid tag %#q: %s", k, microerror.Pretty(err, false))})
+				continue
+			} else if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			if len(tags) >= maxUserTags {
+				dropped[k] = true
+				continue
+			}
+
+			tags[k] = v
+		}
+	}
+
+	if len(dropped) > 0 {
+		var keys []string
+		for k := range dropped {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		ct.logger.Debugf(ctx, "dropping tags %#q exceeding the maximum of %d user defined tags", keys, maxUserTags)
+		issues = append(issues, issue{Reason: "CloudTagsDropped", Message: fmt.Sprintf("dropped tags %s exceeding the maximum of %d user defined tags", strings.Join(keys, ", "), maxUserTags)})
+	}
+
+	err = ct.reportIssues(ctx, cl, scope, issues)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for k, v := range key.AWSTags(getter, ct.installationName) {
+		tags[k] = v
 	}
 
 	return tags, nil
 }
 
+// reportIssues emits the given issues as events on the given Cluster CR in
+// case they differ from the issues recorded for the given scope. The issues
+// of all scopes are recorded in the CloudTagsIssues annotation of the Cluster
+// CR, so that the same issues are not emitted on every reconciliation of every
+// resource.
+func (ct *CloudTags) reportIssues(ctx context.Context, cl apiv1beta1.Cluster, scope string, issues []issue) error {
+	recorded := map[string][]issue{}
+	{
+		v, ok := cl.GetAnnotations()[annotation.CloudTagsIssues]
+		if ok {
+			err := json.Unmarshal([]byte(v), &recorded)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+	}
+
+	if reflect.DeepEqual(recorded[scope], issues) {
+		return nil
+	}
+
+	if len(issues) == 0 {
+		delete(recorded, scope)
+	} else {
+		recorded[scope] = issues
+	}
+
+	a := cl.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	if len(recorded) == 0 {
+		delete(a, annotation.CloudTagsIssues)
+	} else {
+		b, err := json.Marshal(recorded)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		a[annotation.CloudTagsIssues] = string(b)
+	}
+	cl.SetAnnotations(a)
+
+	err := ct.k8sClient.CtrlClient().Update(ctx, &cl)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, i := range issues {
+		ct.event.Emit(ctx, &cl, i.Reason, i.Message)
+	}
+
+	return nil
+}
+
 // IsCloudTagKey check is a tag with proper prefix
 func isCloudTagKey(tagKey string) bool {
 	return strings.HasPrefix(tagKey, key.KeyCloudPrefix)
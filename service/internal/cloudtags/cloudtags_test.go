@@ -2,12 +2,19 @@ package cloudtags
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
 
 	"github.com/giantswarm/micrologger"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
 	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
 )
 
@@ -44,6 +51,8 @@ func Test_CloudTags_GetTagsByCluster(t *testing.T) {
 
 	for i, tc := range testCases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			k := unittest.FakeK8sClient()
+
 			var err error
 
 			var logger micrologger.Logger
@@ -59,8 +68,11 @@ func Test_CloudTags_GetTagsByCluster(t *testing.T) {
 			var ct *CloudTags
 			{
 				c := Config{
-					K8sClient: unittest.FakeK8sClient(),
+					Event:     recorder.New(recorder.Config{K8sClient: k}),
+					K8sClient: k,
 					Logger:    logger,
+
+					InstallationName: "dummy",
 				}
 
 				ct, err = New(c)
@@ -85,3 +97,212 @@ func Test_CloudTags_GetTagsByCluster(t *testing.T) {
 		})
 	}
 }
+
+func Test_CloudTags_NodePoolTags(t *testing.T) {
+	// The node pool defines tag-00 and workload, which leaves room for 38 of
+	// the cluster's 40 tags. The cluster's last tag and the installation's tag
+	// are dropped.
+	tooManyTags := map[string]string{}
+	trimmedTags := map[string]string{
+		"workload": "node-pool",
+	}
+	for i := 0; i < maxUserTags; i++ {
+		tooManyTags[fmt.Sprintf("tag.provider.giantswarm.io/tag-%02d", i)] = "value"
+		if i < maxUserTags-1 {
+			trimmedTags[fmt.Sprintf("tag-%02d", i)] = "value"
+		}
+	}
+	trimmedTags["tag-00"] = "node-pool"
+
+	testCases := []struct {
+		name             string
+		installationTags map[string]string
+		namespaceLabels  map[string]string
+		clusterLabels    map[string]string
+		nodePoolLabels   map[string]string
+		expectedTags     map[string]string
+		expectedEvents   []string
+		errorMatcher     func(error) bool
+	}{
+		{
+			name:         "case 0: no user defined tags",
+			expectedTags: map[string]string{},
+		},
+		{
+			name: "case 1: tags of all sources are merged",
+			installationTags: map[string]string{
+				"cost-center": "installation",
+			},
+			namespaceLabels: map[string]string{
+				"tag.provider.giantswarm.io/team": "organization",
+			},
+			clusterLabels: map[string]string{
+				"tag.provider.giantswarm.io/office": "cluster",
+			},
+			nodePoolLabels: map[string]string{
+				"tag.provider.giantswarm.io/workload": "node-pool",
+			},
+			expectedTags: map[string]string{
+				"cost-center": "installation",
+				"office":      "cluster",
+				"team":        "organization",
+				"workload":    "node-pool",
+			},
+		},
+		{
+			name: "case 2: later sources take precedence",
+			installationTags: map[string]string{
+				"cost-center": "installation",
+			},
+			namespaceLabels: map[string]string{
+				"tag.provider.giantswarm.io/cost-center": "organization",
+				"tag.provider.giantswarm.io/team":        "organization",
+			},
+			clusterLabels: map[string]string{
+				"tag.provider.giantswarm.io/team": "cluster",
+			},
+			nodePoolLabels: map[string]string{
+				"tag.provider.giantswarm.io/team": "node-pool",
+			},
+			expectedTags: map[string]string{
+				"cost-center": "organization",
+				"team":        "node-pool",
+			},
+		},
+		{
+			name: "case 3: reserved and invalid tags are skipped",
+			clusterLabels: map[string]string{
+				"tag.provider.giantswarm.io/giantswarm.io/cluster": "other",
+				"tag.provider.giantswarm.io/aws_team":              "a,b",
+				"tag.provider.giantswarm.io/office":                "cluster",
+			},
+			expectedTags: map[string]string{
+				"office": "cluster",
+			},
+			expectedEvents: []string{
+				"CloudTagSkipped",
+				"CloudTagSkipped",
+			},
+		},
+		{
+			name: "case 4: too many tags are trimmed",
+			installationTags: map[string]string{
+				"cost-center": "installation",
+			},
+			clusterLabels: tooManyTags,
+			nodePoolLabels: map[string]string{
+				"tag.provider.giantswarm.io/tag-00":   "node-pool",
+				"tag.provider.giantswarm.io/workload": "node-pool",
+			},
+			expectedTags: trimmedTags,
+			expectedEvents: []string{
+				"CloudTagsDropped",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			ctx := context.Background()
+			k := unittest.FakeK8sClient()
+			e := &recorderMock{}
+
+			var err error
+
+			var ct *CloudTags
+			{
+				c := Config{
+					Event:     e,
+					K8sClient: k,
+					Logger:    microloggertest.New(),
+
+					InstallationName: "dummy",
+					InstallationTags: tc.installationTags,
+				}
+
+				ct, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			{
+				ns := &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: tc.namespaceLabels,
+						Name:   metav1.NamespaceDefault,
+					},
+				}
+				err = k.CtrlClient().Create(ctx, ns)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				labels := map[string]string{}
+				for k, v := range tc.clusterLabels {
+					labels[k] = v
+				}
+				cl := unittest.DefaultCAPIClusterWithLabels(unittest.DefaultClusterID, labels)
+				err = k.CtrlClient().Create(ctx, &cl)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			md := unittest.DefaultMachineDeployment()
+			for k, v := range tc.nodePoolLabels {
+				md.Labels[k] = v
+			}
+
+			// Computing the tags twice simulates subsequent reconciliations.
+			// Events must only be emitted once.
+			var result map[string]string
+			for j := 0; j < 2; j++ {
+				result, err = ct.NodePoolTags(ctx, md)
+				if err != nil {
+					break
+				}
+			}
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			expected := map[string]string{}
+			for k, v := range tc.expectedTags {
+				expected[k] = v
+			}
+			for k, v := range key.AWSTags(&md, "dummy") {
+				expected[k] = v
+			}
+
+			if !reflect.DeepEqual(expected, result) {
+				t.Fatalf("expected %q got %q", expected, result)
+			}
+			if !reflect.DeepEqual(tc.expectedEvents, e.reasons) {
+				t.Fatalf("expected events %q got %q", tc.expectedEvents, e.reasons)
+			}
+		})
+	}
+}
+
+type recorderMock struct {
+	reasons []string
+}
+
+func (r *recorderMock) Emit(ctx context.Context, obj runtime.Object, reason, message string) {
+	r.reasons = append(r.reasons, reason)
+}
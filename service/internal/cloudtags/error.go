@@ -29,3 +29,12 @@ var tooManyCRsError = &microerror.Error{
 func IsTooManyCRsError(err error) bool {
 	return microerror.Cause(err) == tooManyCRsError
 }
+
+var invalidTagError = &microerror.Error{
+	Kind: "invalidTagError",
+}
+
+// IsInvalidTag asserts invalidTagError.
+func IsInvalidTag(err error) bool {
+	return microerror.Cause(err) == invalidTagError
+}
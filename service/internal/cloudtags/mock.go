@@ -0,0 +1,36 @@
+package cloudtags
+
+import (
+	"context"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+// Mock returns the configured tags for all clusters and node pools without
+// looking up any CRs.
+type Mock struct {
+	Tags map[string]string
+}
+
+func (m *Mock) ClusterTags(ctx context.Context, getter key.LabelsGetter) (map[string]string, error) {
+	return m.copyTags(), nil
+}
+
+func (m *Mock) GetTagsByCluster(ctx context.Context, clusterID string) (map[string]string, error) {
+	return m.copyTags(), nil
+}
+
+func (m *Mock) NodePoolTags(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (map[string]string, error) {
+	return m.copyTags(), nil
+}
+
+func (m *Mock) copyTags() map[string]string {
+	tags := map[string]string{}
+	for k, v := range m.Tags {
+		tags[k] = v
+	}
+
+	return tags
+}
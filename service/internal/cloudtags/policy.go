@@ -0,0 +1,83 @@
+package cloudtags
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// maxKeyLength and maxValueLength are the limits AWS enforces for tag keys
+	// and values in characters.
+	maxKeyLength   = 128
+	maxValueLength = 256
+	// maxUserTags is the maximum number of user defined tags per resource.
+	// AWS allows 50 tags per resource. The remainder is kept for the tags the
+	// operator manages itself.
+	maxUserTags = 40
+)
+
+// reservedKeyPrefixes are the tag key prefixes user defined tags must not use,
+// either because AWS reserves them or because the operator, Kubernetes or the
+// cluster autoscaler rely on the tags.
+var reservedKeyPrefixes = []string{
+	"aws:",
+	"giantswarm.io/",
+	"k8s.io/",
+	"kubernetes.io/",
+}
+
+// reservedKeys are the tag keys user defined tags must not use.
+var reservedKeys = []string{
+	"Name",
+}
+
+// tagRegexp matches the characters AWS allows in tag keys and values across
+// services.
+var tagRegexp = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// validateTag returns invalidTagError in case the given user defined tag
+// cannot be applied to AWS resources.
+func validateTag(k string, v string) error {
+	if k == "" {
+		return microerror.Maskf(invalidTagError, "tag key must not be empty")
+	}
+	if utf8.RuneCountInString(k) > maxKeyLength {
+		return microerror.Maskf(invalidTagError, "tag key %#q must not be longer than %d characters", k, maxKeyLength)
+	}
+	if utf8.RuneCountInString(v) > maxValueLength {
+		return microerror.Maskf(invalidTagError, "value of tag %#q must not be longer than %d characters", k, maxValueLength)
+	}
+	if !tagRegexp.MatchString(k) {
+		return microerror.Maskf(invalidTagError, "tag key %#q must only contain letters, numbers, spaces and _.:/=+-@", k)
+	}
+	if !tagRegexp.MatchString(v) {
+		return microerror.Maskf(invalidTagError, "value of tag %#q must only contain letters, numbers, spaces and _.:/=+-@", k)
+	}
+	for _, r := range reservedKeys {
+		if k == r {
+			return microerror.Maskf(invalidTagError, "tag key %#q is reserved", k)
+		}
+	}
+	for _, p := range reservedKeyPrefixes {
+		if strings.HasPrefix(strings.ToLower(k), p) {
+			return microerror.Maskf(invalidTagError, "tag key %#q must not use the reserved prefix %#q", k, p)
+		}
+	}
+
+	return nil
+}
+
+// cloudTagsFromLabels returns the user defined tags of the given labels.
+func cloudTagsFromLabels(labels map[string]string) map[string]string {
+	tags := map[string]string{}
+	for k, v := range labels {
+		if isCloudTagKey(k) {
+			tags[trimCloudTagKey(k)] = v
+		}
+	}
+
+	return tags
+}
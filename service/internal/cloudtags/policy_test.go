@@ -0,0 +1,99 @@
+package cloudtags
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_CloudTags_validateTag(t *testing.T) {
+	testCases := []struct {
+		name         string
+		key          string
+		value        string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:  "case 0: valid tag",
+			key:   "cost-center",
+			value: "1234",
+		},
+		{
+			name:  "case 1: valid tag with empty value",
+			key:   "team",
+			value: "",
+		},
+		{
+			name:  "case 2: valid tag with special characters",
+			key:   "owner/team.name",
+			value: "a b:c=d+e-f@g",
+		},
+		{
+			name:         "case 3: empty key",
+			key:          "",
+			value:        "1234",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 4: key too long",
+			key:          strings.Repeat("a", 129),
+			value:        "1234",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 5: value too long",
+			key:          "cost-center",
+			value:        strings.Repeat("a", 257),
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 6: invalid characters",
+			key:          "cost-center",
+			value:        "a,b",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 7: aws prefix",
+			key:          "AWS:createdBy",
+			value:        "1234",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 8: giantswarm.io prefix",
+			key:          "giantswarm.io/cluster",
+			value:        "1234",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 9: kubernetes.io prefix",
+			key:          "kubernetes.io/cluster/1234",
+			value:        "owned",
+			errorMatcher: IsInvalidTag,
+		},
+		{
+			name:         "case 10: reserved key",
+			key:          "Name",
+			value:        "1234",
+			errorMatcher: IsInvalidTag,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := validateTag(tc.key, tc.value)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
@@ -2,9 +2,42 @@ package cloudtags
 
 import (
 	"context"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 )
 
+// Interface describes the tag policy applied to the AWS resources of tenant
+// clusters. Tags are merged from the following sources, where later sources
+// take precedence over earlier ones.
+//
+//   - The installation wide tags configured for the operator.
+//   - The tag.provider.giantswarm.io/ labels of the organization namespace
+//     the cluster's CRs live in.
+//   - The tag.provider.giantswarm.io/ labels of the CAPI Cluster CR.
+//   - The tag.provider.giantswarm.io/ labels of the AWSMachineDeployment CR,
+//     for resources of node pools only.
+//   - The tags the operator itself manages, like the cluster ID or the
+//     installation name. These always win.
+//
+// User defined tags violating the AWS limits or using reserved key prefixes
+// are skipped. User defined tags exceeding the maximum number of tags per
+// resource are dropped, starting with the least specific source. Both are
+// reported as events on the CAPI Cluster CR whenever they change.
 type Interface interface {
-	// Get Labels from cluster API object
+	// ClusterTags returns the tags of the cluster scoped resources of the
+	// cluster the given object belongs to.
+	ClusterTags(ctx context.Context, getter key.LabelsGetter) (map[string]string, error)
+	// GetTagsByCluster returns the user defined tags of the CAPI Cluster CR of
+	// the given cluster.
 	GetTagsByCluster(ctx context.Context, clusterID string) (map[string]string, error)
+	// NodePoolTags returns the tags of the resources of the given node pool.
+	NodePoolTags(ctx context.Context, cr infrastructurev1alpha3.AWSMachineDeployment) (map[string]string, error)
+}
+
+// issue is a skipped or dropped user defined tag reported as event.
+type issue struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
 }
@@ -2,48 +2,46 @@ package cphostedzone
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/route53"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 )
 
 type Config struct {
+	DNS    dns.Interface
 	Logger micrologger.Logger
-
-	Route53Enabled bool
 }
 
 type HostedZone struct {
+	dns    dns.Interface
 	logger micrologger.Logger
 
 	cachedCPHostedZoneID         string
 	cachedCPInternalHostedZoneID string
 
 	mutex sync.Mutex
-
-	route53Enabled bool
 }
 
 func New(config Config) (*HostedZone, error) {
+	if config.DNS == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.DNS must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	h := &HostedZone{
+		dns:    config.DNS,
 		logger: config.Logger,
 
 		cachedCPHostedZoneID: "",
 		mutex:                sync.Mutex{},
-
-		route53Enabled: config.Route53Enabled,
 	}
 
 	return h, nil
@@ -55,7 +53,9 @@ func (h *HostedZone) Search(ctx context.Context, obj interface{}) (string, strin
 		return "", "", microerror.Mask(err)
 	}
 
-	if !h.route53Enabled {
+	// The hosted zone IDs are only used by the Cloud Formation stacks of
+	// tenant clusters managing their records in Route53.
+	if !h.dns.Route53() {
 		h.logger.Debugf(ctx, "route53 disabled")
 		h.logger.Debugf(ctx, "canceling resource")
 		return "", "", nil
@@ -66,7 +66,15 @@ func (h *HostedZone) Search(ctx context.Context, obj interface{}) (string, strin
 		return "", "", microerror.Mask(err)
 	}
 
-	cpHostedZoneID, cpInternalHostedZoneID, err := h.lookup(ctx, cc.Client.ControlPlane.AWS.Route53, cr)
+	// Private clusters are not delegated to from the control plane's hosted
+	// zones.
+	if cc.Status.TenantCluster.TCCP.PrivateCluster {
+		h.logger.Debugf(ctx, "private cluster")
+		h.logger.Debugf(ctx, "canceling resource")
+		return "", "", nil
+	}
+
+	cpHostedZoneID, cpInternalHostedZoneID, err := h.lookup(ctx, h.dns.Provider(cc.Client.ControlPlane.AWS.Route53), cr)
 	if err != nil {
 		return "", "", microerror.Mask(err)
 	}
@@ -74,7 +82,7 @@ func (h *HostedZone) Search(ctx context.Context, obj interface{}) (string, strin
 	return cpHostedZoneID, cpInternalHostedZoneID, nil
 }
 
-func (h *HostedZone) lookup(ctx context.Context, client Route53, cr infrastructurev1alpha3.AWSCluster) (cpHostedZoneID, cpInternalHostedZoneID string, err error) {
+func (h *HostedZone) lookup(ctx context.Context, provider dns.Provider, cr infrastructurev1alpha3.AWSCluster) (cpHostedZoneID, cpInternalHostedZoneID string, err error) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
@@ -110,31 +118,37 @@ func (h *HostedZone) lookup(ctx context.Context, client Route53, cr infrastructu
 	// installation. So we look it up.
 	h.logger.Debugf(ctx, "finding CP HostedZone IDs")
 
-	hostedZonesInput := &route53.ListHostedZonesByNameInput{
-		DNSName: aws.String(cr.Spec.Cluster.DNS.Domain),
-	}
-
-	o, err := client.ListHostedZonesByName(hostedZonesInput)
-	if err != nil {
-		return "", "", microerror.Mask(err)
-	}
+	baseDomain := key.ClusterBaseDomain(cr)
 
-	baseDomain := fmt.Sprintf("%s.", key.ClusterBaseDomain(cr))
+	if cpHostedZoneID == "" {
+		zone, err := provider.FindZone(ctx, baseDomain, false)
+		if dns.IsZoneNotFound(err) {
+			return "", "", microerror.Maskf(executionFailedError, "failed to find CP public HostedZone ID for base domain %#q", baseDomain)
+		} else if err != nil {
+			return "", "", microerror.Mask(err)
+		}
+		cpHostedZoneID = zone.ID
 
-	for _, zone := range o.HostedZones {
-		if *zone.Name == baseDomain && !*zone.Config.PrivateZone {
-			h.logger.Debugf(ctx, "found CP public HostedZone ID %#q", cpHostedZoneID)
-			cpHostedZoneID = *zone.Id
+		h.logger.Debugf(ctx, "found CP public HostedZone ID %#q", cpHostedZoneID)
 
-			h.logger.Debugf(ctx, "caching CP public HostedZone ID")
-			h.cachedCPHostedZoneID = cpHostedZoneID
-			h.logger.Debugf(ctx, "cached CP public HostedZone ID")
+		h.logger.Debugf(ctx, "caching CP public HostedZone ID")
+		h.cachedCPHostedZoneID = cpHostedZoneID
+		h.logger.Debugf(ctx, "cached CP public HostedZone ID")
+	}
 
+	// Having private hosted zone in CP is not a requirement.
+	if cpInternalHostedZoneID == "" {
+		zone, err := provider.FindZone(ctx, baseDomain, true)
+		if dns.IsZoneNotFound(err) {
+			h.logger.Debugf(ctx, "did not find CP private HostedZone ID")
+		} else if err != nil {
+			return "", "", microerror.Mask(err)
 		}
 
-		if *zone.Name == baseDomain && *zone.Config.PrivateZone {
+		if zone.ID != "" {
+			cpInternalHostedZoneID = zone.ID
+
 			h.logger.Debugf(ctx, "found CP private HostedZone ID %#q", cpInternalHostedZoneID)
-			cpInternalHostedZoneID = *zone.Id
 
 			h.logger.Debugf(ctx, "caching CP private HostedZone ID")
 			h.cachedCPInternalHostedZoneID = cpInternalHostedZoneID
@@ -142,10 +156,5 @@ func (h *HostedZone) lookup(ctx context.Context, client Route53, cr infrastructu
 		}
 	}
 
-	// Fail only if public hosted zone is missing as having private hosted zone in CP is not a requirement
-	if cpHostedZoneID == "" {
-		return "", "", microerror.Maskf(executionFailedError, "failed to find CP public HostedZone ID for base domain %#q", baseDomain)
-	}
-
 	return cpHostedZoneID, cpInternalHostedZoneID, nil
 }
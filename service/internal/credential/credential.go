@@ -23,33 +23,60 @@ const (
 	DefaultNamespace = "giantswarm"
 )
 
+// Credential is the role ARN of a credential secret together with the
+// secret's identity and version.
+type Credential struct {
+	ARN             string
+	Name            string
+	Namespace       string
+	ResourceVersion string
+}
+
 func GetARN(ctx context.Context, k8sClient kubernetes.Interface, cr infrastructurev1alpha3.AWSCluster) (string, error) {
+	c, err := Get(ctx, k8sClient, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return c.ARN, nil
+}
+
+// Get returns the credential referenced by the given cluster. The resource
+// version allows callers to detect changes of the credential secret.
+func Get(ctx context.Context, k8sClient kubernetes.Interface, cr infrastructurev1alpha3.AWSCluster) (Credential, error) {
 	var err error
 
 	var credential *corev1.Secret
 	{
 		credentialName := key.CredentialName(cr)
 		if credentialName == "" {
-			return "", microerror.Mask(credentialNameEmpty)
+			return Credential{}, microerror.Mask(credentialNameEmpty)
 		}
 
 		credentialNamespace := key.CredentialNamespace(cr)
 		if credentialName == "" {
-			return "", microerror.Mask(credentialNamespaceEmpty)
+			return Credential{}, microerror.Mask(credentialNamespaceEmpty)
 		}
 
 		credential, err = k8sClient.CoreV1().Secrets(credentialNamespace).Get(ctx, credentialName, metav1.GetOptions{})
 		if err != nil {
-			return "", microerror.Mask(err)
+			return Credential{}, microerror.Mask(err)
 		}
 	}
 
 	arn, err := getARN(credential)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return Credential{}, microerror.Mask(err)
 	}
 
-	return arn, nil
+	c := Credential{
+		ARN:             arn,
+		Name:            credential.Name,
+		Namespace:       credential.Namespace,
+		ResourceVersion: credential.ResourceVersion,
+	}
+
+	return c, nil
 }
 
 // GetDefaultARN is used only by the bridgezone resource. It should be removed
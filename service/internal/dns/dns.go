@@ -0,0 +1,67 @@
+package dns
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+type Config struct {
+	// Provider is the name of the DNS provider managing the DNS records of
+	// tenant clusters, either ProviderRoute53 or ProviderRFC2136.
+	Provider string
+	// RFC2136 configures the RFC2136 provider and is only used in case
+	// Provider is ProviderRFC2136.
+	RFC2136 RFC2136ProviderConfig
+	// Route53Enabled defines whether Route53 is used at all in case Provider is
+	// ProviderRoute53, e.g. it is not in China regions.
+	Route53Enabled bool
+}
+
+// DNS selects the DNS provider managing the DNS records of tenant clusters
+// based on the operator's configuration. The Route53 provider works on the
+// AWS account of the Route53 client it is given, while the RFC2136 provider
+// always works on the configured DNS server.
+type DNS struct {
+	provider       string
+	rfc2136        *RFC2136Provider
+	route53Enabled bool
+}
+
+func New(config Config) (*DNS, error) {
+	var err error
+
+	var rfc2136 *RFC2136Provider
+	switch config.Provider {
+	case ProviderRoute53:
+	case ProviderRFC2136:
+		rfc2136, err = NewRFC2136Provider(config.RFC2136)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "%T.Provider must be %#q or %#q, got %#q", config, ProviderRoute53, ProviderRFC2136, config.Provider)
+	}
+
+	d := &DNS{
+		provider:       config.Provider,
+		rfc2136:        rfc2136,
+		route53Enabled: config.Route53Enabled,
+	}
+
+	return d, nil
+}
+
+func (d *DNS) Enabled() bool {
+	return d.provider == ProviderRFC2136 || d.route53Enabled
+}
+
+func (d *DNS) Provider(client Route53Client) Provider {
+	if d.provider == ProviderRFC2136 {
+		return d.rfc2136
+	}
+
+	return NewRoute53Provider(client)
+}
+
+func (d *DNS) Route53() bool {
+	return d.provider == ProviderRoute53 && d.route53Enabled
+}
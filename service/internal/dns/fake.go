@@ -0,0 +1,236 @@
+package dns
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/giantswarm/microerror"
+)
+
+// FakeProvider is an in-memory implementation of Provider for tests. Zones
+// must be added via AddZone before records can be managed in them.
+type FakeProvider struct {
+	mutex sync.Mutex
+
+	records map[string][]Record
+	zones   []Zone
+}
+
+func NewFakeProvider() *FakeProvider {
+	p := &FakeProvider{
+		records: map[string][]Record{},
+	}
+
+	return p
+}
+
+// AddZone adds the given zone served by the given name servers. The zone
+// apex gets an SOA record and the NS record of the name servers, like DNS
+// services create them for new zones.
+func (p *FakeProvider) AddZone(zone Zone, nameServers []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	zone.Name = normalizeName(zone.Name)
+	if zone.ID == "" {
+		zone.ID = zone.Name
+	}
+
+	p.zones = append(p.zones, zone)
+	p.records[zone.ID] = []Record{
+		{
+			Name:   zone.Name,
+			TTL:    delegationTTL,
+			Type:   RecordTypeNS,
+			Values: nameServers,
+		},
+		{
+			Name:   zone.Name,
+			TTL:    delegationTTL,
+			Type:   RecordTypeSOA,
+			Values: []string{nameServers[0]},
+		},
+	}
+}
+
+// Records returns the records of the given zone sorted by name and type.
+func (p *FakeProvider) Records(zone Zone) []Record {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	records := append([]Record{}, p.records[zone.ID]...)
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Type < records[j].Type
+	})
+
+	return records
+}
+
+func (p *FakeProvider) CleanupRecords(ctx context.Context, zone Zone, name string, keep []string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := p.ensureZone(zone)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var records []Record
+	for _, r := range p.records[zone.ID] {
+		isApex := r.Name == normalizeName(zone.Name) && (r.Type == RecordTypeSOA || r.Type == RecordTypeNS)
+		isCleanedUp := isSubdomain(r.Name, normalizeName(name)) && !containsName(keep, r.Name)
+
+		if isCleanedUp && !isApex {
+			continue
+		}
+
+		records = append(records, r)
+	}
+	p.records[zone.ID] = records
+
+	return nil
+}
+
+func (p *FakeProvider) Delegate(ctx context.Context, zone Zone, name string, nameServers []string) error {
+	r := Record{
+		Name:   name,
+		TTL:    delegationTTL,
+		Type:   RecordTypeNS,
+		Values: nameServers,
+	}
+
+	err := p.UpsertRecords(ctx, zone, []Record{r})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (p *FakeProvider) FindZone(ctx context.Context, name string, private bool) (Zone, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, z := range p.zones {
+		if z.Name == normalizeName(name) && z.Private == private {
+			return z, nil
+		}
+	}
+
+	return Zone{}, microerror.Maskf(zoneNotFoundError, "zone name %#q", name)
+}
+
+func (p *FakeProvider) NameServers(ctx context.Context, zone Zone) ([]string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := p.ensureZone(zone)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, r := range p.records[zone.ID] {
+		if r.Name == normalizeName(zone.Name) && r.Type == RecordTypeNS {
+			return r.Values, nil
+		}
+	}
+
+	return nil, microerror.Maskf(executionFailedError, "NS record %#q not found", zone.Name)
+}
+
+func (p *FakeProvider) RemoveDelegation(ctx context.Context, zone Zone, name string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := p.ensureZone(zone)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	p.deleteRecordSet(zone, normalizeName(name), RecordTypeNS)
+
+	return nil
+}
+
+func (p *FakeProvider) UpsertRecords(ctx context.Context, zone Zone, records []Record) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := p.ensureZone(zone)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, r := range records {
+		r.Name = normalizeName(r.Name)
+		if !isSubdomain(r.Name, normalizeName(zone.Name)) {
+			return microerror.Maskf(executionFailedError, "record %#q is not within zone %#q", r.Name, zone.Name)
+		}
+
+		p.deleteRecordSet(zone, r.Name, r.Type)
+		p.records[zone.ID] = append(p.records[zone.ID], r)
+	}
+
+	return nil
+}
+
+func (p *FakeProvider) deleteRecordSet(zone Zone, name, recordType string) {
+	var records []Record
+	for _, r := range p.records[zone.ID] {
+		if r.Name == name && r.Type == recordType {
+			continue
+		}
+		records = append(records, r)
+	}
+	p.records[zone.ID] = records
+}
+
+func (p *FakeProvider) ensureZone(zone Zone) error {
+	for _, z := range p.zones {
+		if z.ID == zone.ID {
+			return nil
+		}
+	}
+
+	return microerror.Maskf(zoneNotFoundError, "zone ID %#q", zone.ID)
+}
+
+type FakeDNSConfig struct {
+	// Provider is the provider returned for all AWS accounts.
+	Provider Provider
+	// Route53 defines whether the DNS records of tenant clusters are managed in
+	// Route53 hosted zones.
+	Route53 bool
+}
+
+// FakeDNS is an implementation of Interface for tests always returning the
+// configured provider.
+type FakeDNS struct {
+	provider Provider
+	route53  bool
+}
+
+func NewFakeDNS(config FakeDNSConfig) *FakeDNS {
+	d := &FakeDNS{
+		provider: config.Provider,
+		route53:  config.Route53,
+	}
+
+	return d
+}
+
+func (d *FakeDNS) Enabled() bool {
+	return true
+}
+
+func (d *FakeDNS) Provider(client Route53Client) Provider {
+	return d.provider
+}
+
+func (d *FakeDNS) Route53() bool {
+	return d.route53
+}
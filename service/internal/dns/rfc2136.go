@@ -0,0 +1,603 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// defaultRFC2136Timeout is the timeout of a single exchange with the DNS
+	// server in case the context does not define a deadline.
+	defaultRFC2136Timeout = 30 * time.Second
+
+	opCodeUpdate = 5
+)
+
+type RFC2136ProviderConfig struct {
+	// Server is the address of the DNS server accepting dynamic updates for the
+	// zones of the tenant clusters, e.g. 10.0.0.2:53. The port defaults to 53.
+	Server string
+	// TSIGAlgorithm is the algorithm of the TSIG key, either
+	// TSIGAlgorithmHMACSHA256 or TSIGAlgorithmHMACSHA512. It defaults to
+	// TSIGAlgorithmHMACSHA256.
+	TSIGAlgorithm string
+	// TSIGKeyName is the name of the TSIG key requests are signed with. Requests
+	// are not signed in case it is empty.
+	TSIGKeyName string
+	// TSIGSecret is the base64 encoded secret of the TSIG key.
+	TSIGSecret string
+}
+
+// RFC2136Provider manages the records of the zones of a DNS server supporting
+// dynamic updates as specified in RFC2136, e.g. BIND or PowerDNS. Zones are
+// not created by the provider and must be managed on the DNS server. Records
+// are listed via zone transfers, which the DNS server must allow for the TSIG
+// key. All requests are sent via TCP and signed in case a TSIG key is
+// configured, in which case the responses must be signed with the same key.
+type RFC2136Provider struct {
+	server string
+	tsig   *tsig
+}
+
+func NewRFC2136Provider(config RFC2136ProviderConfig) (*RFC2136Provider, error) {
+	if config.Server == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Server must not be empty", config)
+	}
+
+	server := config.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	var err error
+	var t *tsig
+	if config.TSIGKeyName != "" {
+		t, err = newTSIG(config.TSIGAlgorithm, config.TSIGKeyName, config.TSIGSecret)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	p := &RFC2136Provider{
+		server: server,
+		tsig:   t,
+	}
+
+	return p, nil
+}
+
+func (p *RFC2136Provider) CleanupRecords(ctx context.Context, zone Zone, name string, keep []string) error {
+	resources, err := p.transfer(ctx, zone)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var deletions []rrSet
+	for _, r := range resources {
+		s := rrSet{
+			Name: normalizeName(r.Header.Name.String()),
+			Type: r.Header.Type,
+		}
+
+		if !isSubdomain(s.Name, normalizeName(name)) {
+			continue
+		}
+		if containsName(keep, s.Name) {
+			continue
+		}
+		if s.Name == normalizeName(zone.Name) && (s.Type == dnsmessage.TypeSOA || s.Type == dnsmessage.TypeNS) {
+			continue
+		}
+		if containsRRSet(deletions, s) {
+			continue
+		}
+
+		deletions = append(deletions, s)
+	}
+
+	if len(deletions) == 0 {
+		return nil
+	}
+
+	err = p.update(ctx, zone, func(b *dnsmessage.Builder) error {
+		for _, s := range deletions {
+			err := deleteRRSet(b, s.Name, s.Type)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (p *RFC2136Provider) Delegate(ctx context.Context, zone Zone, name string, nameServers []string) error {
+	r := Record{
+		Name:   name,
+		TTL:    delegationTTL,
+		Type:   RecordTypeNS,
+		Values: nameServers,
+	}
+
+	err := p.UpsertRecords(ctx, zone, []Record{r})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// FindZone returns the zone having the given name in case the DNS server
+// responds with the SOA record of the name. Private zones are not supported,
+// since the DNS server does not know about VPCs.
+func (p *RFC2136Provider) FindZone(ctx context.Context, name string, private bool) (Zone, error) {
+	if private {
+		return Zone{}, microerror.Maskf(zoneNotFoundError, "private zone %#q is not supported by provider %#q", name, ProviderRFC2136)
+	}
+
+	h, answers, err := p.query(ctx, name, dnsmessage.TypeSOA)
+	if err != nil {
+		return Zone{}, microerror.Mask(err)
+	}
+
+	if h.RCode == dnsmessage.RCodeSuccess {
+		for _, a := range answers {
+			if a.Header.Type == dnsmessage.TypeSOA && normalizeName(a.Header.Name.String()) == normalizeName(name) {
+				zone := Zone{
+					ID:   normalizeName(name),
+					Name: normalizeName(name),
+				}
+
+				return zone, nil
+			}
+		}
+	}
+
+	return Zone{}, microerror.Maskf(zoneNotFoundError, "zone name %#q", name)
+}
+
+func (p *RFC2136Provider) NameServers(ctx context.Context, zone Zone) ([]string, error) {
+	h, answers, err := p.query(ctx, zone.Name, dnsmessage.TypeNS)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if h.RCode != dnsmessage.RCodeSuccess {
+		return nil, microerror.Maskf(executionFailedError, "DNS server responded to NS query for %#q with %s", zone.Name, h.RCode)
+	}
+
+	var nameServers []string
+	for _, a := range answers {
+		ns, ok := a.Body.(*dnsmessage.NSResource)
+		if !ok {
+			continue
+		}
+		nameServers = append(nameServers, normalizeName(ns.NS.String()))
+	}
+
+	if len(nameServers) == 0 {
+		return nil, microerror.Maskf(executionFailedError, "NS record %#q not found", zone.Name)
+	}
+
+	return nameServers, nil
+}
+
+func (p *RFC2136Provider) RemoveDelegation(ctx context.Context, zone Zone, name string) error {
+	err := p.update(ctx, zone, func(b *dnsmessage.Builder) error {
+		return deleteRRSet(b, name, dnsmessage.TypeNS)
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (p *RFC2136Provider) UpsertRecords(ctx context.Context, zone Zone, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	err := p.update(ctx, zone, func(b *dnsmessage.Builder) error {
+		for _, r := range records {
+			err := upsertRRSet(b, r)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// exchange sends the given message to the DNS server and calls read for each
+// message of the response until read returns done. Most responses consist of
+// a single message, while zone transfers may consist of many.
+func (p *RFC2136Provider) exchange(ctx context.Context, msg []byte, read func(msg []byte) (done bool, err error)) error {
+	var err error
+
+	var v *tsigVerifier
+	if p.tsig != nil {
+		var mac []byte
+		msg, mac, err = p.tsig.sign(msg, time.Now())
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		v = newTSIGVerifier(p.tsig, mac)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.server)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultRFC2136Timeout)
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Messages sent via TCP are prefixed with their length.
+	_, err = conn.Write(append(uint16Bytes(uint16(len(msg))), msg...))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for {
+		l := make([]byte, 2)
+		_, err = io.ReadFull(conn, l)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		res := make([]byte, binary.BigEndian.Uint16(l))
+		_, err = io.ReadFull(conn, res)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if len(res) < 2 || binary.BigEndian.Uint16(res[0:2]) != binary.BigEndian.Uint16(msg[0:2]) {
+			return microerror.Maskf(executionFailedError, "DNS server responded with unexpected message ID")
+		}
+
+		if v != nil {
+			err = v.verify(res, time.Now())
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		done, err := read(res)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if done {
+			if v != nil {
+				err = v.done()
+				if err != nil {
+					return microerror.Mask(err)
+				}
+			}
+			return nil
+		}
+	}
+}
+
+func (p *RFC2136Provider) query(ctx context.Context, name string, t dnsmessage.Type) (dnsmessage.Header, []dnsmessage.Resource, error) {
+	n, err := dnsmessage.NewName(normalizeName(name) + ".")
+	if err != nil {
+		return dnsmessage.Header{}, nil, microerror.Mask(err)
+	}
+
+	var msg []byte
+	{
+		b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: newMessageID()})
+		err = b.StartQuestions()
+		if err != nil {
+			return dnsmessage.Header{}, nil, microerror.Mask(err)
+		}
+		err = b.Question(dnsmessage.Question{Name: n, Type: t, Class: dnsmessage.ClassINET})
+		if err != nil {
+			return dnsmessage.Header{}, nil, microerror.Mask(err)
+		}
+		msg, err = b.Finish()
+		if err != nil {
+			return dnsmessage.Header{}, nil, microerror.Mask(err)
+		}
+	}
+
+	var h dnsmessage.Header
+	var answers []dnsmessage.Resource
+	err = p.exchange(ctx, msg, func(res []byte) (bool, error) {
+		var err error
+
+		var pa dnsmessage.Parser
+		h, err = pa.Start(res)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		err = pa.SkipAllQuestions()
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		answers, err = pa.AllAnswers()
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return dnsmessage.Header{}, nil, microerror.Mask(err)
+	}
+
+	return h, answers, nil
+}
+
+// transfer returns all records of the given zone via a zone transfer. The
+// transfer starts and ends with the SOA record of the zone.
+func (p *RFC2136Provider) transfer(ctx context.Context, zone Zone) ([]dnsmessage.Resource, error) {
+	n, err := dnsmessage.NewName(normalizeName(zone.Name) + ".")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var msg []byte
+	{
+		b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: newMessageID()})
+		err = b.StartQuestions()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		err = b.Question(dnsmessage.Question{Name: n, Type: dnsmessage.TypeAXFR, Class: dnsmessage.ClassINET})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		msg, err = b.Finish()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var soas int
+	var resources []dnsmessage.Resource
+	err = p.exchange(ctx, msg, func(res []byte) (bool, error) {
+		var pa dnsmessage.Parser
+		h, err := pa.Start(res)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if h.RCode != dnsmessage.RCodeSuccess {
+			return false, microerror.Maskf(executionFailedError, "DNS server responded to zone transfer of %#q with %s", zone.Name, h.RCode)
+		}
+		err = pa.SkipAllQuestions()
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		answers, err := pa.AllAnswers()
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		for _, a := range answers {
+			if a.Header.Type == dnsmessage.TypeSOA {
+				soas++
+				// The closing SOA record is a repetition of the opening one.
+				if soas == 2 {
+					return true, nil
+				}
+			}
+			resources = append(resources, a)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return resources, nil
+}
+
+// update sends a dynamic update of the given zone to the DNS server. The
+// update section is populated by build.
+func (p *RFC2136Provider) update(ctx context.Context, zone Zone, build func(b *dnsmessage.Builder) error) error {
+	n, err := dnsmessage.NewName(normalizeName(zone.Name) + ".")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var msg []byte
+	{
+		b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: newMessageID(), OpCode: opCodeUpdate})
+		// The zone section of updates uses the layout of the question
+		// section.
+		err = b.StartQuestions()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = b.Question(dnsmessage.Question{Name: n, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		// The prerequisite section uses the layout of the answer section and
+		// the update section the layout of the authority section.
+		err = b.StartAnswers()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = b.StartAuthorities()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = build(&b)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		msg, err = b.Finish()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	err = p.exchange(ctx, msg, func(res []byte) (bool, error) {
+		var pa dnsmessage.Parser
+		h, err := pa.Start(res)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if h.RCode != dnsmessage.RCodeSuccess {
+			return false, microerror.Maskf(executionFailedError, "DNS server responded to update of zone %#q with %s", zone.Name, h.RCode)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+type rrSet struct {
+	Name string
+	Type dnsmessage.Type
+}
+
+func containsRRSet(list []rrSet, s rrSet) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteRRSet adds the deletion of the record set of the given name and type
+// to the update section, which is a record of class ANY without data.
+func deleteRRSet(b *dnsmessage.Builder, name string, t dnsmessage.Type) error {
+	n, err := dnsmessage.NewName(normalizeName(name) + ".")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	h := dnsmessage.ResourceHeader{
+		Name:  n,
+		Class: classANY,
+	}
+
+	err = b.UnknownResource(h, dnsmessage.UnknownResource{Type: t})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// upsertRRSet adds the replacement of the record set of the given record to
+// the update section, which is the deletion of the record set followed by the
+// addition of all its values.
+func upsertRRSet(b *dnsmessage.Builder, r Record) error {
+	n, err := dnsmessage.NewName(normalizeName(r.Name) + ".")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	h := dnsmessage.ResourceHeader{
+		Name:  n,
+		Class: dnsmessage.ClassINET,
+		TTL:   uint32(r.TTL),
+	}
+
+	switch r.Type {
+	case RecordTypeA:
+		err = deleteRRSet(b, r.Name, dnsmessage.TypeA)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, v := range r.Values {
+			ip := net.ParseIP(v).To4()
+			if ip == nil {
+				return microerror.Maskf(executionFailedError, "value %#q of A record %#q must be an IPv4 address", v, r.Name)
+			}
+
+			var a [4]byte
+			copy(a[:], ip)
+
+			err = b.AResource(h, dnsmessage.AResource{A: a})
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+	case RecordTypeCNAME:
+		if len(r.Values) != 1 {
+			return microerror.Maskf(executionFailedError, "CNAME record %#q must have exactly one value, got %d", r.Name, len(r.Values))
+		}
+
+		err = deleteRRSet(b, r.Name, dnsmessage.TypeCNAME)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		v, err := dnsmessage.NewName(normalizeName(r.Values[0]) + ".")
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = b.CNAMEResource(h, dnsmessage.CNAMEResource{CNAME: v})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+	case RecordTypeNS:
+		err = deleteRRSet(b, r.Name, dnsmessage.TypeNS)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, v := range r.Values {
+			ns, err := dnsmessage.NewName(normalizeName(v) + ".")
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			err = b.NSResource(h, dnsmessage.NSResource{NS: ns})
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+	default:
+		return microerror.Maskf(executionFailedError, "record type %#q of record %#q is not supported by provider %#q", r.Type, r.Name, ProviderRFC2136)
+	}
+
+	return nil
+}
+
+func newMessageID() uint16 {
+	return uint16(rand.Intn(1 << 16)) // nolint:gosec
+}
@@ -0,0 +1,686 @@
+package dns
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	testTSIGKeyName = "aws-operator"
+	testTSIGSecret  = "c2VjcmV0LW9mLXRoZS10c2lnLWtleQ=="
+)
+
+// testServer is a minimal authoritative DNS server for a single zone
+// supporting SOA and NS queries, zone transfers and dynamic updates signed
+// with the test TSIG key. Responses are signed with the response secret.
+type testServer struct {
+	listener       net.Listener
+	mutex          sync.Mutex
+	records        []Record
+	responseSecret string
+	zone           string
+}
+
+func newTestServer(t *testing.T, zone string, records []Record) *testServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testServer{
+		listener:       l,
+		records:        records,
+		responseSecret: testTSIGSecret,
+		zone:           zone,
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() {
+		l.Close()
+	})
+
+	return s
+}
+
+func (s *testServer) Records() []Record {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records := append([]Record{}, s.records...)
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Type < records[j].Type
+	})
+
+	return records
+}
+
+func (s *testServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	l := make([]byte, 2)
+	_, err := io.ReadFull(conn, l)
+	if err != nil {
+		return
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(l))
+	_, err = io.ReadFull(conn, msg)
+	if err != nil {
+		return
+	}
+
+	for _, res := range s.handle(msg) {
+		_, err = conn.Write(append(uint16Bytes(uint16(len(res))), res...))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *testServer) handle(msg []byte) [][]byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil {
+		return nil
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil
+	}
+
+	mac, ok := verifyTestTSIG(msg)
+	if !ok {
+		return [][]byte{s.response(h, q, dnsmessage.RCode(9), nil)}
+	}
+
+	if h.OpCode == opCodeUpdate {
+		err = p.SkipAllQuestions()
+		if err != nil {
+			return nil
+		}
+		err = p.SkipAllAnswers()
+		if err != nil {
+			return nil
+		}
+		updates, err := p.AllAuthorities()
+		if err != nil {
+			return nil
+		}
+
+		for _, u := range updates {
+			name := normalizeName(u.Header.Name.String())
+			recordType := recordType(u.Header.Type)
+
+			if u.Header.Class == classANY {
+				var records []Record
+				for _, r := range s.records {
+					if r.Name == name && r.Type == recordType {
+						continue
+					}
+					records = append(records, r)
+				}
+				s.records = records
+				continue
+			}
+
+			r := Record{
+				Name:   name,
+				TTL:    int64(u.Header.TTL),
+				Type:   recordType,
+				Values: []string{recordValue(u.Body)},
+			}
+			s.records = append(s.records, r)
+		}
+
+		return s.sign(mac, s.response(h, q, dnsmessage.RCodeSuccess, nil))
+	}
+
+	name := normalizeName(q.Name.String())
+
+	switch q.Type {
+	case dnsmessage.TypeAXFR:
+		if name != s.zone {
+			return [][]byte{s.response(h, q, dnsmessage.RCodeRefused, nil)}
+		}
+
+		// The transfer is split into three messages, the first and the last
+		// containing one of the SOA records framing the transfer.
+		soa, others := s.soa(), s.others()
+		return s.sign(mac,
+			s.response(h, q, dnsmessage.RCodeSuccess, append([]Record{soa}, others[:len(others)/3]...)),
+			s.response(h, q, dnsmessage.RCodeSuccess, others[len(others)/3:2*len(others)/3]),
+			s.response(h, q, dnsmessage.RCodeSuccess, append(others[2*len(others)/3:], soa)),
+		)
+
+	case dnsmessage.TypeSOA:
+		if name != s.zone {
+			return s.sign(mac, s.response(h, q, dnsmessage.RCodeNameError, nil))
+		}
+		return s.sign(mac, s.response(h, q, dnsmessage.RCodeSuccess, []Record{s.soa()}))
+
+	case dnsmessage.TypeNS:
+		var answers []Record
+		for _, r := range s.records {
+			if r.Name == name && r.Type == RecordTypeNS {
+				answers = append(answers, r)
+			}
+		}
+		return s.sign(mac, s.response(h, q, dnsmessage.RCodeSuccess, answers))
+	}
+
+	return s.sign(mac, s.response(h, q, dnsmessage.RCodeNotImplemented, nil))
+}
+
+// sign signs the given messages of a response to a request with the given MAC
+// independently of the verifying implementation. The first and the last
+// message are signed, while the ones in between are left unsigned. The MAC of
+// the first message covers all TSIG variables, the ones of further messages
+// only the time signed and the fudge. Messages are not signed in case the
+// response secret is empty.
+func (s *testServer) sign(requestMAC []byte, msgs ...[]byte) [][]byte {
+	if s.responseSecret == "" {
+		return msgs
+	}
+
+	keyName := []byte("\x0caws-operator\x00")
+	algorithm := []byte("\x0bhmac-sha256\x00")
+	secret, _ := base64.StdEncoding.DecodeString(s.responseSecret)
+
+	timeSigned := make([]byte, 8)
+	binary.BigEndian.PutUint64(timeSigned, uint64(time.Now().Unix()))
+	timeSigned = timeSigned[2:]
+
+	prior := requestMAC
+	var unsigned []byte
+	var signed [][]byte
+	for i, msg := range msgs {
+		if i != 0 && i != len(msgs)-1 {
+			unsigned = append(unsigned, msg...)
+			signed = append(signed, msg)
+			continue
+		}
+
+		m := hmac.New(sha256.New, secret)
+		m.Write([]byte{0, byte(len(prior))})
+		m.Write(prior)
+		m.Write(unsigned)
+		m.Write(msg)
+		if i == 0 {
+			m.Write(keyName)
+			m.Write([]byte{0, 255, 0, 0, 0, 0})
+			m.Write(algorithm)
+			m.Write(timeSigned)
+			m.Write([]byte{1, 44, 0, 0, 0, 0})
+		} else {
+			m.Write(timeSigned)
+			m.Write([]byte{1, 44})
+		}
+		mac := m.Sum(nil)
+
+		var rdata []byte
+		rdata = append(rdata, algorithm...)
+		rdata = append(rdata, timeSigned...)
+		rdata = append(rdata, 1, 44, 0, byte(len(mac)))
+		rdata = append(rdata, mac...)
+		rdata = append(rdata, msg[0:2]...)
+		rdata = append(rdata, 0, 0, 0, 0)
+
+		res := append([]byte{}, msg...)
+		res = append(res, keyName...)
+		res = append(res, 0, 250, 0, 255, 0, 0, 0, 0, 0, byte(len(rdata)))
+		res = append(res, rdata...)
+		binary.BigEndian.PutUint16(res[10:12], binary.BigEndian.Uint16(res[10:12])+1)
+
+		signed = append(signed, res)
+		prior = mac
+		unsigned = nil
+	}
+
+	return signed
+}
+
+func (s *testServer) others() []Record {
+	var records []Record
+	for _, r := range s.records {
+		if r.Type != RecordTypeSOA {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func (s *testServer) soa() Record {
+	for _, r := range s.records {
+		if r.Type == RecordTypeSOA {
+			return r
+		}
+	}
+	return Record{}
+}
+
+func (s *testServer) response(h dnsmessage.Header, q dnsmessage.Question, rcode dnsmessage.RCode, answers []Record) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: h.ID, Response: true, OpCode: h.OpCode, RCode: rcode})
+	_ = b.StartQuestions()
+	_ = b.Question(q)
+	_ = b.StartAnswers()
+
+	for _, r := range answers {
+		rh := dnsmessage.ResourceHeader{
+			Name:  dnsmessage.MustNewName(r.Name + "."),
+			Class: dnsmessage.ClassINET,
+			TTL:   uint32(r.TTL),
+		}
+		switch r.Type {
+		case RecordTypeA:
+			var a [4]byte
+			copy(a[:], net.ParseIP(r.Values[0]).To4())
+			_ = b.AResource(rh, dnsmessage.AResource{A: a})
+		case RecordTypeCNAME:
+			_ = b.CNAMEResource(rh, dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(r.Values[0] + ".")})
+		case RecordTypeNS:
+			_ = b.NSResource(rh, dnsmessage.NSResource{NS: dnsmessage.MustNewName(r.Values[0] + ".")})
+		case RecordTypeSOA:
+			_ = b.SOAResource(rh, dnsmessage.SOAResource{NS: dnsmessage.MustNewName(r.Values[0] + "."), MBox: dnsmessage.MustNewName("hostmaster." + r.Name + ".")})
+		}
+	}
+
+	msg, _ := b.Finish()
+	return msg
+}
+
+func recordType(t dnsmessage.Type) string {
+	switch t {
+	case dnsmessage.TypeA:
+		return RecordTypeA
+	case dnsmessage.TypeCNAME:
+		return RecordTypeCNAME
+	case dnsmessage.TypeNS:
+		return RecordTypeNS
+	case dnsmessage.TypeSOA:
+		return RecordTypeSOA
+	}
+	return t.String()
+}
+
+func recordValue(b dnsmessage.ResourceBody) string {
+	switch r := b.(type) {
+	case *dnsmessage.AResource:
+		return net.IP(r.A[:]).String()
+	case *dnsmessage.CNAMEResource:
+		return normalizeName(r.CNAME.String())
+	case *dnsmessage.NSResource:
+		return normalizeName(r.NS.String())
+	}
+	return ""
+}
+
+// verifyTestTSIG verifies the TSIG record of the given request independently
+// of the signing implementation and returns its MAC. The TSIG record is the
+// last record of the message and its MAC covers the message without the TSIG
+// record followed by the TSIG variables.
+func verifyTestTSIG(msg []byte) ([]byte, bool) {
+	keyName := []byte("\x0caws-operator\x00")
+	algorithm := []byte("\x0bhmac-sha256\x00")
+
+	// The TSIG record consists of the key name, type, class, TTL and the
+	// length of the data, followed by the algorithm, the 48 bit time signed,
+	// the fudge, the MAC size, the 32 byte MAC of SHA256, the original ID, the
+	// error and the length of the other data.
+	rdataLen := len(algorithm) + 6 + 2 + 2 + sha256.Size + 2 + 2 + 2
+	rrLen := len(keyName) + 10 + rdataLen
+	if len(msg) < 12+rrLen {
+		return nil, false
+	}
+
+	unsigned := append([]byte{}, msg[:len(msg)-rrLen]...)
+	rr := msg[len(msg)-rrLen:]
+	binary.BigEndian.PutUint16(unsigned[10:12], binary.BigEndian.Uint16(unsigned[10:12])-1)
+
+	if string(rr[:len(keyName)]) != string(keyName) {
+		return nil, false
+	}
+	if binary.BigEndian.Uint16(rr[len(keyName):]) != typeTSIG {
+		return nil, false
+	}
+
+	rdata := rr[len(keyName)+10:]
+	if string(rdata[:len(algorithm)]) != string(algorithm) {
+		return nil, false
+	}
+	timeSigned := rdata[len(algorithm) : len(algorithm)+6]
+	mac := rdata[len(algorithm)+10 : len(algorithm)+10+sha256.Size]
+
+	secret, _ := base64.StdEncoding.DecodeString(testTSIGSecret)
+	m := hmac.New(sha256.New, secret)
+	m.Write(unsigned)
+	m.Write(keyName)
+	m.Write([]byte{0, 255, 0, 0, 0, 0})
+	m.Write(algorithm)
+	m.Write(timeSigned)
+	m.Write([]byte{1, 44, 0, 0, 0, 0})
+
+	return mac, hmac.Equal(mac, m.Sum(nil))
+}
+
+func testZoneRecords() []Record {
+	return []Record{
+		{Name: "example.com", TTL: 300, Type: RecordTypeSOA, Values: []string{"ns1.example.com"}},
+		{Name: "example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns1.example.com"}},
+		{Name: "api.abc12.k8s.example.com", TTL: 300, Type: RecordTypeCNAME, Values: []string{"lb.elb.amazonaws.com"}},
+		{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.1"}},
+		{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.2"}},
+		{Name: "www.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.3"}},
+	}
+}
+
+func newTestRFC2136Provider(t *testing.T, s *testServer, secret string) *RFC2136Provider {
+	c := RFC2136ProviderConfig{
+		Server:      s.listener.Addr().String(),
+		TSIGKeyName: testTSIGKeyName,
+		TSIGSecret:  secret,
+	}
+
+	p, err := NewRFC2136Provider(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func Test_RFC2136Provider_FindZone(t *testing.T) {
+	testCases := []struct {
+		name         string
+		zone         string
+		private      bool
+		expectedZone Zone
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: zone apex is found",
+			zone:         "example.com",
+			expectedZone: Zone{ID: "example.com", Name: "example.com"},
+		},
+		{
+			name:         "case 1: subdomain within zone is not a zone",
+			zone:         "abc12.k8s.example.com",
+			errorMatcher: IsZoneNotFound,
+		},
+		{
+			name:         "case 2: private zones are not supported",
+			zone:         "example.com",
+			private:      true,
+			errorMatcher: IsZoneNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			s := newTestServer(t, "example.com", testZoneRecords())
+			p := newTestRFC2136Provider(t, s, testTSIGSecret)
+
+			zone, err := p.FindZone(context.Background(), tc.zone, tc.private)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !cmp.Equal(zone, tc.expectedZone) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedZone, zone))
+			}
+		})
+	}
+}
+
+func Test_RFC2136Provider_Records(t *testing.T) {
+	zone := Zone{ID: "example.com", Name: "example.com"}
+
+	testCases := []struct {
+		name            string
+		f               func(ctx context.Context, p Provider) error
+		expectedRecords []Record
+	}{
+		{
+			name: "case 0: records are created and replaced",
+			f: func(ctx context.Context, p Provider) error {
+				records := []Record{
+					{Name: "api.abc12.k8s.example.com", TTL: 60, Type: RecordTypeCNAME, Values: []string{"new-lb.elb.amazonaws.com"}},
+					{Name: "etcd1.abc12.k8s.example.com", TTL: 60, Type: RecordTypeA, Values: []string{"10.1.0.1"}},
+					{Name: "*.abc12.k8s.example.com", TTL: 60, Type: RecordTypeCNAME, Values: []string{"ingress.abc12.k8s.example.com"}},
+				}
+				return p.UpsertRecords(ctx, zone, records)
+			},
+			expectedRecords: []Record{
+				{Name: "*.abc12.k8s.example.com", TTL: 60, Type: RecordTypeCNAME, Values: []string{"ingress.abc12.k8s.example.com"}},
+				{Name: "api.abc12.k8s.example.com", TTL: 60, Type: RecordTypeCNAME, Values: []string{"new-lb.elb.amazonaws.com"}},
+				{Name: "etcd1.abc12.k8s.example.com", TTL: 60, Type: RecordTypeA, Values: []string{"10.1.0.1"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns1.example.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeSOA, Values: []string{"ns1.example.com"}},
+				{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.1"}},
+				{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.2"}},
+				{Name: "www.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.3"}},
+			},
+		},
+		{
+			name: "case 1: records of the cluster are cleaned up",
+			f: func(ctx context.Context, p Provider) error {
+				return p.CleanupRecords(ctx, zone, "abc12.k8s.example.com", nil)
+			},
+			expectedRecords: []Record{
+				{Name: "example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns1.example.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeSOA, Values: []string{"ns1.example.com"}},
+				{Name: "www.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.3"}},
+			},
+		},
+		{
+			name: "case 2: kept records and the zone apex are not cleaned up",
+			f: func(ctx context.Context, p Provider) error {
+				return p.CleanupRecords(ctx, zone, "example.com", []string{"api.abc12.k8s.example.com"})
+			},
+			expectedRecords: []Record{
+				{Name: "api.abc12.k8s.example.com", TTL: 300, Type: RecordTypeCNAME, Values: []string{"lb.elb.amazonaws.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns1.example.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeSOA, Values: []string{"ns1.example.com"}},
+			},
+		},
+		{
+			name: "case 3: subdomain is delegated",
+			f: func(ctx context.Context, p Provider) error {
+				return p.Delegate(ctx, zone, "abc12.k8s.example.com", []string{"ns-1.awsdns-1.com", "ns-2.awsdns-2.net"})
+			},
+			expectedRecords: []Record{
+				{Name: "abc12.k8s.example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns-1.awsdns-1.com"}},
+				{Name: "abc12.k8s.example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns-2.awsdns-2.net"}},
+				{Name: "api.abc12.k8s.example.com", TTL: 300, Type: RecordTypeCNAME, Values: []string{"lb.elb.amazonaws.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeNS, Values: []string{"ns1.example.com"}},
+				{Name: "example.com", TTL: 300, Type: RecordTypeSOA, Values: []string{"ns1.example.com"}},
+				{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.1"}},
+				{Name: "foo.abc12.k8s.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.2"}},
+				{Name: "www.example.com", TTL: 300, Type: RecordTypeA, Values: []string{"10.0.0.3"}},
+			},
+		},
+		{
+			name: "case 4: delegation is removed",
+			f: func(ctx context.Context, p Provider) error {
+				err := p.Delegate(ctx, zone, "abc12.k8s.example.com", []string{"ns-1.awsdns-1.com"})
+				if err != nil {
+					return err
+				}
+				return p.RemoveDelegation(ctx, zone, "abc12.k8s.example.com")
+			},
+			expectedRecords: testZoneRecords(),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			s := newTestServer(t, "example.com", testZoneRecords())
+			p := newTestRFC2136Provider(t, s, testTSIGSecret)
+
+			err := tc.f(context.Background(), p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := append([]Record{}, tc.expectedRecords...)
+			sort.Slice(expected, func(i, j int) bool {
+				if expected[i].Name != expected[j].Name {
+					return expected[i].Name < expected[j].Name
+				}
+				return expected[i].Type < expected[j].Type
+			})
+
+			records := s.Records()
+			if !cmp.Equal(records, expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(expected, records))
+			}
+		})
+	}
+}
+
+func Test_RFC2136Provider_NameServers(t *testing.T) {
+	s := newTestServer(t, "example.com", testZoneRecords())
+	p := newTestRFC2136Provider(t, s, testTSIGSecret)
+
+	nameServers, err := p.NameServers(context.Background(), Zone{ID: "example.com", Name: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"ns1.example.com"}
+	if !cmp.Equal(nameServers, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, nameServers))
+	}
+}
+
+func Test_RFC2136Provider_InvalidTSIGSecret(t *testing.T) {
+	s := newTestServer(t, "example.com", testZoneRecords())
+	p := newTestRFC2136Provider(t, s, base64.StdEncoding.EncodeToString([]byte("wrong-secret")))
+
+	r := Record{Name: "api.abc12.k8s.example.com", TTL: 60, Type: RecordTypeCNAME, Values: []string{"other.elb.amazonaws.com"}}
+
+	err := p.UpsertRecords(context.Background(), Zone{ID: "example.com", Name: "example.com"}, []Record{r})
+	if err == nil {
+		t.Fatalf("error == nil, want non-nil")
+	}
+
+	if !cmp.Equal(s.Records(), testZoneRecordsSorted()) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(testZoneRecordsSorted(), s.Records()))
+	}
+}
+
+func Test_RFC2136Provider_ResponseTSIG(t *testing.T) {
+	testCases := []struct {
+		name           string
+		responseSecret string
+		errorMatcher   func(error) bool
+	}{
+		{
+			name:           "case 0: signed responses are accepted",
+			responseSecret: testTSIGSecret,
+		},
+		{
+			name:         "case 1: unsigned responses are rejected",
+			errorMatcher: IsInvalidSignature,
+		},
+		{
+			name:           "case 2: responses signed with another secret are rejected",
+			responseSecret: base64.StdEncoding.EncodeToString([]byte("wrong-secret")),
+			errorMatcher:   IsInvalidSignature,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			s := newTestServer(t, "example.com", testZoneRecords())
+			s.responseSecret = tc.responseSecret
+			p := newTestRFC2136Provider(t, s, testTSIGSecret)
+
+			// The zone transfer of the cleanup consists of multiple messages,
+			// not all of which are signed.
+			err := p.CleanupRecords(context.Background(), Zone{ID: "example.com", Name: "example.com"}, "abc12.k8s.example.com", nil)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func Test_FindEnclosingZone(t *testing.T) {
+	s := newTestServer(t, "example.com", testZoneRecords())
+	p := newTestRFC2136Provider(t, s, testTSIGSecret)
+
+	zone, err := FindEnclosingZone(context.Background(), p, "abc12.k8s.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Zone{ID: "example.com", Name: "example.com"}
+	if !cmp.Equal(zone, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, zone))
+	}
+
+	_, err = FindEnclosingZone(context.Background(), p, "abc12.k8s.example.org")
+	if !IsZoneNotFound(err) {
+		t.Fatalf("error == %#v, want matching", err)
+	}
+}
+
+func testZoneRecordsSorted() []Record {
+	records := testZoneRecords()
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Type < records[j].Type
+	})
+	return records
+}
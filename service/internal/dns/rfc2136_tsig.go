@@ -0,0 +1,424 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	TSIGAlgorithmHMACSHA256 = "hmac-sha256"
+	TSIGAlgorithmHMACSHA512 = "hmac-sha512"
+)
+
+const (
+	// tsigFudge is the number of seconds the clocks of the operator and the DNS
+	// server may differ.
+	tsigFudge = 300
+
+	// tsigMaxUnsigned is the maximum number of unsigned messages in a row the
+	// DNS server may send within a response, as specified in RFC8945.
+	tsigMaxUnsigned = 99
+
+	classANY = 255
+	typeTSIG = 250
+)
+
+// tsig signs DNS messages with a transaction signature as specified in
+// RFC8945, which is how DNS servers usually authenticate dynamic updates. The
+// DNS server signs its responses with the same key, see tsigVerifier.
+type tsig struct {
+	algorithm string
+	hash      func() hash.Hash
+	keyName   string
+	secret    []byte
+}
+
+func newTSIG(algorithm, keyName, secret string) (*tsig, error) {
+	if algorithm == "" {
+		algorithm = TSIGAlgorithmHMACSHA256
+	}
+
+	var h func() hash.Hash
+	switch strings.ToLower(algorithm) {
+	case TSIGAlgorithmHMACSHA256:
+		h = sha256.New
+	case TSIGAlgorithmHMACSHA512:
+		h = sha512.New
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "TSIG algorithm must be %#q or %#q, got %#q", TSIGAlgorithmHMACSHA256, TSIGAlgorithmHMACSHA512, algorithm)
+	}
+
+	s, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "TSIG secret must be base64 encoded")
+	}
+	if len(s) == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "TSIG secret must not be empty")
+	}
+
+	t := &tsig{
+		algorithm: strings.ToLower(algorithm),
+		hash:      h,
+		keyName:   normalizeName(keyName),
+		secret:    s,
+	}
+
+	return t, nil
+}
+
+// sign appends the TSIG record to the given DNS message and returns the
+// signed message and its MAC, which the signatures of the responses cover.
+// The message must not contain any additional records yet, since the TSIG
+// record must always be the last one.
+func (t *tsig) sign(msg []byte, now time.Time) ([]byte, []byte, error) {
+	keyName, err := encodeName(t.keyName)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+	algorithm, err := encodeName(t.algorithm)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	// The time signed is a 48 bit number of seconds since epoch.
+	timeSigned := make([]byte, 6)
+	{
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(now.Unix()))
+		copy(timeSigned, b[2:])
+	}
+
+	var mac []byte
+	{
+		m := hmac.New(t.hash, t.secret)
+		m.Write(msg)
+		m.Write(keyName)
+		m.Write(uint16Bytes(classANY))
+		m.Write(uint32Bytes(0))
+		m.Write(algorithm)
+		m.Write(timeSigned)
+		m.Write(uint16Bytes(tsigFudge))
+		// The error and the length of the other data are always empty for
+		// requests.
+		m.Write(uint16Bytes(0))
+		m.Write(uint16Bytes(0))
+		mac = m.Sum(nil)
+	}
+
+	var rdata []byte
+	{
+		rdata = append(rdata, algorithm...)
+		rdata = append(rdata, timeSigned...)
+		rdata = append(rdata, uint16Bytes(tsigFudge)...)
+		rdata = append(rdata, uint16Bytes(uint16(len(mac)))...)
+		rdata = append(rdata, mac...)
+		// The original ID is the ID of the message.
+		rdata = append(rdata, msg[0:2]...)
+		rdata = append(rdata, uint16Bytes(0)...)
+		rdata = append(rdata, uint16Bytes(0)...)
+	}
+
+	signed := make([]byte, 0, len(msg)+len(keyName)+10+len(rdata))
+	signed = append(signed, msg...)
+	signed = append(signed, keyName...)
+	signed = append(signed, uint16Bytes(typeTSIG)...)
+	signed = append(signed, uint16Bytes(classANY)...)
+	signed = append(signed, uint32Bytes(0)...)
+	signed = append(signed, uint16Bytes(uint16(len(rdata)))...)
+	signed = append(signed, rdata...)
+
+	// The TSIG record is counted as additional record.
+	arcount := binary.BigEndian.Uint16(signed[10:12])
+	binary.BigEndian.PutUint16(signed[10:12], arcount+1)
+
+	return signed, mac, nil
+}
+
+// tsigVerifier verifies the transaction signatures of the messages of a
+// response to a signed request. The first and the last message of a response
+// must be signed, while the DNS server may leave up to 99 messages in between
+// unsigned, e.g. during zone transfers. The MAC of each signed message covers
+// the MAC of the request or the previously signed message and all unsigned
+// messages received since.
+type tsigVerifier struct {
+	tsig *tsig
+	// mac is the MAC of the request or the previously signed message.
+	mac []byte
+	// signed is whether a signed message has been received already.
+	signed bool
+	// unsigned are the unsigned messages received since the previously signed
+	// message.
+	unsigned [][]byte
+}
+
+func newTSIGVerifier(t *tsig, mac []byte) *tsigVerifier {
+	v := &tsigVerifier{
+		tsig: t,
+		mac:  mac,
+	}
+
+	return v
+}
+
+// verify verifies the transaction signature of the given message of the
+// response.
+func (v *tsigVerifier) verify(msg []byte, now time.Time) error {
+	offset, err := tsigOffset(msg)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if offset == -1 {
+		if !v.signed {
+			return microerror.Maskf(invalidSignatureError, "DNS server response must be signed")
+		}
+		if len(v.unsigned) == tsigMaxUnsigned {
+			return microerror.Maskf(invalidSignatureError, "DNS server response must not contain more than %d unsigned messages in a row", tsigMaxUnsigned)
+		}
+		v.unsigned = append(v.unsigned, msg)
+		return nil
+	}
+
+	var keyName string
+	var rdata []byte
+	{
+		var p dnsmessage.Parser
+		_, err := p.Start(msg)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = p.SkipAllQuestions()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = p.SkipAllAnswers()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		err = p.SkipAllAuthorities()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		additionals, err := p.AllAdditionals()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r := additionals[len(additionals)-1]
+		u, ok := r.Body.(*dnsmessage.UnknownResource)
+		if !ok {
+			return microerror.Maskf(invalidSignatureError, "DNS server response must contain a valid TSIG record")
+		}
+
+		keyName = normalizeName(r.Header.Name.String())
+		rdata = u.Data
+	}
+
+	if keyName != v.tsig.keyName {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must be signed with TSIG key %#q, got %#q", v.tsig.keyName, keyName)
+	}
+
+	algorithm, err := encodeName(v.tsig.algorithm)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// The TSIG data consists of the uncompressed algorithm, the 48 bit time
+	// signed, the fudge, the MAC size, the MAC, the original ID, the error and
+	// the length of the other data followed by the other data.
+	if len(rdata) < len(algorithm)+10 || !bytes.EqualFold(rdata[:len(algorithm)], algorithm) {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must be signed with TSIG algorithm %#q", v.tsig.algorithm)
+	}
+	timers := rdata[len(algorithm) : len(algorithm)+8]
+	macSize := int(binary.BigEndian.Uint16(rdata[len(algorithm)+8:]))
+	if len(rdata) < len(algorithm)+10+macSize+6 {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must contain a valid TSIG record")
+	}
+	mac := rdata[len(algorithm)+10 : len(algorithm)+10+macSize]
+	originalID := rdata[len(algorithm)+10+macSize : len(algorithm)+10+macSize+2]
+	tsigError := binary.BigEndian.Uint16(rdata[len(algorithm)+10+macSize+2:])
+	other := rdata[len(algorithm)+10+macSize+6:]
+	if len(other) != int(binary.BigEndian.Uint16(rdata[len(algorithm)+10+macSize+4:])) {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must contain a valid TSIG record")
+	}
+
+	if tsigError != 0 {
+		return microerror.Maskf(invalidSignatureError, "DNS server rejected the transaction signature with error %d", tsigError)
+	}
+
+	// The MAC covers the message without the TSIG record, as it was before
+	// signing.
+	unsigned := append([]byte{}, msg[:offset]...)
+	copy(unsigned[0:2], originalID)
+	binary.BigEndian.PutUint16(unsigned[10:12], binary.BigEndian.Uint16(unsigned[10:12])-1)
+
+	var expected []byte
+	{
+		m := hmac.New(v.tsig.hash, v.tsig.secret)
+		m.Write(uint16Bytes(uint16(len(v.mac))))
+		m.Write(v.mac)
+		for _, u := range v.unsigned {
+			m.Write(u)
+		}
+		m.Write(unsigned)
+		// Only the first signed message covers all TSIG variables, all further
+		// ones only cover the time signed and the fudge.
+		if !v.signed {
+			keyName, err := encodeName(v.tsig.keyName)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			m.Write(keyName)
+			m.Write(uint16Bytes(classANY))
+			m.Write(uint32Bytes(0))
+			m.Write(algorithm)
+			m.Write(timers)
+			m.Write(uint16Bytes(tsigError))
+			m.Write(uint16Bytes(uint16(len(other))))
+			m.Write(other)
+		} else {
+			m.Write(timers)
+		}
+		expected = m.Sum(nil)
+	}
+
+	if !hmac.Equal(mac, expected) {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must have a valid transaction signature")
+	}
+
+	var timeSigned int64
+	{
+		b := make([]byte, 8)
+		copy(b[2:], timers[0:6])
+		timeSigned = int64(binary.BigEndian.Uint64(b))
+	}
+	fudge := int64(binary.BigEndian.Uint16(timers[6:8]))
+	if now.Unix() < timeSigned-fudge || now.Unix() > timeSigned+fudge {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must be signed within %d seconds of the current time", fudge)
+	}
+
+	v.mac = mac
+	v.signed = true
+	v.unsigned = nil
+
+	return nil
+}
+
+// done verifies that the last message of the response was signed.
+func (v *tsigVerifier) done() error {
+	if !v.signed || len(v.unsigned) != 0 {
+		return microerror.Maskf(invalidSignatureError, "DNS server response must end with a signed message")
+	}
+
+	return nil
+}
+
+// tsigOffset returns the offset of the TSIG record of the given message or -1
+// in case the message is not signed. The TSIG record is always the last
+// additional record.
+func tsigOffset(msg []byte) (int, error) {
+	if len(msg) < 12 {
+		return 0, microerror.Maskf(executionFailedError, "DNS message must not be shorter than its header")
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:6]))
+	records := int(binary.BigEndian.Uint16(msg[6:8])) + int(binary.BigEndian.Uint16(msg[8:10])) + int(binary.BigEndian.Uint16(msg[10:12]))
+	if binary.BigEndian.Uint16(msg[10:12]) == 0 {
+		return -1, nil
+	}
+
+	var err error
+	offset := 12
+	for i := 0; i < questions; i++ {
+		offset, err = skipName(msg, offset)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+		// The question type and class.
+		offset += 4
+	}
+
+	for i := 0; i < records-1; i++ {
+		offset, err = skipName(msg, offset)
+		if err != nil {
+			return 0, microerror.Mask(err)
+		}
+		// The record type, class, TTL and data length followed by the data.
+		if offset+10 > len(msg) {
+			return 0, microerror.Maskf(executionFailedError, "DNS message must not be truncated")
+		}
+		offset += 10 + int(binary.BigEndian.Uint16(msg[offset+8:offset+10]))
+	}
+
+	n, err := skipName(msg, offset)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+	if n+2 > len(msg) || binary.BigEndian.Uint16(msg[n:n+2]) != typeTSIG {
+		return -1, nil
+	}
+
+	return offset, nil
+}
+
+// skipName returns the offset following the possibly compressed name at the
+// given offset of the given message.
+func skipName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, microerror.Maskf(executionFailedError, "DNS message must not be truncated")
+		}
+
+		l := int(msg[offset])
+		switch {
+		case l == 0:
+			return offset + 1, nil
+		case l&0xC0 == 0xC0:
+			// A pointer to a name elsewhere in the message ends the name.
+			return offset + 2, nil
+		default:
+			offset += 1 + l
+		}
+	}
+}
+
+// encodeName returns the uncompressed wire format of the given name.
+func encodeName(name string) ([]byte, error) {
+	var b []byte
+	for _, l := range strings.Split(normalizeName(name), ".") {
+		if l == "" {
+			continue
+		}
+		if len(l) > 63 {
+			return nil, microerror.Maskf(executionFailedError, "label %#q of name %#q exceeds 63 characters", l, name)
+		}
+		b = append(b, byte(len(l)))
+		b = append(b, l...)
+	}
+	b = append(b, 0)
+
+	return b, nil
+}
+
+func uint16Bytes(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
@@ -0,0 +1,309 @@
+package dns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
+)
+
+const (
+	// delegationTTL is the TTL of the NS records delegating subdomains.
+	delegationTTL = 300
+
+	// route53MaxBatchChars is the maximum number of characters of all record
+	// values of a single change batch.
+	route53MaxBatchChars = 32000
+	// route53MaxBatchRecords is the maximum number of resource records of a
+	// single change batch.
+	route53MaxBatchRecords = 1000
+)
+
+// Route53Provider manages the hosted zones of the AWS account of its Route53
+// client.
+type Route53Provider struct {
+	client Route53Client
+}
+
+func NewRoute53Provider(client Route53Client) *Route53Provider {
+	p := &Route53Provider{
+		client: client,
+	}
+
+	return p
+}
+
+func (p *Route53Provider) CleanupRecords(ctx context.Context, zone Zone, name string, keep []string) error {
+	i := &route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(zone.ID),
+	}
+
+	recordSets, err := awslist.ResourceRecordSets(ctx, p.client, i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var changes []*route53.Change
+	for _, rs := range recordSets {
+		n := route53RecordName(aws.StringValue(rs.Name))
+		t := aws.StringValue(rs.Type)
+
+		if !isSubdomain(n, normalizeName(name)) {
+			continue
+		}
+		if containsName(keep, n) {
+			continue
+		}
+		if n == normalizeName(zone.Name) && (t == RecordTypeSOA || t == RecordTypeNS) {
+			continue
+		}
+
+		// Route53 only deletes record sets matching the given record set
+		// exactly, including e.g. routing policies and health checks, which is
+		// why the listed record set is used as is.
+		c := &route53.Change{
+			Action:            aws.String(route53.ChangeActionDelete),
+			ResourceRecordSet: rs,
+		}
+
+		changes = append(changes, c)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	err = p.changeRecordSets(ctx, zone, changes)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (p *Route53Provider) Delegate(ctx context.Context, zone Zone, name string, nameServers []string) error {
+	r := Record{
+		Name:   name,
+		TTL:    delegationTTL,
+		Type:   RecordTypeNS,
+		Values: nameServers,
+	}
+
+	err := p.UpsertRecords(ctx, zone, []Record{r})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// FindZone lists the hosted zones starting at the given name. Hosted zones
+// are listed in lexicographic order, so that the hosted zones having the given
+// name are listed first.
+//
+// See also
+// https://godoc.org/github.com/aws/aws-sdk-go/service/route53#Route53.ListHostedZonesByName.
+func (p *Route53Provider) FindZone(ctx context.Context, name string, private bool) (Zone, error) {
+	i := &route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(name),
+	}
+
+	hostedZones, err := awslist.HostedZonesByName(ctx, p.client, i)
+	if err != nil {
+		return Zone{}, microerror.Mask(err)
+	}
+
+	for _, z := range hostedZones {
+		if normalizeName(aws.StringValue(z.Name)) != normalizeName(name) {
+			continue
+		}
+		if z.Config != nil && aws.BoolValue(z.Config.PrivateZone) != private {
+			continue
+		}
+
+		zone := Zone{
+			ID:      aws.StringValue(z.Id),
+			Name:    normalizeName(name),
+			Private: private,
+		}
+
+		return zone, nil
+	}
+
+	return Zone{}, microerror.Maskf(zoneNotFoundError, "hosted zone name %#q", name)
+}
+
+func (p *Route53Provider) NameServers(ctx context.Context, zone Zone) ([]string, error) {
+	rs, err := p.findNSRecordSet(ctx, zone, zone.Name)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if rs == nil {
+		return nil, microerror.Maskf(executionFailedError, "NS record %#q for hosted zone %#q not found", zone.Name, zone.ID)
+	}
+
+	var nameServers []string
+	for _, r := range rs.ResourceRecords {
+		nameServers = append(nameServers, normalizeName(aws.StringValue(r.Value)))
+	}
+
+	return nameServers, nil
+}
+
+func (p *Route53Provider) RemoveDelegation(ctx context.Context, zone Zone, name string) error {
+	rs, err := p.findNSRecordSet(ctx, zone, name)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if rs == nil {
+		return nil
+	}
+
+	// Route53 only deletes record sets matching the given record set exactly,
+	// which is why the existing record set is used as is.
+	c := &route53.Change{
+		Action:            aws.String(route53.ChangeActionDelete),
+		ResourceRecordSet: rs,
+	}
+
+	err = p.changeRecordSets(ctx, zone, []*route53.Change{c})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (p *Route53Provider) UpsertRecords(ctx context.Context, zone Zone, records []Record) error {
+	var changes []*route53.Change
+	for _, r := range records {
+		var resourceRecords []*route53.ResourceRecord
+		for _, v := range r.Values {
+			resourceRecords = append(resourceRecords, &route53.ResourceRecord{
+				Value: aws.String(v),
+			})
+		}
+
+		c := &route53.Change{
+			Action: aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name:            aws.String(r.Name),
+				ResourceRecords: resourceRecords,
+				TTL:             aws.Int64(r.TTL),
+				Type:            aws.String(r.Type),
+			},
+		}
+
+		changes = append(changes, c)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	err := p.changeRecordSets(ctx, zone, changes)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// changeRecordSets applies the given changes in as few change batches as
+// Route53 accepts. Each batch is applied atomically, but the batches are not,
+// so that large cleanups may be applied partially in case a batch fails.
+func (p *Route53Provider) changeRecordSets(ctx context.Context, zone Zone, changes []*route53.Change) error {
+	for _, batch := range route53ChangeBatches(changes) {
+		i := &route53.ChangeResourceRecordSetsInput{
+			ChangeBatch: &route53.ChangeBatch{
+				Changes: batch,
+			},
+			HostedZoneId: aws.String(zone.ID),
+		}
+
+		_, err := p.client.ChangeResourceRecordSetsWithContext(ctx, i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// findNSRecordSet returns the NS record set of the given name within zone or
+// nil in case it does not exist.
+func (p *Route53Provider) findNSRecordSet(ctx context.Context, zone Zone, name string) (*route53.ResourceRecordSet, error) {
+	i := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zone.ID),
+		StartRecordName: aws.String(name),
+		StartRecordType: aws.String(route53.RRTypeNs),
+	}
+
+	recordSets, err := awslist.ResourceRecordSets(ctx, p.client, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// Route53 lists the record sets starting at the given name and type, so
+	// that the first record set is some other record set in case the NS
+	// record set does not exist.
+	if len(recordSets) == 0 {
+		return nil, nil
+	}
+
+	rs := recordSets[0]
+	if route53RecordName(aws.StringValue(rs.Name)) != normalizeName(name) || aws.StringValue(rs.Type) != route53.RRTypeNs {
+		return nil, nil
+	}
+
+	return rs, nil
+}
+
+// route53ChangeBatches splits the given changes into batches within the
+// limits Route53 imposes on a single change batch, which are 1000 resource
+// records and 32000 characters of record values. Upserts count twice.
+//
+// See also
+// https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DNSLimitations.html#limits-api-requests-changeresourcerecordsets.
+func route53ChangeBatches(changes []*route53.Change) [][]*route53.Change {
+	var batches [][]*route53.Change
+
+	var batch []*route53.Change
+	var records, chars int
+	for _, c := range changes {
+		r, n := 1, 0
+		if c.ResourceRecordSet != nil && len(c.ResourceRecordSet.ResourceRecords) != 0 {
+			r = len(c.ResourceRecordSet.ResourceRecords)
+			for _, rr := range c.ResourceRecordSet.ResourceRecords {
+				n += len(aws.StringValue(rr.Value))
+			}
+		}
+		if aws.StringValue(c.Action) == route53.ChangeActionUpsert {
+			r, n = 2*r, 2*n
+		}
+
+		if len(batch) != 0 && (records+r > route53MaxBatchRecords || chars+n > route53MaxBatchChars) {
+			batches = append(batches, batch)
+			batch, records, chars = nil, 0, 0
+		}
+
+		batch = append(batch, c)
+		records += r
+		chars += n
+	}
+	if len(batch) != 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// route53RecordName returns the normalized name of a record set listed by
+// Route53, which escapes the wildcard of wildcard records.
+func route53RecordName(name string) string {
+	return normalizeName(strings.ReplaceAll(name, `\052`, "*"))
+}
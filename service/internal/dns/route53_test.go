@@ -0,0 +1,306 @@
+package dns
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeRoute53 struct {
+	changed     []*route53.ResourceRecordSet
+	changes     []string
+	hostedZones []*route53.HostedZone
+	recordSets  []*route53.ResourceRecordSet
+}
+
+func (f *fakeRoute53) ChangeResourceRecordSetsWithContext(ctx context.Context, input *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error) {
+	for _, c := range input.ChangeBatch.Changes {
+		f.changed = append(f.changed, c.ResourceRecordSet)
+		f.changes = append(f.changes, aws.StringValue(c.Action)+" "+aws.StringValue(c.ResourceRecordSet.Type)+" "+aws.StringValue(c.ResourceRecordSet.Name))
+	}
+	return &route53.ChangeResourceRecordSetsOutput{}, nil
+}
+
+// ListHostedZonesByName returns one hosted zone per page, so that hosted zones
+// are only found in case all pages are listed.
+func (f *fakeRoute53) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	var start int
+	for i, z := range f.hostedZones {
+		if aws.StringValue(z.Id) == aws.StringValue(input.HostedZoneId) {
+			start = i
+		}
+	}
+
+	o := &route53.ListHostedZonesByNameOutput{}
+	if start < len(f.hostedZones) {
+		o.HostedZones = f.hostedZones[start : start+1]
+	}
+	if start+1 < len(f.hostedZones) {
+		o.IsTruncated = aws.Bool(true)
+		o.NextDNSName = f.hostedZones[start+1].Name
+		o.NextHostedZoneId = f.hostedZones[start+1].Id
+	}
+
+	return o, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	// The fake only supports starting at a name and type of the listed record
+	// sets as done when looking up single record sets.
+	if input.StartRecordName != nil {
+		for i, rs := range f.recordSets {
+			if aws.StringValue(rs.Name) == aws.StringValue(input.StartRecordName)+"." && aws.StringValue(rs.Type) == aws.StringValue(input.StartRecordType) {
+				return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: f.recordSets[i:]}, nil
+			}
+		}
+		return &route53.ListResourceRecordSetsOutput{}, nil
+	}
+
+	return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: f.recordSets}, nil
+}
+
+func newFakeRecordSet(name, recordType string) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:            aws.String(name),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("ns-1.awsdns-1.com.")}},
+		TTL:             aws.Int64(300),
+		Type:            aws.String(recordType),
+	}
+}
+
+// newFakeLatencyRecordSet returns a record set using latency based routing
+// and a health check, which Route53 only deletes in case all fields match.
+func newFakeLatencyRecordSet(name, recordType string) *route53.ResourceRecordSet {
+	rs := newFakeRecordSet(name, recordType)
+	rs.HealthCheckId = aws.String("abcdef11-2222-3333-4444-555555fedcba")
+	rs.Region = aws.String("eu-central-1")
+	rs.SetIdentifier = aws.String("eu-central-1")
+
+	return rs
+}
+
+func containsRecordSet(list []*route53.ResourceRecordSet, rs *route53.ResourceRecordSet) bool {
+	for _, l := range list {
+		if l == rs {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Test_Route53Provider_CleanupRecords(t *testing.T) {
+	testCases := []struct {
+		name            string
+		keep            []string
+		expectedChanges []string
+	}{
+		{
+			name: "case 0: all record sets of the cluster are deleted",
+			expectedChanges: []string{
+				"DELETE A api.abc12.k8s.example.com.",
+				"DELETE CNAME \\052.abc12.k8s.example.com.",
+				"DELETE NS abc12.k8s.example.com.",
+			},
+		},
+		{
+			name: "case 1: kept record sets are not deleted",
+			keep: []string{"*.abc12.k8s.example.com", "abc12.k8s.example.com"},
+			expectedChanges: []string{
+				"DELETE A api.abc12.k8s.example.com.",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			client := &fakeRoute53{
+				recordSets: []*route53.ResourceRecordSet{
+					newFakeRecordSet("example.com.", route53.RRTypeNs),
+					newFakeRecordSet("example.com.", route53.RRTypeSoa),
+					newFakeRecordSet("\\052.abc12.k8s.example.com.", route53.RRTypeCname),
+					newFakeRecordSet("abc12.k8s.example.com.", route53.RRTypeNs),
+					newFakeLatencyRecordSet("api.abc12.k8s.example.com.", route53.RRTypeA),
+					newFakeRecordSet("api.xyz34.k8s.example.com.", route53.RRTypeA),
+				},
+			}
+
+			p := NewRoute53Provider(client)
+
+			err := p.CleanupRecords(context.Background(), Zone{ID: "/hostedzone/Z1", Name: "example.com"}, "abc12.k8s.example.com", tc.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Strings(client.changes)
+			if !cmp.Equal(client.changes, tc.expectedChanges) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedChanges, client.changes))
+			}
+
+			// Route53 only deletes record sets matching exactly, so the listed
+			// record sets must be deleted as they are.
+			for _, rs := range client.changed {
+				if !containsRecordSet(client.recordSets, rs) {
+					t.Fatalf("expected listed record set got %s", rs)
+				}
+			}
+		})
+	}
+}
+
+func Test_Route53Provider_FindZone(t *testing.T) {
+	testCases := []struct {
+		name         string
+		zone         string
+		private      bool
+		expectedZone Zone
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: public hosted zone is found",
+			zone:         "abc12.k8s.example.com",
+			expectedZone: Zone{ID: "/hostedzone/Z1", Name: "abc12.k8s.example.com"},
+		},
+		{
+			name:         "case 1: private hosted zone is found",
+			zone:         "abc12.k8s.example.com",
+			private:      true,
+			expectedZone: Zone{ID: "/hostedzone/Z2", Name: "abc12.k8s.example.com", Private: true},
+		},
+		{
+			name:         "case 2: hosted zones of subdomains do not match",
+			zone:         "k8s.example.com",
+			errorMatcher: IsZoneNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			client := &fakeRoute53{
+				hostedZones: []*route53.HostedZone{
+					{Id: aws.String("/hostedzone/Z1"), Name: aws.String("abc12.k8s.example.com."), Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)}},
+					{Id: aws.String("/hostedzone/Z2"), Name: aws.String("abc12.k8s.example.com."), Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)}},
+				},
+			}
+
+			p := NewRoute53Provider(client)
+
+			zone, err := p.FindZone(context.Background(), tc.zone, tc.private)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !cmp.Equal(zone, tc.expectedZone) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedZone, zone))
+			}
+		})
+	}
+}
+
+func Test_Route53Provider_RemoveDelegation(t *testing.T) {
+	client := &fakeRoute53{
+		recordSets: []*route53.ResourceRecordSet{
+			newFakeRecordSet("example.com.", route53.RRTypeNs),
+			newFakeRecordSet("abc12.k8s.example.com.", route53.RRTypeNs),
+		},
+	}
+
+	p := NewRoute53Provider(client)
+	zone := Zone{ID: "/hostedzone/Z1", Name: "example.com"}
+
+	err := p.RemoveDelegation(context.Background(), zone, "abc12.k8s.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.RemoveDelegation(context.Background(), zone, "xyz34.k8s.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"DELETE NS abc12.k8s.example.com."}
+	if !cmp.Equal(client.changes, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, client.changes))
+	}
+}
+
+func Test_Route53ChangeBatches(t *testing.T) {
+	testCases := []struct {
+		name          string
+		changes       []*route53.Change
+		expectedSizes []int
+	}{
+		{
+			name:          "case 0: no changes result in no batches",
+			expectedSizes: nil,
+		},
+		{
+			name:          "case 1: deletions are split at 1000 records",
+			changes:       newFakeChanges(2500, route53.ChangeActionDelete, 1, "a"),
+			expectedSizes: []int{1000, 1000, 500},
+		},
+		{
+			name:          "case 2: upserts count twice",
+			changes:       newFakeChanges(600, route53.ChangeActionUpsert, 1, "a"),
+			expectedSizes: []int{500, 100},
+		},
+		{
+			name:          "case 3: record sets with multiple records count each record",
+			changes:       newFakeChanges(300, route53.ChangeActionDelete, 4, "a"),
+			expectedSizes: []int{250, 50},
+		},
+		{
+			name:          "case 4: deletions are split at 32000 characters",
+			changes:       newFakeChanges(400, route53.ChangeActionDelete, 1, strings.Repeat("a", 100)),
+			expectedSizes: []int{320, 80},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var sizes []int
+			for _, b := range route53ChangeBatches(tc.changes) {
+				sizes = append(sizes, len(b))
+			}
+
+			if !cmp.Equal(sizes, tc.expectedSizes) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedSizes, sizes))
+			}
+		})
+	}
+}
+
+func newFakeChanges(n int, action string, records int, value string) []*route53.Change {
+	var changes []*route53.Change
+	for i := 0; i < n; i++ {
+		rs := newFakeRecordSet("r"+strconv.Itoa(i)+".example.com.", route53.RRTypeA)
+		rs.ResourceRecords = nil
+		for j := 0; j < records; j++ {
+			rs.ResourceRecords = append(rs.ResourceRecords, &route53.ResourceRecord{Value: aws.String(value)})
+		}
+
+		changes = append(changes, &route53.Change{Action: aws.String(action), ResourceRecordSet: rs})
+	}
+
+	return changes
+}
@@ -0,0 +1,87 @@
+package dns
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+const (
+	// ProviderRoute53 manages the DNS records of tenant clusters in Route53
+	// hosted zones, which are mostly managed by the Cloud Formation stacks of
+	// the tenant clusters.
+	ProviderRoute53 = "route53"
+	// ProviderRFC2136 manages the DNS records of tenant clusters on a DNS
+	// server supporting dynamic updates as specified in RFC2136, e.g. BIND or
+	// PowerDNS.
+	ProviderRFC2136 = "rfc2136"
+)
+
+const (
+	RecordTypeA     = "A"
+	RecordTypeCNAME = "CNAME"
+	RecordTypeNS    = "NS"
+	RecordTypeSOA   = "SOA"
+)
+
+// Interface selects the DNS provider managing the DNS records of tenant
+// clusters.
+type Interface interface {
+	// Enabled returns whether the DNS records of tenant clusters are managed at
+	// all.
+	Enabled() bool
+	// Provider returns the DNS provider to use for the AWS account of the given
+	// Route53 client. The client is ignored by providers other than Route53.
+	Provider(client Route53Client) Provider
+	// Route53 returns whether the DNS records of tenant clusters are managed in
+	// Route53 hosted zones. The hosted zones and most of their records are then
+	// managed by the Cloud Formation stacks of the tenant clusters.
+	Route53() bool
+}
+
+// Provider manages zones and records of a DNS service. All names are fully
+// qualified domain names without trailing dot.
+type Provider interface {
+	// CleanupRecords deletes all records within zone having the given name or
+	// being below it, except the records having one of the names in keep. The
+	// SOA and NS records of the zone apex are never deleted.
+	CleanupRecords(ctx context.Context, zone Zone, name string, keep []string) error
+	// Delegate ensures the NS records of the subdomain name within zone point
+	// to the given name servers.
+	Delegate(ctx context.Context, zone Zone, name string, nameServers []string) error
+	// FindZone returns the public or private zone with the given name. The
+	// returned error is matched by IsZoneNotFound in case the zone does not
+	// exist.
+	FindZone(ctx context.Context, name string, private bool) (Zone, error)
+	// NameServers returns the name servers the given zone is served by.
+	NameServers(ctx context.Context, zone Zone) ([]string, error)
+	// RemoveDelegation removes the NS records of the subdomain name within
+	// zone. Delegations not existing are ignored.
+	RemoveDelegation(ctx context.Context, zone Zone, name string) error
+	// UpsertRecords creates the given records within zone or replaces the
+	// existing records of the same name and type.
+	UpsertRecords(ctx context.Context, zone Zone, records []Record) error
+}
+
+// Route53Client is the subset of the Route53 API used by the Route53 provider.
+type Route53Client interface {
+	ChangeResourceRecordSetsWithContext(ctx context.Context, input *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error)
+	ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error)
+	ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error)
+}
+
+type Record struct {
+	Name   string
+	TTL    int64
+	Type   string
+	Values []string
+}
+
+type Zone struct {
+	// ID is the provider specific identifier of the zone, e.g. the hosted zone
+	// ID in case of Route53.
+	ID      string
+	Name    string
+	Private bool
+}
@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// FindEnclosingZone returns the public zone name belongs to, which is either
+// the zone of name itself or the zone of the closest parent domain. DNS
+// servers managed by customers usually do not provide a zone per tenant
+// cluster, so that the records of the tenant cluster are managed within the
+// zone of e.g. the installation's base domain.
+func FindEnclosingZone(ctx context.Context, p Provider, name string) (Zone, error) {
+	for n := name; n != ""; n = parentName(n) {
+		z, err := p.FindZone(ctx, n, false)
+		if IsZoneNotFound(err) {
+			continue
+		} else if err != nil {
+			return Zone{}, microerror.Mask(err)
+		}
+
+		return z, nil
+	}
+
+	return Zone{}, microerror.Maskf(zoneNotFoundError, "zone of %#q", name)
+}
+
+func containsName(list []string, name string) bool {
+	for _, l := range list {
+		if l == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSubdomain returns whether name is domain or below it.
+func isSubdomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// normalizeName returns name in lower case and without trailing dot.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func parentName(name string) string {
+	i := strings.Index(name, ".")
+	if i == -1 {
+		return ""
+	}
+
+	return name[i+1:]
+}
@@ -3,6 +3,7 @@ package ebs
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/awslist"
 )
 
 const (
@@ -46,6 +48,59 @@ func New(config Config) (*EBS, error) {
 	return e, nil
 }
 
+// CreateSnapshot creates a snapshot of the given EBS volume tagged with the
+// given tags and returns the ID of the created snapshot.
+func (e *EBS) CreateSnapshot(ctx context.Context, volumeID string, tags map[string]string) (string, error) {
+	e.logger.Debugf(ctx, "creating snapshot of EBS volume %#q", volumeID)
+
+	var ec2Tags []*ec2.Tag
+	for _, k := range sortedKeys(tags) {
+		ec2Tags = append(ec2Tags, &ec2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+
+	i := &ec2.CreateSnapshotInput{
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+				Tags:         ec2Tags,
+			},
+		},
+		VolumeId: aws.String(volumeID),
+	}
+
+	o, err := e.client.CreateSnapshot(i)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	e.logger.Debugf(ctx, "created snapshot %#q of EBS volume %#q", *o.SnapshotId, volumeID)
+
+	return *o.SnapshotId, nil
+}
+
+// DeleteSnapshot deletes an EBS snapshot.
+func (e *EBS) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	e.logger.Debugf(ctx, "deleting EBS snapshot %#q", snapshotID)
+
+	i := &ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(snapshotID),
+	}
+
+	_, err := e.client.DeleteSnapshot(i)
+	if IsSnapshotNotFound(err) {
+		// Fall through.
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	e.logger.Debugf(ctx, "deleted EBS snapshot %#q", snapshotID)
+
+	return nil
+}
+
 // DeleteVolume deletes an EBS volume with retry logic.
 func (e *EBS) DeleteVolume(ctx context.Context, volumeID string) error {
 	e.logger.Debugf(ctx, "deleting EBS volume %#q", volumeID)
@@ -143,21 +198,21 @@ func (e *EBS) DetachVolume(ctx context.Context, volumeID string, attachment Volu
 			},
 		}
 
-		v, err := e.client.DescribeVolumes(i)
+		v, err := awslist.Volumes(ctx, e.client, i)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		if len(v.Volumes) == 0 {
+		if len(v) == 0 {
 			// Fall through.
 			return microerror.Maskf(executionFailedError, "no volume found")
 		}
 
-		if len(v.Volumes) > 1 {
+		if len(v) > 1 {
 			return microerror.Maskf(executionFailedError, "more than one volume has been found")
 		}
 
-		d := v.Volumes[0]
+		d := v[0]
 		if len(d.Attachments) > 1 {
 			// Shouldn't happen; log so we know if it is
 			e.logger.Debugf(ctx, "found multiple attachment for volume %#q", volumeID)
@@ -200,12 +255,12 @@ func (e *EBS) ListVolumes(ctx context.Context, cr infrastructurev1alpha3.AWSClus
 		},
 	}
 
-	o, err := e.client.DescribeVolumes(i)
+	ebsVolumes, err := awslist.Volumes(ctx, e.client, i)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	for _, v := range o.Volumes {
+	for _, v := range ebsVolumes {
 		if !IsFiltered(v, filterFuncs) {
 			continue
 		}
@@ -220,14 +275,14 @@ func (e *EBS) ListVolumes(ctx context.Context, cr infrastructurev1alpha3.AWSClus
 						InstanceIds: aws.StringSlice([]string{*a.InstanceId}),
 					}
 
-					o, err := e.client.DescribeInstances(i)
+					instances, err := awslist.Instances(ctx, e.client, i)
 					if err != nil {
 						return nil, microerror.Mask(err)
 					}
 
 					hasWrongClusterID := false
-					if len(o.Reservations) > 0 && len(o.Reservations[0].Instances) > 0 {
-						hasWrongClusterID = awstags.ValueForKey(o.Reservations[0].Instances[0].Tags, key.TagCluster) != cr.Labels[key.TagCluster]
+					if len(instances) > 0 {
+						hasWrongClusterID = awstags.ValueForKey(instances[0].Tags, key.TagCluster) != cr.Labels[key.TagCluster]
 					}
 
 					// if the instance does not have the proper cluster-id tag than ignore the volume
@@ -255,6 +310,7 @@ func (e *EBS) ListVolumes(ctx context.Context, cr infrastructurev1alpha3.AWSClus
 		volume := Volume{
 			VolumeID:    *v.VolumeId,
 			Attachments: attachments,
+			Name:        awstags.ValueForKey(v.Tags, nameTagKey),
 		}
 
 		volumes = append(volumes, volume)
@@ -262,3 +318,81 @@ func (e *EBS) ListVolumes(ctx context.Context, cr infrastructurev1alpha3.AWSClus
 
 	return volumes, nil
 }
+
+// ListSnapshots lists the EBS snapshots owned by the account which are tagged
+// with all of the given tags.
+func (e *EBS) ListSnapshots(ctx context.Context, tags map[string]string) ([]Snapshot, error) {
+	i := &ec2.DescribeSnapshotsInput{
+		OwnerIds: aws.StringSlice([]string{"self"}),
+	}
+	for _, k := range sortedKeys(tags) {
+		i.Filters = append(i.Filters, &ec2.Filter{
+			Name:   aws.String(fmt.Sprintf("tag:%s", k)),
+			Values: aws.StringSlice([]string{tags[k]}),
+		})
+	}
+
+	snapshots, err := e.listSnapshots(ctx, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return snapshots, nil
+}
+
+// ListVolumeSnapshots lists the EBS snapshots owned by the account which were
+// taken of any of the given volumes.
+func (e *EBS) ListVolumeSnapshots(ctx context.Context, volumeIDs []string) ([]Snapshot, error) {
+	i := &ec2.DescribeSnapshotsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: aws.StringSlice(volumeIDs),
+			},
+		},
+		OwnerIds: aws.StringSlice([]string{"self"}),
+	}
+
+	snapshots, err := e.listSnapshots(ctx, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return snapshots, nil
+}
+
+func (e *EBS) listSnapshots(ctx context.Context, i *ec2.DescribeSnapshotsInput) ([]Snapshot, error) {
+	var snapshots []Snapshot
+
+	ebsSnapshots, err := awslist.Snapshots(ctx, e.client, i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, s := range ebsSnapshots {
+		t := map[string]string{}
+		for _, tag := range s.Tags {
+			t[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			SnapshotID: aws.StringValue(s.SnapshotId),
+			StartTime:  aws.TimeValue(s.StartTime),
+			State:      aws.StringValue(s.State),
+			Tags:       t,
+			VolumeID:   aws.StringValue(s.VolumeId),
+		})
+	}
+
+	return snapshots, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
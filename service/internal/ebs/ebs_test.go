@@ -89,6 +89,7 @@ func Test_ListVolumes(t *testing.T) {
 				{
 					Attachments: []VolumeAttachment{},
 					VolumeID:    "vol-6789",
+					Name:        "test-cluster-etcd",
 				},
 			},
 			ebsVolumes: []ebsVolumeMock{
@@ -255,6 +256,7 @@ func Test_ListVolumes(t *testing.T) {
 				{
 					Attachments: []VolumeAttachment{},
 					VolumeID:    "vol-6789",
+					Name:        "test-cluster-etcd",
 				},
 			},
 			ebsVolumes: []ebsVolumeMock{
@@ -387,6 +389,66 @@ func Test_ListVolumes(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "case 9: only control plane etcd volumes",
+			obj:         customObject,
+			filterFuncs: []func(t *ec2.Tag) bool{
+				NewControlPlaneEtcdVolumeFilter(customObject),
+			},
+			expectedVolumes: []Volume{
+				{
+					Attachments: []VolumeAttachment{},
+					VolumeID:    "vol-1234",
+					Name:        "test-cluster-master1-etcd",
+				},
+				{
+					Attachments: []VolumeAttachment{},
+					VolumeID:    "vol-5678",
+					Name:        "test-cluster-master2-etcd",
+				},
+			},
+			ebsVolumes: []ebsVolumeMock{
+				{
+					volumeID: "vol-1234",
+					tags: []*ec2.Tag{
+						{
+							Key:   aws.String("kubernetes.io/cluster/test-cluster"),
+							Value: aws.String("owned"),
+						},
+						{
+							Key:   aws.String("Name"),
+							Value: aws.String("test-cluster-master1-etcd"),
+						},
+					},
+				},
+				{
+					volumeID: "vol-5678",
+					tags: []*ec2.Tag{
+						{
+							Key:   aws.String("kubernetes.io/cluster/test-cluster"),
+							Value: aws.String("owned"),
+						},
+						{
+							Key:   aws.String("Name"),
+							Value: aws.String("test-cluster-master2-etcd"),
+						},
+					},
+				},
+				{
+					volumeID: "vol-6789",
+					tags: []*ec2.Tag{
+						{
+							Key:   aws.String("kubernetes.io/cluster/test-cluster"),
+							Value: aws.String("owned"),
+						},
+						{
+							Key:   aws.String("Name"),
+							Value: aws.String("test-cluster-etcd"),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
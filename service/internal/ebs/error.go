@@ -60,3 +60,17 @@ var volumeAttachedError = &microerror.Error{
 func IsVolumeAttached(err error) bool {
 	return microerror.Cause(err) == volumeAttachedError
 }
+
+// IsSnapshotNotFound asserts snapshot not found error from upstream's API code.
+func IsSnapshotNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	return aerr.Code() == "InvalidSnapshot.NotFound"
+}
@@ -1,6 +1,9 @@
 package ebs
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/aws/aws-sdk-go/service/ec2"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
 
@@ -42,6 +45,16 @@ func NewEtcdVolumeFilter(cr infrastructurev1alpha3.AWSCluster) func(t *ec2.Tag)
 	}
 }
 
+// NewControlPlaneEtcdVolumeFilter matches the etcd volumes of the control plane
+// nodes managed by the TCCPN stack, which are named after their master ID.
+func NewControlPlaneEtcdVolumeFilter(cr infrastructurev1alpha3.AWSCluster) func(t *ec2.Tag) bool {
+	r := regexp.MustCompile(fmt.Sprintf(`^%s-master[0-9]+-etcd$`, regexp.QuoteMeta(key.ClusterID(&cr))))
+
+	return func(t *ec2.Tag) bool {
+		return *t.Key == nameTagKey && r.MatchString(*t.Value)
+	}
+}
+
 func NewPersistentVolumeFilter(cr infrastructurev1alpha3.AWSCluster) func(t *ec2.Tag) bool {
 	return func(t *ec2.Tag) bool {
 		return *t.Key == cloudProviderPersistentVolumeTagKey
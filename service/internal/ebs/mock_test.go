@@ -19,6 +19,14 @@ type ebsVolumeMock struct {
 	tags        []*ec2.Tag
 }
 
+func (e *EC2ClientMock) CreateSnapshot(*ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	return nil, nil
+}
+
+func (e *EC2ClientMock) DeleteSnapshot(*ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	return nil, nil
+}
+
 func (e *EC2ClientMock) DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
 	return nil, nil
 }
@@ -47,6 +55,10 @@ func (e *EC2ClientMock) DescribeInstances(input *ec2.DescribeInstancesInput) (*e
 	return o, nil
 }
 
+func (e *EC2ClientMock) DescribeSnapshots(*ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	return &ec2.DescribeSnapshotsOutput{}, nil
+}
+
 func (e *EC2ClientMock) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
 	output := &ec2.DescribeVolumesOutput{}
 	volumes := []*ec2.Volume{}
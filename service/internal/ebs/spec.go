@@ -2,6 +2,7 @@ package ebs
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
@@ -9,6 +10,11 @@ import (
 
 // Interface describes the methods provided by the helm client.
 type Interface interface {
+	// CreateSnapshot creates a snapshot of the given EBS volume tagged with the
+	// given tags and returns the ID of the created snapshot.
+	CreateSnapshot(ctx context.Context, volumeID string, tags map[string]string) (string, error)
+	// DeleteSnapshot deletes an EBS snapshot.
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
 	// DeleteVolume deletes an EBS volume with retry logic.
 	DeleteVolume(ctx context.Context, volumeID string) error
 	// DetachVolume detaches an EBS volume. If force is specified data loss may
@@ -19,22 +25,42 @@ type Interface interface {
 	// persistentVolume is true then any Persistent Volumes associated with the
 	// cluster will be returned.
 	ListVolumes(ctx context.Context, customObject infrastructurev1alpha3.AWSCluster, filterFuncs ...func(t *ec2.Tag) bool) ([]Volume, error)
+	// ListSnapshots lists the EBS snapshots owned by the account which are
+	// tagged with all of the given tags.
+	ListSnapshots(ctx context.Context, tags map[string]string) ([]Snapshot, error)
+	// ListVolumeSnapshots lists the EBS snapshots owned by the account which
+	// were taken of any of the given volumes.
+	ListVolumeSnapshots(ctx context.Context, volumeIDs []string) ([]Snapshot, error)
 }
 
 // EC2Client describes the methods required to be implemented by an EC2 AWS client.
 type EC2Client interface {
+	CreateSnapshot(*ec2.CreateSnapshotInput) (*ec2.Snapshot, error)
+	DeleteSnapshot(*ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
 	DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
 	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	DescribeSnapshots(*ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error)
 	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 	DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
 	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
 	WaitUntilInstanceStopped(*ec2.DescribeInstancesInput) error
 }
 
+// Snapshot is an EBS snapshot of a volume.
+type Snapshot struct {
+	SnapshotID string
+	StartTime  time.Time
+	State      string
+	Tags       map[string]string
+	VolumeID   string
+}
+
 // Volume is an EBS volume and its attachments.
 type Volume struct {
 	VolumeID    string
 	Attachments []VolumeAttachment
+	// Name is the value of the volume's Name tag.
+	Name string
 }
 
 // VolumeAttachment is an EBS volume attached to an EC2 instance.
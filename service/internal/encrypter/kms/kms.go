@@ -12,37 +12,34 @@ import (
 	"github.com/giantswarm/aws-operator/v16/pkg/awstags"
 	"github.com/giantswarm/aws-operator/v16/service/controller/controllercontext"
 	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
 )
 
 type EncrypterConfig struct {
-	Logger micrologger.Logger
-
-	InstallationName string
+	CloudTags cloudtags.Interface
+	Logger    micrologger.Logger
 }
 
 type Encrypter struct {
-	logger micrologger.Logger
+	cloudTags cloudtags.Interface
+	logger    micrologger.Logger
 
 	cache *Cache
-
-	installationName string
 }
 
 func NewEncrypter(c *EncrypterConfig) (*Encrypter, error) {
+	if c.CloudTags == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CloudTags must not be empty", c)
+	}
 	if c.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", c)
 	}
 
-	if c.InstallationName == "" {
-		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", c)
-	}
-
 	kms := &Encrypter{
-		logger: c.Logger,
+		cloudTags: c.CloudTags,
+		logger:    c.Logger,
 
 		cache: NewCache(),
-
-		installationName: c.InstallationName,
 	}
 
 	return kms, nil
@@ -99,7 +96,10 @@ func (e *Encrypter) EnsureCreatedEncryptionKey(ctx context.Context, cr infrastru
 	{
 		e.logger.Debugf(ctx, "creating encryption key")
 
-		tags := key.AWSTags(&cr, e.installationName)
+		tags, err := e.cloudTags.ClusterTags(ctx, &cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
 
 		// TODO already created case should be handled here. Otherwise there is a
 		// chance alias wasn't created yet and EncryptionKey will tell there is no
@@ -0,0 +1,23 @@
+package images
+
+import (
+	k8scloudconfig "github.com/giantswarm/k8scloudconfig/v18/pkg/template"
+)
+
+const (
+	arm64TagSuffix = "-arm64"
+)
+
+// arm64Images returns the given cloud config images for arm64 nodes. Upstream
+// Kubernetes, etcd and Calico images are published as multi-arch manifests,
+// which container runtimes resolve to the node's architecture. Images built by
+// Giant Swarm are published as amd64 images and arm64 variants tagged with the
+// arm64 suffix.
+func arm64Images(im k8scloudconfig.Images) k8scloudconfig.Images {
+	im.Envsubst = im.Envsubst + arm64TagSuffix
+	im.Hyperkube = im.Hyperkube + arm64TagSuffix
+	im.KubernetesAPIHealthz = im.KubernetesAPIHealthz + arm64TagSuffix
+	im.KubernetesNetworkSetupDocker = im.KubernetesNetworkSetupDocker + arm64TagSuffix
+
+	return im
+}
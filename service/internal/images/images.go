@@ -54,7 +54,7 @@ func New(c Config) (*Images, error) {
 	return i, nil
 }
 
-func (i *Images) AMI(ctx context.Context, obj interface{}, flatcarReleaseVersion string) (string, error) {
+func (i *Images) AMI(ctx context.Context, obj interface{}, flatcarReleaseVersion string, architecture string) (string, error) {
 	cr, err := meta.Accessor(obj)
 	if err != nil {
 		return "", microerror.Mask(err)
@@ -70,7 +70,7 @@ func (i *Images) AMI(ctx context.Context, obj interface{}, flatcarReleaseVersion
 		return "", microerror.Mask(err)
 	}
 
-	ami, err := key.AMI(key.Region(cl), re, flatcarReleaseVersion)
+	ami, err := key.AMI(key.Region(cl), re, flatcarReleaseVersion, architecture)
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
@@ -98,7 +98,7 @@ func (i *Images) AWSCNI(ctx context.Context, obj interface{}) (string, error) {
 	return "", microerror.Maskf(notFoundError, "aws cni version not found in the release")
 }
 
-func (i *Images) CC(ctx context.Context, obj interface{}) (k8scloudconfig.Images, error) {
+func (i *Images) CC(ctx context.Context, obj interface{}, architecture string) (k8scloudconfig.Images, error) {
 	var im k8scloudconfig.Images
 	{
 		v, err := i.Versions(ctx, obj)
@@ -109,6 +109,10 @@ func (i *Images) CC(ctx context.Context, obj interface{}) (k8scloudconfig.Images
 		im = k8scloudconfig.BuildImages(i.registryDomain, v)
 	}
 
+	if architecture == key.ArchitectureARM64 {
+		im = arm64Images(im)
+	}
+
 	return im, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/giantswarm/operatorkit/v7/pkg/controller/context/cachekeycontext"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
 
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
 	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
 )
 
@@ -130,7 +131,7 @@ func Test_Images_Cache(t *testing.T) {
 			}
 
 			{
-				ami1, err = im.AMI(tc.ctx, &cl, "")
+				ami1, err = im.AMI(tc.ctx, &cl, "", key.ArchitectureAMD64)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -157,7 +158,7 @@ func Test_Images_Cache(t *testing.T) {
 			}
 
 			{
-				ami2, err = im.AMI(tc.ctx, &cl, "")
+				ami2, err = im.AMI(tc.ctx, &cl, "", key.ArchitectureAMD64)
 				if err != nil {
 					t.Fatal(err)
 				}
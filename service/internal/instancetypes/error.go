@@ -0,0 +1,42 @@
+package instancetypes
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var instanceTypeNotFoundError = &microerror.Error{
+	Kind: "instanceTypeNotFoundError",
+}
+
+// IsInstanceTypeNotFound asserts instanceTypeNotFoundError and invalid
+// instance type errors from the upstream's API code.
+func IsInstanceTypeNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	c := microerror.Cause(err)
+	if c == instanceTypeNotFoundError {
+		return true
+	}
+
+	aerr, ok := c.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if aerr.Code() == "InvalidInstanceType" {
+		return true
+	}
+
+	return false
+}
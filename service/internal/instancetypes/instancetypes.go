@@ -0,0 +1,100 @@
+package instancetypes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	gocache "github.com/patrickmn/go-cache"
+)
+
+const (
+	// expiration is the time instance types are cached for. Instance types do
+	// not change once they are available, so that they are only refreshed
+	// occasionally.
+	expiration = 12 * time.Hour
+)
+
+type Config struct {
+	Logger micrologger.Logger
+}
+
+type InstanceTypes struct {
+	logger micrologger.Logger
+
+	cache *gocache.Cache
+}
+
+func New(config Config) (*InstanceTypes, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	i := &InstanceTypes{
+		logger: config.Logger,
+
+		cache: gocache.New(expiration, expiration/2),
+	}
+
+	return i, nil
+}
+
+func (i *InstanceTypes) InstanceType(ctx context.Context, client EC2, region string, instanceType string) (InstanceType, error) {
+	k := fmt.Sprintf("%s/%s", region, instanceType)
+
+	val, ok := i.cache.Get(k)
+	if ok {
+		return val.(InstanceType), nil
+	}
+
+	i.logger.Debugf(ctx, "describing instance type %#q in region %#q", instanceType, region)
+
+	in := &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{
+			aws.String(instanceType),
+		},
+	}
+
+	o, err := client.DescribeInstanceTypes(in)
+	if IsInstanceTypeNotFound(err) {
+		return InstanceType{}, microerror.Maskf(instanceTypeNotFoundError, "instance type %#q in region %#q", instanceType, region)
+	} else if err != nil {
+		return InstanceType{}, microerror.Mask(err)
+	}
+
+	if len(o.InstanceTypes) != 1 {
+		return InstanceType{}, microerror.Maskf(instanceTypeNotFoundError, "instance type %#q in region %#q", instanceType, region)
+	}
+
+	t := newInstanceType(o.InstanceTypes[0])
+	i.cache.SetDefault(k, t)
+
+	i.logger.Debugf(ctx, "described instance type %#q in region %#q", instanceType, region)
+
+	return t, nil
+}
+
+func newInstanceType(info *ec2.InstanceTypeInfo) InstanceType {
+	var t InstanceType
+
+	if info.VCpuInfo != nil {
+		t.VCPUs = aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+	}
+	if info.MemoryInfo != nil {
+		t.MemoryMiB = aws.Int64Value(info.MemoryInfo.SizeInMiB)
+	}
+	if info.GpuInfo != nil {
+		for _, g := range info.GpuInfo.Gpus {
+			if strings.EqualFold(aws.StringValue(g.Manufacturer), "NVIDIA") {
+				t.GPUs += aws.Int64Value(g.Count)
+			}
+		}
+	}
+
+	return t
+}
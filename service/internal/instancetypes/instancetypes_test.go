@@ -0,0 +1,127 @@
+package instancetypes
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+type ec2Mock struct {
+	calls         int
+	instanceTypes map[string]*ec2.InstanceTypeInfo
+}
+
+func (e *ec2Mock) DescribeInstanceTypes(input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	e.calls++
+
+	o := &ec2.DescribeInstanceTypesOutput{}
+	for _, t := range input.InstanceTypes {
+		info, ok := e.instanceTypes[aws.StringValue(t)]
+		if !ok {
+			return nil, awserr.New("InvalidInstanceType", "The following supplied instance types do not exist.", nil)
+		}
+		o.InstanceTypes = append(o.InstanceTypes, info)
+	}
+
+	return o, nil
+}
+
+func Test_InstanceTypes_InstanceType(t *testing.T) {
+	client := &ec2Mock{
+		instanceTypes: map[string]*ec2.InstanceTypeInfo{
+			"m5.2xlarge": {
+				MemoryInfo: &ec2.MemoryInfo{SizeInMiB: aws.Int64(32768)},
+				VCpuInfo:   &ec2.VCpuInfo{DefaultVCpus: aws.Int64(8)},
+			},
+			"g4dn.xlarge": {
+				GpuInfo: &ec2.GpuInfo{
+					Gpus: []*ec2.GpuDeviceInfo{
+						{Count: aws.Int64(1), Manufacturer: aws.String("NVIDIA")},
+					},
+				},
+				MemoryInfo: &ec2.MemoryInfo{SizeInMiB: aws.Int64(16384)},
+				VCpuInfo:   &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		region        string
+		instanceType  string
+		expected      InstanceType
+		expectedCalls int
+		errorMatcher  func(error) bool
+	}{
+		{
+			name:          "case 0: describe instance type",
+			region:        "eu-central-1",
+			instanceType:  "m5.2xlarge",
+			expected:      InstanceType{MemoryMiB: 32768, VCPUs: 8},
+			expectedCalls: 1,
+		},
+		{
+			name:          "case 1: cached instance type",
+			region:        "eu-central-1",
+			instanceType:  "m5.2xlarge",
+			expected:      InstanceType{MemoryMiB: 32768, VCPUs: 8},
+			expectedCalls: 1,
+		},
+		{
+			name:          "case 2: instance type cached per region",
+			region:        "eu-west-1",
+			instanceType:  "m5.2xlarge",
+			expected:      InstanceType{MemoryMiB: 32768, VCPUs: 8},
+			expectedCalls: 2,
+		},
+		{
+			name:          "case 3: gpu instance type",
+			region:        "eu-central-1",
+			instanceType:  "g4dn.xlarge",
+			expected:      InstanceType{GPUs: 1, MemoryMiB: 16384, VCPUs: 4},
+			expectedCalls: 3,
+		},
+		{
+			name:          "case 4: unknown instance type",
+			region:        "eu-central-1",
+			instanceType:  "x9.huge",
+			expectedCalls: 4,
+			errorMatcher:  IsInstanceTypeNotFound,
+		},
+	}
+
+	i, err := New(Config{Logger: microloggertest.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			t.Log(tc.name)
+
+			instanceType, err := i.InstanceType(context.Background(), client, tc.region, tc.instanceType)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !cmp.Equal(instanceType, tc.expected) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expected, instanceType))
+			}
+			if client.calls != tc.expectedCalls {
+				t.Fatalf("calls == %d, want %d", client.calls, tc.expectedCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package instancetypes
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Mock returns the configured instance types without calling the EC2 API.
+type Mock struct {
+	InstanceTypes map[string]InstanceType
+}
+
+func (m *Mock) InstanceType(ctx context.Context, client EC2, region string, instanceType string) (InstanceType, error) {
+	t, ok := m.InstanceTypes[instanceType]
+	if !ok {
+		return InstanceType{}, microerror.Maskf(instanceTypeNotFoundError, "instance type %#q in region %#q", instanceType, region)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,32 @@
+package instancetypes
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EC2 is the part of the EC2 API used to describe instance types.
+type EC2 interface {
+	DescribeInstanceTypes(*ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error)
+}
+
+// Interface describes how implementations should behave when providing
+// information about EC2 instance types. Instance types do not differ between
+// AWS accounts, so that implementations may cache results per region across
+// tenant clusters.
+type Interface interface {
+	// InstanceType returns the resources of the given instance type in the
+	// given region, using the given client on cache misses. Unknown instance
+	// types result in instanceTypeNotFoundError.
+	InstanceType(ctx context.Context, client EC2, region string, instanceType string) (InstanceType, error)
+}
+
+// InstanceType describes the resources an instance type provides to the
+// node running on it.
+type InstanceType struct {
+	// GPUs is the number of NVIDIA GPUs.
+	GPUs      int64
+	MemoryMiB int64
+	VCPUs     int64
+}
@@ -0,0 +1,52 @@
+package interruption
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidEventError = &microerror.Error{
+	Kind: "invalidEventError",
+}
+
+// IsInvalidEvent asserts invalidEventError.
+func IsInvalidEvent(err error) bool {
+	return microerror.Cause(err) == invalidEventError
+}
+
+var queueNotFoundError = &microerror.Error{
+	Kind: "queueNotFoundError",
+}
+
+// IsQueueNotFound asserts queueNotFoundError and queue not found errors from
+// the upstream's API code.
+func IsQueueNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	c := microerror.Cause(err)
+	if c == queueNotFoundError {
+		return true
+	}
+
+	aerr, ok := c.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if aerr.Code() == sqs.ErrCodeQueueDoesNotExist {
+		return true
+	}
+
+	return false
+}
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
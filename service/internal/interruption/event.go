@@ -0,0 +1,85 @@
+package interruption
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	detailTypeInstanceTerminate       = "EC2 Instance-terminate Lifecycle Action"
+	detailTypeRebalanceRecommendation = "EC2 Instance Rebalance Recommendation"
+	detailTypeScheduledChange         = "AWS Health Event"
+	detailTypeSpotInterruption        = "EC2 Spot Instance Interruption Warning"
+)
+
+// eventBridgeEvent is the envelope of the events EventBridge delivers. Only
+// the fields required to find the affected instances are decoded.
+type eventBridgeEvent struct {
+	Detail     eventBridgeEventDetail `json:"detail"`
+	DetailType string                 `json:"detail-type"`
+	ID         string                 `json:"id"`
+	Time       time.Time              `json:"time"`
+}
+
+type eventBridgeEventDetail struct {
+	// AffectedEntities is set for AWS Health events.
+	AffectedEntities []eventBridgeEventDetailEntity `json:"affectedEntities"`
+	// EC2InstanceID is set for ASG lifecycle actions.
+	EC2InstanceID string `json:"EC2InstanceId"`
+	// InstanceID is set for spot interruption warnings and rebalance
+	// recommendations.
+	InstanceID string `json:"instance-id"`
+}
+
+type eventBridgeEventDetailEntity struct {
+	EntityValue string `json:"entityValue"`
+}
+
+// ParseEvent decodes the given EventBridge event. Events of unknown types are
+// returned with KindUnknown.
+func ParseEvent(body []byte) (Event, error) {
+	var e eventBridgeEvent
+	err := json.Unmarshal(body, &e)
+	if err != nil {
+		return Event{}, microerror.Maskf(invalidEventError, err.Error())
+	}
+
+	event := Event{
+		ID:   e.ID,
+		Kind: KindUnknown,
+		Time: e.Time,
+	}
+
+	switch e.DetailType {
+	case detailTypeInstanceTerminate:
+		event.Kind = KindInstanceTerminate
+		event.InstanceIDs = appendNonEmpty(nil, e.Detail.EC2InstanceID)
+	case detailTypeRebalanceRecommendation:
+		event.Kind = KindRebalanceRecommendation
+		event.InstanceIDs = appendNonEmpty(nil, e.Detail.InstanceID)
+	case detailTypeScheduledChange:
+		event.Kind = KindScheduledChange
+		for _, a := range e.Detail.AffectedEntities {
+			event.InstanceIDs = appendNonEmpty(event.InstanceIDs, a.EntityValue)
+		}
+	case detailTypeSpotInterruption:
+		event.Kind = KindSpotInterruption
+		event.InstanceIDs = appendNonEmpty(nil, e.Detail.InstanceID)
+	}
+
+	if event.Kind != KindUnknown && len(event.InstanceIDs) == 0 {
+		return Event{}, microerror.Maskf(invalidEventError, "%s event %#q does not reference any instance", e.DetailType, e.ID)
+	}
+
+	return event, nil
+}
+
+func appendNonEmpty(l []string, s string) []string {
+	if s == "" {
+		return l
+	}
+
+	return append(l, s)
+}
@@ -0,0 +1,160 @@
+package interruption
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ParseEvent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          string
+		expectedEvent Event
+		errorMatcher  func(error) bool
+	}{
+		{
+			name: "case 0: spot interruption warning",
+			body: `{
+				"version": "0",
+				"id": "1e5527d7-bb36-4607-3370-4164db56a40e",
+				"detail-type": "EC2 Spot Instance Interruption Warning",
+				"source": "aws.ec2",
+				"time": "2026-10-17T14:05:00Z",
+				"region": "eu-central-1",
+				"resources": ["arn:aws:ec2:eu-central-1b:instance/i-0b662ef9931388ba0"],
+				"detail": {
+					"instance-id": "i-0b662ef9931388ba0",
+					"instance-action": "terminate"
+				}
+			}`,
+			expectedEvent: Event{
+				ID:          "1e5527d7-bb36-4607-3370-4164db56a40e",
+				InstanceIDs: []string{"i-0b662ef9931388ba0"},
+				Kind:        KindSpotInterruption,
+				Time:        time.Date(2026, 10, 17, 14, 5, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "case 1: rebalance recommendation",
+			body: `{
+				"id": "4b6a5f23-3a2f-4c21-8a54-2f2b0c4f5f0e",
+				"detail-type": "EC2 Instance Rebalance Recommendation",
+				"source": "aws.ec2",
+				"time": "2026-10-17T14:00:00Z",
+				"detail": {
+					"instance-id": "i-0b662ef9931388ba0"
+				}
+			}`,
+			expectedEvent: Event{
+				ID:          "4b6a5f23-3a2f-4c21-8a54-2f2b0c4f5f0e",
+				InstanceIDs: []string{"i-0b662ef9931388ba0"},
+				Kind:        KindRebalanceRecommendation,
+				Time:        time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "case 2: scheduled change affecting multiple instances",
+			body: `{
+				"id": "7bf73129-1428-4cd3-a780-95db273d1602",
+				"detail-type": "AWS Health Event",
+				"source": "aws.health",
+				"time": "2026-10-17T12:00:00Z",
+				"detail": {
+					"service": "EC2",
+					"eventTypeCode": "AWS_EC2_INSTANCE_RETIREMENT_SCHEDULED",
+					"eventTypeCategory": "scheduledChange",
+					"affectedEntities": [
+						{"entityValue": "i-0b662ef9931388ba0"},
+						{"entityValue": "i-0c4a7d6e1f2b3a495"}
+					]
+				}
+			}`,
+			expectedEvent: Event{
+				ID:          "7bf73129-1428-4cd3-a780-95db273d1602",
+				InstanceIDs: []string{"i-0b662ef9931388ba0", "i-0c4a7d6e1f2b3a495"},
+				Kind:        KindScheduledChange,
+				Time:        time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "case 3: instance terminate lifecycle action",
+			body: `{
+				"id": "c2a3e4f5-6b7c-4d8e-9f0a-1b2c3d4e5f6a",
+				"detail-type": "EC2 Instance-terminate Lifecycle Action",
+				"source": "aws.autoscaling",
+				"time": "2026-10-17T13:00:00Z",
+				"detail": {
+					"LifecycleActionToken": "87654321-4321-4321-4321-210987654321",
+					"AutoScalingGroupName": "cluster-al9qy-tcnp-a7k1m-NodePoolAutoScalingGroup-1X2Y3Z",
+					"LifecycleHookName": "NodePool",
+					"EC2InstanceId": "i-0c4a7d6e1f2b3a495",
+					"LifecycleTransition": "autoscaling:EC2_INSTANCE_TERMINATING"
+				}
+			}`,
+			expectedEvent: Event{
+				ID:          "c2a3e4f5-6b7c-4d8e-9f0a-1b2c3d4e5f6a",
+				InstanceIDs: []string{"i-0c4a7d6e1f2b3a495"},
+				Kind:        KindInstanceTerminate,
+				Time:        time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "case 4: unknown event type",
+			body: `{
+				"id": "0d1e2f3a-4b5c-6d7e-8f9a-0b1c2d3e4f5a",
+				"detail-type": "EC2 Instance State-change Notification",
+				"source": "aws.ec2",
+				"time": "2026-10-17T13:00:00Z",
+				"detail": {
+					"instance-id": "i-0c4a7d6e1f2b3a495",
+					"state": "running"
+				}
+			}`,
+			expectedEvent: Event{
+				ID:   "0d1e2f3a-4b5c-6d7e-8f9a-0b1c2d3e4f5a",
+				Kind: KindUnknown,
+				Time: time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "case 5: spot interruption warning without instance",
+			body: `{
+				"id": "1e5527d7-bb36-4607-3370-4164db56a40e",
+				"detail-type": "EC2 Spot Instance Interruption Warning",
+				"source": "aws.ec2",
+				"time": "2026-10-17T14:05:00Z",
+				"detail": {}
+			}`,
+			errorMatcher: IsInvalidEvent,
+		},
+		{
+			name:         "case 6: malformed message",
+			body:         `not json`,
+			errorMatcher: IsInvalidEvent,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			event, err := ParseEvent([]byte(tc.body))
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if !cmp.Equal(event, tc.expectedEvent, cmp.AllowUnexported(Event{})) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedEvent, event, cmp.AllowUnexported(Event{})))
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package interruption
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake implements Interface using in memory events. It is meant to be used in
+// tests of the consumers of the interruption queue.
+type Fake struct {
+	mutex   sync.Mutex
+	deleted []Event
+	events  []Event
+}
+
+func NewFake(events ...Event) *Fake {
+	f := &Fake{
+		events: events,
+	}
+
+	return f
+}
+
+func (f *Fake) Delete(ctx context.Context, client SQS, event Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for i, e := range f.events {
+		if e.ID == event.ID {
+			f.events = append(f.events[:i], f.events[i+1:]...)
+			f.deleted = append(f.deleted, e)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Deleted returns the events deleted from the fake queue so far.
+func (f *Fake) Deleted() []Event {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]Event(nil), f.deleted...)
+}
+
+func (f *Fake) Receive(ctx context.Context, client SQS, queueName string) ([]Event, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]Event(nil), f.events...), nil
+}
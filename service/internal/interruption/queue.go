@@ -0,0 +1,121 @@
+package interruption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	// maxMessages is the maximum number of messages SQS returns per receive
+	// request.
+	maxMessages = 10
+	// maxReceives limits the number of receive requests per call to Receive,
+	// so that a busy queue does not block the reconciliation.
+	maxReceives = 10
+	// visibilityTimeout is the number of seconds received messages are hidden
+	// from further receive requests. Messages which did not get deleted within
+	// this time are delivered again.
+	visibilityTimeout = 60
+	// waitTimeSeconds enables short long polling, so that SQS queries all of
+	// its servers for messages instead of a subset.
+	waitTimeSeconds = 1
+)
+
+type QueueConfig struct {
+	Logger micrologger.Logger
+}
+
+// Queue implements Interface using SQS.
+type Queue struct {
+	logger micrologger.Logger
+}
+
+func NewQueue(config QueueConfig) (*Queue, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	q := &Queue{
+		logger: config.Logger,
+	}
+
+	return q, nil
+}
+
+func (q *Queue) Delete(ctx context.Context, client SQS, event Event) error {
+	i := &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(event.queueURL),
+		ReceiptHandle: aws.String(event.receiptHandle),
+	}
+
+	_, err := client.DeleteMessage(i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (q *Queue) Receive(ctx context.Context, client SQS, queueName string) ([]Event, error) {
+	var queueURL string
+	{
+		i := &sqs.GetQueueUrlInput{
+			QueueName: aws.String(queueName),
+		}
+
+		o, err := client.GetQueueUrl(i)
+		if IsQueueNotFound(err) {
+			return nil, microerror.Maskf(queueNotFoundError, queueName)
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		queueURL = aws.StringValue(o.QueueUrl)
+	}
+
+	var events []Event
+	for n := 0; n < maxReceives; n++ {
+		i := &sqs.ReceiveMessageInput{
+			MaxNumberOfMessages: aws.Int64(maxMessages),
+			QueueUrl:            aws.String(queueURL),
+			VisibilityTimeout:   aws.Int64(visibilityTimeout),
+			WaitTimeSeconds:     aws.Int64(waitTimeSeconds),
+		}
+
+		o, err := client.ReceiveMessage(i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, m := range o.Messages {
+			e, err := ParseEvent([]byte(aws.StringValue(m.Body)))
+			if IsInvalidEvent(err) {
+				// Messages we cannot make sense of would be delivered over and over
+				// again. They are returned as unknown events so that they get
+				// deleted like any other event.
+				q.logger.Debugf(ctx, "failed to parse message %#q of queue %#q: %s", aws.StringValue(m.MessageId), queueName, err.Error())
+				e = Event{
+					ID:   aws.StringValue(m.MessageId),
+					Kind: KindUnknown,
+				}
+			} else if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			e.queueURL = queueURL
+			e.receiptHandle = aws.StringValue(m.ReceiptHandle)
+
+			events = append(events, e)
+		}
+
+		if len(o.Messages) < maxMessages {
+			break
+		}
+	}
+
+	return events, nil
+}
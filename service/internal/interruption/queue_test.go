@@ -0,0 +1,186 @@
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+type sqsMock struct {
+	deleted  []string
+	messages []*sqs.Message
+	queues   map[string]string
+}
+
+func (s *sqsMock) DeleteMessage(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error) {
+	s.deleted = append(s.deleted, aws.StringValue(input.QueueUrl)+"/"+aws.StringValue(input.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (s *sqsMock) GetQueueUrl(input *sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error) {
+	u, ok := s.queues[aws.StringValue(input.QueueName)]
+	if !ok {
+		return nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "The specified queue does not exist for this wsdl version.", nil)
+	}
+
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String(u)}, nil
+}
+
+func (s *sqsMock) ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	n := min(int(aws.Int64Value(input.MaxNumberOfMessages)), len(s.messages))
+
+	o := &sqs.ReceiveMessageOutput{
+		Messages: s.messages[:n],
+	}
+	s.messages = s.messages[n:]
+
+	return o, nil
+}
+
+func Test_Queue_Receive(t *testing.T) {
+	message := func(id string, body string) *sqs.Message {
+		return &sqs.Message{
+			Body:          aws.String(body),
+			MessageId:     aws.String(id),
+			ReceiptHandle: aws.String("handle-" + id),
+		}
+	}
+	spotInterruption := func(id string) string {
+		return fmt.Sprintf(`{"id": %q, "detail-type": "EC2 Spot Instance Interruption Warning", "detail": {"instance-id": "i-%s"}}`, id, id)
+	}
+
+	testCases := []struct {
+		name              string
+		messages          []*sqs.Message
+		queueName         string
+		expectedEvents    int
+		expectedKinds     map[string]int
+		expectedRemaining int
+		errorMatcher      func(error) bool
+	}{
+		{
+			name:         "case 0: queue does not exist",
+			queueName:    "al9qy-unknown",
+			errorMatcher: IsQueueNotFound,
+		},
+		{
+			name:          "case 1: empty queue",
+			queueName:     "al9qy-interruption",
+			expectedKinds: map[string]int{},
+		},
+		{
+			name:      "case 2: malformed messages are returned as unknown events",
+			queueName: "al9qy-interruption",
+			messages: []*sqs.Message{
+				message("1", spotInterruption("1")),
+				message("2", "not json"),
+			},
+			expectedEvents: 2,
+			expectedKinds:  map[string]int{KindSpotInterruption: 1, KindUnknown: 1},
+		},
+		{
+			name:      "case 3: multiple batches are received",
+			queueName: "al9qy-interruption",
+			messages: func() []*sqs.Message {
+				var l []*sqs.Message
+				for i := 0; i < 25; i++ {
+					l = append(l, message(strconv.Itoa(i), spotInterruption(strconv.Itoa(i))))
+				}
+				return l
+			}(),
+			expectedEvents: 25,
+			expectedKinds:  map[string]int{KindSpotInterruption: 25},
+		},
+		{
+			name:      "case 4: receiving stops after the maximum number of batches",
+			queueName: "al9qy-interruption",
+			messages: func() []*sqs.Message {
+				var l []*sqs.Message
+				for i := 0; i < 105; i++ {
+					l = append(l, message(strconv.Itoa(i), spotInterruption(strconv.Itoa(i))))
+				}
+				return l
+			}(),
+			expectedEvents:    100,
+			expectedKinds:     map[string]int{KindSpotInterruption: 100},
+			expectedRemaining: 5,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			client := &sqsMock{
+				messages: tc.messages,
+				queues: map[string]string{
+					"al9qy-interruption": "https://sqs.eu-central-1.amazonaws.com/123456789012/al9qy-interruption",
+				},
+			}
+
+			var q *Queue
+			{
+				c := QueueConfig{
+					Logger: microloggertest.New(),
+				}
+
+				var err error
+				q, err = NewQueue(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events, err := q.Receive(context.Background(), client, tc.queueName)
+
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("expected %#v got %#v", true, false)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if len(events) != tc.expectedEvents {
+				t.Fatalf("expected %d events got %d", tc.expectedEvents, len(events))
+			}
+			if len(client.messages) != tc.expectedRemaining {
+				t.Fatalf("expected %d remaining messages got %d", tc.expectedRemaining, len(client.messages))
+			}
+
+			kinds := map[string]int{}
+			for _, e := range events {
+				kinds[e.Kind]++
+
+				err = q.Delete(context.Background(), client, e)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !cmp.Equal(kinds, tc.expectedKinds) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedKinds, kinds))
+			}
+			if len(client.deleted) != len(events) {
+				t.Fatalf("expected %d deleted messages got %d", len(events), len(client.deleted))
+			}
+			for _, d := range client.deleted {
+				if !strings.HasPrefix(d, "https://sqs.eu-central-1.amazonaws.com/123456789012/al9qy-interruption/handle-") {
+					t.Fatalf("expected message to be deleted by its receipt handle got %#q", d)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package interruption
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+const (
+	// KindInstanceTerminate is the kind of ASG lifecycle actions of instances
+	// being terminated.
+	KindInstanceTerminate = "InstanceTerminate"
+	// KindRebalanceRecommendation is the kind of EC2 rebalance
+	// recommendations. Spot instances receiving them are at elevated risk of
+	// being interrupted.
+	KindRebalanceRecommendation = "RebalanceRecommendation"
+	// KindScheduledChange is the kind of AWS Health events announcing scheduled
+	// maintenance of instances, e.g. retirements or reboots.
+	KindScheduledChange = "ScheduledChange"
+	// KindSpotInterruption is the kind of EC2 spot instance interruption
+	// warnings. The instance is reclaimed two minutes after the warning.
+	KindSpotInterruption = "SpotInterruption"
+	// KindUnknown is the kind of messages which could not be parsed or do not
+	// relate to any instance.
+	KindUnknown = "Unknown"
+)
+
+// SQS provides the set of methods required to consume the interruption
+// queue. *SQS struct from "github.com/aws/aws-sdk-go/service/sqs" fulfils this
+// interface.
+type SQS interface {
+	DeleteMessage(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error)
+	GetQueueUrl(input *sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error)
+	ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error)
+}
+
+// Interface describes how implementations should behave when consuming the
+// interruption events of a tenant cluster.
+type Interface interface {
+	// Receive returns the events currently available in the queue with the
+	// given name. Received events are invisible to further calls of Receive
+	// for a while, so events which did not get deleted are delivered again in
+	// case handling them failed.
+	Receive(ctx context.Context, client SQS, queueName string) ([]Event, error)
+	// Delete removes the handled event from its queue.
+	Delete(ctx context.Context, client SQS, event Event) error
+}
+
+// Event is an interruption event EventBridge delivered to the queue.
+type Event struct {
+	// ID is the EventBridge event ID.
+	ID string
+	// InstanceIDs are the IDs of the EC2 instances affected by the event.
+	InstanceIDs []string
+	Kind        string
+	Time        time.Time
+
+	queueURL      string
+	receiptHandle string
+}
@@ -0,0 +1,237 @@
+// Package stackmetrics exports the state of the Cloud Formation stacks of
+// tenant clusters as Prometheus metrics. The stack resources report the
+// stacks they describe during reconciliation anyway, so no additional AWS API
+// calls are made for the metrics.
+package stackmetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+const (
+	labelClusterID = "cluster_id"
+	labelReason    = "reason"
+	labelStack     = "stack"
+	labelStackName = "stack_name"
+	labelStatus    = "status"
+)
+
+const (
+	gaugeValue float64 = 1
+)
+
+var (
+	stackLabels = []string{labelClusterID, labelStack, labelStackName}
+
+	stackDriftStatus = prometheus.NewDesc(
+		"aws_operator_cloudformation_stack_drift_status",
+		"Gauge representing the latest drift detection status of a Cloud Formation stack.",
+		append(stackLabels, labelStatus),
+		nil,
+	)
+	stackInProgress = prometheus.NewDesc(
+		"aws_operator_cloudformation_stack_in_progress_seconds",
+		"Gauge representing the time a Cloud Formation stack is in progress, 0 if the stack is not in progress.",
+		stackLabels,
+		nil,
+	)
+	stackLastUpdateReason = prometheus.NewDesc(
+		"aws_operator_cloudformation_stack_last_update_reason",
+		"Gauge representing the reasons change detection found for the latest update of a Cloud Formation stack.",
+		append(stackLabels, labelReason),
+		nil,
+	)
+	stackStatus = prometheus.NewDesc(
+		"aws_operator_cloudformation_stack_status",
+		"Gauge representing the current status of a Cloud Formation stack.",
+		append(stackLabels, labelStatus),
+		nil,
+	)
+	stackStatusTransition = prometheus.NewDesc(
+		"aws_operator_cloudformation_stack_status_transition_timestamp_seconds",
+		"Gauge representing the time of the latest status transition of a Cloud Formation stack.",
+		stackLabels,
+		nil,
+	)
+)
+
+var defaultCollector = newCollector(time.Now)
+
+func init() {
+	prometheus.MustRegister(defaultCollector)
+}
+
+// DeleteStack removes all metrics of the given stack, e.g. once the stack got
+// deleted.
+func DeleteStack(stackName string) {
+	defaultCollector.deleteStack(stackName)
+}
+
+// ReportStack records the state of the given stack as returned by
+// DescribeStacks. The stack is one of the stack kinds defined in the key
+// package, e.g. key.StackTCCP.
+func ReportStack(clusterID string, stack string, s *cloudformation.Stack) {
+	defaultCollector.reportStack(clusterID, stack, s)
+}
+
+// ReportUpdateReasons records the reasons change detection found for updating
+// the given stack. Reasons are kept until the next update is detected.
+func ReportUpdateReasons(clusterID string, stack string, stackName string, reasons []string) {
+	defaultCollector.reportUpdateReasons(clusterID, stack, stackName, reasons)
+}
+
+type stackState struct {
+	clusterID string
+	stack     string
+	stackName string
+
+	driftStatus string
+	reasons     []string
+	status      string
+	transition  time.Time
+}
+
+type collector struct {
+	mutex  sync.Mutex
+	now    func() time.Time
+	stacks map[string]*stackState
+}
+
+func newCollector(now func() time.Time) *collector {
+	c := &collector{
+		now:    now,
+		stacks: map[string]*stackState{},
+	}
+
+	return c
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stackDriftStatus
+	ch <- stackInProgress
+	ch <- stackLastUpdateReason
+	ch <- stackStatus
+	ch <- stackStatusTransition
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+
+	for _, s := range c.stacks {
+		labels := []string{s.clusterID, s.stack, s.stackName}
+
+		for _, r := range s.reasons {
+			ch <- prometheus.MustNewConstMetric(stackLastUpdateReason, prometheus.GaugeValue, gaugeValue, append(labels, r)...)
+		}
+
+		// Update reasons may be reported before the stack was described, in
+		// which case we do not know anything about its status yet.
+		if s.status == "" {
+			continue
+		}
+
+		var inProgress float64
+		if key.StackInProgress(s.status) {
+			inProgress = now.Sub(s.transition).Seconds()
+		}
+
+		ch <- prometheus.MustNewConstMetric(stackDriftStatus, prometheus.GaugeValue, gaugeValue, append(labels, s.driftStatus)...)
+		ch <- prometheus.MustNewConstMetric(stackInProgress, prometheus.GaugeValue, inProgress, labels...)
+		ch <- prometheus.MustNewConstMetric(stackStatus, prometheus.GaugeValue, gaugeValue, append(labels, s.status)...)
+		ch <- prometheus.MustNewConstMetric(stackStatusTransition, prometheus.GaugeValue, float64(s.transition.Unix()), labels...)
+	}
+}
+
+func (c *collector) deleteStack(stackName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.stacks, stackName)
+}
+
+func (c *collector) reportStack(clusterID string, stack string, s *cloudformation.Stack) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := c.state(clusterID, stack, aws.StringValue(s.StackName))
+
+	status := aws.StringValue(s.StackStatus)
+	if state.status != status {
+		// Stack status transitions are only observed while reconciling. The
+		// first time we see a stack, e.g. after the operator restarted, the
+		// latest operation of the stack is the best approximation we have.
+		if state.status == "" {
+			state.transition = latestOperation(s)
+		} else {
+			state.transition = c.now()
+		}
+		state.status = status
+	}
+
+	state.driftStatus = cloudformation.StackDriftStatusNotChecked
+	if s.DriftInformation != nil && s.DriftInformation.StackDriftStatus != nil {
+		state.driftStatus = *s.DriftInformation.StackDriftStatus
+	}
+}
+
+func (c *collector) reportUpdateReasons(clusterID string, stack string, stackName string, reasons []string) {
+	if len(reasons) == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := c.state(clusterID, stack, stackName)
+
+	// The same reason may be reported for multiple fields, e.g. changed
+	// instance types of different node pool settings, but must only be
+	// exported once.
+	var unique []string
+	seen := map[string]bool{}
+	for _, r := range reasons {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		unique = append(unique, r)
+	}
+
+	state.reasons = unique
+}
+
+// state returns the state of the given stack. Callers must hold the lock.
+func (c *collector) state(clusterID string, stack string, stackName string) *stackState {
+	s, ok := c.stacks[stackName]
+	if !ok {
+		s = &stackState{
+			clusterID: clusterID,
+			stack:     stack,
+			stackName: stackName,
+		}
+		c.stacks[stackName] = s
+	}
+
+	return s
+}
+
+func latestOperation(s *cloudformation.Stack) time.Time {
+	switch {
+	case s.DeletionTime != nil:
+		return *s.DeletionTime
+	case s.LastUpdatedTime != nil:
+		return *s.LastUpdatedTime
+	default:
+		return aws.TimeValue(s.CreationTime)
+	}
+}
@@ -0,0 +1,113 @@
+package stackmetrics
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_Collector(t *testing.T) {
+	created := time.Unix(1600000000, 0)
+	now := created.Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		report   func(c *collector)
+		expected string
+	}{
+		{
+			name: "case 0: first observation of an in progress stack",
+			report: func(c *collector) {
+				c.reportStack("8y5ck", "tccp", &cloudformation.Stack{
+					CreationTime:    aws.Time(created.Add(-time.Hour)),
+					LastUpdatedTime: aws.Time(created),
+					StackName:       aws.String("cluster-8y5ck-tccp"),
+					StackStatus:     aws.String(cloudformation.StackStatusUpdateInProgress),
+				})
+			},
+			expected: `
+# HELP aws_operator_cloudformation_stack_drift_status Gauge representing the latest drift detection status of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_drift_status gauge
+aws_operator_cloudformation_stack_drift_status{cluster_id="8y5ck",stack="tccp",stack_name="cluster-8y5ck-tccp",status="NOT_CHECKED"} 1
+# HELP aws_operator_cloudformation_stack_in_progress_seconds Gauge representing the time a Cloud Formation stack is in progress, 0 if the stack is not in progress.
+# TYPE aws_operator_cloudformation_stack_in_progress_seconds gauge
+aws_operator_cloudformation_stack_in_progress_seconds{cluster_id="8y5ck",stack="tccp",stack_name="cluster-8y5ck-tccp"} 3600
+# HELP aws_operator_cloudformation_stack_status Gauge representing the current status of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_status gauge
+aws_operator_cloudformation_stack_status{cluster_id="8y5ck",stack="tccp",stack_name="cluster-8y5ck-tccp",status="UPDATE_IN_PROGRESS"} 1
+# HELP aws_operator_cloudformation_stack_status_transition_timestamp_seconds Gauge representing the time of the latest status transition of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_status_transition_timestamp_seconds gauge
+aws_operator_cloudformation_stack_status_transition_timestamp_seconds{cluster_id="8y5ck",stack="tccp",stack_name="cluster-8y5ck-tccp"} 1.6e+09
+`,
+		},
+		{
+			name: "case 1: observed status transition with drift and update reasons",
+			report: func(c *collector) {
+				c.reportStack("8y5ck", "tcnp", &cloudformation.Stack{
+					CreationTime: aws.Time(created),
+					StackName:    aws.String("cluster-8y5ck-tcnp-al9qy"),
+					StackStatus:  aws.String(cloudformation.StackStatusCreateComplete),
+				})
+				c.reportUpdateReasons("8y5ck", "tcnp", "cluster-8y5ck-tcnp-al9qy", []string{"InstanceTypeChanged", "AMIChanged", "InstanceTypeChanged"})
+				c.reportStack("8y5ck", "tcnp", &cloudformation.Stack{
+					CreationTime: aws.Time(created),
+					DriftInformation: &cloudformation.StackDriftInformation{
+						StackDriftStatus: aws.String(cloudformation.StackDriftStatusDrifted),
+					},
+					StackName:   aws.String("cluster-8y5ck-tcnp-al9qy"),
+					StackStatus: aws.String(cloudformation.StackStatusUpdateRollbackFailed),
+				})
+			},
+			expected: `
+# HELP aws_operator_cloudformation_stack_drift_status Gauge representing the latest drift detection status of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_drift_status gauge
+aws_operator_cloudformation_stack_drift_status{cluster_id="8y5ck",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy",status="DRIFTED"} 1
+# HELP aws_operator_cloudformation_stack_in_progress_seconds Gauge representing the time a Cloud Formation stack is in progress, 0 if the stack is not in progress.
+# TYPE aws_operator_cloudformation_stack_in_progress_seconds gauge
+aws_operator_cloudformation_stack_in_progress_seconds{cluster_id="8y5ck",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy"} 0
+# HELP aws_operator_cloudformation_stack_last_update_reason Gauge representing the reasons change detection found for the latest update of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_last_update_reason gauge
+aws_operator_cloudformation_stack_last_update_reason{cluster_id="8y5ck",reason="AMIChanged",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy"} 1
+aws_operator_cloudformation_stack_last_update_reason{cluster_id="8y5ck",reason="InstanceTypeChanged",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy"} 1
+# HELP aws_operator_cloudformation_stack_status Gauge representing the current status of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_status gauge
+aws_operator_cloudformation_stack_status{cluster_id="8y5ck",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy",status="UPDATE_ROLLBACK_FAILED"} 1
+# HELP aws_operator_cloudformation_stack_status_transition_timestamp_seconds Gauge representing the time of the latest status transition of a Cloud Formation stack.
+# TYPE aws_operator_cloudformation_stack_status_transition_timestamp_seconds gauge
+aws_operator_cloudformation_stack_status_transition_timestamp_seconds{cluster_id="8y5ck",stack="tcnp",stack_name="cluster-8y5ck-tcnp-al9qy"} 1.6000036e+09
+`,
+		},
+		{
+			name: "case 2: deleted stack",
+			report: func(c *collector) {
+				c.reportStack("8y5ck", "tccpi", &cloudformation.Stack{
+					CreationTime: aws.Time(created),
+					StackName:    aws.String("cluster-8y5ck-tccpi"),
+					StackStatus:  aws.String(cloudformation.StackStatusCreateComplete),
+				})
+				c.reportUpdateReasons("8y5ck", "tccpi", "cluster-8y5ck-tccpi", []string{"OperatorVersionChanged"})
+				c.deleteStack("cluster-8y5ck-tccpi")
+			},
+			expected: ``,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			c := newCollector(func() time.Time { return now })
+			tc.report(c)
+
+			err := testutil.CollectAndCompare(c, strings.NewReader(tc.expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -19,6 +19,9 @@ func ChinaControllerContext() controllercontext.Context {
 						{
 							Name: "cn-north-1a",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.0.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.0/27"),
 									ID:   "private-subnet-id-1a",
@@ -32,6 +35,9 @@ func ChinaControllerContext() controllercontext.Context {
 						{
 							Name: "cn-north-1b",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.64.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.64/27"),
 									ID:   "private-subnet-id-1b",
@@ -45,6 +51,9 @@ func ChinaControllerContext() controllercontext.Context {
 						{
 							Name: "cn-north-1c",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.128.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.128/27"),
 									ID:   "private-subnet-id-1c",
@@ -66,7 +75,8 @@ func ChinaControllerContext() controllercontext.Context {
 							},
 							Subnet: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnet{
 								Private: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate{
-									CIDR: mustParseCIDR("10.100.3.0/27"),
+									CIDR:          mustParseCIDR("10.100.3.0/27"),
+									IPv6CIDRIndex: 16,
 								},
 							},
 						},
@@ -77,7 +87,8 @@ func ChinaControllerContext() controllercontext.Context {
 							},
 							Subnet: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnet{
 								Private: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate{
-									CIDR: mustParseCIDR("10.100.3.64/27"),
+									CIDR:          mustParseCIDR("10.100.3.64/27"),
+									IPv6CIDRIndex: 18,
 								},
 							},
 						},
@@ -259,6 +270,9 @@ func DefaultControllerContext() controllercontext.Context {
 						{
 							Name: "eu-central-1a",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.0.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.0/27"),
 									ID:   "private-subnet-id-1a",
@@ -272,6 +286,9 @@ func DefaultControllerContext() controllercontext.Context {
 						{
 							Name: "eu-central-1b",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.64.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.64/27"),
 									ID:   "private-subnet-id-1b",
@@ -285,6 +302,9 @@ func DefaultControllerContext() controllercontext.Context {
 						{
 							Name: "eu-central-1c",
 							Subnet: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnet{
+								AWSCNI: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetAWSCNI{
+									CIDR: mustParseCIDR("172.17.128.0/18"),
+								},
 								Private: controllercontext.ContextSpecTenantClusterTCCPAvailabilityZoneSubnetPrivate{
 									CIDR: mustParseCIDR("10.100.3.128/27"),
 									ID:   "private-subnet-id-1c",
@@ -306,7 +326,8 @@ func DefaultControllerContext() controllercontext.Context {
 							},
 							Subnet: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnet{
 								Private: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate{
-									CIDR: mustParseCIDR("10.100.3.0/27"),
+									CIDR:          mustParseCIDR("10.100.3.0/27"),
+									IPv6CIDRIndex: 16,
 								},
 							},
 						},
@@ -317,7 +338,8 @@ func DefaultControllerContext() controllercontext.Context {
 							},
 							Subnet: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnet{
 								Private: controllercontext.ContextSpecTenantClusterTCNPAvailabilityZoneSubnetPrivate{
-									CIDR: mustParseCIDR("10.100.3.64/27"),
+									CIDR:          mustParseCIDR("10.100.3.64/27"),
+									IPv6CIDRIndex: 18,
 								},
 							},
 						},
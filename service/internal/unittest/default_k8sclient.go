@@ -20,6 +20,7 @@ import (
 type fakeK8sClient struct {
 	ctrlClient client.Client
 	k8sClient  *fakek8s.Clientset
+	scheme     *runtime.Scheme
 }
 
 func FakeK8sClient(objects ...runtime.Object) k8sclient.Interface {
@@ -48,6 +49,7 @@ func FakeK8sClient(objects ...runtime.Object) k8sclient.Interface {
 		k8sClient = &fakeK8sClient{
 			ctrlClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
 			k8sClient:  fakek8s.NewSimpleClientset(),
+			scheme:     scheme,
 		}
 	}
 
@@ -83,5 +85,5 @@ func (f *fakeK8sClient) RESTConfig() *rest.Config {
 }
 
 func (f *fakeK8sClient) Scheme() *runtime.Scheme {
-	return nil
+	return f.scheme
 }
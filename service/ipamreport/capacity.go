@@ -0,0 +1,91 @@
+package ipamreport
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+)
+
+type interval struct {
+	start uint64
+	end   uint64
+}
+
+// capacity computes the capacity of the given IPv4 network range considering
+// the given allocated subnets. It returns the number of allocated subnets
+// overlapping with the range, the number of free addresses and the number of
+// free aligned subnets of the given mask size.
+func capacity(networkRange net.IPNet, subnets []net.IPNet, maskBits int) (int, uint64, uint64) {
+	r := toInterval(networkRange)
+
+	var overlapping []interval
+	for _, s := range subnets {
+		i := toInterval(s)
+		if i.end <= r.start || i.start >= r.end {
+			continue
+		}
+
+		overlapping = append(overlapping, interval{
+			start: max(i.start, r.start),
+			end:   min(i.end, r.end),
+		})
+	}
+
+	sort.Slice(overlapping, func(i, j int) bool {
+		return overlapping[i].start < overlapping[j].start
+	})
+
+	var gaps []interval
+	{
+		cursor := r.start
+		for _, i := range overlapping {
+			if i.start > cursor {
+				gaps = append(gaps, interval{start: cursor, end: i.start})
+			}
+			if i.end > cursor {
+				cursor = i.end
+			}
+		}
+		if cursor < r.end {
+			gaps = append(gaps, interval{start: cursor, end: r.end})
+		}
+	}
+
+	var freeAddresses uint64
+	var freeSubnets uint64
+	{
+		ones, _ := networkRange.Mask.Size()
+
+		for _, g := range gaps {
+			freeAddresses += g.end - g.start
+
+			if maskBits < ones {
+				continue
+			}
+
+			size := uint64(1) << uint(32-maskBits)
+			first := (g.start + size - 1) / size
+			last := g.end / size
+			if last > first {
+				freeSubnets += last - first
+			}
+		}
+	}
+
+	return len(overlapping), freeAddresses, freeSubnets
+}
+
+func size(n net.IPNet) uint64 {
+	i := toInterval(n)
+	return i.end - i.start
+}
+
+func toInterval(n net.IPNet) interval {
+	ones, _ := n.Mask.Size()
+	start := uint64(binary.BigEndian.Uint32(n.IP.Mask(n.Mask).To4()))
+
+	return interval{
+		start: start,
+		end:   start + uint64(1)<<uint(32-ones),
+	}
+}
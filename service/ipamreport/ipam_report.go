@@ -0,0 +1,196 @@
+package ipamreport
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"sort"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+)
+
+type Config struct {
+	CtrlClient ctrlClient.Client
+	Logger     micrologger.Logger
+
+	AllocatedSubnetMaskBits int
+	NetworkRange            net.IPNet
+}
+
+// IPAMReport reports the subnets allocated by the ipam resource based on the
+// AWSCluster, AWSMachineDeployment and NetworkPool CRs. Subnets of VPCs not
+// managed by the operator are not considered, since they are only visible
+// within the tenant cluster accounts.
+type IPAMReport struct {
+	ctrlClient ctrlClient.Client
+	logger     micrologger.Logger
+
+	allocatedSubnetMaskBits int
+	networkRange            net.IPNet
+}
+
+func New(config Config) (*IPAMReport, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.AllocatedSubnetMaskBits == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AllocatedSubnetMaskBits must not be empty", config)
+	}
+	if reflect.DeepEqual(config.NetworkRange, net.IPNet{}) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRange must not be empty", config)
+	}
+
+	r := &IPAMReport{
+		ctrlClient: config.CtrlClient,
+		logger:     config.Logger,
+
+		allocatedSubnetMaskBits: config.AllocatedSubnetMaskBits,
+		networkRange:            config.NetworkRange,
+	}
+
+	return r, nil
+}
+
+func (r *IPAMReport) Report(ctx context.Context) (Report, error) {
+	var clusters infrastructurev1alpha3.AWSClusterList
+	{
+		err := r.ctrlClient.List(ctx, &clusters)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+	}
+
+	var machineDeployments infrastructurev1alpha3.AWSMachineDeploymentList
+	{
+		err := r.ctrlClient.List(ctx, &machineDeployments)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+	}
+
+	var networkPools infrastructurev1alpha3.NetworkPoolList
+	{
+		err := r.ctrlClient.List(ctx, &networkPools)
+		if err != nil {
+			return Report{}, microerror.Mask(err)
+		}
+	}
+
+	var subnets []Subnet
+	var allocated []net.IPNet
+	{
+		// Node pools allocate their subnets from the network range of the
+		// cluster they belong to, which is why we need to know the NetworkPool
+		// of every cluster.
+		clusterNetworkPools := map[string]string{}
+		for _, cr := range clusters.Items {
+			clusterNetworkPools[key.ClusterID(&cr)] = cr.Spec.Provider.Nodes.NetworkPool
+		}
+
+		for _, cr := range clusters.Items {
+			cidr := key.StatusClusterNetworkCIDR(cr)
+			if cidr == "" {
+				continue
+			}
+
+			_, n, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return Report{}, microerror.Mask(err)
+			}
+
+			allocated = append(allocated, *n)
+			subnets = append(subnets, Subnet{
+				CIDR:        n.String(),
+				Cluster:     key.ClusterID(&cr),
+				Kind:        KindAWSCluster,
+				Name:        cr.GetName(),
+				Namespace:   cr.GetNamespace(),
+				NetworkPool: cr.Spec.Provider.Nodes.NetworkPool,
+			})
+		}
+
+		for _, cr := range machineDeployments.Items {
+			cidr := key.MachineDeploymentSubnet(cr)
+			if cidr == "" {
+				continue
+			}
+
+			_, n, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return Report{}, microerror.Mask(err)
+			}
+
+			allocated = append(allocated, *n)
+			subnets = append(subnets, Subnet{
+				CIDR:        n.String(),
+				Cluster:     key.ClusterID(&cr),
+				Kind:        KindAWSMachineDeployment,
+				Name:        cr.GetName(),
+				Namespace:   cr.GetNamespace(),
+				NetworkPool: clusterNetworkPools[key.ClusterID(&cr)],
+			})
+		}
+
+		sort.SliceStable(subnets, func(i, j int) bool {
+			a, _, _ := net.ParseCIDR(subnets[i].CIDR)
+			b, _, _ := net.ParseCIDR(subnets[j].CIDR)
+			return bytes.Compare(a.To4(), b.To4()) < 0
+		})
+	}
+
+	var ranges []Range
+	{
+		ranges = append(ranges, r.newRange(r.networkRange, "", "", allocated))
+
+		sort.Slice(networkPools.Items, func(i, j int) bool {
+			a := networkPools.Items[i]
+			b := networkPools.Items[j]
+			if a.GetNamespace() != b.GetNamespace() {
+				return a.GetNamespace() < b.GetNamespace()
+			}
+			return a.GetName() < b.GetName()
+		})
+
+		for _, np := range networkPools.Items {
+			_, n, err := net.ParseCIDR(np.Spec.CIDRBlock)
+			if err != nil {
+				r.logger.Errorf(ctx, err, "skipping NetworkPool %#q with invalid CIDR block", np.GetNamespace()+"/"+np.GetName())
+				continue
+			}
+
+			ranges = append(ranges, r.newRange(*n, np.GetName(), np.GetNamespace(), allocated))
+		}
+	}
+
+	report := Report{
+		Ranges:  ranges,
+		Subnets: subnets,
+	}
+
+	return report, nil
+}
+
+func (r *IPAMReport) newRange(networkRange net.IPNet, networkPool string, namespace string, allocated []net.IPNet) Range {
+	allocatedSubnets, freeAddresses, freeSubnets := capacity(networkRange, allocated, r.allocatedSubnetMaskBits)
+
+	return Range{
+		CIDR:        networkRange.String(),
+		NetworkPool: networkPool,
+		Namespace:   namespace,
+
+		AllocatedSubnets: allocatedSubnets,
+		FreeAddresses:    freeAddresses,
+		FreeSubnets:      freeSubnets,
+		TotalAddresses:   size(networkRange),
+	}
+}
@@ -0,0 +1,184 @@
+package ipamreport
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/label"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_IPAMReport_Report(t *testing.T) {
+	testCases := []struct {
+		name           string
+		objects        func() []ctrlClient.Object
+		expectedReport Report
+	}{
+		{
+			name: "case 0: empty installation",
+			objects: func() []ctrlClient.Object {
+				return nil
+			},
+			expectedReport: Report{
+				Ranges: []Range{
+					{
+						CIDR:             "10.0.0.0/16",
+						AllocatedSubnets: 0,
+						FreeAddresses:    65536,
+						FreeSubnets:      256,
+						TotalAddresses:   65536,
+					},
+				},
+			},
+		},
+		{
+			name: "case 1: clusters and node pools with and without network pool",
+			objects: func() []ctrlClient.Object {
+				c1 := unittest.DefaultCluster()
+				c1.Spec.Provider.Nodes.NetworkPool = unittest.DefaultClusterID
+				c1.Status.Provider.Network.CIDR = "10.100.0.0/24"
+
+				md := unittest.DefaultMachineDeployment()
+
+				c2 := unittest.DefaultCluster()
+				c2.Name = "b3k8s"
+				c2.Labels[label.Cluster] = "b3k8s"
+				c2.Status.Provider.Network.CIDR = "10.0.0.0/23"
+
+				np := unittest.DefaultNetworkPool("10.100.0.0/16")
+
+				return []ctrlClient.Object{&c1, &md, &c2, &np}
+			},
+			expectedReport: Report{
+				Ranges: []Range{
+					{
+						CIDR:             "10.0.0.0/16",
+						AllocatedSubnets: 1,
+						FreeAddresses:    65024,
+						FreeSubnets:      254,
+						TotalAddresses:   65536,
+					},
+					{
+						CIDR:             "10.100.0.0/16",
+						NetworkPool:      unittest.DefaultClusterID,
+						Namespace:        "default",
+						AllocatedSubnets: 2,
+						FreeAddresses:    65024,
+						FreeSubnets:      254,
+						TotalAddresses:   65536,
+					},
+				},
+				Subnets: []Subnet{
+					{
+						CIDR:      "10.0.0.0/23",
+						Cluster:   "b3k8s",
+						Kind:      KindAWSCluster,
+						Name:      "b3k8s",
+						Namespace: "default",
+					},
+					{
+						CIDR:        "10.100.0.0/24",
+						Cluster:     unittest.DefaultClusterID,
+						Kind:        KindAWSCluster,
+						Name:        unittest.DefaultClusterID,
+						Namespace:   "default",
+						NetworkPool: unittest.DefaultClusterID,
+					},
+					{
+						CIDR:        "10.100.8.0/24",
+						Cluster:     unittest.DefaultClusterID,
+						Kind:        KindAWSMachineDeployment,
+						Name:        unittest.DefaultMachineDeploymentID,
+						Namespace:   "default",
+						NetworkPool: unittest.DefaultClusterID,
+					},
+				},
+			},
+		},
+		{
+			name: "case 2: partially overlapping subnets only count their overlap",
+			objects: func() []ctrlClient.Object {
+				c := unittest.DefaultCluster()
+				c.Status.Provider.Network.CIDR = "10.0.0.0/8"
+
+				return []ctrlClient.Object{&c}
+			},
+			expectedReport: Report{
+				Ranges: []Range{
+					{
+						CIDR:             "10.0.0.0/16",
+						AllocatedSubnets: 1,
+						FreeAddresses:    0,
+						FreeSubnets:      0,
+						TotalAddresses:   65536,
+					},
+				},
+				Subnets: []Subnet{
+					{
+						CIDR:      "10.0.0.0/8",
+						Cluster:   unittest.DefaultClusterID,
+						Kind:      KindAWSCluster,
+						Name:      unittest.DefaultClusterID,
+						Namespace: "default",
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var err error
+
+			ctx := context.Background()
+			k := unittest.FakeK8sClient()
+
+			for _, o := range tc.objects() {
+				err = k.CtrlClient().Create(ctx, o)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var r *IPAMReport
+			{
+				c := Config{
+					CtrlClient: k.CtrlClient(),
+					Logger:     microloggertest.New(),
+
+					AllocatedSubnetMaskBits: 24,
+					NetworkRange:            mustParseCIDR("10.0.0.0/16"),
+				}
+
+				r, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := r.Report(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(report, tc.expectedReport) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedReport, report))
+			}
+		})
+	}
+}
+
+func mustParseCIDR(val string) net.IPNet {
+	_, n, err := net.ParseCIDR(val)
+	if err != nil {
+		panic(err)
+	}
+
+	return *n
+}
@@ -0,0 +1,54 @@
+package ipamreport
+
+import "context"
+
+const (
+	KindAWSCluster           = "AWSCluster"
+	KindAWSMachineDeployment = "AWSMachineDeployment"
+)
+
+type Interface interface {
+	// Report lists all subnets allocated for tenant clusters and node pools as
+	// well as the remaining capacity of the installation's network range and
+	// of every NetworkPool CR.
+	Report(ctx context.Context) (Report, error)
+}
+
+type Report struct {
+	Ranges  []Range  `json:"ranges"`
+	Subnets []Subnet `json:"subnets"`
+}
+
+// Range describes the capacity of a network range subnets are allocated from.
+type Range struct {
+	CIDR string `json:"cidr"`
+	// NetworkPool and Namespace identify the NetworkPool CR defining the range.
+	// Both are empty for the installation's network range.
+	NetworkPool string `json:"networkPool,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+
+	// AllocatedSubnets is the number of allocated subnets overlapping with the
+	// range.
+	AllocatedSubnets int `json:"allocatedSubnets"`
+	// FreeAddresses is the number of addresses within the range not covered by
+	// any allocated subnet.
+	FreeAddresses uint64 `json:"freeAddresses"`
+	// FreeSubnets is the number of subnets of the default allocation size which
+	// can still be allocated from the range.
+	FreeSubnets uint64 `json:"freeSubnets"`
+	// TotalAddresses is the number of addresses within the range.
+	TotalAddresses uint64 `json:"totalAddresses"`
+}
+
+// Subnet describes an allocated subnet and the runtime object owning it.
+type Subnet struct {
+	CIDR      string `json:"cidr"`
+	Cluster   string `json:"cluster"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// NetworkPool is the name of the NetworkPool CR the owning cluster
+	// allocates subnets from. It is empty for the installation's network
+	// range.
+	NetworkPool string `json:"networkPool,omitempty"`
+}
@@ -0,0 +1,117 @@
+package plan
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/microerror"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// manifests are the CRs of a single tenant cluster the plan is rendered for.
+type manifests struct {
+	cluster            *infrastructurev1alpha3.AWSCluster
+	controlPlane       *infrastructurev1alpha3.AWSControlPlane
+	machineDeployments []*infrastructurev1alpha3.AWSMachineDeployment
+	networkPools       []*infrastructurev1alpha3.NetworkPool
+	release            *releasev1alpha1.Release
+
+	// others are all further objects given, e.g. the CAPI Cluster CR, which
+	// are otherwise derived from the CRs above.
+	others []client.Object
+}
+
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+
+	builders := []func(*runtime.Scheme) error{
+		apiv1beta1.AddToScheme,
+		corev1.AddToScheme,
+		infrastructurev1alpha3.AddToScheme,
+		releasev1alpha1.AddToScheme,
+	}
+	for _, b := range builders {
+		err := b(scheme)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return scheme, nil
+}
+
+// decodeManifests decodes the given YAML or JSON documents. Every document
+// may contain multiple objects separated by "---".
+func decodeManifests(scheme *runtime.Scheme, documents [][]byte) (manifests, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var m manifests
+	for _, d := range documents {
+		r := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(d)))
+
+		for {
+			b, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return manifests{}, microerror.Mask(err)
+			}
+
+			if len(bytes.TrimSpace(b)) == 0 {
+				continue
+			}
+
+			obj, _, err := decoder.Decode(b, nil, nil)
+			if err != nil {
+				return manifests{}, microerror.Maskf(invalidManifestError, "%s", err)
+			}
+
+			switch o := obj.(type) {
+			case *infrastructurev1alpha3.AWSCluster:
+				if m.cluster != nil {
+					return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 AWSCluster CR got more")
+				}
+				m.cluster = o
+			case *infrastructurev1alpha3.AWSControlPlane:
+				if m.controlPlane != nil {
+					return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 AWSControlPlane CR got more")
+				}
+				m.controlPlane = o
+			case *infrastructurev1alpha3.AWSMachineDeployment:
+				m.machineDeployments = append(m.machineDeployments, o)
+			case *infrastructurev1alpha3.NetworkPool:
+				m.networkPools = append(m.networkPools, o)
+			case *releasev1alpha1.Release:
+				if m.release != nil {
+					return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 Release CR got more")
+				}
+				m.release = o
+			case client.Object:
+				m.others = append(m.others, o)
+			default:
+				return manifests{}, microerror.Maskf(invalidManifestError, "unsupported object %T", obj)
+			}
+		}
+	}
+
+	if m.cluster == nil {
+		return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 AWSCluster CR got 0")
+	}
+	if m.controlPlane == nil {
+		return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 AWSControlPlane CR got 0")
+	}
+	if m.release == nil {
+		return manifests{}, microerror.Maskf(invalidManifestError, "expected 1 Release CR got 0")
+	}
+
+	return m, nil
+}
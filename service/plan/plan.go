@@ -0,0 +1,829 @@
+// Package plan renders the CloudFormation templates and cloud configs of a
+// tenant cluster offline, without talking to AWS or a Kubernetes API. It is
+// meant to review template changes between operator versions, e.g. in CI.
+//
+// All information the operator discovers at runtime, like VPC IDs, subnets
+// of availability zones or load balancer DNS names, are taken from the
+// controller context defaults of the unittest package. Certificates, random
+// keys and secrets are replaced by placeholders. Hosted zone IDs of the
+// control plane are not looked up and left empty.
+package plan
+
+import (
+	"context"
+	"net"
+	"os"
+	"path"
+	"strconv"
+
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/certs/v4/pkg/certs"
+	"github.com/giantswarm/certs/v4/pkg/certstest"
+	"github.com/giantswarm/ipam"
+	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
+	k8sannotation "github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/giantswarm/randomkeys/v3/randomkeystest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpapiwhitelist"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpf"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpi"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpn"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnp"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpf"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tcnpnodeconfig"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changedetection"
+	"github.com/giantswarm/aws-operator/v16/service/internal/changeset"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudconfig"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
+	"github.com/giantswarm/aws-operator/v16/service/internal/cphostedzone"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
+	"github.com/giantswarm/aws-operator/v16/service/internal/encrypter"
+	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
+	"github.com/giantswarm/aws-operator/v16/service/internal/images"
+	"github.com/giantswarm/aws-operator/v16/service/internal/instancetypes"
+	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+	"github.com/giantswarm/aws-operator/v16/service/internal/releases"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+const (
+	// placeholder is used for all secret values rendered into cloud configs.
+	placeholder = "plan-placeholder"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+
+	AlikeInstances   string
+	CalicoCIDR       int
+	CalicoMTU        int
+	CalicoSubnet     string
+	ClusterDomain    string
+	ClusterIPRange   string
+	DockerDaemonCIDR string
+	IgnitionPath     string
+	InstallationName string
+	InstallationTags map[string]string
+	// InstanceTypes are the resources of the instance types used by node
+	// pools, including their alike instance types, which the operator
+	// otherwise looks up via the EC2 API.
+	InstanceTypes           map[string]InstanceType
+	NetworkCIDR             string
+	NetworkSetupDockerImage string
+	RegistryDomain          string
+	RegistryMirrors         []string
+	Route53Enabled          bool
+	SubnetMaskBits          int
+}
+
+// InstanceType describes the resources of an EC2 instance type.
+type InstanceType struct {
+	GPUs      int64 `json:"gpus"`
+	MemoryMiB int64 `json:"memoryMiB"`
+	VCPUs     int64 `json:"vcpus"`
+}
+
+type Plan struct {
+	logger micrologger.Logger
+
+	alikeInstances          string
+	calicoCIDR              int
+	calicoMTU               int
+	calicoSubnet            string
+	clusterDomain           string
+	clusterIPRange          string
+	dockerDaemonCIDR        string
+	ignitionPath            string
+	installationName        string
+	installationTags        map[string]string
+	instanceTypes           map[string]instancetypes.InstanceType
+	networkRange            net.IPNet
+	networkSetupDockerImage string
+	registryDomain          string
+	registryMirrors         []string
+	route53Enabled          bool
+	subnetMask              net.IPMask
+}
+
+func New(config Config) (*Plan, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.InstallationName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", config)
+	}
+	if config.SubnetMaskBits == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.SubnetMaskBits must not be empty", config)
+	}
+
+	// The cloud config templates are rendered from the files of the
+	// k8scloudconfig repository, which must be available locally.
+	_, err := os.Stat(config.IgnitionPath)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.IgnitionPath must be a directory: %s", config, err)
+	}
+
+	_, networkRange, err := net.ParseCIDR(config.NetworkCIDR)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkCIDR must be a valid CIDR: %s", config, err)
+	}
+
+	instanceTypes := map[string]instancetypes.InstanceType{}
+	for k, v := range config.InstanceTypes {
+		instanceTypes[k] = instancetypes.InstanceType{
+			GPUs:      v.GPUs,
+			MemoryMiB: v.MemoryMiB,
+			VCPUs:     v.VCPUs,
+		}
+	}
+
+	p := &Plan{
+		logger: config.Logger,
+
+		alikeInstances:          config.AlikeInstances,
+		calicoCIDR:              config.CalicoCIDR,
+		calicoMTU:               config.CalicoMTU,
+		calicoSubnet:            config.CalicoSubnet,
+		clusterDomain:           config.ClusterDomain,
+		clusterIPRange:          config.ClusterIPRange,
+		dockerDaemonCIDR:        config.DockerDaemonCIDR,
+		ignitionPath:            config.IgnitionPath,
+		installationName:        config.InstallationName,
+		installationTags:        config.InstallationTags,
+		instanceTypes:           instanceTypes,
+		networkRange:            *networkRange,
+		networkSetupDockerImage: config.NetworkSetupDockerImage,
+		registryDomain:          config.RegistryDomain,
+		registryMirrors:         config.RegistryMirrors,
+		route53Enabled:          config.Route53Enabled,
+		subnetMask:              net.CIDRMask(config.SubnetMaskBits, 32),
+	}
+
+	return p, nil
+}
+
+// Render renders the CloudFormation templates of all stacks and the cloud
+// configs of the tenant cluster described by the given manifests. The
+// manifests must contain the AWSCluster, AWSControlPlane and Release CRs and
+// may contain AWSMachineDeployment and NetworkPool CRs. The returned map
+// contains the rendered files by their relative path, e.g.
+//
+//	cluster-al9qy-tccp.yaml
+//	cloudconfig/cluster-al9qy-tcnp-g3j50
+func (p *Plan) Render(ctx context.Context, documents ...[]byte) (map[string]string, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	m, err := decodeManifests(scheme, documents)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = p.allocateSubnets(m)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	k := unittest.FakeK8sClient()
+
+	for _, o := range p.newObjects(m) {
+		err = k.CtrlClient().Create(ctx, o)
+		if apierrors.IsAlreadyExists(err) {
+			// Objects given with the manifests take precedence over the ones
+			// derived from the AWS CRs.
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	r, err := p.newRenderers(k)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	files := map[string]string{}
+
+	// The TCCP stack renders the API whitelist the cluster defines via
+	// annotations or a ConfigMap given with the manifests.
+	tccpCtx := unittest.DefaultContext()
+	err = r.tccpAPIWhitelist.EnsureCreated(tccpCtx, m.cluster)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	stacks := []stack{
+		{name: key.StackNameTCCPI(m.cluster), obj: m.cluster, render: r.tccpi.Plan, ctx: unittest.DefaultContext()},
+		{name: key.StackNameTCCP(m.cluster), obj: m.cluster, render: r.tccp.Plan, ctx: tccpCtx},
+		{name: key.StackNameTCCPF(m.cluster), obj: m.cluster, render: r.tccpf.Plan, ctx: unittest.DefaultContext()},
+		{name: key.StackNameTCCPN(m.cluster), obj: m.controlPlane, render: r.tccpn.Plan, ctx: unittest.DefaultContextControlPlane()},
+	}
+	// The TCNP stacks and cloud configs render the node config the node pools
+	// define via annotations or a ConfigMap given with the manifests.
+	tcnpCtxs := map[string]context.Context{}
+	for _, md := range m.machineDeployments {
+		tcnpCtx := unittest.DefaultContext()
+		err = r.tcnpNodeConfig.EnsureCreated(tcnpCtx, md)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		tcnpCtxs[md.GetName()] = tcnpCtx
+	}
+
+	for _, md := range m.machineDeployments {
+		stacks = append(stacks,
+			stack{name: key.StackNameTCNP(md), obj: md, render: r.tcnp.Plan, ctx: tcnpCtxs[md.GetName()]},
+			stack{name: key.StackNameTCNPF(md), obj: md, render: r.tcnpf.Plan, ctx: unittest.DefaultContext()},
+		)
+	}
+
+	for _, s := range stacks {
+		p.logger.Debugf(ctx, "rendering template of cloud formation stack %#q", s.name)
+
+		body, err := s.render(s.ctx, s.obj)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		files[s.name+".yaml"] = body
+
+		p.logger.Debugf(ctx, "rendered template of cloud formation stack %#q", s.name)
+	}
+
+	cloudConfigs := []cloudConfig{
+		{obj: m.controlPlane, cloudConfig: r.tccpnCloudConfig, ctx: unittest.DefaultContextControlPlane()},
+	}
+	for _, md := range m.machineDeployments {
+		cloudConfigs = append(cloudConfigs, cloudConfig{obj: md, cloudConfig: r.tcnpCloudConfig, ctx: tcnpCtxs[md.GetName()]})
+	}
+
+	for _, c := range cloudConfigs {
+		paths, err := c.cloudConfig.NewPaths(c.ctx, c.obj)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		templates, err := c.cloudConfig.NewTemplates(c.ctx, c.obj)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for i := range paths {
+			p.logger.Debugf(ctx, "rendered cloud config %#q", path.Base(paths[i]))
+			files[path.Join("cloudconfig", path.Base(paths[i]))] = templates[i]
+		}
+	}
+
+	return files, nil
+}
+
+// allocateSubnets allocates the subnets of the cluster and its node pools
+// the same way the IPAM resource does, unless they are already allocated.
+func (p *Plan) allocateSubnets(m manifests) error {
+	networkRange := p.networkRange
+	if m.cluster.Spec.Provider.Nodes.NetworkPool != "" {
+		var found bool
+		for _, np := range m.networkPools {
+			if np.Name != m.cluster.Spec.Provider.Nodes.NetworkPool {
+				continue
+			}
+
+			_, ipnet, err := net.ParseCIDR(np.Spec.CIDRBlock)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			networkRange = *ipnet
+			found = true
+		}
+
+		if !found {
+			return microerror.Maskf(invalidManifestError, "expected NetworkPool CR %#q", m.cluster.Spec.Provider.Nodes.NetworkPool)
+		}
+	}
+
+	var allocated []net.IPNet
+	{
+		subnets := []string{key.StatusClusterNetworkCIDR(*m.cluster)}
+		for _, md := range m.machineDeployments {
+			subnets = append(subnets, md.GetAnnotations()[annotation.MachineDeploymentSubnet])
+		}
+
+		for _, s := range subnets {
+			if s == "" {
+				continue
+			}
+
+			_, ipnet, err := net.ParseCIDR(s)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			allocated = append(allocated, *ipnet)
+		}
+	}
+
+	free := func(obj client.Object) (net.IPNet, error) {
+		mask := p.subnetMask
+		if s, ok := obj.GetAnnotations()[k8sannotation.AWSSubnetSize]; ok {
+			bits, err := strconv.Atoi(s)
+			if err != nil {
+				return net.IPNet{}, microerror.Mask(err)
+			}
+			mask = net.CIDRMask(bits, 32)
+		}
+
+		subnet, err := ipam.Free(networkRange, mask, allocated)
+		if err != nil {
+			return net.IPNet{}, microerror.Mask(err)
+		}
+		allocated = append(allocated, subnet)
+
+		return subnet, nil
+	}
+
+	if key.StatusClusterNetworkCIDR(*m.cluster) == "" {
+		subnet, err := free(m.cluster)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		m.cluster.Status.Provider.Network.CIDR = subnet.String()
+	}
+
+	for _, md := range m.machineDeployments {
+		if md.GetAnnotations()[annotation.MachineDeploymentSubnet] != "" {
+			continue
+		}
+
+		subnet, err := free(md)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if md.Annotations == nil {
+			md.Annotations = map[string]string{}
+		}
+		md.Annotations[annotation.MachineDeploymentSubnet] = subnet.String()
+	}
+
+	return nil
+}
+
+// newObjects returns the objects given with the manifests followed by all
+// objects the operator expects to exist for the given AWS CRs.
+func (p *Plan) newObjects(m manifests) []client.Object {
+	objects := append([]client.Object{}, m.others...)
+
+	objects = append(objects, m.cluster, m.controlPlane, m.release)
+	for _, md := range m.machineDeployments {
+		objects = append(objects, md)
+	}
+	for _, np := range m.networkPools {
+		objects = append(objects, np)
+	}
+
+	objects = append(objects,
+		&apiv1beta1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:    copyMap(m.cluster.Labels),
+				Name:      m.cluster.Name,
+				Namespace: m.cluster.Namespace,
+			},
+		},
+		&infrastructurev1alpha3.G8sControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:    copyMap(m.controlPlane.Labels),
+				Name:      m.controlPlane.Name,
+				Namespace: m.controlPlane.Namespace,
+			},
+			Spec: infrastructurev1alpha3.G8sControlPlaneSpec{
+				Replicas: len(m.controlPlane.Spec.AvailabilityZones),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.EncryptionConfigSecretName(key.ClusterID(m.cluster)),
+				Namespace: m.cluster.Namespace,
+			},
+			Data: map[string][]byte{
+				key.EncryptionProviderConfig: []byte(placeholder),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.ServiceAccountV2SecretName(key.ClusterID(m.cluster)),
+				Namespace: m.cluster.Namespace,
+			},
+			Data: map[string][]byte{
+				key.ServiceAccountV2Priv: []byte(placeholder),
+				key.ServiceAccountV2Pub:  []byte(placeholder),
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.IRSACloudfrontConfigMap(key.ClusterID(m.cluster)),
+				Namespace: m.cluster.Namespace,
+			},
+			Data: map[string]string{
+				"domain": placeholder,
+			},
+		},
+	)
+
+	for _, md := range m.machineDeployments {
+		objects = append(objects, &apiv1beta1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:    copyMap(md.Labels),
+				Name:      md.Name,
+				Namespace: md.Namespace,
+			},
+		})
+	}
+
+	return objects
+}
+
+// stack is a CloudFormation stack rendered with its own controller context,
+// since resources may modify the context.
+type stack struct {
+	name   string
+	obj    client.Object
+	render func(ctx context.Context, obj interface{}) (string, error)
+	ctx    context.Context
+}
+
+type cloudConfig struct {
+	obj         client.Object
+	cloudConfig cloudconfig.Interface
+	ctx         context.Context
+}
+
+type renderers struct {
+	tccpAPIWhitelist *tccpapiwhitelist.Resource
+	tcnpNodeConfig   *tcnpnodeconfig.Resource
+
+	tccpi *tccpi.Resource
+	tccp  *tccp.Resource
+	tccpf *tccpf.Resource
+	tccpn *tccpn.Resource
+	tcnp  *tcnp.Resource
+	tcnpf *tcnpf.Resource
+
+	tccpnCloudConfig *cloudconfig.TCCPN
+	tcnpCloudConfig  *cloudconfig.TCNP
+}
+
+func (p *Plan) newRenderers(k k8sclient.Interface) (renderers, error) {
+	var err error
+
+	var e recorder.Interface
+	{
+		c := recorder.Config{
+			K8sClient: k,
+
+			Component: "plan",
+		}
+
+		e = recorder.New(c)
+	}
+
+	var ct cloudtags.Interface
+	{
+		c := cloudtags.Config{
+			Event:     e,
+			K8sClient: k,
+			Logger:    p.logger,
+
+			InstallationName: p.installationName,
+			InstallationTags: p.installationTags,
+		}
+
+		ct, err = cloudtags.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	var cs changeset.Interface
+	{
+		c := changeset.Config{
+			Event:     e,
+			K8sClient: k,
+			Logger:    p.logger,
+		}
+
+		cs, err = changeset.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	var rel releases.Interface
+	{
+		c := releases.Config{
+			K8sClient: k,
+		}
+
+		rel, err = releases.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	var h hamaster.Interface
+	{
+		c := hamaster.Config{
+			K8sClient: k,
+		}
+
+		h, err = hamaster.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	var i images.Interface
+	{
+		c := images.Config{
+			K8sClient: k,
+
+			RegistryDomain: p.registryDomain,
+		}
+
+		i, err = images.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	// Hosted zones are looked up via the Route53 API, which is not available
+	// offline.
+	var hz *cphostedzone.HostedZone
+	{
+		c := cphostedzone.Config{
+			DNS:    dns.NewFakeDNS(dns.FakeDNSConfig{Provider: dns.NewFakeProvider()}),
+			Logger: p.logger,
+		}
+
+		hz, err = cphostedzone.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	enc := &encrypter.Mock{}
+
+	var r renderers
+
+	{
+		c := tccpapiwhitelist.Config{
+			Event:     e,
+			K8sClient: k,
+			Logger:    p.logger,
+		}
+
+		r.tccpAPIWhitelist, err = tccpapiwhitelist.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := tcnpnodeconfig.Config{
+			K8sClient: k,
+			Logger:    p.logger,
+		}
+
+		r.tcnpNodeConfig, err = tcnpnodeconfig.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := tccpi.Config{
+			CloudTags: ct,
+			Event:     e,
+			Logger:    p.logger,
+		}
+
+		r.tccpi, err = tccpi.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		var d *changedetection.TCCP
+		{
+			c := changedetection.TCCPConfig{
+				Event:     e,
+				K8sClient: k,
+				Logger:    p.logger,
+			}
+
+			d, err = changedetection.NewTCCP(c)
+			if err != nil {
+				return renderers{}, microerror.Mask(err)
+			}
+		}
+
+		c := tccp.Config{
+			ChangeSet:  cs,
+			CloudTags:  ct,
+			CtrlClient: k.CtrlClient(),
+			Event:      e,
+			HAMaster:   h,
+			Detection:  d,
+			K8sClient:  k,
+			Logger:     p.logger,
+
+			CIDRBlockAWSCNI:  p.dockerDaemonCIDR,
+			InstallationName: p.installationName,
+			Route53Enabled:   p.route53Enabled,
+		}
+
+		r.tccp, err = tccp.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		var d *changedetection.TCCPF
+		{
+			c := changedetection.TCCPFConfig{
+				Event:     e,
+				K8sClient: k,
+				Logger:    p.logger,
+			}
+
+			d, err = changedetection.NewTCCPF(c)
+			if err != nil {
+				return renderers{}, microerror.Mask(err)
+			}
+		}
+
+		c := tccpf.Config{
+			CloudTags:  ct,
+			Detection:  d,
+			Event:      e,
+			HostedZone: hz,
+			Logger:     p.logger,
+
+			Route53Enabled: p.route53Enabled,
+		}
+
+		r.tccpf, err = tccpf.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		var d *changedetection.TCCPN
+		{
+			c := changedetection.TCCPNConfig{
+				Event:     e,
+				HAMaster:  h,
+				K8sClient: k,
+				Logger:    p.logger,
+				Releases:  rel,
+			}
+
+			d, err = changedetection.NewTCCPN(c)
+			if err != nil {
+				return renderers{}, microerror.Mask(err)
+			}
+		}
+
+		c := tccpn.Config{
+			ChangeSet: cs,
+			CloudTags: ct,
+			Detection: d,
+			Encrypter: enc,
+			Event:     e,
+			HAMaster:  h,
+			Images:    i,
+			K8sClient: k,
+			Logger:    p.logger,
+
+			Route53Enabled: p.route53Enabled,
+		}
+
+		r.tccpn, err = tccpn.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		var d *changedetection.TCNP
+		{
+			c := changedetection.TCNPConfig{
+				Event:     e,
+				K8sClient: k,
+				Logger:    p.logger,
+				Releases:  rel,
+			}
+
+			d, err = changedetection.NewTCNP(c)
+			if err != nil {
+				return renderers{}, microerror.Mask(err)
+			}
+		}
+
+		c := tcnp.Config{
+			ChangeSet:     cs,
+			CloudTags:     ct,
+			Detection:     d,
+			Encrypter:     enc,
+			Event:         e,
+			Images:        i,
+			InstanceTypes: &instancetypes.Mock{InstanceTypes: p.instanceTypes},
+			K8sClient:     k,
+			Logger:        p.logger,
+
+			AlikeInstances:   p.alikeInstances,
+			InstallationName: p.installationName,
+		}
+
+		r.tcnp, err = tcnp.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	{
+		c := tcnpf.Config{
+			CloudTags: ct,
+			Event:     e,
+			Logger:    p.logger,
+		}
+
+		r.tcnpf, err = tcnpf.New(c)
+		if err != nil {
+			return renderers{}, microerror.Mask(err)
+		}
+	}
+
+	cc := cloudconfig.Config{
+		CertsSearcher: certstest.NewSearcher(certstest.Config{
+			TLS: certs.TLS{
+				CA:  []byte(placeholder),
+				Crt: []byte(placeholder),
+				Key: []byte(placeholder),
+			},
+		}),
+		CloudTags:          ct,
+		Encrypter:          enc,
+		Event:              e,
+		HAMaster:           h,
+		Images:             i,
+		K8sClient:          k,
+		Logger:             p.logger,
+		RandomKeysSearcher: randomkeystest.NewSearcher(),
+
+		CalicoCIDR:              p.calicoCIDR,
+		CalicoMTU:               p.calicoMTU,
+		CalicoSubnet:            p.calicoSubnet,
+		ClusterDomain:           p.clusterDomain,
+		ClusterIPRange:          p.clusterIPRange,
+		DockerDaemonCIDR:        p.dockerDaemonCIDR,
+		DockerhubToken:          placeholder,
+		IgnitionPath:            p.ignitionPath,
+		NetworkSetupDockerImage: p.networkSetupDockerImage,
+		RegistryDomain:          p.registryDomain,
+		RegistryMirrors:         p.registryMirrors,
+		SSHUserList:             "plan:" + placeholder,
+		SSOPublicKey:            placeholder,
+	}
+
+	r.tccpnCloudConfig, err = cloudconfig.NewTCCPN(cloudconfig.TCCPNConfig{Config: cc})
+	if err != nil {
+		return renderers{}, microerror.Mask(err)
+	}
+
+	r.tcnpCloudConfig, err = cloudconfig.NewTCNP(cloudconfig.TCNPConfig{Config: cc})
+	if err != nil {
+		return renderers{}, microerror.Mask(err)
+	}
+
+	return r, nil
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := map[string]string{}
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
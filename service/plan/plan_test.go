@@ -0,0 +1,276 @@
+package plan
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
+	"github.com/giantswarm/micrologger/microloggertest"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v4/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/aws-operator/v16/pkg/annotation"
+	"github.com/giantswarm/aws-operator/v16/service/controller/key"
+	"github.com/giantswarm/aws-operator/v16/service/internal/unittest"
+)
+
+func Test_Plan_Render(t *testing.T) {
+	testCases := []struct {
+		name            string
+		manifests       func() [][]byte
+		expectedFiles   []string
+		expectedSubnets []string
+		errorMatcher    func(error) bool
+	}{
+		{
+			name: "case 0: cluster with node pool",
+			manifests: func() [][]byte {
+				cl := newCluster()
+				cp := unittest.DefaultAWSControlPlane()
+				md := unittest.DefaultMachineDeployment()
+				re := newRelease()
+
+				return [][]byte{
+					mustMarshal(t, &cl, "infrastructure.giantswarm.io/v1alpha3", "AWSCluster"),
+					mustMarshal(t, &cp, "infrastructure.giantswarm.io/v1alpha3", "AWSControlPlane"),
+					mustMarshal(t, &md, "infrastructure.giantswarm.io/v1alpha3", "AWSMachineDeployment"),
+					mustMarshal(t, &re, "release.giantswarm.io/v1alpha1", "Release"),
+				}
+			},
+			expectedFiles: []string{
+				"cloudconfig/cluster-8y5ck-tccpn-0",
+				"cloudconfig/cluster-8y5ck-tcnp-al9qy",
+				"cluster-8y5ck-tccp.yaml",
+				"cluster-8y5ck-tccpf.yaml",
+				"cluster-8y5ck-tccpi.yaml",
+				"cluster-8y5ck-tccpn.yaml",
+				"cluster-8y5ck-tcnp-al9qy.yaml",
+				"cluster-8y5ck-tcnpf-al9qy.yaml",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 1: subnets allocated from network pool",
+			manifests: func() [][]byte {
+				cl := newCluster()
+				cl.Spec.Provider.Nodes.NetworkPool = "custom"
+				cl.Status.Provider.Network.CIDR = ""
+				cp := unittest.DefaultAWSControlPlane()
+				md := unittest.DefaultMachineDeployment()
+				delete(md.Annotations, annotation.MachineDeploymentSubnet)
+				np := unittest.DefaultNetworkPool("10.200.0.0/16")
+				np.Name = "custom"
+				re := newRelease()
+
+				return [][]byte{
+					mustMarshal(t, &cl, "infrastructure.giantswarm.io/v1alpha3", "AWSCluster"),
+					append(append(
+						mustMarshal(t, &cp, "infrastructure.giantswarm.io/v1alpha3", "AWSControlPlane"), []byte("\n---\n")...),
+						mustMarshal(t, &md, "infrastructure.giantswarm.io/v1alpha3", "AWSMachineDeployment")...,
+					),
+					mustMarshal(t, &np, "infrastructure.giantswarm.io/v1alpha3", "NetworkPool"),
+					mustMarshal(t, &re, "release.giantswarm.io/v1alpha1", "Release"),
+				}
+			},
+			expectedFiles: []string{
+				"cloudconfig/cluster-8y5ck-tccpn-0",
+				"cloudconfig/cluster-8y5ck-tcnp-al9qy",
+				"cluster-8y5ck-tccp.yaml",
+				"cluster-8y5ck-tccpf.yaml",
+				"cluster-8y5ck-tccpi.yaml",
+				"cluster-8y5ck-tccpn.yaml",
+				"cluster-8y5ck-tcnp-al9qy.yaml",
+				"cluster-8y5ck-tcnpf-al9qy.yaml",
+			},
+			expectedSubnets: []string{
+				"10.200.0.0/24",
+				"10.200.1.0/24",
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 2: missing release",
+			manifests: func() [][]byte {
+				cl := newCluster()
+				cp := unittest.DefaultAWSControlPlane()
+
+				return [][]byte{
+					mustMarshal(t, &cl, "infrastructure.giantswarm.io/v1alpha3", "AWSCluster"),
+					mustMarshal(t, &cp, "infrastructure.giantswarm.io/v1alpha3", "AWSControlPlane"),
+				}
+			},
+			errorMatcher: IsInvalidManifest,
+		},
+		{
+			name: "case 3: missing network pool",
+			manifests: func() [][]byte {
+				cl := newCluster()
+				cl.Spec.Provider.Nodes.NetworkPool = "custom"
+				cp := unittest.DefaultAWSControlPlane()
+				re := newRelease()
+
+				return [][]byte{
+					mustMarshal(t, &cl, "infrastructure.giantswarm.io/v1alpha3", "AWSCluster"),
+					mustMarshal(t, &cp, "infrastructure.giantswarm.io/v1alpha3", "AWSControlPlane"),
+					mustMarshal(t, &re, "release.giantswarm.io/v1alpha1", "Release"),
+				}
+			},
+			errorMatcher: IsInvalidManifest,
+		},
+	}
+
+	// The cloud config templates are rendered from the k8scloudconfig module
+	// the operator depends on.
+	var ignitionPath string
+	{
+		out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/giantswarm/k8scloudconfig/v18").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ignitionPath = strings.TrimSpace(string(out))
+	}
+
+	amiFile := filepath.Join(t.TempDir(), "ami.json")
+	{
+		err := os.WriteFile(amiFile, []byte(`{"2345.3.1":{"eu-central-1":"ami-0a9a5d2b65cce04eb"}}`), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = key.LoadAMIs(amiFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var err error
+
+			var p *Plan
+			{
+				c := Config{
+					Logger: microloggertest.New(),
+
+					AlikeInstances:   "{}",
+					CalicoCIDR:       16,
+					CalicoMTU:        1430,
+					CalicoSubnet:     "10.2.0.0",
+					ClusterDomain:    "cluster.local",
+					ClusterIPRange:   "172.31.0.0/16",
+					DockerDaemonCIDR: "172.17.0.1/16",
+					IgnitionPath:     ignitionPath,
+					InstallationName: "dummy",
+					InstanceTypes: map[string]InstanceType{
+						"m5.2xlarge": {
+							MemoryMiB: 32768,
+							VCPUs:     8,
+						},
+					},
+					NetworkCIDR:             "10.1.0.0/16",
+					NetworkSetupDockerImage: "dummy",
+					RegistryDomain:          "dummy",
+					Route53Enabled:          true,
+					SubnetMaskBits:          24,
+				}
+
+				p, err = New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			files, err := p.Render(context.Background(), tc.manifests()...)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			var names []string
+			for n, f := range files {
+				names = append(names, n)
+
+				if filepath.Ext(n) != ".yaml" {
+					continue
+				}
+				_, err = yaml.YAMLToJSONStrict([]byte(f))
+				if err != nil {
+					t.Fatalf("file %#q: %s", n, err)
+				}
+			}
+			sort.Strings(names)
+
+			if !cmp.Equal(names, tc.expectedFiles) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedFiles, names))
+			}
+
+			for _, s := range tc.expectedSubnets {
+				var found bool
+				for _, f := range files {
+					if strings.Contains(f, s) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("expected subnet %#q to be rendered", s)
+				}
+			}
+		})
+	}
+}
+
+// newCluster returns the default cluster with the pod network configured,
+// which is required to render cloud configs.
+func newCluster() infrastructurev1alpha3.AWSCluster {
+	cl := unittest.DefaultCluster()
+	cl.Spec.Provider.Pods.CIDRBlock = "100.64.0.0/16"
+
+	return cl
+}
+
+// newRelease returns the default release with all components required to
+// render cloud configs.
+func newRelease() releasev1alpha1.Release {
+	re := unittest.DefaultRelease()
+	re.Spec.Components = append(re.Spec.Components,
+		releasev1alpha1.ReleaseSpecComponent{Name: "aws-cni", Version: "1.11.4"},
+		releasev1alpha1.ReleaseSpecComponent{Name: "calico", Version: "3.21.5"},
+		releasev1alpha1.ReleaseSpecComponent{Name: "etcd", Version: "3.5.4"},
+		releasev1alpha1.ReleaseSpecComponent{Name: "kubernetes", Version: "1.25.16"},
+	)
+
+	return re
+}
+
+func mustMarshal(t *testing.T, obj runtime.Object, apiVersion string, kind string) []byte {
+	obj.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
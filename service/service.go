@@ -4,10 +4,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	corev1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/core/v1alpha1"
 	infrastructurev1alpha3 "github.com/giantswarm/apiextensions/v6/pkg/apis/infrastructure/v1alpha3"
@@ -28,12 +30,14 @@ import (
 	"github.com/giantswarm/aws-operator/v16/flag"
 	"github.com/giantswarm/aws-operator/v16/pkg/project"
 	"github.com/giantswarm/aws-operator/v16/service/controller"
-	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccp"
+	"github.com/giantswarm/aws-operator/v16/service/controller/resource/tccpapiwhitelist"
 	"github.com/giantswarm/aws-operator/v16/service/internal/cloudtags"
+	"github.com/giantswarm/aws-operator/v16/service/internal/dns"
 	"github.com/giantswarm/aws-operator/v16/service/internal/hamaster"
 	"github.com/giantswarm/aws-operator/v16/service/internal/images"
 	"github.com/giantswarm/aws-operator/v16/service/internal/locker"
 	"github.com/giantswarm/aws-operator/v16/service/internal/recorder"
+	"github.com/giantswarm/aws-operator/v16/service/ipamreport"
 )
 
 // Config represents the configuration used to create a new service.
@@ -45,12 +49,14 @@ type Config struct {
 }
 
 type Service struct {
-	Version *version.Service
+	IPAMReport *ipamreport.IPAMReport
+	Version    *version.Service
 
 	bootOnce                           sync.Once
 	clusterController                  *controller.Cluster
 	controlPlaneController             *controller.ControlPlane
 	controlPlaneDrainerController      *controller.ControlPlaneDrainer
+	interruptionHandlerController      *controller.InterruptionHandler
 	machineDeploymentController        *controller.MachineDeployment
 	machineDeploymentDrainerController *controller.MachineDeploymentDrainer
 	terminateUnhealthyNodeController   *controller.TerminateUnhealthyNode
@@ -124,27 +130,27 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
-	var certsSearcher *certs.Searcher
+	var awsClientPool *aws.Pool
 	{
-		c := certs.Config{
-			K8sClient: k8sClient.K8sClient(),
-			Logger:    config.Logger,
+		c := aws.PoolConfig{
+			ExpiryWindow: 5 * time.Minute,
+			IdleTTL:      30 * time.Minute,
 		}
 
-		certsSearcher, err = certs.NewSearcher(c)
+		awsClientPool, err = aws.NewPool(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 	}
 
-	var cloudtagObject cloudtags.Interface
+	var certsSearcher *certs.Searcher
 	{
-		c := cloudtags.Config{
-			K8sClient: k8sClient,
+		c := certs.Config{
+			K8sClient: k8sClient.K8sClient(),
 			Logger:    config.Logger,
 		}
 
-		cloudtagObject, err = cloudtags.New(c)
+		certsSearcher, err = certs.NewSearcher(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -161,6 +167,31 @@ func New(config Config) (*Service, error) {
 		event = recorder.New(c)
 	}
 
+	var cloudtagObject cloudtags.Interface
+	{
+		var installationTags map[string]string
+		if t := config.Viper.GetString(config.Flag.Service.AWS.Tags); t != "" {
+			err := json.Unmarshal([]byte(t), &installationTags)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+
+		c := cloudtags.Config{
+			Event:     event,
+			K8sClient: k8sClient,
+			Logger:    config.Logger,
+
+			InstallationName: config.Viper.GetString(config.Flag.Service.Installation.Name),
+			InstallationTags: installationTags,
+		}
+
+		cloudtagObject, err = cloudtags.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var ha hamaster.Interface
 	{
 		c := hamaster.Config{
@@ -209,6 +240,22 @@ func New(config Config) (*Service, error) {
 		ipamNetworkRange = *ipnet
 	}
 
+	var ipamReport *ipamreport.IPAMReport
+	{
+		c := ipamreport.Config{
+			CtrlClient: k8sClient.CtrlClient(),
+			Logger:     config.Logger,
+
+			AllocatedSubnetMaskBits: config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.SubnetMaskBits),
+			NetworkRange:            ipamNetworkRange,
+		}
+
+		ipamReport, err = ipamreport.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var randomKeysSearcher randomkeys.Interface
 	{
 		c := randomkeys.Config{
@@ -222,41 +269,65 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
+	var dnsService *dns.DNS
+	{
+		c := dns.Config{
+			Provider: config.Viper.GetString(config.Flag.Service.AWS.DNS.Provider),
+			RFC2136: dns.RFC2136ProviderConfig{
+				Server:        config.Viper.GetString(config.Flag.Service.AWS.DNS.RFC2136.Server),
+				TSIGAlgorithm: config.Viper.GetString(config.Flag.Service.AWS.DNS.RFC2136.TSIGAlgorithm),
+				TSIGKeyName:   config.Viper.GetString(config.Flag.Service.AWS.DNS.RFC2136.TSIGKeyName),
+				TSIGSecret:    config.Viper.GetString(config.Flag.Service.AWS.DNS.RFC2136.TSIGSecret),
+			},
+			Route53Enabled: config.Viper.GetBool(config.Flag.Service.AWS.Route53.Enabled),
+		}
+
+		dnsService, err = dns.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var clusterController *controller.Cluster
 	{
 		c := controller.ClusterConfig{
-			CloudTags: cloudtagObject,
-			Event:     event,
-			K8sClient: k8sClient,
-			HAMaster:  ha,
-			Locker:    kubeLockLocker,
-			Logger:    config.Logger,
+			AWSClientPool: awsClientPool,
+			CloudTags:     cloudtagObject,
+			DNS:           dnsService,
+			Event:         event,
+			K8sClient:     k8sClient,
+			HAMaster:      ha,
+			Locker:        kubeLockLocker,
+			Logger:        config.Logger,
 
 			AccessLogsExpiration:  config.Viper.GetInt(config.Flag.Service.AWS.S3AccessLogsExpiration),
 			AdvancedMonitoringEC2: config.Viper.GetBool(config.Flag.Service.AWS.AdvancedMonitoringEC2),
-			APIWhitelist: tccp.ConfigAPIWhitelist{
-				Private: tccp.ConfigAPIWhitelistSecurityGroup{
+			APIWhitelist: tccpapiwhitelist.ConfigAPIWhitelist{
+				Private: tccpapiwhitelist.ConfigAPIWhitelistSecurityGroup{
 					Enabled:    config.Viper.GetBool(config.Flag.Service.Installation.Guest.Kubernetes.API.Security.Whitelist.Private.Enabled),
 					SubnetList: strings.Split(config.Viper.GetString(config.Flag.Service.Installation.Guest.Kubernetes.API.Security.Whitelist.Private.SubnetList), ","),
 				},
-				Public: tccp.ConfigAPIWhitelistSecurityGroup{
+				Public: tccpapiwhitelist.ConfigAPIWhitelistSecurityGroup{
 					Enabled:    config.Viper.GetBool(config.Flag.Service.Installation.Guest.Kubernetes.API.Security.Whitelist.Public.Enabled),
 					SubnetList: strings.Split(config.Viper.GetString(config.Flag.Service.Installation.Guest.Kubernetes.API.Security.Whitelist.Public.SubnetList), ","),
 				},
 			},
-			CalicoCIDR:                 config.Viper.GetInt(config.Flag.Service.Cluster.Calico.CIDR),
-			CalicoSubnet:               config.Viper.GetString(config.Flag.Service.Cluster.Calico.Subnet),
-			DeleteLoggingBucket:        config.Viper.GetBool(config.Flag.Service.AWS.LoggingBucket.Delete),
-			GuestPrivateSubnetMaskBits: config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.PrivateSubnetMaskBits),
-			GuestPublicSubnetMaskBits:  config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.PublicSubnetMaskBits),
-			GuestSubnetMaskBits:        config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.SubnetMaskBits),
-			HostAWSConfig:              awsConfig,
-			IncludeTags:                config.Viper.GetBool(config.Flag.Service.AWS.IncludeTags),
-			InstallationName:           config.Viper.GetString(config.Flag.Service.Installation.Name),
-			IPAMNetworkRange:           ipamNetworkRange,
-			RegistryDomain:             config.Viper.GetString(config.Flag.Service.Registry.Domain),
-			Route53Enabled:             config.Viper.GetBool(config.Flag.Service.AWS.Route53.Enabled),
-			RouteTables:                config.Viper.GetString(config.Flag.Service.AWS.RouteTables),
+			CalicoCIDR:                  config.Viper.GetInt(config.Flag.Service.Cluster.Calico.CIDR),
+			CalicoSubnet:                config.Viper.GetString(config.Flag.Service.Cluster.Calico.Subnet),
+			DeleteLoggingBucket:         config.Viper.GetBool(config.Flag.Service.AWS.LoggingBucket.Delete),
+			GuestPrivateSubnetMaskBits:  config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.PrivateSubnetMaskBits),
+			GuestPublicSubnetMaskBits:   config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.PublicSubnetMaskBits),
+			GuestSubnetMaskBits:         config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.SubnetMaskBits),
+			HostAWSConfig:               awsConfig,
+			IncludeTags:                 config.Viper.GetBool(config.Flag.Service.AWS.IncludeTags),
+			InstallationName:            config.Viper.GetString(config.Flag.Service.Installation.Name),
+			InterruptionHandlingEnabled: config.Viper.GetBool(config.Flag.Service.AWS.InterruptionHandling.Enabled),
+			IPAMNetworkRange:            ipamNetworkRange,
+			IPAMPoolID:                  config.Viper.GetString(config.Flag.Service.AWS.IPAM.PoolID),
+			RegistryDomain:              config.Viper.GetString(config.Flag.Service.Registry.Domain),
+			Route53Enabled:              dnsService.Route53(),
+			RouteTables:                 config.Viper.GetString(config.Flag.Service.AWS.RouteTables),
+			VPCEndpointsEnabled:         config.Viper.GetBool(config.Flag.Service.AWS.VPCEndpoints.Enabled),
 		}
 
 		clusterController, err = controller.NewCluster(c)
@@ -268,6 +339,7 @@ func New(config Config) (*Service, error) {
 	var controlPlaneController *controller.ControlPlane
 	{
 		c := controller.ControlPlaneConfig{
+			AWSClientPool:      awsClientPool,
 			CertsSearcher:      certsSearcher,
 			CloudTags:          cloudtagObject,
 			Event:              event,
@@ -284,12 +356,14 @@ func New(config Config) (*Service, error) {
 			ClusterIPRange:          config.Viper.GetString(config.Flag.Service.Cluster.Kubernetes.API.ClusterIPRange),
 			DockerDaemonCIDR:        config.Viper.GetString(config.Flag.Service.Cluster.Docker.Daemon.CIDR),
 			DockerhubToken:          config.Viper.GetString(config.Flag.Service.Registry.DockerhubToken),
+			EtcdSnapshotInterval:    config.Viper.GetDuration(config.Flag.Service.AWS.EtcdSnapshots.Interval),
+			EtcdSnapshotRetention:   config.Viper.GetInt(config.Flag.Service.AWS.EtcdSnapshots.Retention),
 			ExternalSNAT:            config.Viper.GetBool(config.Flag.Service.AWS.CNI.ExternalSNAT),
 			IgnitionPath:            config.Viper.GetString(config.Flag.Service.Guest.Ignition.Path),
 			InstallationName:        config.Viper.GetString(config.Flag.Service.Installation.Name),
 			NetworkSetupDockerImage: config.Viper.GetString(config.Flag.Service.Cluster.Kubernetes.NetworkSetup.Docker.Image),
 			PodInfraContainerImage:  config.Viper.GetString(config.Flag.Service.AWS.PodInfraContainerImage),
-			Route53Enabled:          config.Viper.GetBool(config.Flag.Service.AWS.Route53.Enabled),
+			Route53Enabled:          dnsService.Route53(),
 			RegistryDomain:          config.Viper.GetString(config.Flag.Service.Registry.Domain),
 			RegistryMirrors:         config.Viper.GetStringSlice(config.Flag.Service.Registry.Mirrors),
 			SSHUserList:             config.Viper.GetString(config.Flag.Service.Cluster.Kubernetes.SSH.UserList),
@@ -307,9 +381,10 @@ func New(config Config) (*Service, error) {
 	var controlPlaneDrainerController *controller.ControlPlaneDrainer
 	{
 		c := controller.ControlPlaneDrainerConfig{
-			Event:     event,
-			K8sClient: k8sClient,
-			Logger:    config.Logger,
+			AWSClientPool: awsClientPool,
+			Event:         event,
+			K8sClient:     k8sClient,
+			Logger:        config.Logger,
 
 			HostAWSConfig: awsConfig,
 		}
@@ -320,9 +395,28 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
+	var interruptionHandlerController *controller.InterruptionHandler
+	{
+		c := controller.InterruptionHandlerConfig{
+			AWSClientPool: awsClientPool,
+			K8sClient:     k8sClient,
+			Event:         event,
+			Logger:        config.Logger,
+
+			HostAWSConfig:               awsConfig,
+			InterruptionHandlingEnabled: config.Viper.GetBool(config.Flag.Service.AWS.InterruptionHandling.Enabled),
+		}
+
+		interruptionHandlerController, err = controller.NewInterruptionHandler(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var machineDeploymentController *controller.MachineDeployment
 	{
 		c := controller.MachineDeploymentConfig{
+			AWSClientPool:      awsClientPool,
 			CertsSearcher:      certsSearcher,
 			CloudTags:          cloudtagObject,
 			Event:              event,
@@ -349,6 +443,7 @@ func New(config Config) (*Service, error) {
 			IgnitionPath:               config.Viper.GetString(config.Flag.Service.Guest.Ignition.Path),
 			InstallationName:           config.Viper.GetString(config.Flag.Service.Installation.Name),
 			IPAMNetworkRange:           ipamNetworkRange,
+			IPAMPoolID:                 config.Viper.GetString(config.Flag.Service.AWS.IPAM.PoolID),
 			NetworkSetupDockerImage:    config.Viper.GetString(config.Flag.Service.Cluster.Kubernetes.NetworkSetup.Docker.Image),
 			PodInfraContainerImage:     config.Viper.GetString(config.Flag.Service.AWS.PodInfraContainerImage),
 			RegistryDomain:             config.Viper.GetString(config.Flag.Service.Registry.Domain),
@@ -367,9 +462,10 @@ func New(config Config) (*Service, error) {
 	var machineDeploymentDrainerController *controller.MachineDeploymentDrainer
 	{
 		c := controller.MachineDeploymentDrainerConfig{
-			Event:     event,
-			K8sClient: k8sClient,
-			Logger:    config.Logger,
+			AWSClientPool: awsClientPool,
+			Event:         event,
+			K8sClient:     k8sClient,
+			Logger:        config.Logger,
 
 			HostAWSConfig: awsConfig,
 		}
@@ -383,10 +479,11 @@ func New(config Config) (*Service, error) {
 	var terminateUnhealthyNodeController *controller.TerminateUnhealthyNode
 	{
 		c := controller.TerminateUnhealthyNodeConfig{
-			K8sClient: k8sClient,
-			Event:     event,
-			Locker:    kubeLockLocker,
-			Logger:    config.Logger,
+			AWSClientPool: awsClientPool,
+			K8sClient:     k8sClient,
+			Event:         event,
+			Locker:        kubeLockLocker,
+			Logger:        config.Logger,
 
 			HostAWSConfig: awsConfig,
 		}
@@ -414,12 +511,14 @@ func New(config Config) (*Service, error) {
 	}
 
 	s := &Service{
-		Version: versionService,
+		IPAMReport: ipamReport,
+		Version:    versionService,
 
 		bootOnce:                           sync.Once{},
 		clusterController:                  clusterController,
 		controlPlaneController:             controlPlaneController,
 		controlPlaneDrainerController:      controlPlaneDrainerController,
+		interruptionHandlerController:      interruptionHandlerController,
 		machineDeploymentController:        machineDeploymentController,
 		machineDeploymentDrainerController: machineDeploymentDrainerController,
 		terminateUnhealthyNodeController:   terminateUnhealthyNodeController,
@@ -433,6 +532,7 @@ func (s *Service) Boot(ctx context.Context) {
 		go s.clusterController.Boot(ctx)
 		go s.controlPlaneController.Boot(ctx)
 		go s.controlPlaneDrainerController.Boot(ctx)
+		go s.interruptionHandlerController.Boot(ctx)
 		go s.machineDeploymentController.Boot(ctx)
 		go s.machineDeploymentDrainerController.Boot(ctx)
 		go s.terminateUnhealthyNodeController.Boot(ctx)